@@ -0,0 +1,298 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	api_security_v1 "istio.io/api/security/v1"
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+)
+
+// defaultTrustDomain is the Istio trust domain when the mesh config doesn't set one
+const defaultTrustDomain = "cluster.local"
+
+// AuthorizationSimulatorService evaluates the AuthorizationPolicies of the mesh for a given request
+type AuthorizationSimulatorService struct {
+	businessLayer *Layer
+	conf          *config.Config
+	discovery     meshDiscovery
+	kialiCache    cache.KialiCache
+}
+
+// SimulateAuthorization evaluates the AuthorizationPolicies applying to the destination workload of the request, from
+// its namespace and the root namespace, in the order of the proxy: CUSTOM, DENY and ALLOW. AUDIT policies don't
+// change the verdict. Policies attached to waypoints with a targetRef are not evaluated.
+func (in *AuthorizationSimulatorService) SimulateAuthorization(ctx context.Context, cluster string, request models.AuthorizationSimulationRequest) (models.AuthorizationSimulation, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "SimulateAuthorization",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", request.Namespace),
+		observability.Attribute("workload", request.Workload),
+	)
+	defer end()
+
+	if request.Namespace == "" || request.Workload == "" {
+		return models.AuthorizationSimulation{}, api_errors.NewBadRequest("The namespace and name of the destination workload are required")
+	}
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetClusterNamespace(ctx, request.Namespace, cluster); err != nil {
+		return models.AuthorizationSimulation{}, err
+	}
+
+	workload, err := in.businessLayer.Workload.GetWorkload(ctx, WorkloadCriteria{Cluster: cluster, Namespace: request.Namespace, WorkloadName: request.Workload})
+	if err != nil {
+		return models.AuthorizationSimulation{}, err
+	}
+
+	kubeCache, err := in.kialiCache.GetKubeCache(cluster)
+	if err != nil {
+		return models.AuthorizationSimulation{}, err
+	}
+	policies, err := kubeCache.GetAuthorizationPolicies(request.Namespace, "")
+	if err != nil {
+		return models.AuthorizationSimulation{}, err
+	}
+	rootNamespace := in.conf.ExternalServices.Istio.RootNamespace
+	if rootNamespace != request.Namespace {
+		meshPolicies, err := kubeCache.GetAuthorizationPolicies(rootNamespace, "")
+		if err != nil {
+			return models.AuthorizationSimulation{}, err
+		}
+		policies = append(meshPolicies, policies...)
+	}
+
+	return simulateAuthorization(request, in.trustDomain(ctx), workload.Labels, policies, rootNamespace), nil
+}
+
+// trustDomain returns the trust domain of the mesh, used in the principal of the source
+func (in *AuthorizationSimulatorService) trustDomain(ctx context.Context) string {
+	if in.discovery == nil {
+		return defaultTrustDomain
+	}
+	mesh, err := in.discovery.Mesh(ctx)
+	if err != nil || len(mesh.ControlPlanes) == 0 || mesh.ControlPlanes[0].Config.TrustDomain == "" {
+		return defaultTrustDomain
+	}
+	return mesh.ControlPlanes[0].Config.TrustDomain
+}
+
+// authorizationSimulator holds the attributes of the simulated request
+type authorizationSimulator struct {
+	request         models.AuthorizationSimulationRequest
+	principal       string
+	sourceNamespace string
+	port            string
+	notes           []string
+}
+
+func simulateAuthorization(request models.AuthorizationSimulationRequest, trustDomain string, workloadLabels map[string]string, policies []*security_v1.AuthorizationPolicy, rootNamespace string) models.AuthorizationSimulation {
+	s := &authorizationSimulator{request: request, principal: request.SourcePrincipal, sourceNamespace: request.SourceNamespace, notes: []string{}}
+	if s.principal == "" && request.SourceNamespace != "" {
+		serviceAccount := request.SourceServiceAccount
+		if serviceAccount == "" {
+			serviceAccount = "default"
+		}
+		s.principal = fmt.Sprintf("%s/ns/%s/sa/%s", trustDomain, request.SourceNamespace, serviceAccount)
+	}
+	// the source namespace is the one of the principal
+	if _, after, found := strings.Cut(s.principal, "/ns/"); found {
+		s.sourceNamespace, _, _ = strings.Cut(after, "/")
+	}
+	if request.Port > 0 {
+		s.port = strconv.Itoa(request.Port)
+	}
+
+	simulation := models.AuthorizationSimulation{Request: request, Policies: []models.AuthorizationPolicyEvaluation{}}
+	for _, ap := range policies {
+		if ap.Namespace != rootNamespace && ap.Namespace != request.Namespace {
+			continue
+		}
+		if ap.Spec.TargetRef != nil || len(ap.Spec.TargetRefs) > 0 {
+			s.note("AuthorizationPolicy %s/%s targets a waypoint, it is not evaluated", ap.Namespace, ap.Name)
+			continue
+		}
+		if ap.Spec.Selector != nil && !common.SelectorMatchesLabels(ap.Spec.Selector.MatchLabels, workloadLabels) {
+			continue
+		}
+		simulation.Policies = append(simulation.Policies, models.AuthorizationPolicyEvaluation{
+			Name:         ap.Name,
+			Namespace:    ap.Namespace,
+			Action:       ap.Spec.Action.String(),
+			MatchedRules: s.matchedRules(ap),
+		})
+	}
+	sort.Slice(simulation.Policies, func(i, j int) bool {
+		if simulation.Policies[i].Namespace != simulation.Policies[j].Namespace {
+			return simulation.Policies[i].Namespace < simulation.Policies[j].Namespace
+		}
+		return simulation.Policies[i].Name < simulation.Policies[j].Name
+	})
+
+	// indexes of the policies of the action, only those matching the request when matched is true
+	policiesOf := func(action api_security_v1.AuthorizationPolicy_Action, matched bool) []int {
+		indexes := []int{}
+		for i, p := range simulation.Policies {
+			if p.Action == action.String() && (!matched || len(p.MatchedRules) > 0) {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+	allowPolicies := policiesOf(api_security_v1.AuthorizationPolicy_ALLOW, false)
+	matchedAllow := policiesOf(api_security_v1.AuthorizationPolicy_ALLOW, true)
+	matchedDeny := policiesOf(api_security_v1.AuthorizationPolicy_DENY, true)
+	matchedCustom := policiesOf(api_security_v1.AuthorizationPolicy_CUSTOM, true)
+	simulation.Audited = len(policiesOf(api_security_v1.AuthorizationPolicy_AUDIT, true)) > 0
+
+	var determining []int
+	switch {
+	case len(matchedDeny) > 0:
+		simulation.Verdict, determining = models.AuthorizationDeny, matchedDeny
+		simulation.Reason = "The request matches a DENY policy"
+	case len(allowPolicies) > 0 && len(matchedAllow) == 0:
+		simulation.Verdict, determining = models.AuthorizationDeny, allowPolicies
+		simulation.Reason = "ALLOW policies apply to the workload but none matches the request"
+	case len(matchedCustom) > 0:
+		simulation.Verdict, determining = models.AuthorizationCustom, matchedCustom
+		simulation.Reason = "The request matches a CUSTOM policy, it is allowed only if the external authorizer allows it"
+	case len(matchedAllow) > 0:
+		simulation.Verdict, determining = models.AuthorizationAllow, matchedAllow
+		simulation.Reason = "The request matches an ALLOW policy"
+	default:
+		simulation.Verdict = models.AuthorizationAllow
+		simulation.Reason = "No ALLOW policy applies to the workload"
+	}
+	for _, i := range determining {
+		simulation.Policies[i].Determining = true
+	}
+	simulation.Notes = s.notes
+	return simulation
+}
+
+func (s *authorizationSimulator) note(format string, args ...interface{}) {
+	if note := fmt.Sprintf(format, args...); !slices.Contains(s.notes, note) {
+		s.notes = append(s.notes, note)
+	}
+}
+
+// matchedRules returns the indexes of the rules of the policy matching the request. A policy without rules
+// matches no request.
+func (s *authorizationSimulator) matchedRules(ap *security_v1.AuthorizationPolicy) []int {
+	matched := []int{}
+	for i, rule := range ap.Spec.Rules {
+		if rule != nil && s.ruleMatches(ap, i, rule) {
+			matched = append(matched, i)
+		}
+	}
+	return matched
+}
+
+// ruleMatches returns true when any source and any operation of the rule match the request, and all its conditions
+func (s *authorizationSimulator) ruleMatches(ap *security_v1.AuthorizationPolicy, ruleIdx int, rule *api_security_v1.Rule) bool {
+	if len(rule.From) > 0 && !slices.ContainsFunc(rule.From, func(from *api_security_v1.Rule_From) bool {
+		return from != nil && from.Source != nil && s.sourceMatches(from.Source)
+	}) {
+		return false
+	}
+	if len(rule.To) > 0 && !slices.ContainsFunc(rule.To, func(to *api_security_v1.Rule_To) bool {
+		return to != nil && to.Operation != nil && s.operationMatches(to.Operation)
+	}) {
+		return false
+	}
+	for _, condition := range rule.When {
+		if condition != nil && !s.conditionMatches(ap, ruleIdx, condition) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *authorizationSimulator) sourceMatches(source *api_security_v1.Source) bool {
+	return valueMatches(s.principal, source.Principals, source.NotPrincipals, false) &&
+		valueMatches(s.request.RequestPrincipal, source.RequestPrincipals, source.NotRequestPrincipals, false) &&
+		valueMatches(s.sourceNamespace, source.Namespaces, source.NotNamespaces, false) &&
+		ipMatches(s.request.SourceIP, source.IpBlocks, source.NotIpBlocks) &&
+		ipMatches(s.request.SourceIP, source.RemoteIpBlocks, source.NotRemoteIpBlocks)
+}
+
+func (s *authorizationSimulator) operationMatches(operation *api_security_v1.Operation) bool {
+	return valueMatches(s.request.Host, operation.Hosts, operation.NotHosts, true) &&
+		valueMatches(s.port, operation.Ports, operation.NotPorts, false) &&
+		valueMatches(s.request.Method, operation.Methods, operation.NotMethods, false) &&
+		valueMatches(s.request.Path, operation.Paths, operation.NotPaths, false)
+}
+
+func (s *authorizationSimulator) conditionMatches(ap *security_v1.AuthorizationPolicy, ruleIdx int, condition *api_security_v1.Condition) bool {
+	switch condition.Key {
+	case "source.ip", "remote.ip":
+		return ipMatches(s.request.SourceIP, condition.Values, condition.NotValues)
+	case "source.namespace":
+		return valueMatches(s.sourceNamespace, condition.Values, condition.NotValues, false)
+	case "source.principal":
+		return valueMatches(s.principal, condition.Values, condition.NotValues, false)
+	case "request.auth.principal":
+		return valueMatches(s.request.RequestPrincipal, condition.Values, condition.NotValues, false)
+	case "destination.port":
+		return valueMatches(s.port, condition.Values, condition.NotValues, false)
+	}
+	s.note("Condition %s of AuthorizationPolicy %s/%s rule %d is not simulated, it is assumed not matching", condition.Key, ap.Namespace, ap.Name, ruleIdx)
+	return false
+}
+
+// valueMatches returns true when the value matches any of the values, if any, and none of the not values.
+// A missing value matches nothing.
+func valueMatches(value string, values, notValues []string, ignoreCase bool) bool {
+	matchesAny := func(patterns []string) bool {
+		return slices.ContainsFunc(patterns, func(pattern string) bool {
+			if ignoreCase {
+				return stringMatches(strings.ToLower(value), strings.ToLower(pattern))
+			}
+			return stringMatches(value, pattern)
+		})
+	}
+	return (len(values) == 0 || matchesAny(values)) && !matchesAny(notValues)
+}
+
+// stringMatches matches the value with an exact, prefix (abc*), suffix (*abc) or presence (*) pattern
+func stringMatches(value, pattern string) bool {
+	switch {
+	case value == "":
+		return false
+	case pattern == "*":
+		return true
+	case strings.HasPrefix(pattern, "*"):
+		return strings.HasSuffix(value, pattern[1:])
+	case strings.HasSuffix(pattern, "*"):
+		return strings.HasPrefix(value, pattern[:len(pattern)-1])
+	}
+	return value == pattern
+}
+
+// ipMatches returns true when the IP is in any of the blocks, if any, and in none of the not blocks.
+// Blocks are IPs or CIDRs.
+func ipMatches(ip string, blocks, notBlocks []string) bool {
+	parsedIP := net.ParseIP(ip)
+	inAny := func(blocks []string) bool {
+		return parsedIP != nil && slices.ContainsFunc(blocks, func(block string) bool {
+			if _, ipNet, err := net.ParseCIDR(block); err == nil {
+				return ipNet.Contains(parsedIP)
+			}
+			return parsedIP.Equal(net.ParseIP(block))
+		})
+	}
+	return (len(blocks) == 0 || inAny(blocks)) && !inAny(notBlocks)
+}
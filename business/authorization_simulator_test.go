@@ -0,0 +1,222 @@
+package business
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_security_v1 "istio.io/api/security/v1"
+	api_v1beta1 "istio.io/api/type/v1beta1"
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+var reviewsLabels = map[string]string{"app": "reviews", "version": "v1"}
+
+func fakeAuthorizationPolicy(name, namespace string, action api_security_v1.AuthorizationPolicy_Action, selector map[string]string, rules ...*api_security_v1.Rule) *security_v1.AuthorizationPolicy {
+	ap := &security_v1.AuthorizationPolicy{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace}}
+	ap.Spec.Action = action
+	ap.Spec.Rules = rules
+	if selector != nil {
+		ap.Spec.Selector = &api_v1beta1.WorkloadSelector{MatchLabels: selector}
+	}
+	return ap
+}
+
+func fromPrincipals(principals ...string) *api_security_v1.Rule_From {
+	return &api_security_v1.Rule_From{Source: &api_security_v1.Source{Principals: principals}}
+}
+
+func toOperation(methods []string, paths []string) *api_security_v1.Rule_To {
+	return &api_security_v1.Rule_To{Operation: &api_security_v1.Operation{Methods: methods, Paths: paths}}
+}
+
+func productpageRequest() models.AuthorizationSimulationRequest {
+	return models.AuthorizationSimulationRequest{
+		SourceNamespace:      "bookinfo",
+		SourceServiceAccount: "bookinfo-productpage",
+		Namespace:            "bookinfo",
+		Workload:             "reviews-v1",
+		Port:                 9080,
+		Method:               "GET",
+		Path:                 "/reviews/1",
+	}
+}
+
+func TestSimulateAuthorizationNoPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	simulation := simulateAuthorization(productpageRequest(), "cluster.local", reviewsLabels, nil, "istio-system")
+	assert.Equal(models.AuthorizationAllow, simulation.Verdict)
+	assert.Equal("No ALLOW policy applies to the workload", simulation.Reason)
+	assert.Empty(simulation.Policies)
+}
+
+func TestSimulateAuthorizationAllow(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	policies := []*security_v1.AuthorizationPolicy{
+		fakeAuthorizationPolicy("allow-productpage", "bookinfo", api_security_v1.AuthorizationPolicy_ALLOW, map[string]string{"app": "reviews"},
+			&api_security_v1.Rule{From: []*api_security_v1.Rule_From{fromPrincipals("cluster.local/ns/bookinfo/sa/bookinfo-ratings")}},
+			&api_security_v1.Rule{
+				From: []*api_security_v1.Rule_From{fromPrincipals("cluster.local/ns/bookinfo/sa/bookinfo-productpage")},
+				To:   []*api_security_v1.Rule_To{toOperation([]string{"GET"}, []string{"/reviews/*"})},
+			}),
+		// another workload
+		fakeAuthorizationPolicy("allow-details", "bookinfo", api_security_v1.AuthorizationPolicy_ALLOW, map[string]string{"app": "details"}, &api_security_v1.Rule{}),
+		// another namespace
+		fakeAuthorizationPolicy("allow-all", "default", api_security_v1.AuthorizationPolicy_ALLOW, nil, &api_security_v1.Rule{}),
+	}
+
+	simulation := simulateAuthorization(productpageRequest(), "cluster.local", reviewsLabels, policies, "istio-system")
+	assert.Equal(models.AuthorizationAllow, simulation.Verdict)
+	require.Len(simulation.Policies, 1)
+	assert.Equal("allow-productpage", simulation.Policies[0].Name)
+	assert.Equal([]int{1}, simulation.Policies[0].MatchedRules)
+	assert.True(simulation.Policies[0].Determining)
+
+	// the method doesn't match, no ALLOW policy matches
+	request := productpageRequest()
+	request.Method = "POST"
+	simulation = simulateAuthorization(request, "cluster.local", reviewsLabels, policies, "istio-system")
+	assert.Equal(models.AuthorizationDeny, simulation.Verdict)
+	assert.Equal("ALLOW policies apply to the workload but none matches the request", simulation.Reason)
+	assert.Empty(simulation.Policies[0].MatchedRules)
+	assert.True(simulation.Policies[0].Determining)
+
+	// an ALLOW policy without rules allows nothing
+	policies = []*security_v1.AuthorizationPolicy{fakeAuthorizationPolicy("allow-nothing", "bookinfo", api_security_v1.AuthorizationPolicy_ALLOW, nil)}
+	simulation = simulateAuthorization(productpageRequest(), "cluster.local", reviewsLabels, policies, "istio-system")
+	assert.Equal(models.AuthorizationDeny, simulation.Verdict)
+}
+
+func TestSimulateAuthorizationDeny(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	policies := []*security_v1.AuthorizationPolicy{
+		fakeAuthorizationPolicy("allow-all", "bookinfo", api_security_v1.AuthorizationPolicy_ALLOW, nil, &api_security_v1.Rule{}),
+		// mesh-wide
+		fakeAuthorizationPolicy("deny-other-namespaces", "istio-system", api_security_v1.AuthorizationPolicy_DENY, nil,
+			&api_security_v1.Rule{From: []*api_security_v1.Rule_From{{Source: &api_security_v1.Source{NotNamespaces: []string{"bookinfo", "istio-system"}}}}}),
+		fakeAuthorizationPolicy("audit-reviews", "bookinfo", api_security_v1.AuthorizationPolicy_AUDIT, reviewsLabels, &api_security_v1.Rule{}),
+	}
+
+	simulation := simulateAuthorization(productpageRequest(), "cluster.local", reviewsLabels, policies, "istio-system")
+	assert.Equal(models.AuthorizationAllow, simulation.Verdict)
+	assert.True(simulation.Audited)
+	require.Len(simulation.Policies, 3)
+	assert.Equal("allow-all", simulation.Policies[0].Name)
+	assert.True(simulation.Policies[0].Determining)
+	assert.False(simulation.Policies[1].Determining)
+
+	request := productpageRequest()
+	request.SourcePrincipal = "cluster.local/ns/default/sa/sleep"
+	simulation = simulateAuthorization(request, "cluster.local", reviewsLabels, policies, "istio-system")
+	assert.Equal(models.AuthorizationDeny, simulation.Verdict)
+	assert.Equal("The request matches a DENY policy", simulation.Reason)
+	assert.Equal("deny-other-namespaces", simulation.Policies[2].Name)
+	assert.Equal([]int{0}, simulation.Policies[2].MatchedRules)
+	assert.True(simulation.Policies[2].Determining)
+	assert.False(simulation.Policies[0].Determining)
+}
+
+func TestSimulateAuthorizationCustom(t *testing.T) {
+	assert := assert.New(t)
+
+	policies := []*security_v1.AuthorizationPolicy{
+		fakeAuthorizationPolicy("ext-authz", "bookinfo", api_security_v1.AuthorizationPolicy_CUSTOM, nil,
+			&api_security_v1.Rule{To: []*api_security_v1.Rule_To{toOperation(nil, []string{"/reviews*"})}}),
+	}
+	simulation := simulateAuthorization(productpageRequest(), "cluster.local", reviewsLabels, policies, "istio-system")
+	assert.Equal(models.AuthorizationCustom, simulation.Verdict)
+	assert.True(simulation.Policies[0].Determining)
+
+	request := productpageRequest()
+	request.Path = "/health"
+	simulation = simulateAuthorization(request, "cluster.local", reviewsLabels, policies, "istio-system")
+	assert.Equal(models.AuthorizationAllow, simulation.Verdict)
+}
+
+func TestSimulateAuthorizationConditions(t *testing.T) {
+	assert := assert.New(t)
+
+	policies := []*security_v1.AuthorizationPolicy{
+		fakeAuthorizationPolicy("deny-ips", "bookinfo", api_security_v1.AuthorizationPolicy_DENY, nil,
+			&api_security_v1.Rule{When: []*api_security_v1.Condition{{Key: "source.ip", Values: []string{"10.0.0.0/16"}}}}),
+		fakeAuthorizationPolicy("deny-header", "bookinfo", api_security_v1.AuthorizationPolicy_DENY, nil,
+			&api_security_v1.Rule{When: []*api_security_v1.Condition{{Key: "request.headers[x-token]", Values: []string{"bad"}}}}),
+		fakeAuthorizationPolicy("waypoint", "bookinfo", api_security_v1.AuthorizationPolicy_DENY, nil, &api_security_v1.Rule{}),
+	}
+	policies[2].Spec.TargetRefs = []*api_v1beta1.PolicyTargetReference{{Kind: "Gateway", Name: "waypoint"}}
+
+	request := productpageRequest()
+	request.SourceIP = "10.0.1.5"
+	simulation := simulateAuthorization(request, "cluster.local", reviewsLabels, policies, "istio-system")
+	assert.Equal(models.AuthorizationDeny, simulation.Verdict)
+	assert.Len(simulation.Policies, 2)
+	assert.Equal([]string{
+		"Condition request.headers[x-token] of AuthorizationPolicy bookinfo/deny-header rule 0 is not simulated, it is assumed not matching",
+		"AuthorizationPolicy bookinfo/waypoint targets a waypoint, it is not evaluated",
+	}, simulation.Notes)
+
+	request.SourceIP = "10.1.1.5"
+	simulation = simulateAuthorization(request, "cluster.local", reviewsLabels, policies, "istio-system")
+	assert.Equal(models.AuthorizationAllow, simulation.Verdict)
+}
+
+func TestValueMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(valueMatches("/reviews/1", []string{"/reviews/*"}, nil, false))
+	assert.True(valueMatches("cluster.local/ns/bookinfo/sa/default", []string{"*/ns/bookinfo/sa/default"}, nil, false))
+	assert.True(valueMatches("anything", []string{"*"}, nil, false))
+	assert.True(valueMatches("Reviews.Bookinfo", []string{"reviews.*"}, nil, true))
+	assert.True(valueMatches("", nil, []string{"*"}, false))
+	assert.False(valueMatches("", []string{"*"}, nil, false))
+	assert.False(valueMatches("GET", []string{"GET"}, []string{"G*"}, false))
+	assert.True(ipMatches("10.0.0.1", []string{"10.0.0.1"}, nil))
+	assert.False(ipMatches("", []string{"10.0.0.0/8"}, nil))
+}
+
+func TestSimulateAuthorizationService(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	deployment := &apps_v1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1", Namespace: "bookinfo"},
+		Spec: apps_v1.DeploymentSpec{
+			Template: core_v1.PodTemplateSpec{ObjectMeta: meta_v1.ObjectMeta{Labels: reviewsLabels}},
+		},
+	}
+	k8s := kubetest.NewFakeK8sClient(
+		&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-system"}},
+		deployment,
+		fakeAuthorizationPolicy("deny-all", "istio-system", api_security_v1.AuthorizationPolicy_DENY, nil, &api_security_v1.Rule{}),
+	)
+	SetupBusinessLayer(t, k8s, *conf)
+	clients := map[string]kubernetes.ClientInterface{conf.KubernetesConfig.ClusterName: k8s}
+	layer := NewWithBackends(clients, clients, new(prometheustest.PromClientMock), nil)
+
+	simulation, err := layer.Authorization.SimulateAuthorization(context.TODO(), conf.KubernetesConfig.ClusterName, productpageRequest())
+	require.NoError(err)
+	assert.Equal(models.AuthorizationDeny, simulation.Verdict)
+	require.Len(simulation.Policies, 1)
+	assert.Equal("deny-all", simulation.Policies[0].Name)
+
+	_, err = layer.Authorization.SimulateAuthorization(context.TODO(), conf.KubernetesConfig.ClusterName, models.AuthorizationSimulationRequest{Namespace: "bookinfo"})
+	assert.Error(err)
+}
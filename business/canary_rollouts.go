@@ -0,0 +1,443 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"golang.org/x/exp/maps"
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	// CanaryRolloutAnnotation holds the state of the canary rollout in the VirtualService of the service
+	CanaryRolloutAnnotation = "kiali.io/canary-rollout"
+
+	// the label set by the Kiali wizards, the UI shows the rolled out services as traffic shifted
+	wizardLabel           = "kiali_wizard"
+	wizardTrafficShifting = "traffic_shifting"
+)
+
+// The defaults of a CanaryRolloutSpec
+var (
+	defaultCanaryMaxErrorRatio = 0.05
+	defaultCanarySteps         = []int32{10, 25, 50, 100}
+	defaultCanaryStepInterval  = "5m"
+)
+
+// CanaryRolloutService shifts the traffic of a service to a canary version with weighted routes, the same routes
+// the traffic shifting wizard creates. The rollouts are progressed in the background, see RunCanaryRollouts.
+type CanaryRolloutService struct {
+	businessLayer *Layer
+	conf          *config.Config
+	kialiCache    cache.KialiCache
+	userClients   map[string]kubernetes.ClientInterface
+}
+
+// StartCanaryRollout starts the rollout of the canary version of a service: it creates or updates the DestinationRule
+// with the subsets of both versions and the VirtualService sending the weight of the first step to the canary.
+// A rollout already started for the service is replaced. Rollouts can't be started when they are disabled, nothing
+// would progress them or roll them back.
+func (in *CanaryRolloutService) StartCanaryRollout(ctx context.Context, cluster, namespace, service string, spec models.CanaryRolloutSpec) (*models.CanaryRollout, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "StartCanaryRollout",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("service", service),
+	)
+	defer end()
+
+	if !in.conf.CanaryRollouts.Enabled {
+		return nil, api_errors.NewServiceUnavailable("Canary rollouts are disabled in the Kiali configuration")
+	}
+	if err := in.checkService(ctx, cluster, namespace, service); err != nil {
+		return nil, err
+	}
+	if err := validateCanaryRolloutSpec(&spec); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	rollout := &models.CanaryRollout{
+		Cluster:            cluster,
+		Namespace:          namespace,
+		Service:            service,
+		Spec:               spec,
+		Phase:              models.CanaryRolloutProgressing,
+		Step:               0,
+		CanaryWeight:       spec.Steps[0],
+		StartTime:          now,
+		LastTransitionTime: now,
+		Message:            fmt.Sprintf("Canary weight set to %d%%", spec.Steps[0]),
+	}
+	if err := in.applyDestinationRule(ctx, rollout); err != nil {
+		return nil, err
+	}
+	if err := in.applyVirtualService(ctx, rollout); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// GetCanaryRollout returns the state of the last canary rollout of a service
+func (in *CanaryRolloutService) GetCanaryRollout(ctx context.Context, cluster, namespace, service string) (*models.CanaryRollout, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "GetCanaryRollout",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("service", service),
+	)
+	defer end()
+
+	vs, err := in.businessLayer.IstioConfig.GetIstioConfigDetails(ctx, cluster, namespace, kubernetes.VirtualServices, service)
+	if err != nil && !api_errors.IsNotFound(err) {
+		return nil, err
+	}
+	if vs.VirtualService != nil {
+		if rollout, ok := canaryRolloutOf(cluster, vs.VirtualService); ok {
+			return rollout, nil
+		}
+	}
+	return nil, api_errors.NewNotFound(schema.GroupResource{Group: "kiali.io", Resource: "canaryrollouts"}, namespace+"/"+service)
+}
+
+// RollbackCanaryRollout aborts a progressing canary rollout, sending all the traffic back to the stable version
+func (in *CanaryRolloutService) RollbackCanaryRollout(ctx context.Context, cluster, namespace, service string) (*models.CanaryRollout, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "RollbackCanaryRollout",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("service", service),
+	)
+	defer end()
+
+	rollout, err := in.GetCanaryRollout(ctx, cluster, namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	if rollout.Phase != models.CanaryRolloutProgressing {
+		return nil, api_errors.NewBadRequest(fmt.Sprintf("The canary rollout of [%s/%s] is not progressing, it is %s", namespace, service, rollout.Phase))
+	}
+	if err := in.rollBack(ctx, rollout, "Rolled back on request", time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// RunCanaryRollouts checks and progresses the canary rollouts of all the clusters every interval until the context
+// is done. The state of the rollouts is kept in their VirtualService, the rollouts resume after a restart.
+func RunCanaryRollouts(ctx context.Context, conf *config.Config, getBusiness func() *Layer) {
+	// the durations are validated with the config
+	interval, _ := time.ParseDuration(conf.CanaryRollouts.Interval)
+	if interval <= 0 {
+		return
+	}
+	log.Infof("Canary rollouts: interval [%v]", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		getBusiness().CanaryRollout.progressCanaryRollouts(ctx, conf.CanaryRollouts.RateInterval, time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// progressCanaryRollouts progresses the canary rollouts of all the clusters
+func (in *CanaryRolloutService) progressCanaryRollouts(ctx context.Context, rateInterval string, now time.Time) {
+	clusters := maps.Keys(in.userClients)
+	sort.Strings(clusters)
+	for _, cluster := range clusters {
+		istioConfigList, err := in.businessLayer.IstioConfig.GetIstioConfigList(ctx, cluster, IstioConfigCriteria{
+			IncludeVirtualServices: true,
+			LabelSelector:          wizardLabel + "=" + wizardTrafficShifting,
+		})
+		if err != nil {
+			log.Errorf("Canary rollouts: unable to list the VirtualServices of cluster [%s]: %v", cluster, err)
+			continue
+		}
+		for _, vs := range istioConfigList.VirtualServices {
+			rollout, ok := canaryRolloutOf(cluster, vs)
+			if !ok || rollout.Phase != models.CanaryRolloutProgressing {
+				continue
+			}
+			if err := in.progressCanaryRollout(ctx, rollout, rateInterval, now); err != nil {
+				log.Errorf("Canary rollouts: unable to progress the rollout of [%s/%s/%s]: %v", cluster, rollout.Namespace, rollout.Service, err)
+			}
+		}
+	}
+}
+
+// progressCanaryRollout checks the health of the canary version of a progressing rollout. The rollout is rolled back
+// when the health is over the thresholds, otherwise it moves to the next step once the step interval has elapsed.
+// It succeeds when the canary version has been receiving all the traffic for a step interval.
+// Without requests to the canary version the health is within the thresholds.
+func (in *CanaryRolloutService) progressCanaryRollout(ctx context.Context, rollout *models.CanaryRollout, rateInterval string, now time.Time) error {
+	health, err := in.businessLayer.Health.GetServiceVersionHealth(ctx, rollout.Namespace, rollout.Cluster, rollout.Service, rollout.Spec.CanaryVersion, rateInterval, now)
+	if err != nil {
+		// not knowing the health is not a reason to roll back, it is checked again at the next interval
+		return err
+	}
+	rollout.CanaryHealth = &health
+
+	if violation := canaryViolation(rollout.Spec, health); violation != "" {
+		return in.rollBack(ctx, rollout, violation, now)
+	}
+
+	// validated when the rollout is started
+	stepInterval, _ := time.ParseDuration(rollout.Spec.StepInterval)
+	if now.Sub(rollout.LastTransitionTime) < stepInterval {
+		return nil
+	}
+
+	rollout.LastTransitionTime = now
+	if rollout.Step == len(rollout.Spec.Steps)-1 {
+		rollout.Phase = models.CanaryRolloutSucceeded
+		rollout.Message = fmt.Sprintf("Version [%s] receives all the traffic", rollout.Spec.CanaryVersion)
+	} else {
+		rollout.Step++
+		rollout.CanaryWeight = rollout.Spec.Steps[rollout.Step]
+		rollout.Message = fmt.Sprintf("Canary weight set to %d%%", rollout.CanaryWeight)
+	}
+	return in.applyVirtualService(ctx, rollout)
+}
+
+// rollBack sends all the traffic of the rollout back to the stable version
+func (in *CanaryRolloutService) rollBack(ctx context.Context, rollout *models.CanaryRollout, reason string, now time.Time) error {
+	log.Infof("Canary rollouts: rolling back [%s/%s/%s]: %s", rollout.Cluster, rollout.Namespace, rollout.Service, reason)
+	rollout.Phase = models.CanaryRolloutRolledBack
+	rollout.CanaryWeight = 0
+	rollout.LastTransitionTime = now
+	rollout.Message = reason
+	return in.applyVirtualService(ctx, rollout)
+}
+
+// canaryViolation returns why the health of the canary version is over the thresholds, empty when it is not
+func canaryViolation(spec models.CanaryRolloutSpec, health models.ServiceVersionHealth) string {
+	// the max error ratio is set when the rollout starts
+	if spec.MaxErrorRatio != nil && health.ErrorRatio > *spec.MaxErrorRatio {
+		return fmt.Sprintf("Error ratio of version [%s] is %.2f%%, over the %.2f%% threshold", health.Version, health.ErrorRatio*100, *spec.MaxErrorRatio*100)
+	}
+	if spec.MaxLatency > 0 && health.LatencyP95 > spec.MaxLatency {
+		return fmt.Sprintf("95th percentile latency of version [%s] is %.0fms, over the %.0fms threshold", health.Version, health.LatencyP95, spec.MaxLatency)
+	}
+	return ""
+}
+
+// checkService checks that the service of a rollout exists and is accessible
+func (in *CanaryRolloutService) checkService(ctx context.Context, cluster, namespace, service string) error {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetClusterNamespace(ctx, namespace, cluster); err != nil {
+		return err
+	}
+	kubeCache, err := in.kialiCache.GetKubeCache(cluster)
+	if err != nil {
+		return err
+	}
+	_, err = kubeCache.GetService(namespace, service)
+	return err
+}
+
+// validateCanaryRolloutSpec sets the defaults of the spec and checks it
+func validateCanaryRolloutSpec(spec *models.CanaryRolloutSpec) error {
+	if spec.StableVersion == "" || spec.CanaryVersion == "" {
+		return api_errors.NewBadRequest("The stable and canary versions are required")
+	}
+	if spec.StableVersion == spec.CanaryVersion {
+		return api_errors.NewBadRequest(fmt.Sprintf("The stable and canary versions are the same [%s]", spec.StableVersion))
+	}
+
+	if len(spec.Steps) == 0 {
+		spec.Steps = defaultCanarySteps
+	}
+	for i, weight := range spec.Steps {
+		if weight <= 0 || weight > 100 || (i > 0 && weight <= spec.Steps[i-1]) {
+			return api_errors.NewBadRequest(fmt.Sprintf("Invalid steps %v, the weights must increase from 1 to 100", spec.Steps))
+		}
+	}
+	if spec.Steps[len(spec.Steps)-1] != 100 {
+		return api_errors.NewBadRequest(fmt.Sprintf("Invalid steps %v, the last weight must be 100", spec.Steps))
+	}
+
+	if spec.StepInterval == "" {
+		spec.StepInterval = defaultCanaryStepInterval
+	}
+	if d, err := time.ParseDuration(spec.StepInterval); err != nil || d <= 0 {
+		return api_errors.NewBadRequest(fmt.Sprintf("Invalid step interval [%s]", spec.StepInterval))
+	}
+
+	if spec.MaxErrorRatio == nil {
+		spec.MaxErrorRatio = util.AsPtr(defaultCanaryMaxErrorRatio)
+	}
+	if *spec.MaxErrorRatio < 0 || *spec.MaxErrorRatio > 1 {
+		return api_errors.NewBadRequest(fmt.Sprintf("Invalid max error ratio [%v], it must be between 0 and 1", *spec.MaxErrorRatio))
+	}
+	if spec.MaxLatency < 0 {
+		return api_errors.NewBadRequest(fmt.Sprintf("Invalid max latency [%v]", spec.MaxLatency))
+	}
+	return nil
+}
+
+// canaryRolloutOf reads the state of the canary rollout kept in a VirtualService
+func canaryRolloutOf(cluster string, vs *networking_v1.VirtualService) (*models.CanaryRollout, bool) {
+	state, ok := vs.Annotations[CanaryRolloutAnnotation]
+	if !ok {
+		return nil, false
+	}
+	rollout := &models.CanaryRollout{}
+	if err := json.Unmarshal([]byte(state), rollout); err != nil {
+		log.Errorf("Canary rollouts: invalid state of [%s/%s/%s]: %v", cluster, vs.Namespace, vs.Name, err)
+		return nil, false
+	}
+	rollout.Cluster = cluster
+	return rollout, true
+}
+
+func (in *CanaryRolloutService) serviceHost(rollout *models.CanaryRollout) string {
+	return fmt.Sprintf("%s.%s.%s", rollout.Service, rollout.Namespace, in.conf.ExternalServices.Istio.IstioIdentityDomain)
+}
+
+// currentObject returns the object named after the service of the rollout, the object is nil when it doesn't exist
+func (in *CanaryRolloutService) currentObject(ctx context.Context, rollout *models.CanaryRollout, resourceType string) (models.IstioConfigDetails, error) {
+	current, err := in.businessLayer.IstioConfig.GetIstioConfigDetails(ctx, rollout.Cluster, rollout.Namespace, resourceType, rollout.Service)
+	if err != nil && !api_errors.IsNotFound(err) {
+		return current, err
+	}
+	return current, nil
+}
+
+// applyDestinationRule creates or updates the DestinationRule of the service with a subset per version of the rollout.
+// The subsets of an existing DestinationRule named after the versions are replaced, the other subsets, the host and
+// the traffic policy are kept.
+func (in *CanaryRolloutService) applyDestinationRule(ctx context.Context, rollout *models.CanaryRollout) error {
+	current, err := in.currentObject(ctx, rollout, kubernetes.DestinationRules)
+	if err != nil {
+		return err
+	}
+
+	versionLabel := in.conf.IstioLabels.VersionLabelName
+	versions := []string{rollout.Spec.StableVersion, rollout.Spec.CanaryVersion}
+	rolloutSubset := func(version string) *api_networking_v1.Subset {
+		return &api_networking_v1.Subset{Name: version, Labels: map[string]string{versionLabel: version}}
+	}
+	subsets := []*api_networking_v1.Subset{}
+	if current.DestinationRule != nil {
+		for _, subset := range current.DestinationRule.Spec.Subsets {
+			if slices.Contains(versions, subset.Name) {
+				subset = rolloutSubset(subset.Name)
+				versions = slices.DeleteFunc(versions, func(version string) bool { return version == subset.Name })
+			}
+			subsets = append(subsets, subset)
+		}
+	}
+	for _, version := range versions {
+		subsets = append(subsets, rolloutSubset(version))
+	}
+
+	spec := map[string]interface{}{"subsets": subsets}
+	if current.DestinationRule == nil {
+		spec["host"] = in.serviceHost(rollout)
+	}
+	dr := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{wizardLabel: wizardTrafficShifting},
+		},
+		"spec": spec,
+	}
+	return in.applyIstioObject(ctx, rollout, kubernetes.DestinationRules, current.DestinationRule != nil, dr)
+}
+
+// applyVirtualService creates or updates the VirtualService of the service with the weights of the rollout and keeps
+// the state of the rollout in it. Only the destinations of the default route, the first HTTP route without match
+// conditions, of an existing VirtualService are replaced: the other routes, the other settings of the default route
+// (retries, timeouts, faults...), the hosts and the gateways are kept. A default route is added when there is none.
+func (in *CanaryRolloutService) applyVirtualService(ctx context.Context, rollout *models.CanaryRollout) error {
+	current, err := in.currentObject(ctx, rollout, kubernetes.VirtualServices)
+	if err != nil {
+		return err
+	}
+	state, err := json.Marshal(rollout)
+	if err != nil {
+		return err
+	}
+
+	host := in.serviceHost(rollout)
+	destinations := []*api_networking_v1.HTTPRouteDestination{
+		{Destination: &api_networking_v1.Destination{Host: host, Subset: rollout.Spec.StableVersion}, Weight: 100 - rollout.CanaryWeight},
+		{Destination: &api_networking_v1.Destination{Host: host, Subset: rollout.Spec.CanaryVersion}, Weight: rollout.CanaryWeight},
+	}
+	http := []*api_networking_v1.HTTPRoute{}
+	if current.VirtualService != nil {
+		// the cached object is not modified
+		http = slices.Clone(current.VirtualService.Spec.Http)
+	}
+	defaultRoute := slices.IndexFunc(http, func(route *api_networking_v1.HTTPRoute) bool { return len(route.Match) == 0 })
+	if defaultRoute < 0 {
+		http = append(http, &api_networking_v1.HTTPRoute{Route: destinations})
+	} else {
+		route := http[defaultRoute].DeepCopy()
+		if route.Redirect != nil || route.DirectResponse != nil || route.Delegate != nil {
+			return api_errors.NewBadRequest(fmt.Sprintf("The default route of VirtualService [%s/%s] doesn't route to destinations, it can't be weighted", rollout.Namespace, rollout.Service))
+		}
+		route.Route = destinations
+		http[defaultRoute] = route
+	}
+
+	spec := map[string]interface{}{"http": http}
+	if current.VirtualService == nil {
+		spec["hosts"] = []string{rollout.Service}
+	}
+	vs := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]string{wizardLabel: wizardTrafficShifting},
+			"annotations": map[string]string{CanaryRolloutAnnotation: string(state)},
+		},
+		"spec": spec,
+	}
+	return in.applyIstioObject(ctx, rollout, kubernetes.VirtualServices, current.VirtualService != nil, vs)
+}
+
+// applyIstioObject patches the object named after the service of the rollout when it exists, or creates it.
+// The changes are recorded in the Istio config history.
+func (in *CanaryRolloutService) applyIstioObject(ctx context.Context, rollout *models.CanaryRollout, resourceType string, exists bool, object map[string]interface{}) error {
+	if exists {
+		patch, err := json.Marshal(object)
+		if err != nil {
+			return err
+		}
+		_, err = in.businessLayer.IstioConfig.UpdateIstioConfigDetail(ctx, rollout.Cluster, rollout.Namespace, resourceType, rollout.Service, string(patch))
+		return err
+	}
+
+	metadata := object["metadata"].(map[string]interface{})
+	metadata["name"] = rollout.Service
+	metadata["namespace"] = rollout.Namespace
+	body, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	_, err = in.businessLayer.IstioConfig.CreateIstioConfigDetail(ctx, rollout.Cluster, rollout.Namespace, resourceType, body)
+	return err
+}
@@ -0,0 +1,304 @@
+package business
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	core_v1 "k8s.io/api/core/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func setupCanaryRollouts(t *testing.T, canaryRates model.Vector, canaryLatency float64, objects ...runtime.Object) (*Layer, *kubetest.FakeK8sClient) {
+	conf := config.NewConfig()
+	conf.CanaryRollouts.Enabled = true
+	config.Set(conf)
+	objects = append(objects,
+		&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "test"}},
+		&core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "test"}},
+	)
+	k8s := kubetest.NewFakeK8sClient(objects...)
+	SetupBusinessLayer(t, k8s, *conf)
+
+	prom := new(prometheustest.PromClientMock)
+	prom.MockServiceRequestRates("test", conf.KubernetesConfig.ClusterName, "reviews", canaryRates)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", mock.AnythingOfType("string"), "", "1m", false, []string{"0.95"}, mock.AnythingOfType("time.Time")).
+		Return(map[string]model.Vector{"0.95": {&model.Sample{Value: model.SampleValue(canaryLatency)}}}, nil)
+
+	clients := map[string]kubernetes.ClientInterface{conf.KubernetesConfig.ClusterName: k8s}
+	return NewWithBackends(clients, clients, prom, nil), k8s
+}
+
+func reviewsRequests(version, code string, rate float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"destination_service_name": "reviews",
+			"destination_version":      model.LabelValue(version),
+			"request_protocol":         "http",
+			"response_code":            model.LabelValue(code),
+			"reporter":                 "destination",
+		},
+		Value: model.SampleValue(rate),
+	}
+}
+
+func TestStartCanaryRollout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer, k8s := setupCanaryRollouts(t, model.Vector{}, 0)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	rollout, err := layer.CanaryRollout.StartCanaryRollout(context.TODO(), cluster, "test", "reviews", models.CanaryRolloutSpec{StableVersion: "v1", CanaryVersion: "v2"})
+	require.NoError(err)
+	assert.Equal(models.CanaryRolloutProgressing, rollout.Phase)
+	assert.Equal(int32(10), rollout.CanaryWeight)
+	// the defaults
+	assert.Equal([]int32{10, 25, 50, 100}, rollout.Spec.Steps)
+	assert.Equal("5m", rollout.Spec.StepInterval)
+	require.NotNil(rollout.Spec.MaxErrorRatio)
+	assert.Equal(0.05, *rollout.Spec.MaxErrorRatio)
+
+	dr, err := k8s.Istio().NetworkingV1().DestinationRules("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Equal("traffic_shifting", dr.Labels["kiali_wizard"])
+	assert.Equal("reviews.test.svc.cluster.local", dr.Spec.Host)
+	require.Len(dr.Spec.Subsets, 2)
+	assert.Equal("v2", dr.Spec.Subsets[1].Name)
+	assert.Equal(map[string]string{"version": "v2"}, dr.Spec.Subsets[1].Labels)
+
+	vs, err := k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Equal([]string{"reviews"}, vs.Spec.Hosts)
+	require.Len(vs.Spec.Http, 1)
+	require.Len(vs.Spec.Http[0].Route, 2)
+	assert.Equal("v1", vs.Spec.Http[0].Route[0].Destination.Subset)
+	assert.Equal(int32(90), vs.Spec.Http[0].Route[0].Weight)
+	assert.Equal("v2", vs.Spec.Http[0].Route[1].Destination.Subset)
+	assert.Equal(int32(10), vs.Spec.Http[0].Route[1].Weight)
+	assert.Contains(vs.Annotations, CanaryRolloutAnnotation)
+
+	state, err := layer.CanaryRollout.GetCanaryRollout(context.TODO(), cluster, "test", "reviews")
+	require.NoError(err)
+	assert.Equal(models.CanaryRolloutProgressing, state.Phase)
+	assert.Equal(int32(10), state.CanaryWeight)
+	assert.Equal("v2", state.Spec.CanaryVersion)
+
+	_, err = layer.CanaryRollout.GetCanaryRollout(context.TODO(), cluster, "test", "ratings")
+	assert.True(api_errors.IsNotFound(err))
+	_, err = layer.CanaryRollout.StartCanaryRollout(context.TODO(), cluster, "test", "ratings", models.CanaryRolloutSpec{StableVersion: "v1", CanaryVersion: "v2"})
+	assert.True(api_errors.IsNotFound(err))
+
+	for _, spec := range []models.CanaryRolloutSpec{
+		{StableVersion: "v1"},
+		{StableVersion: "v1", CanaryVersion: "v1"},
+		{StableVersion: "v1", CanaryVersion: "v2", Steps: []int32{50, 25, 100}},
+		{StableVersion: "v1", CanaryVersion: "v2", Steps: []int32{10, 50}},
+		{StableVersion: "v1", CanaryVersion: "v2", StepInterval: "often"},
+		{StableVersion: "v1", CanaryVersion: "v2", MaxErrorRatio: util.AsPtr(5.0)},
+		{StableVersion: "v1", CanaryVersion: "v2", MaxErrorRatio: util.AsPtr(-0.1)},
+	} {
+		_, err := layer.CanaryRollout.StartCanaryRollout(context.TODO(), cluster, "test", "reviews", spec)
+		assert.True(api_errors.IsBadRequest(err), "%+v", spec)
+	}
+
+	// not started when nothing would progress it
+	layer.CanaryRollout.conf.CanaryRollouts.Enabled = false
+	_, err = layer.CanaryRollout.StartCanaryRollout(context.TODO(), cluster, "test", "reviews", models.CanaryRolloutSpec{StableVersion: "v1", CanaryVersion: "v2"})
+	assert.True(api_errors.IsServiceUnavailable(err))
+}
+
+func TestStartCanaryRolloutKeepsExistingConfig(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	vs := &networking_v1.VirtualService{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "test"},
+		Spec: api_networking_v1.VirtualService{
+			Hosts:    []string{"reviews.bookinfo.com"},
+			Gateways: []string{"bookinfo-gateway"},
+			Http: []*api_networking_v1.HTTPRoute{
+				{
+					Match: []*api_networking_v1.HTTPMatchRequest{{Headers: map[string]*api_networking_v1.StringMatch{
+						"end-user": {MatchType: &api_networking_v1.StringMatch_Exact{Exact: "jason"}},
+					}}},
+					Route: []*api_networking_v1.HTTPRouteDestination{{Destination: &api_networking_v1.Destination{Host: "reviews", Subset: "v3"}}},
+				},
+				{
+					Route:   []*api_networking_v1.HTTPRouteDestination{{Destination: &api_networking_v1.Destination{Host: "reviews", Subset: "v1"}}},
+					Retries: &api_networking_v1.HTTPRetry{Attempts: 3},
+				},
+			},
+		},
+	}
+	dr := &networking_v1.DestinationRule{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "test"},
+		Spec: api_networking_v1.DestinationRule{
+			Host:          "reviews",
+			TrafficPolicy: &api_networking_v1.TrafficPolicy{Tls: &api_networking_v1.ClientTLSSettings{Mode: api_networking_v1.ClientTLSSettings_ISTIO_MUTUAL}},
+			Subsets: []*api_networking_v1.Subset{
+				{Name: "v1", Labels: map[string]string{"version": "v1"}},
+				{Name: "v3", Labels: map[string]string{"version": "v3"}},
+			},
+		},
+	}
+	layer, k8s := setupCanaryRollouts(t, model.Vector{}, 0, vs, dr)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	_, err := layer.CanaryRollout.StartCanaryRollout(context.TODO(), cluster, "test", "reviews", models.CanaryRolloutSpec{StableVersion: "v1", CanaryVersion: "v2"})
+	require.NoError(err)
+
+	updatedDR, err := k8s.Istio().NetworkingV1().DestinationRules("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Equal("reviews", updatedDR.Spec.Host)
+	assert.NotNil(updatedDR.Spec.TrafficPolicy)
+	require.Len(updatedDR.Spec.Subsets, 3)
+	assert.Equal([]string{"v1", "v3", "v2"}, []string{updatedDR.Spec.Subsets[0].Name, updatedDR.Spec.Subsets[1].Name, updatedDR.Spec.Subsets[2].Name})
+
+	updatedVS, err := k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Equal([]string{"reviews.bookinfo.com"}, updatedVS.Spec.Hosts)
+	assert.Equal([]string{"bookinfo-gateway"}, updatedVS.Spec.Gateways)
+	require.Len(updatedVS.Spec.Http, 2)
+	// the match route is kept
+	require.Len(updatedVS.Spec.Http[0].Match, 1)
+	assert.Equal("jason", updatedVS.Spec.Http[0].Match[0].Headers["end-user"].GetExact())
+	assert.Equal("v3", updatedVS.Spec.Http[0].Route[0].Destination.Subset)
+	// the default route is weighted, its settings are kept
+	defaultRoute := updatedVS.Spec.Http[1]
+	assert.Equal(int32(3), defaultRoute.Retries.Attempts)
+	require.Len(defaultRoute.Route, 2)
+	assert.Equal("v1", defaultRoute.Route[0].Destination.Subset)
+	assert.Equal(int32(90), defaultRoute.Route[0].Weight)
+	assert.Equal("v2", defaultRoute.Route[1].Destination.Subset)
+	assert.Equal(int32(10), defaultRoute.Route[1].Weight)
+}
+
+func TestProgressCanaryRollout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// the stable version errors are not the canary's
+	rates := model.Vector{reviewsRequests("v1", "500", 5), reviewsRequests("v2", "200", 9.9), reviewsRequests("v2", "503", 0.1)}
+	layer, k8s := setupCanaryRollouts(t, rates, 120)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	started, err := layer.CanaryRollout.StartCanaryRollout(context.TODO(), cluster, "test", "reviews", models.CanaryRolloutSpec{StableVersion: "v1", CanaryVersion: "v2", MaxLatency: 200})
+	require.NoError(err)
+
+	// the weight is kept for the step interval
+	layer.CanaryRollout.progressCanaryRollouts(context.TODO(), "1m", started.StartTime.Add(time.Minute))
+	rollout, err := layer.CanaryRollout.GetCanaryRollout(context.TODO(), cluster, "test", "reviews")
+	require.NoError(err)
+	assert.Equal(int32(10), rollout.CanaryWeight)
+
+	now := started.StartTime
+	for _, weight := range []int32{25, 50, 100} {
+		now = now.Add(6 * time.Minute)
+		layer.CanaryRollout.progressCanaryRollouts(context.TODO(), "1m", now)
+		rollout, err = layer.CanaryRollout.GetCanaryRollout(context.TODO(), cluster, "test", "reviews")
+		require.NoError(err)
+		assert.Equal(models.CanaryRolloutProgressing, rollout.Phase)
+		assert.Equal(weight, rollout.CanaryWeight)
+		require.NotNil(rollout.CanaryHealth)
+		assert.InDelta(0.01, rollout.CanaryHealth.ErrorRatio, 0.0001)
+		assert.Equal(120.0, rollout.CanaryHealth.LatencyP95)
+	}
+
+	now = now.Add(6 * time.Minute)
+	layer.CanaryRollout.progressCanaryRollouts(context.TODO(), "1m", now)
+	rollout, err = layer.CanaryRollout.GetCanaryRollout(context.TODO(), cluster, "test", "reviews")
+	require.NoError(err)
+	assert.Equal(models.CanaryRolloutSucceeded, rollout.Phase)
+	assert.Equal(int32(100), rollout.CanaryWeight)
+
+	vs, err := k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Equal(int32(0), vs.Spec.Http[0].Route[0].Weight)
+	assert.Equal(int32(100), vs.Spec.Http[0].Route[1].Weight)
+
+	// nothing to roll back
+	_, err = layer.CanaryRollout.RollbackCanaryRollout(context.TODO(), cluster, "test", "reviews")
+	assert.True(api_errors.IsBadRequest(err))
+}
+
+func TestProgressCanaryRolloutRollback(t *testing.T) {
+	cases := map[string]struct {
+		rates         model.Vector
+		latency       float64
+		maxErrorRatio *float64
+		message       string
+	}{
+		"errors":    {rates: model.Vector{reviewsRequests("v2", "200", 9), reviewsRequests("v2", "500", 1)}, latency: 10, message: "Error ratio of version [v2] is 10.00%, over the 5.00% threshold"},
+		"no errors": {rates: model.Vector{reviewsRequests("v2", "200", 9.9), reviewsRequests("v2", "503", 0.1)}, latency: 10, maxErrorRatio: util.AsPtr(0.0), message: "Error ratio of version [v2] is 1.00%, over the 0.00% threshold"},
+		"latency":   {rates: model.Vector{reviewsRequests("v2", "200", 10)}, latency: 350, message: "95th percentile latency of version [v2] is 350ms, over the 200ms threshold"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			layer, k8s := setupCanaryRollouts(t, tc.rates, tc.latency)
+			cluster := config.Get().KubernetesConfig.ClusterName
+
+			started, err := layer.CanaryRollout.StartCanaryRollout(context.TODO(), cluster, "test", "reviews", models.CanaryRolloutSpec{StableVersion: "v1", CanaryVersion: "v2", MaxErrorRatio: tc.maxErrorRatio, MaxLatency: 200})
+			require.NoError(err)
+
+			// rolled back without waiting for the step interval
+			layer.CanaryRollout.progressCanaryRollouts(context.TODO(), "1m", started.StartTime.Add(time.Minute))
+			rollout, err := layer.CanaryRollout.GetCanaryRollout(context.TODO(), cluster, "test", "reviews")
+			require.NoError(err)
+			assert.Equal(models.CanaryRolloutRolledBack, rollout.Phase)
+			assert.Equal(int32(0), rollout.CanaryWeight)
+			assert.Equal(tc.message, rollout.Message)
+
+			vs, err := k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+			require.NoError(err)
+			assert.Equal(int32(100), vs.Spec.Http[0].Route[0].Weight)
+			assert.Equal(int32(0), vs.Spec.Http[0].Route[1].Weight)
+		})
+	}
+}
+
+func TestRollbackCanaryRollout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer, k8s := setupCanaryRollouts(t, model.Vector{}, 0)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	_, err := layer.CanaryRollout.RollbackCanaryRollout(context.TODO(), cluster, "test", "reviews")
+	assert.True(api_errors.IsNotFound(err))
+
+	_, err = layer.CanaryRollout.StartCanaryRollout(context.TODO(), cluster, "test", "reviews", models.CanaryRolloutSpec{StableVersion: "v1", CanaryVersion: "v2", Steps: []int32{50, 100}})
+	require.NoError(err)
+	rollout, err := layer.CanaryRollout.RollbackCanaryRollout(context.TODO(), cluster, "test", "reviews")
+	require.NoError(err)
+	assert.Equal(models.CanaryRolloutRolledBack, rollout.Phase)
+
+	vs, err := k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Equal(int32(100), vs.Spec.Http[0].Route[0].Weight)
+
+	// a rolled back rollout is not progressed anymore
+	layer.CanaryRollout.progressCanaryRollouts(context.TODO(), "1m", time.Now().Add(time.Hour))
+	rollout, err = layer.CanaryRollout.GetCanaryRollout(context.TODO(), cluster, "test", "reviews")
+	require.NoError(err)
+	assert.Equal(models.CanaryRolloutRolledBack, rollout.Phase)
+	assert.Equal(int32(0), rollout.CanaryWeight)
+}
@@ -44,15 +44,18 @@ func (wsc GenericNoWorkloadFoundChecker) Check() ([]*models.IstioCheck, bool) {
 }
 
 func (wsc GenericNoWorkloadFoundChecker) hasMatchingWorkload(labelSelector map[string]string) bool {
-	selector := labels.SelectorFromSet(labelSelector)
-
 	for _, wls := range wsc.WorkloadsPerNamespace {
 		for _, wl := range wls.Workloads {
-			wlLabelSet := labels.Set(wl.Labels)
-			if selector.Matches(wlLabelSet) {
+			if SelectorMatchesLabels(labelSelector, wl.Labels) {
 				return true
 			}
 		}
 	}
 	return false
 }
+
+// SelectorMatchesLabels returns true when the workload labels contain all the selector labels.
+// An empty selector matches any workload.
+func SelectorMatchesLabels(selectorLabels map[string]string, workloadLabels map[string]string) bool {
+	return labels.SelectorFromSet(selectorLabels).Matches(labels.Set(workloadLabels))
+}
@@ -49,6 +49,17 @@ func TestWorkloadNotFound(t *testing.T) {
 	testFailureWithEmptyWorkloadList(assert, map[string]string{"app": "wrong"})
 }
 
+func TestSelectorMatchesLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	workloadLabels := map[string]string{"app": "details", "version": "v1"}
+	assert.True(SelectorMatchesLabels(map[string]string{"app": "details"}, workloadLabels))
+	assert.True(SelectorMatchesLabels(map[string]string{"app": "details", "version": "v1"}, workloadLabels))
+	assert.True(SelectorMatchesLabels(nil, workloadLabels))
+	assert.False(SelectorMatchesLabels(map[string]string{"app": "details", "version": "v2"}, workloadLabels))
+	assert.False(SelectorMatchesLabels(map[string]string{"app": "details"}, nil))
+}
+
 func testFailureWithWorkloadList(assert *assert.Assertions, selector map[string]string) {
 	testFailure(assert, selector, workloadList(), "generic.selector.workloadnotfound")
 }
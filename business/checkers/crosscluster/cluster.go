@@ -0,0 +1,29 @@
+package crosscluster
+
+import (
+	"github.com/kiali/kiali/models"
+)
+
+// Cluster is the config of a cluster of the mesh, validated against the config of the other clusters
+type Cluster struct {
+	Name               string
+	IstioConfigList    models.IstioConfigList
+	Namespaces         []string
+	Services           []string
+	TrustDomain        string
+	TrustDomainAliases []string
+}
+
+// addCheck adds the check to the validation of the object, with references to the conflicting objects
+func addCheck(validations models.IstioValidations, checkId, path string, key models.IstioValidationKey, references []models.IstioValidationKey) {
+	check := models.Build(checkId, path)
+	validations.MergeValidations(models.IstioValidations{key: &models.IstioValidation{
+		Cluster:    key.Cluster,
+		Name:       key.Name,
+		Namespace:  key.Namespace,
+		ObjectType: key.ObjectType,
+		Valid:      check.Severity != models.ErrorSeverity,
+		Checks:     []*models.IstioCheck{&check},
+		References: references,
+	}})
+}
@@ -0,0 +1,63 @@
+package crosscluster
+
+import (
+	"google.golang.org/protobuf/proto"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// DestinationRuleTrafficPolicyChecker validates that the DestinationRules of a host set the same traffic policy in
+// every cluster
+type DestinationRuleTrafficPolicyChecker struct {
+	Clusters []Cluster
+}
+
+type clusterDestinationRule struct {
+	cluster string
+	dr      *networking_v1.DestinationRule
+}
+
+func (tc DestinationRuleTrafficPolicyChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	hosts := []string{}
+	drsByHost := map[string][]clusterDestinationRule{}
+	for _, cluster := range tc.Clusters {
+		for _, dr := range cluster.IstioConfigList.DestinationRules {
+			host := kubernetes.GetHost(dr.Spec.Host, dr.Namespace, cluster.Namespaces)
+			if host.IsWildcard() {
+				continue
+			}
+			if _, found := drsByHost[host.String()]; !found {
+				hosts = append(hosts, host.String())
+			}
+			drsByHost[host.String()] = append(drsByHost[host.String()], clusterDestinationRule{cluster: cluster.Name, dr: dr})
+		}
+	}
+
+	for _, host := range hosts {
+		drs := drsByHost[host]
+		for i, current := range drs {
+			references := []models.IstioValidationKey{}
+			for j, other := range drs {
+				if i == j || current.cluster == other.cluster {
+					continue
+				}
+				if !proto.Equal(current.dr.Spec.TrafficPolicy, other.dr.Spec.TrafficPolicy) {
+					references = append(references, destinationRuleKey(other))
+				}
+			}
+			if len(references) > 0 {
+				addCheck(validations, "crosscluster.destinationrule.trafficpolicy", "spec/trafficPolicy", destinationRuleKey(current), references)
+			}
+		}
+	}
+
+	return validations
+}
+
+func destinationRuleKey(cdr clusterDestinationRule) models.IstioValidationKey {
+	return models.BuildKey(models.ObjectTypeSingular[kubernetes.DestinationRules], cdr.dr.Name, cdr.dr.Namespace, cdr.cluster)
+}
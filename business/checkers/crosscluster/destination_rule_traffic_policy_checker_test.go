@@ -0,0 +1,64 @@
+package crosscluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakeCluster(name string, istioConfigList models.IstioConfigList, services ...string) Cluster {
+	return Cluster{Name: name, IstioConfigList: istioConfigList, Namespaces: []string{"bookinfo", "istio-system"}, Services: services, TrustDomain: "cluster.local"}
+}
+
+func fakeDestinationRule(host string, lb api_networking_v1.LoadBalancerSettings_SimpleLB) *networking_v1.DestinationRule {
+	dr := &networking_v1.DestinationRule{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}}
+	dr.Spec.Host = host
+	dr.Spec.TrafficPolicy = &api_networking_v1.TrafficPolicy{
+		LoadBalancer: &api_networking_v1.LoadBalancerSettings{LbPolicy: &api_networking_v1.LoadBalancerSettings_Simple{Simple: lb}},
+	}
+	return dr
+}
+
+func TestSameTrafficPolicy(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals := DestinationRuleTrafficPolicyChecker{Clusters: []Cluster{
+		fakeCluster("east", models.IstioConfigList{DestinationRules: []*networking_v1.DestinationRule{fakeDestinationRule("reviews", api_networking_v1.LoadBalancerSettings_ROUND_ROBIN)}}),
+		fakeCluster("west", models.IstioConfigList{DestinationRules: []*networking_v1.DestinationRule{fakeDestinationRule("reviews.bookinfo.svc.cluster.local", api_networking_v1.LoadBalancerSettings_ROUND_ROBIN)}}),
+	}}.Check()
+
+	assert.Empty(vals)
+}
+
+func TestDifferentTrafficPolicy(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals := DestinationRuleTrafficPolicyChecker{Clusters: []Cluster{
+		fakeCluster("east", models.IstioConfigList{DestinationRules: []*networking_v1.DestinationRule{fakeDestinationRule("reviews", api_networking_v1.LoadBalancerSettings_ROUND_ROBIN)}}),
+		fakeCluster("west", models.IstioConfigList{DestinationRules: []*networking_v1.DestinationRule{fakeDestinationRule("reviews", api_networking_v1.LoadBalancerSettings_LEAST_REQUEST)}}),
+		// same policy as east
+		fakeCluster("north", models.IstioConfigList{DestinationRules: []*networking_v1.DestinationRule{fakeDestinationRule("reviews", api_networking_v1.LoadBalancerSettings_ROUND_ROBIN)}}),
+	}}.Check()
+
+	assert.Len(vals, 3)
+	validation, ok := vals[models.BuildKey("destinationrule", "reviews", "bookinfo", "east")]
+	assert.True(ok)
+	assert.True(validation.Valid)
+	assert.Len(validation.Checks, 1)
+	assert.Equal(models.WarningSeverity, validation.Checks[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("crosscluster.destinationrule.trafficpolicy", validation.Checks[0]))
+	assert.Equal("spec/trafficPolicy", validation.Checks[0].Path)
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("destinationrule", "reviews", "bookinfo", "west")}, validation.References)
+
+	validation = vals[models.BuildKey("destinationrule", "reviews", "bookinfo", "west")]
+	assert.Len(validation.References, 2)
+}
@@ -0,0 +1,69 @@
+package crosscluster
+
+import (
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// PeerAuthenticationModeChecker validates that the PeerAuthentications of the same workloads set the same mTLS mode in
+// every cluster
+type PeerAuthenticationModeChecker struct {
+	Clusters []Cluster
+}
+
+type clusterPeerAuthentication struct {
+	cluster string
+	pa      *security_v1.PeerAuthentication
+}
+
+func (mc PeerAuthenticationModeChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	// PeerAuthentications apply to the same workloads when they have the same namespace and selector
+	scopes := []string{}
+	pasByScope := map[string][]clusterPeerAuthentication{}
+	for _, cluster := range mc.Clusters {
+		for _, pa := range cluster.IstioConfigList.PeerAuthentications {
+			selector := ""
+			if pa.Spec.Selector != nil {
+				selector = labels.Set(pa.Spec.Selector.MatchLabels).String()
+			}
+			scope := pa.Namespace + "/" + selector
+			if _, found := pasByScope[scope]; !found {
+				scopes = append(scopes, scope)
+			}
+			pasByScope[scope] = append(pasByScope[scope], clusterPeerAuthentication{cluster: cluster.Name, pa: pa})
+		}
+	}
+
+	for _, scope := range scopes {
+		pas := pasByScope[scope]
+		for i, current := range pas {
+			references := []models.IstioValidationKey{}
+			for j, other := range pas {
+				if i == j || current.cluster == other.cluster {
+					continue
+				}
+				if mtlsMode(current.pa) != mtlsMode(other.pa) {
+					references = append(references, peerAuthenticationKey(other))
+				}
+			}
+			if len(references) > 0 {
+				addCheck(validations, "crosscluster.peerauthentication.mode", "spec/mtls/mode", peerAuthenticationKey(current), references)
+			}
+		}
+	}
+
+	return validations
+}
+
+func mtlsMode(pa *security_v1.PeerAuthentication) string {
+	return pa.Spec.GetMtls().GetMode().String()
+}
+
+func peerAuthenticationKey(cpa clusterPeerAuthentication) models.IstioValidationKey {
+	return models.BuildKey(models.ObjectTypeSingular[kubernetes.PeerAuthentications], cpa.pa.Name, cpa.pa.Namespace, cpa.cluster)
+}
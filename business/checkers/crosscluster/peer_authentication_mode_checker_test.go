@@ -0,0 +1,52 @@
+package crosscluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api_security_v1 "istio.io/api/security/v1"
+	api_v1beta1 "istio.io/api/type/v1beta1"
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakePeerAuthentication(name string, selector map[string]string, mode api_security_v1.PeerAuthentication_MutualTLS_Mode) *security_v1.PeerAuthentication {
+	pa := &security_v1.PeerAuthentication{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo"}}
+	pa.Spec.Mtls = &api_security_v1.PeerAuthentication_MutualTLS{Mode: mode}
+	if selector != nil {
+		pa.Spec.Selector = &api_v1beta1.WorkloadSelector{MatchLabels: selector}
+	}
+	return pa
+}
+
+func TestPeerAuthenticationModes(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals := PeerAuthenticationModeChecker{Clusters: []Cluster{
+		fakeCluster("east", models.IstioConfigList{PeerAuthentications: []*security_v1.PeerAuthentication{
+			fakePeerAuthentication("default", nil, api_security_v1.PeerAuthentication_MutualTLS_STRICT),
+			fakePeerAuthentication("reviews", map[string]string{"app": "reviews"}, api_security_v1.PeerAuthentication_MutualTLS_PERMISSIVE),
+		}}),
+		fakeCluster("west", models.IstioConfigList{PeerAuthentications: []*security_v1.PeerAuthentication{
+			fakePeerAuthentication("strict", nil, api_security_v1.PeerAuthentication_MutualTLS_STRICT),
+			fakePeerAuthentication("reviews", map[string]string{"app": "reviews"}, api_security_v1.PeerAuthentication_MutualTLS_STRICT),
+			// other workloads
+			fakePeerAuthentication("ratings", map[string]string{"app": "ratings"}, api_security_v1.PeerAuthentication_MutualTLS_DISABLE),
+		}}),
+	}}.Check()
+
+	assert.Len(vals, 2)
+	validation, ok := vals[models.BuildKey("peerauthentication", "reviews", "bookinfo", "east")]
+	assert.True(ok)
+	assert.Len(validation.Checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("crosscluster.peerauthentication.mode", validation.Checks[0]))
+	assert.Equal("spec/mtls/mode", validation.Checks[0].Path)
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("peerauthentication", "reviews", "bookinfo", "west")}, validation.References)
+	_, ok = vals[models.BuildKey("peerauthentication", "reviews", "bookinfo", "west")]
+	assert.True(ok)
+}
@@ -0,0 +1,61 @@
+package crosscluster
+
+import (
+	"fmt"
+	"slices"
+
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ServicePresenceChecker validates that the services routed by the VirtualServices of a cluster exist in it when they
+// exist in other clusters. Istio resolves the hosts with the services of the local cluster.
+type ServicePresenceChecker struct {
+	Clusters []Cluster
+}
+
+func (pc ServicePresenceChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, cluster := range pc.Clusters {
+		for _, vs := range cluster.IstioConfigList.VirtualServices {
+			for i, route := range vs.Spec.Http {
+				for j, rd := range route.GetRoute() {
+					pc.checkHost(validations, cluster, vs, rd.GetDestination(), fmt.Sprintf("spec/http[%d]/route[%d]/destination/host", i, j))
+				}
+			}
+			for i, route := range vs.Spec.Tcp {
+				for j, rd := range route.GetRoute() {
+					pc.checkHost(validations, cluster, vs, rd.GetDestination(), fmt.Sprintf("spec/tcp[%d]/route[%d]/destination/host", i, j))
+				}
+			}
+			for i, route := range vs.Spec.Tls {
+				for j, rd := range route.GetRoute() {
+					pc.checkHost(validations, cluster, vs, rd.GetDestination(), fmt.Sprintf("spec/tls[%d]/route[%d]/destination/host", i, j))
+				}
+			}
+		}
+	}
+
+	return validations
+}
+
+func (pc ServicePresenceChecker) checkHost(validations models.IstioValidations, cluster Cluster, vs *networking_v1.VirtualService, destination *api_networking_v1.Destination, path string) {
+	if destination == nil {
+		return
+	}
+	host := kubernetes.GetHost(destination.Host, vs.Namespace, cluster.Namespaces).String()
+	if slices.Contains(cluster.Services, host) {
+		return
+	}
+	for _, other := range pc.Clusters {
+		if other.Name != cluster.Name && slices.Contains(other.Services, host) {
+			key := models.BuildKey(models.ObjectTypeSingular[kubernetes.VirtualServices], vs.Name, vs.Namespace, cluster.Name)
+			addCheck(validations, "crosscluster.service.missing", path, key, []models.IstioValidationKey{})
+			return
+		}
+	}
+}
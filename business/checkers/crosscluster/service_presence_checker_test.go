@@ -0,0 +1,46 @@
+package crosscluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakeVirtualService(hosts ...string) *networking_v1.VirtualService {
+	vs := &networking_v1.VirtualService{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}}
+	vs.Spec.Hosts = []string{"reviews"}
+	route := &api_networking_v1.HTTPRoute{}
+	for _, host := range hosts {
+		route.Route = append(route.Route, &api_networking_v1.HTTPRouteDestination{Destination: &api_networking_v1.Destination{Host: host}})
+	}
+	vs.Spec.Http = []*api_networking_v1.HTTPRoute{route}
+	return vs
+}
+
+func TestServicePresence(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := fakeVirtualService("reviews", "ratings", "www.example.com")
+	vals := ServicePresenceChecker{Clusters: []Cluster{
+		fakeCluster("east", models.IstioConfigList{VirtualServices: []*networking_v1.VirtualService{vs}},
+			"reviews.bookinfo.svc.cluster.local", "ratings.bookinfo.svc.cluster.local"),
+		fakeCluster("west", models.IstioConfigList{VirtualServices: []*networking_v1.VirtualService{vs}},
+			"reviews.bookinfo.svc.cluster.local"),
+	}}.Check()
+
+	assert.Len(vals, 1)
+	validation, ok := vals[models.BuildKey("virtualservice", "reviews", "bookinfo", "west")]
+	assert.True(ok)
+	assert.True(validation.Valid)
+	assert.Len(validation.Checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("crosscluster.service.missing", validation.Checks[0]))
+	assert.Equal("spec/http[0]/route[1]/destination/host", validation.Checks[0].Path)
+}
@@ -0,0 +1,30 @@
+package crosscluster
+
+import (
+	"slices"
+
+	"github.com/kiali/kiali/models"
+)
+
+// TrustDomainChecker validates that every cluster trusts the identities of the other clusters of the mesh
+type TrustDomainChecker struct {
+	Cluster  Cluster
+	Clusters []Cluster
+}
+
+func (dc TrustDomainChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	for _, other := range dc.Clusters {
+		if other.Name == dc.Cluster.Name || other.TrustDomain == dc.Cluster.TrustDomain {
+			continue
+		}
+		if !slices.Contains(dc.Cluster.TrustDomainAliases, other.TrustDomain) || !slices.Contains(other.TrustDomainAliases, dc.Cluster.TrustDomain) {
+			check := models.Build("crosscluster.trustdomain.mismatch", "trustDomain")
+			checks = append(checks, &check)
+			return checks, false
+		}
+	}
+
+	return checks, true
+}
@@ -0,0 +1,36 @@
+package crosscluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func TestTrustDomains(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	east := fakeCluster("east", models.IstioConfigList{})
+	west := fakeCluster("west", models.IstioConfigList{})
+	checks, valid := TrustDomainChecker{Cluster: east, Clusters: []Cluster{east, west}}.Check()
+	assert.Empty(checks)
+	assert.True(valid)
+
+	west.TrustDomain = "west.example.com"
+	checks, valid = TrustDomainChecker{Cluster: east, Clusters: []Cluster{east, west}}.Check()
+	assert.False(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.ErrorSeverity, checks[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("crosscluster.trustdomain.mismatch", checks[0]))
+
+	// the clusters trust each other
+	east.TrustDomainAliases = []string{"west.example.com"}
+	west.TrustDomainAliases = []string{"cluster.local"}
+	checks, valid = TrustDomainChecker{Cluster: east, Clusters: []Cluster{east, west}}.Check()
+	assert.Empty(checks)
+	assert.True(valid)
+}
@@ -0,0 +1,108 @@
+package custom
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// RuleChecker evaluates a custom validation rule on a single object
+type RuleChecker struct {
+	// Object is the validated object, it is evaluated as its JSON representation
+	Object interface{}
+	Rule   config.CustomValidationRule
+}
+
+// Check adds the rule check when the rule expression is false for the object.
+// Expressions are compiled when the config is validated, one that fails for the object adds the check with the error.
+func (r RuleChecker) Check() ([]*models.IstioCheck, bool) {
+	program, err := Compile(r.Rule.Expression)
+	if err != nil {
+		return nil, true
+	}
+
+	object, err := toCELObject(r.Object)
+	if err != nil {
+		log.Errorf("Unable to convert object for the custom validation rule [%s]: %s", r.Rule.Code, err)
+		return nil, true
+	}
+	spec, ok := object["spec"]
+	if !ok {
+		spec = map[string]interface{}{}
+	}
+
+	message := r.Rule.Message
+	out, _, err := program.Eval(map[string]interface{}{"object": object, "spec": spec})
+	if err == nil {
+		if valid, isBool := out.Value().(bool); isBool && valid {
+			return nil, true
+		} else if !isBool {
+			message = fmt.Sprintf("%s (rule error: the expression returned %v)", message, out.Value())
+		}
+	} else {
+		message = fmt.Sprintf("%s (rule error: %s)", message, err)
+	}
+
+	check := &models.IstioCheck{
+		Code:     r.Rule.Code,
+		Message:  message,
+		Severity: models.SeverityLevel(r.Rule.Severity),
+		Path:     r.Rule.Path,
+	}
+	return []*models.IstioCheck{check}, check.Severity != models.ErrorSeverity
+}
+
+func toCELObject(object interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	celObject := map[string]interface{}{}
+	err = json.Unmarshal(raw, &celObject)
+	return celObject, err
+}
+
+type compiledRule struct {
+	program cel.Program
+	err     error
+}
+
+var (
+	compiledRules   = map[string]compiledRule{}
+	compiledRulesMu sync.RWMutex
+)
+
+// Compile returns the CEL program of the rule expression. Programs, and compile errors, are cached by expression,
+// so a wrong expression is logged once.
+func Compile(expression string) (cel.Program, error) {
+	compiledRulesMu.RLock()
+	compiled, found := compiledRules[expression]
+	compiledRulesMu.RUnlock()
+	if found {
+		return compiled.program, compiled.err
+	}
+
+	compiled.program, compiled.err = compile(expression)
+	if compiled.err != nil {
+		log.Errorf("Unable to compile the custom validation rule expression [%s]: %s", expression, compiled.err)
+	}
+
+	compiledRulesMu.Lock()
+	compiledRules[expression] = compiled
+	compiledRulesMu.Unlock()
+	return compiled.program, compiled.err
+}
+
+func compile(expression string) (cel.Program, error) {
+	env, ast, err := config.CompileCustomValidationExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	return env.Program(ast)
+}
@@ -0,0 +1,84 @@
+package custom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_networking_v1 "istio.io/api/networking/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func outlierDetectionRule() config.CustomValidationRule {
+	return config.CustomValidationRule{
+		Code:       "ACME001",
+		Expression: "has(spec.trafficPolicy) && has(spec.trafficPolicy.outlierDetection)",
+		Kinds:      []string{"destinationrule"},
+		Message:    "DestinationRule must set outlierDetection",
+		Path:       "spec/trafficPolicy",
+		Severity:   "error",
+	}
+}
+
+func TestRuleCheckerValid(t *testing.T) {
+	dr := data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews")
+	dr.Spec.TrafficPolicy = &api_networking_v1.TrafficPolicy{OutlierDetection: &api_networking_v1.OutlierDetection{}}
+
+	checks, valid := RuleChecker{Object: dr, Rule: outlierDetectionRule()}.Check()
+	assert.True(t, valid)
+	assert.Empty(t, checks)
+}
+
+func TestRuleCheckerInvalid(t *testing.T) {
+	dr := data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews")
+
+	checks, valid := RuleChecker{Object: dr, Rule: outlierDetectionRule()}.Check()
+	assert.False(t, valid)
+	require.Len(t, checks, 1)
+	assert.Equal(t, "ACME001", checks[0].Code)
+	assert.Equal(t, "DestinationRule must set outlierDetection", checks[0].Message)
+	assert.Equal(t, models.ErrorSeverity, checks[0].Severity)
+	assert.Equal(t, "spec/trafficPolicy", checks[0].Path)
+
+	// warnings keep the object valid
+	rule := outlierDetectionRule()
+	rule.Severity = "warning"
+	checks, valid = RuleChecker{Object: dr, Rule: rule}.Check()
+	assert.True(t, valid)
+	require.Len(t, checks, 1)
+	assert.Equal(t, models.WarningSeverity, checks[0].Severity)
+}
+
+func TestRuleCheckerObjectVariable(t *testing.T) {
+	rule := outlierDetectionRule()
+	rule.Expression = "object.metadata.namespace != 'default'"
+
+	_, valid := RuleChecker{Object: data.CreateEmptyDestinationRule("default", "reviews", "reviews"), Rule: rule}.Check()
+	assert.False(t, valid)
+	_, valid = RuleChecker{Object: data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews"), Rule: rule}.Check()
+	assert.True(t, valid)
+}
+
+func TestRuleCheckerErrors(t *testing.T) {
+	dr := data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews")
+
+	// the evaluation fails on a missing field, the check reports the error
+	rule := outlierDetectionRule()
+	rule.Expression = "spec.trafficPolicy.outlierDetection != null"
+	checks, valid := RuleChecker{Object: dr, Rule: rule}.Check()
+	assert.False(t, valid)
+	require.Len(t, checks, 1)
+	assert.Contains(t, checks[0].Message, "rule error")
+
+	// a wrong expression is not evaluated
+	rule.Expression = "spec.trafficPolicy +"
+	checks, valid = RuleChecker{Object: dr, Rule: rule}.Check()
+	assert.True(t, valid)
+	assert.Empty(t, checks)
+
+	_, err := Compile("size(spec)")
+	assert.Error(t, err, "the expression must return a bool")
+}
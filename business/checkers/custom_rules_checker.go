@@ -0,0 +1,81 @@
+package checkers
+
+import (
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/business/checkers/custom"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// WorkloadEntryCheckerType is the object type of the WorkloadEntries, which only custom rules validate
+const WorkloadEntryCheckerType = "workloadentry"
+
+// CustomRulesChecker runs the custom validation rules of the config on the objects of the kinds of each rule
+type CustomRulesChecker struct {
+	Cluster         string
+	IstioConfigList models.IstioConfigList
+	MTLSDetails     kubernetes.MTLSDetails
+	RBACDetails     kubernetes.RBACDetails
+	Rules           []config.CustomValidationRule
+}
+
+func (c CustomRulesChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+	if len(c.Rules) == 0 {
+		return validations
+	}
+
+	objectsByKind := c.objectsByKind()
+	for _, rule := range c.Rules {
+		for _, kind := range rule.Kinds {
+			for _, object := range objectsByKind[kind] {
+				validations.MergeValidations(c.runRule(rule, kind, object))
+			}
+		}
+	}
+
+	return validations
+}
+
+func (c CustomRulesChecker) runRule(rule config.CustomValidationRule, kind string, object meta_v1.Object) models.IstioValidations {
+	key, validation := EmptyValidValidation(object.GetName(), object.GetNamespace(), kind, c.Cluster)
+	checks, valid := custom.RuleChecker{Object: object, Rule: rule}.Check()
+	validation.Checks = append(validation.Checks, checks...)
+	validation.Valid = valid
+	return models.IstioValidations{key: validation}
+}
+
+// objectsByKind must return every kind of config.CustomValidationRuleKinds
+func (c CustomRulesChecker) objectsByKind() map[string][]meta_v1.Object {
+	return map[string][]meta_v1.Object{
+		AuthorizationPolicyCheckerType:   toObjects(c.RBACDetails.AuthorizationPolicies),
+		DestinationRuleCheckerType:       toObjects(c.IstioConfigList.DestinationRules),
+		EnvoyFilterCheckerType:           toObjects(c.IstioConfigList.EnvoyFilters),
+		GatewayCheckerType:               toObjects(c.IstioConfigList.Gateways),
+		K8sGatewayCheckerType:            toObjects(c.IstioConfigList.K8sGateways),
+		K8sGRPCRouteCheckerType:          toObjects(c.IstioConfigList.K8sGRPCRoutes),
+		K8sHTTPRouteCheckerType:          toObjects(c.IstioConfigList.K8sHTTPRoutes),
+		K8sReferenceGrantCheckerType:     toObjects(c.IstioConfigList.K8sReferenceGrants),
+		K8sTCPRouteCheckerType:           toObjects(c.IstioConfigList.K8sTCPRoutes),
+		K8sTLSRouteCheckerType:           toObjects(c.IstioConfigList.K8sTLSRoutes),
+		PeerAuthenticationCheckerType:    toObjects(c.MTLSDetails.PeerAuthentications),
+		RequestAuthenticationCheckerType: toObjects(c.IstioConfigList.RequestAuthentications),
+		ServiceEntryCheckerType:          toObjects(c.IstioConfigList.ServiceEntries),
+		SidecarCheckerType:               toObjects(c.IstioConfigList.Sidecars),
+		TelemetryCheckerType:             toObjects(c.IstioConfigList.Telemetries),
+		VirtualCheckerType:               toObjects(c.IstioConfigList.VirtualServices),
+		WasmPluginCheckerType:            toObjects(c.IstioConfigList.WasmPlugins),
+		WorkloadEntryCheckerType:         toObjects(c.IstioConfigList.WorkloadEntries),
+		WorkloadGroupCheckerType:         toObjects(c.IstioConfigList.WorkloadGroups),
+	}
+}
+
+func toObjects[T meta_v1.Object](objects []T) []meta_v1.Object {
+	result := make([]meta_v1.Object, 0, len(objects))
+	for _, o := range objects {
+		result = append(result, o)
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package checkers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestCustomRulesChecker(t *testing.T) {
+	vs := data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})
+	vs.Spec.Http = []*api_networking_v1.HTTPRoute{{
+		Match: []*api_networking_v1.HTTPMatchRequest{{
+			Uri: &api_networking_v1.StringMatch{MatchType: &api_networking_v1.StringMatch_Regex{Regex: "/api/.*"}},
+		}},
+	}}
+
+	checker := CustomRulesChecker{
+		Cluster: config.DefaultClusterID,
+		IstioConfigList: models.IstioConfigList{
+			DestinationRules: []*networking_v1.DestinationRule{data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews")},
+			VirtualServices:  []*networking_v1.VirtualService{vs, data.CreateEmptyVirtualService("ratings", "bookinfo", []string{"ratings"})},
+		},
+		Rules: []config.CustomValidationRule{
+			{
+				Code:       "ACME001",
+				Expression: "has(spec.trafficPolicy) && has(spec.trafficPolicy.outlierDetection)",
+				Kinds:      []string{DestinationRuleCheckerType},
+				Message:    "DestinationRule must set outlierDetection",
+				Severity:   "warning",
+			},
+			{
+				Code:       "ACME002",
+				Expression: "!has(spec.http) || !spec.http.exists(r, has(r.match) && r.match.exists(m, has(m.uri) && has(m.uri.regex)))",
+				Kinds:      []string{VirtualCheckerType},
+				Message:    "VirtualService can't use regex URI matches",
+				Severity:   "error",
+			},
+		},
+	}
+
+	validations := checker.Check()
+	require.Len(t, validations, 3)
+
+	drValidation := validations[models.BuildKey(DestinationRuleCheckerType, "reviews", "bookinfo", config.DefaultClusterID)]
+	require.NotNil(t, drValidation)
+	assert.True(t, drValidation.Valid)
+	require.Len(t, drValidation.Checks, 1)
+	assert.Equal(t, "ACME001", drValidation.Checks[0].Code)
+
+	vsValidation := validations[models.BuildKey(VirtualCheckerType, "reviews", "bookinfo", config.DefaultClusterID)]
+	require.NotNil(t, vsValidation)
+	assert.False(t, vsValidation.Valid)
+	require.Len(t, vsValidation.Checks, 1)
+	assert.Equal(t, "ACME002", vsValidation.Checks[0].Code)
+
+	vsValidation = validations[models.BuildKey(VirtualCheckerType, "ratings", "bookinfo", config.DefaultClusterID)]
+	require.NotNil(t, vsValidation)
+	assert.True(t, vsValidation.Valid)
+	assert.Empty(t, vsValidation.Checks)
+
+	checker.Rules = nil
+	assert.Empty(t, checker.Check())
+}
+
+func TestCustomRulesCheckerSupportsConfigKinds(t *testing.T) {
+	kinds := []string{}
+	for kind := range (CustomRulesChecker{}).objectsByKind() {
+		kinds = append(kinds, kind)
+	}
+	assert.ElementsMatch(t, config.CustomValidationRuleKinds, kinds)
+}
@@ -1,6 +1,8 @@
 package destinationrules
 
 import (
+	"fmt"
+
 	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
 
 	"github.com/kiali/kiali/kubernetes"
@@ -28,7 +30,23 @@ func (m MeshWideMTLSChecker) Check() ([]*models.IstioCheck, bool) {
 	}
 
 	check := models.Build("destinationrules.mtls.meshpolicymissing", "spec/trafficPolicy/tls/mode")
+	m.addFixes(&check)
 	validations = append(validations, &check)
 
 	return validations, false
 }
+
+// addFixes suggests enabling mTLS in the mesh-wide PeerAuthentication, or disabling it in the DestinationRule
+func (m MeshWideMTLSChecker) addFixes(check *models.IstioCheck) {
+	for _, mp := range m.MTLSDetails.MeshPeerAuthentications {
+		if mp.Spec.Selector == nil {
+			// permissive mode enables mTLS without rejecting plain text traffic
+			patch := map[string]interface{}{"spec": map[string]interface{}{"mtls": map[string]interface{}{"mode": "PERMISSIVE"}}}
+			check.AddFix(fmt.Sprintf("Enable mTLS in PeerAuthentication %s", mp.Name), kubernetes.PeerAuthentications, mp.Namespace, mp.Name, patch)
+			break
+		}
+	}
+
+	patch := map[string]interface{}{"spec": map[string]interface{}{"trafficPolicy": map[string]interface{}{"tls": nil}}}
+	check.AddFix(fmt.Sprintf("Disable mTLS in DestinationRule %s", m.DestinationRule.Name), kubernetes.DestinationRules, m.DestinationRule.Namespace, m.DestinationRule.Name, patch)
+}
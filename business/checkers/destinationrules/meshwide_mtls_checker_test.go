@@ -27,6 +27,46 @@ func TestMTLSMeshWideDREnabledWithNoMeshPolicy(t *testing.T) {
 	testReturnsAValidation(t, destinationRule, mTlsDetails)
 }
 
+// Context: DestinationRule enables mesh-wide mTLS
+// Context: There is one MeshPolicy in DISABLE mode
+// It returns a validation with the fixes of both objects
+func TestMTLSMeshWideDREnabledWithMeshPolicyDisabledFixes(t *testing.T) {
+	assert := assert.New(t)
+
+	destinationRule := data.AddTrafficPolicyToDestinationRule(data.CreateMTLSTrafficPolicyForDestinationRules(),
+		data.CreateEmptyDestinationRule("istio-system", "dr-mtls", "*.local"))
+
+	mTlsDetails := kubernetes.MTLSDetails{
+		MeshPeerAuthentications: []*security_v1.PeerAuthentication{
+			data.CreateEmptyMeshPeerAuthentication("default", data.CreateMTLS("DISABLE")),
+		},
+	}
+
+	vals, valid := MeshWideMTLSChecker{
+		DestinationRule: destinationRule,
+		MTLSDetails:     mTlsDetails,
+	}.Check()
+	assert.False(valid)
+	assert.Len(vals, 1)
+	fixes := vals[0].Fixes
+	assert.Len(fixes, 2)
+
+	assert.Equal(models.IstioCheckFix{
+		Description: "Enable mTLS in PeerAuthentication default",
+		ObjectType:  "peerauthentications",
+		Namespace:   "istio-system",
+		Name:        "default",
+		Patch:       `{"spec":{"mtls":{"mode":"PERMISSIVE"}}}`,
+	}, fixes[0])
+	assert.Equal(models.IstioCheckFix{
+		Description: "Disable mTLS in DestinationRule dr-mtls",
+		ObjectType:  "destinationrules",
+		Namespace:   "istio-system",
+		Name:        "dr-mtls",
+		Patch:       `{"spec":{"trafficPolicy":{"tls":null}}}`,
+	}, fixes[1])
+}
+
 // Context: DestinationRule enables mesh-wide mTLS
 // Context: There is one MeshPolicy in PERMISSIVE mode
 // It doesn't return any validation
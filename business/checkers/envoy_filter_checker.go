@@ -0,0 +1,51 @@
+package checkers
+
+import (
+	networking_v1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/envoyfilters"
+	"github.com/kiali/kiali/models"
+)
+
+const EnvoyFilterCheckerType = "envoyfilter"
+
+type EnvoyFilterChecker struct {
+	Cluster               string
+	EnvoyFilters          []*networking_v1alpha3.EnvoyFilter
+	ProxyVersions         []string
+	WorkloadsPerNamespace map[string]models.WorkloadList
+}
+
+func (in EnvoyFilterChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, envoyFilter := range in.EnvoyFilters {
+		validations.MergeValidations(in.runChecks(envoyFilter))
+	}
+
+	return validations
+}
+
+// runChecks runs all the individual checks for a single envoy filter and appends the result into validations.
+func (in EnvoyFilterChecker) runChecks(envoyFilter *networking_v1alpha3.EnvoyFilter) models.IstioValidations {
+	key, rrValidation := EmptyValidValidation(envoyFilter.Name, envoyFilter.Namespace, EnvoyFilterCheckerType, in.Cluster)
+	selectorLabels := make(map[string]string)
+	if envoyFilter.Spec.WorkloadSelector != nil {
+		selectorLabels = envoyFilter.Spec.WorkloadSelector.Labels
+	}
+
+	enabledCheckers := []Checker{
+		common.WorkloadSelectorNoWorkloadFoundChecker(EnvoyFilterCheckerType, selectorLabels, in.WorkloadsPerNamespace),
+		envoyfilters.ProxyVersionChecker{EnvoyFilter: envoyFilter, ProxyVersions: in.ProxyVersions},
+		envoyfilters.ContextChecker{EnvoyFilter: envoyFilter, WorkloadsPerNamespace: in.WorkloadsPerNamespace},
+	}
+
+	for _, checker := range enabledCheckers {
+		checks, validChecker := checker.Check()
+		rrValidation.Checks = append(rrValidation.Checks, checks...)
+		rrValidation.Valid = rrValidation.Valid && validChecker
+	}
+
+	return models.IstioValidations{key: rrValidation}
+}
@@ -0,0 +1,68 @@
+package envoyfilters
+
+import (
+	"fmt"
+
+	api_networking_v1alpha3 "istio.io/api/networking/v1alpha3"
+	networking_v1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// ContextChecker validates that the patch contexts apply to the kind of proxies of the selected workloads
+type ContextChecker struct {
+	EnvoyFilter           *networking_v1alpha3.EnvoyFilter
+	WorkloadsPerNamespace map[string]models.WorkloadList
+}
+
+func (cc ContextChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	// A filter without selector applies to every proxy of the namespace
+	selector := cc.EnvoyFilter.Spec.WorkloadSelector
+	if selector == nil || len(selector.Labels) == 0 {
+		return checks, true
+	}
+
+	gateways, sidecars := 0, 0
+	for _, wls := range cc.WorkloadsPerNamespace {
+		// Filters of the root namespace select workloads of all the namespaces
+		if wls.Namespace != cc.EnvoyFilter.Namespace && !config.IsRootNamespace(cc.EnvoyFilter.Namespace) {
+			continue
+		}
+		for i := range wls.Workloads {
+			if !common.SelectorMatchesLabels(selector.Labels, wls.Workloads[i].Labels) {
+				continue
+			}
+			if wls.Workloads[i].IsGateway() {
+				gateways++
+			} else {
+				sidecars++
+			}
+		}
+	}
+	// Filters not selecting any workload are reported by the selector checker
+	if gateways+sidecars == 0 {
+		return checks, true
+	}
+
+	for i, patch := range cc.EnvoyFilter.Spec.ConfigPatches {
+		path := fmt.Sprintf("spec/configPatches[%d]/match/context", i)
+		switch patch.GetMatch().GetContext() {
+		case api_networking_v1alpha3.EnvoyFilter_GATEWAY:
+			if gateways == 0 {
+				check := models.Build("envoyfilter.context.nogateway", path)
+				checks = append(checks, &check)
+			}
+		case api_networking_v1alpha3.EnvoyFilter_SIDECAR_INBOUND, api_networking_v1alpha3.EnvoyFilter_SIDECAR_OUTBOUND:
+			if sidecars == 0 {
+				check := models.Build("envoyfilter.context.nosidecar", path)
+				checks = append(checks, &check)
+			}
+		}
+	}
+
+	return checks, true
+}
@@ -0,0 +1,90 @@
+package envoyfilters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api_networking_v1alpha3 "istio.io/api/networking/v1alpha3"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakeContextWorkloads() map[string]models.WorkloadList {
+	gateway := data.CreateWorkloadListItem("istio-ingressgateway", map[string]string{"istio": "ingressgateway"})
+	gateway.Type = "Deployment"
+	reviews := data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"})
+	reviews.Type = "Deployment"
+	return map[string]models.WorkloadList{
+		"bookinfo":     data.CreateWorkloadList("bookinfo", reviews),
+		"istio-system": data.CreateWorkloadList("istio-system", gateway),
+	}
+}
+
+func TestContextMatchesWorkloads(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := ContextChecker{
+		EnvoyFilter: fakeEnvoyFilter(map[string]string{"app": "reviews"},
+			fakePatch(api_networking_v1alpha3.EnvoyFilter_SIDECAR_INBOUND, ""),
+			fakePatch(api_networking_v1alpha3.EnvoyFilter_ANY, "")),
+		WorkloadsPerNamespace: fakeContextWorkloads(),
+	}.Check()
+
+	assert.Empty(vals)
+	assert.True(valid)
+}
+
+func TestContextGatewayOnSidecars(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := ContextChecker{
+		EnvoyFilter:           fakeEnvoyFilter(map[string]string{"app": "reviews"}, fakePatch(api_networking_v1alpha3.EnvoyFilter_GATEWAY, "")),
+		WorkloadsPerNamespace: fakeContextWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(vals, 1)
+	assert.Equal(models.WarningSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("envoyfilter.context.nogateway", vals[0]))
+	assert.Equal("spec/configPatches[0]/match/context", vals[0].Path)
+}
+
+func TestContextSidecarOnGateways(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	envoyFilter := fakeEnvoyFilter(map[string]string{"istio": "ingressgateway"},
+		fakePatch(api_networking_v1alpha3.EnvoyFilter_GATEWAY, ""),
+		fakePatch(api_networking_v1alpha3.EnvoyFilter_SIDECAR_OUTBOUND, ""))
+
+	// the gateway is in another namespace
+	vals, valid := ContextChecker{EnvoyFilter: envoyFilter, WorkloadsPerNamespace: fakeContextWorkloads()}.Check()
+	assert.Empty(vals)
+	assert.True(valid)
+
+	envoyFilter.Namespace = conf.ExternalServices.Istio.RootNamespace
+	vals, valid = ContextChecker{EnvoyFilter: envoyFilter, WorkloadsPerNamespace: fakeContextWorkloads()}.Check()
+	assert.True(valid)
+	assert.Len(vals, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("envoyfilter.context.nosidecar", vals[0]))
+	assert.Equal("spec/configPatches[1]/match/context", vals[0].Path)
+}
+
+func TestContextWithoutSelector(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := ContextChecker{
+		EnvoyFilter:           fakeEnvoyFilter(nil, fakePatch(api_networking_v1alpha3.EnvoyFilter_GATEWAY, "")),
+		WorkloadsPerNamespace: fakeContextWorkloads(),
+	}.Check()
+
+	assert.Empty(vals)
+	assert.True(valid)
+}
@@ -0,0 +1,52 @@
+package envoyfilters
+
+import (
+	"fmt"
+	"regexp"
+
+	networking_v1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+
+	"github.com/kiali/kiali/models"
+)
+
+// ProxyVersionChecker validates the proxy version regexes of the patches against the versions of the control planes
+type ProxyVersionChecker struct {
+	EnvoyFilter   *networking_v1alpha3.EnvoyFilter
+	ProxyVersions []string
+}
+
+func (pvc ProxyVersionChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	for i, patch := range pvc.EnvoyFilter.Spec.ConfigPatches {
+		proxyVersion := patch.GetMatch().GetProxy().GetProxyVersion()
+		if proxyVersion == "" {
+			continue
+		}
+		path := fmt.Sprintf("spec/configPatches[%d]/match/proxy/proxyVersion", i)
+		re, err := regexp.Compile(proxyVersion)
+		if err != nil {
+			check := models.Build("envoyfilter.proxyversion.invalid", path)
+			checks = append(checks, &check)
+			valid = false
+			continue
+		}
+		// Without known versions the patch can't be checked
+		if len(pvc.ProxyVersions) == 0 {
+			continue
+		}
+		matches := false
+		for _, version := range pvc.ProxyVersions {
+			if re.MatchString(version) {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			check := models.Build("envoyfilter.proxyversion.nomatch", path)
+			checks = append(checks, &check)
+		}
+	}
+
+	return checks, valid
+}
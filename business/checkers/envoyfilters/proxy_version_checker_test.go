@@ -0,0 +1,86 @@
+package envoyfilters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api_networking_v1alpha3 "istio.io/api/networking/v1alpha3"
+	networking_v1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakeEnvoyFilter(selector map[string]string, patches ...*api_networking_v1alpha3.EnvoyFilter_EnvoyConfigObjectPatch) *networking_v1alpha3.EnvoyFilter {
+	ef := &networking_v1alpha3.EnvoyFilter{ObjectMeta: meta_v1.ObjectMeta{Name: "filter", Namespace: "bookinfo"}}
+	if selector != nil {
+		ef.Spec.WorkloadSelector = &api_networking_v1alpha3.WorkloadSelector{Labels: selector}
+	}
+	ef.Spec.ConfigPatches = patches
+	return ef
+}
+
+func fakePatch(context api_networking_v1alpha3.EnvoyFilter_PatchContext, proxyVersion string) *api_networking_v1alpha3.EnvoyFilter_EnvoyConfigObjectPatch {
+	match := &api_networking_v1alpha3.EnvoyFilter_EnvoyConfigObjectMatch{Context: context}
+	if proxyVersion != "" {
+		match.Proxy = &api_networking_v1alpha3.EnvoyFilter_ProxyMatch{ProxyVersion: proxyVersion}
+	}
+	return &api_networking_v1alpha3.EnvoyFilter_EnvoyConfigObjectPatch{ApplyTo: api_networking_v1alpha3.EnvoyFilter_HTTP_FILTER, Match: match}
+}
+
+func TestProxyVersionMatches(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := ProxyVersionChecker{
+		EnvoyFilter:   fakeEnvoyFilter(nil, fakePatch(api_networking_v1alpha3.EnvoyFilter_ANY, `^1\.22.*`), fakePatch(api_networking_v1alpha3.EnvoyFilter_ANY, "")),
+		ProxyVersions: []string{"1.21.2", "1.22.1"},
+	}.Check()
+
+	assert.Empty(vals)
+	assert.True(valid)
+}
+
+func TestProxyVersionNoMatch(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := ProxyVersionChecker{
+		EnvoyFilter:   fakeEnvoyFilter(nil, fakePatch(api_networking_v1alpha3.EnvoyFilter_ANY, `^1\.22.*`), fakePatch(api_networking_v1alpha3.EnvoyFilter_ANY, `^1\.1[0-9].*`)),
+		ProxyVersions: []string{"1.19.0"},
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(vals, 1)
+	assert.Equal(models.WarningSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("envoyfilter.proxyversion.nomatch", vals[0]))
+	assert.Equal("spec/configPatches[0]/match/proxy/proxyVersion", vals[0].Path)
+}
+
+func TestProxyVersionInvalid(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := ProxyVersionChecker{
+		EnvoyFilter: fakeEnvoyFilter(nil, fakePatch(api_networking_v1alpha3.EnvoyFilter_ANY, `^1\.(22`)),
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(vals, 1)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("envoyfilter.proxyversion.invalid", vals[0]))
+}
+
+func TestProxyVersionUnknownVersions(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := ProxyVersionChecker{
+		EnvoyFilter: fakeEnvoyFilter(nil, fakePatch(api_networking_v1alpha3.EnvoyFilter_ANY, `^1\.22.*`)),
+	}.Check()
+
+	assert.Empty(vals)
+	assert.True(valid)
+}
@@ -4,7 +4,7 @@ import (
 	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
 	k8s_networking_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
-	"github.com/kiali/kiali/business/checkers/k8sgrpcroutes"
+	"github.com/kiali/kiali/business/checkers/k8sroutes"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
 )
@@ -25,6 +25,7 @@ func (in K8sGRPCRouteChecker) Check() models.IstioValidations {
 	validations := models.IstioValidations{}
 
 	validations = validations.MergeValidations(in.runIndividualChecks())
+	validations = validations.MergeValidations(in.runGroupChecks())
 
 	return validations
 }
@@ -42,19 +43,40 @@ func (in K8sGRPCRouteChecker) runIndividualChecks() models.IstioValidations {
 	return validations
 }
 
+// Runs checks across all the GRPC Routes
+func (in K8sGRPCRouteChecker) runGroupChecks() models.IstioValidations {
+	routes := make([]k8sroutes.Route, 0, len(in.K8sGRPCRoutes))
+	for _, rt := range in.K8sGRPCRoutes {
+		routes = append(routes, k8sroutes.FromGRPCRoute(rt))
+	}
+
+	return k8sroutes.ConflictChecker{Cluster: in.Cluster, Routes: routes}.Check()
+}
+
 func (in K8sGRPCRouteChecker) runChecks(rt *k8s_networking_v1.GRPCRoute, gatewayNames map[string]struct{}) models.IstioValidations {
 	key, validations := EmptyValidValidation(rt.Name, rt.Namespace, K8sGRPCRouteCheckerType, in.Cluster)
+	route := k8sroutes.FromGRPCRoute(rt)
 
 	enabledCheckers := []Checker{
-		k8sgrpcroutes.NoK8sGatewayChecker{
-			K8sGRPCRoute: rt,
+		k8sroutes.NoK8sGatewayChecker{
 			GatewayNames: gatewayNames,
+			Route:        route,
 		},
-		k8sgrpcroutes.NoHostChecker{
-			Namespaces:         in.Namespaces,
-			K8sGRPCRoute:       rt,
+		k8sroutes.NoHostChecker{
 			K8sReferenceGrants: in.K8sReferenceGrants,
+			Namespaces:         in.Namespaces,
 			RegistryServices:   in.RegistryServices,
+			Route:              route,
+		},
+		k8sroutes.ParentRefChecker{
+			K8sGateways: in.K8sGateways,
+			Namespaces:  in.Namespaces,
+			Route:       route,
+		},
+		k8sroutes.BackendRefChecker{
+			Namespaces:       in.Namespaces,
+			RegistryServices: in.RegistryServices,
+			Route:            route,
 		},
 	}
 
@@ -4,7 +4,7 @@ import (
 	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
 	k8s_networking_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
-	"github.com/kiali/kiali/business/checkers/k8shttproutes"
+	"github.com/kiali/kiali/business/checkers/k8sroutes"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
 )
@@ -25,6 +25,7 @@ func (in K8sHTTPRouteChecker) Check() models.IstioValidations {
 	validations := models.IstioValidations{}
 
 	validations = validations.MergeValidations(in.runIndividualChecks())
+	validations = validations.MergeValidations(in.runGroupChecks())
 
 	return validations
 }
@@ -42,19 +43,40 @@ func (in K8sHTTPRouteChecker) runIndividualChecks() models.IstioValidations {
 	return validations
 }
 
+// Runs checks across all the HTTP Routes
+func (in K8sHTTPRouteChecker) runGroupChecks() models.IstioValidations {
+	routes := make([]k8sroutes.Route, 0, len(in.K8sHTTPRoutes))
+	for _, rt := range in.K8sHTTPRoutes {
+		routes = append(routes, k8sroutes.FromHTTPRoute(rt))
+	}
+
+	return k8sroutes.ConflictChecker{Cluster: in.Cluster, Routes: routes}.Check()
+}
+
 func (in K8sHTTPRouteChecker) runChecks(rt *k8s_networking_v1.HTTPRoute, gatewayNames map[string]struct{}) models.IstioValidations {
 	key, validations := EmptyValidValidation(rt.Name, rt.Namespace, K8sHTTPRouteCheckerType, in.Cluster)
+	route := k8sroutes.FromHTTPRoute(rt)
 
 	enabledCheckers := []Checker{
-		k8shttproutes.NoK8sGatewayChecker{
-			K8sHTTPRoute: rt,
+		k8sroutes.NoK8sGatewayChecker{
 			GatewayNames: gatewayNames,
+			Route:        route,
 		},
-		k8shttproutes.NoHostChecker{
-			Namespaces:         in.Namespaces,
-			K8sHTTPRoute:       rt,
+		k8sroutes.NoHostChecker{
 			K8sReferenceGrants: in.K8sReferenceGrants,
+			Namespaces:         in.Namespaces,
 			RegistryServices:   in.RegistryServices,
+			Route:              route,
+		},
+		k8sroutes.ParentRefChecker{
+			K8sGateways: in.K8sGateways,
+			Namespaces:  in.Namespaces,
+			Route:       route,
+		},
+		k8sroutes.BackendRefChecker{
+			Namespaces:       in.Namespaces,
+			RegistryServices: in.RegistryServices,
+			Route:            route,
 		},
 	}
 
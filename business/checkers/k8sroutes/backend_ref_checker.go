@@ -0,0 +1,70 @@
+package k8sroutes
+
+import (
+	"fmt"
+
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// supportedBackendKinds are the backend kinds supported by Istio, besides the Services
+var supportedBackendKinds = map[string]string{
+	"Hostname":     kubernetes.NetworkingGroupVersionV1.Group,
+	"ServiceEntry": kubernetes.NetworkingGroupVersionV1.Group,
+}
+
+// BackendRefChecker validates the kind of the backends of the route, and the port of the Service backends
+type BackendRefChecker struct {
+	Namespaces       models.Namespaces
+	RegistryServices []*kubernetes.RegistryService
+	Route            Route
+}
+
+func (b BackendRefChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+
+	for k, rule := range b.Route.Rules {
+		for i, ref := range rule.BackendRefs {
+			path := fmt.Sprintf("spec/rules[%d]/backendRefs[%d]", k, i)
+			if !IsServiceRef(ref) {
+				if !isSupportedBackend(ref) {
+					validations = append(validations, buildCheck("k8sroutes.backendref.kindnotsupported", path+"/kind"))
+				}
+				continue
+			}
+			// The port of a Service backend is required by the API server
+			if ref.Port != nil && !b.hasPort(string(ref.Name), b.Route.BackendNamespace(ref), int(*ref.Port)) {
+				validations = append(validations, buildCheck("k8sroutes.backendref.portnotfound", path+"/port"))
+			}
+		}
+	}
+
+	return validations, len(validations) == 0
+}
+
+// hasPort returns false only when the Service is found and has no such port, missing Services are reported by the NoHostChecker
+func (b BackendRefChecker) hasPort(name, namespace string, port int) bool {
+	fqdn := kubernetes.GetHost(name, namespace, b.Namespaces.GetNames()).String()
+	for _, rs := range b.RegistryServices {
+		if rs.Hostname != fqdn || len(rs.Ports) == 0 {
+			continue
+		}
+		for _, p := range rs.Ports {
+			if p.Port == port {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func isSupportedBackend(ref k8s_networking_v1.BackendRef) bool {
+	if ref.Kind == nil || ref.Group == nil {
+		return false
+	}
+	group, found := supportedBackendKinds[string(*ref.Kind)]
+	return found && group == string(*ref.Group)
+}
@@ -0,0 +1,85 @@
+package k8sroutes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakeRegistryServices() []*kubernetes.RegistryService {
+	registryServices := data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*")
+	registryServices[0].Ports = append(registryServices[0].Ports, struct {
+		Name     string `json:"name,omitempty"`
+		Port     int    `json:"port"`
+		Protocol string `json:"protocol,omitempty"`
+	}{Name: "http", Port: 9080, Protocol: "HTTP"})
+	return registryServices
+}
+
+func backendRefChecks(route Route) ([]*models.IstioCheck, bool) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	return BackendRefChecker{
+		Namespaces:       models.Namespaces{{Name: "bookinfo"}},
+		RegistryServices: fakeRegistryServices(),
+		Route:            route,
+	}.Check()
+}
+
+func TestBackendRefValidPort(t *testing.T) {
+	assert := assert.New(t)
+
+	route := FromTCPRoute(data.AddBackendRefToTCPRoute("reviews", "bookinfo", 9080, data.CreateTCPRoute("route", "bookinfo", "gatewayapi")))
+
+	checks, valid := backendRefChecks(route)
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestBackendRefPortNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	route := FromTCPRoute(data.AddBackendRefToTCPRoute("reviews", "bookinfo", 9443, data.CreateTCPRoute("route", "bookinfo", "gatewayapi")))
+
+	checks, valid := backendRefChecks(route)
+	assert.False(valid)
+	assert.Len(checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.backendref.portnotfound", checks[0]))
+	assert.Equal("spec/rules[0]/backendRefs[0]/port", checks[0].Path)
+
+	// Missing Services are left to the NoHostChecker
+	route = FromTCPRoute(data.AddBackendRefToTCPRoute("ratings", "bookinfo", 9443, data.CreateTCPRoute("route", "bookinfo", "gatewayapi")))
+
+	checks, valid = backendRefChecks(route)
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestBackendRefKind(t *testing.T) {
+	assert := assert.New(t)
+
+	rt := data.AddBackendRefToTCPRoute("reviews", "bookinfo", 9080, data.CreateTCPRoute("route", "bookinfo", "gatewayapi"))
+	kind := k8s_networking_v1.Kind("Hostname")
+	group := k8s_networking_v1.Group("networking.istio.io")
+	rt.Spec.Rules[0].BackendRefs[0].Kind = &kind
+	rt.Spec.Rules[0].BackendRefs[0].Group = &group
+
+	checks, valid := backendRefChecks(FromTCPRoute(rt))
+	assert.True(valid)
+	assert.Empty(checks)
+
+	kind = k8s_networking_v1.Kind("ConfigMap")
+	checks, valid = backendRefChecks(FromTCPRoute(rt))
+	assert.False(valid)
+	assert.Len(checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.backendref.kindnotsupported", checks[0]))
+	assert.Equal("spec/rules[0]/backendRefs[0]/kind", checks[0].Path)
+}
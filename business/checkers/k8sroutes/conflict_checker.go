@@ -0,0 +1,97 @@
+package k8sroutes
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kiali/kiali/models"
+)
+
+// ConflictChecker validates that no two routes attached to the same listener select the same requests for the
+// same hostname. The Gateway API gives precedence to the oldest route, then to the route first in alphabetical
+// order by namespace/name, so the conflicting rules of the other routes are never used.
+type ConflictChecker struct {
+	Cluster string
+	Routes  []Route
+}
+
+type routeAttachment struct {
+	parent   string
+	hostname string
+	match    string
+}
+
+func (c ConflictChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	routes := make([]Route, len(c.Routes))
+	copy(routes, c.Routes)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if !routes[i].CreationTimestamp.Equal(&routes[j].CreationTimestamp) {
+			return routes[i].CreationTimestamp.Before(&routes[j].CreationTimestamp)
+		}
+		if routes[i].Namespace != routes[j].Namespace {
+			return routes[i].Namespace < routes[j].Namespace
+		}
+		return routes[i].Name < routes[j].Name
+	})
+
+	owners := map[routeAttachment]Route{}
+	for _, route := range routes {
+		hostnames := []string{allRequests}
+		if route.HasHostnames() && len(route.Hostnames) > 0 {
+			hostnames = hostnames[:0]
+			for _, hostname := range route.Hostnames {
+				hostnames = append(hostnames, string(hostname))
+			}
+		}
+
+		for _, ref := range route.ParentRefs {
+			if !IsGatewayRef(ref) {
+				continue
+			}
+			parent := fmt.Sprintf("%s/%s", route.ParentNamespace(ref), ref.Name)
+			if ref.SectionName != nil {
+				parent += "/" + string(*ref.SectionName)
+			}
+			if ref.Port != nil {
+				parent += fmt.Sprintf(":%d", *ref.Port)
+			}
+
+			for k, rule := range route.Rules {
+				for _, match := range rule.Matches {
+					for _, hostname := range hostnames {
+						attachment := routeAttachment{parent: parent, hostname: hostname, match: match}
+						owner, found := owners[attachment]
+						if !found {
+							owners[attachment] = route
+							continue
+						}
+						if owner.Namespace != route.Namespace || owner.Name != route.Name {
+							validations.MergeValidations(c.conflict(route, owner, fmt.Sprintf("spec/rules[%d]", k)))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return validations
+}
+
+func (c ConflictChecker) conflict(route, owner Route, path string) models.IstioValidations {
+	key := models.IstioValidationKey{Name: route.Name, Namespace: route.Namespace, ObjectType: route.ObjectType(), Cluster: c.Cluster}
+	check := models.Build("k8sroutes.rule.conflict", path)
+	validation := &models.IstioValidation{
+		Cluster:    c.Cluster,
+		Name:       route.Name,
+		Namespace:  route.Namespace,
+		ObjectType: route.ObjectType(),
+		Valid:      true,
+		Checks:     []*models.IstioCheck{&check},
+		References: []models.IstioValidationKey{
+			{Name: owner.Name, Namespace: owner.Namespace, ObjectType: owner.ObjectType(), Cluster: c.Cluster},
+		},
+	}
+	return models.IstioValidations{key: validation}
+}
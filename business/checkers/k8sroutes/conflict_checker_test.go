@@ -0,0 +1,85 @@
+package k8sroutes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakeConflictRoute(name string, age time.Duration, hostname string, path string) *k8s_networking_v1.HTTPRoute {
+	rt := data.AddBackendRefToHTTPRoute("reviews", "bookinfo", data.CreateHTTPRoute(name, "bookinfo", "gatewayapi", []string{hostname}))
+	rt.CreationTimestamp = meta_v1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(-age))
+	if path != "" {
+		pathType := k8s_networking_v1.PathMatchExact
+		rt.Spec.Rules[0].Matches = []k8s_networking_v1.HTTPRouteMatch{{Path: &k8s_networking_v1.HTTPPathMatch{Type: &pathType, Value: &path}}}
+	}
+	return rt
+}
+
+func conflictValidations(routes ...*k8s_networking_v1.HTTPRoute) models.IstioValidations {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	converted := []Route{}
+	for _, rt := range routes {
+		converted = append(converted, FromHTTPRoute(rt))
+	}
+	return ConflictChecker{Cluster: "east", Routes: converted}.Check()
+}
+
+func TestConflictingRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	vals := conflictValidations(
+		fakeConflictRoute("newer", time.Hour, "reviews.bookinfo.com", "/reviews"),
+		fakeConflictRoute("older", 2*time.Hour, "reviews.bookinfo.com", "/reviews"),
+	)
+
+	assert.Len(vals, 1)
+	newer := vals[models.IstioValidationKey{ObjectType: "k8shttproute", Namespace: "bookinfo", Name: "newer", Cluster: "east"}]
+	assert.NotNil(newer)
+	assert.True(newer.Valid)
+	assert.Len(newer.Checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.rule.conflict", newer.Checks[0]))
+	assert.Equal("spec/rules[0]", newer.Checks[0].Path)
+	assert.Equal([]models.IstioValidationKey{{ObjectType: "k8shttproute", Namespace: "bookinfo", Name: "older", Cluster: "east"}}, newer.References)
+}
+
+func TestConflictingRoutesSameAge(t *testing.T) {
+	assert := assert.New(t)
+
+	// The first route in alphabetical order wins
+	vals := conflictValidations(
+		fakeConflictRoute("route-b", time.Hour, "reviews.bookinfo.com", ""),
+		fakeConflictRoute("route-a", time.Hour, "reviews.bookinfo.com", ""),
+	)
+
+	assert.Len(vals, 1)
+	assert.NotNil(vals[models.IstioValidationKey{ObjectType: "k8shttproute", Namespace: "bookinfo", Name: "route-b", Cluster: "east"}])
+}
+
+func TestNoConflictingRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(conflictValidations(
+		fakeConflictRoute("reviews", time.Hour, "reviews.bookinfo.com", "/reviews"),
+		fakeConflictRoute("ratings", 2*time.Hour, "reviews.bookinfo.com", "/ratings"),
+		fakeConflictRoute("details", 3*time.Hour, "details.bookinfo.com", "/reviews"),
+	))
+
+	// Different listeners of the same gateway
+	rt1 := fakeConflictRoute("reviews", time.Hour, "reviews.bookinfo.com", "/reviews")
+	rt2 := fakeConflictRoute("reviews-https", 2*time.Hour, "reviews.bookinfo.com", "/reviews")
+	http, https := k8s_networking_v1.SectionName("http"), k8s_networking_v1.SectionName("https")
+	rt1.Spec.ParentRefs[0].SectionName = &http
+	rt2.Spec.ParentRefs[0].SectionName = &https
+	assert.Empty(conflictValidations(rt1, rt2))
+}
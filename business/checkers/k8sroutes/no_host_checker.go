@@ -0,0 +1,60 @@
+package k8sroutes
+
+import (
+	"fmt"
+	"strings"
+
+	k8s_networking_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// NoHostChecker validates that the Services referenced by the route exist, and that a ReferenceGrant
+// allows the references to Services from other namespaces
+type NoHostChecker struct {
+	K8sReferenceGrants []*k8s_networking_v1beta1.ReferenceGrant
+	Namespaces         models.Namespaces
+	RegistryServices   []*kubernetes.RegistryService
+	Route              Route
+}
+
+func (n NoHostChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+
+	for i, ref := range n.Route.ParentRefs {
+		if ref.Kind == nil || string(*ref.Kind) != kubernetes.ServiceType {
+			continue
+		}
+		if !n.hasService(string(ref.Name), n.Route.ParentNamespace(ref)) {
+			validations = append(validations, buildCheck("k8sroutes.nohost.namenotfound", fmt.Sprintf("spec/parentRefs[%d]/name", i)))
+		}
+	}
+
+	for k, rule := range n.Route.Rules {
+		for i, ref := range rule.BackendRefs {
+			if !IsServiceRef(ref) {
+				continue
+			}
+			if !n.hasService(string(ref.Name), n.Route.BackendNamespace(ref)) {
+				validations = append(validations, buildCheck("k8sroutes.nohost.namenotfound", fmt.Sprintf("spec/rules[%d]/backendRefs[%d]/name", k, i)))
+			}
+		}
+	}
+
+	return validations, len(validations) == 0
+}
+
+// hasService returns true when the Service exists and, when it is in another namespace, a ReferenceGrant allows the route to use it
+func (n NoHostChecker) hasService(name, namespace string) bool {
+	// service name should not be set in fqdn format
+	if strings.Contains(name, ".") {
+		return false
+	}
+	fqdn := kubernetes.GetHost(name, namespace, n.Namespaces.GetNames())
+	if !kubernetes.HasMatchingRegistryService(namespace, fqdn.String(), n.RegistryServices) {
+		return false
+	}
+	return namespace == n.Route.Namespace ||
+		kubernetes.HasMatchingReferenceGrant(n.Route.Namespace, namespace, n.Route.Kind, kubernetes.ServiceType, n.K8sReferenceGrants)
+}
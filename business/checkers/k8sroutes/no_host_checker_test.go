@@ -0,0 +1,342 @@
+package k8sroutes
+
+import (
+	"testing"
+
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	k8s_networking_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func TestValidRefHost(t *testing.T) {
+	c := config.Get()
+	c.ExternalServices.Istio.IstioIdentityDomain = "svc.cluster.local"
+	config.Set(c)
+
+	assert := assert.New(t)
+
+	registryService1 := data.CreateFakeRegistryServices("other.bookinfo.svc.cluster.local", "bookinfo", "*")
+	registryService2 := data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*")
+
+	vals, valid := NoHostChecker{
+		RegistryServices:   append(registryService1, registryService2...),
+		K8sReferenceGrants: []*k8s_networking_v1beta1.ReferenceGrant{data.CreateReferenceGrant("grant", "bookinfo", "bookinfo2")},
+		Route:              FromHTTPRoute(data.AddServiceParentRefToHTTPRoute("reviews", "bookinfo", data.AddBackendRefToHTTPRoute("reviews", "bookinfo", data.CreateHTTPRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(vals)
+}
+
+func TestMissingGrant(t *testing.T) {
+	c := config.Get()
+	c.ExternalServices.Istio.IstioIdentityDomain = "svc.cluster.local"
+	config.Set(c)
+
+	assert := assert.New(t)
+
+	registryService1 := data.CreateFakeRegistryServices("other.bookinfo.svc.cluster.local", "bookinfo", "*")
+	registryService2 := data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*")
+
+	vals, valid := NoHostChecker{
+		RegistryServices: append(registryService1, registryService2...),
+		Route:            FromHTTPRoute(data.AddServiceParentRefToHTTPRoute("reviews", "bookinfo", data.AddBackendRefToHTTPRoute("reviews", "bookinfo", data.CreateHTTPRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+	}.Check()
+
+	assert.False(valid)
+	assert.NotEmpty(vals)
+	assert.Len(vals, 2)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[0]))
+	assert.Equal("spec/parentRefs[1]/name", vals[0].Path)
+	assert.Equal(models.ErrorSeverity, vals[1].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[1]))
+	assert.Equal("spec/rules[0]/backendRefs[0]/name", vals[1].Path)
+}
+
+func TestWrongGrant(t *testing.T) {
+	c := config.Get()
+	c.ExternalServices.Istio.IstioIdentityDomain = "svc.cluster.local"
+	config.Set(c)
+
+	assert := assert.New(t)
+
+	registryService1 := data.CreateFakeRegistryServices("other.bookinfo.svc.cluster.local", "bookinfo", "*")
+	registryService2 := data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*")
+
+	vals, valid := NoHostChecker{
+		RegistryServices:   append(registryService1, registryService2...),
+		K8sReferenceGrants: []*k8s_networking_v1beta1.ReferenceGrant{data.CreateReferenceGrant("grant", "bookinfo", "bookinfo")},
+		Route:              FromHTTPRoute(data.AddServiceParentRefToHTTPRoute("reviews", "bookinfo", data.AddBackendRefToHTTPRoute("reviews", "bookinfo", data.CreateHTTPRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+	}.Check()
+
+	assert.False(valid)
+	assert.NotEmpty(vals)
+	assert.Len(vals, 2)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[0]))
+	assert.Equal("spec/parentRefs[1]/name", vals[0].Path)
+	assert.Equal(models.ErrorSeverity, vals[1].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[1]))
+	assert.Equal("spec/rules[0]/backendRefs[0]/name", vals[1].Path)
+}
+
+func TestValidRefHostDefaultNs(t *testing.T) {
+	c := config.Get()
+	c.ExternalServices.Istio.IstioIdentityDomain = "svc.cluster.local"
+	config.Set(c)
+
+	assert := assert.New(t)
+
+	registryService1 := data.CreateFakeRegistryServices("other.bookinfo.svc.cluster.local", "bookinfo", "*")
+	registryService2 := data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*")
+
+	vals, valid := NoHostChecker{
+		RegistryServices:   append(registryService1, registryService2...),
+		K8sReferenceGrants: []*k8s_networking_v1beta1.ReferenceGrant{data.CreateReferenceGrant("grant", "bookinfo", "bookinfo2")},
+		Route:              FromHTTPRoute(data.AddServiceParentRefToHTTPRoute("reviews", "", data.AddBackendRefToHTTPRoute("reviews", "", data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo"})))),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(vals)
+}
+
+func TestInvalidRefHostDefaultNs(t *testing.T) {
+	c := config.Get()
+	c.ExternalServices.Istio.IstioIdentityDomain = "svc.cluster.local"
+	config.Set(c)
+
+	assert := assert.New(t)
+
+	registryService1 := data.CreateFakeRegistryServices("other.bookinfo.svc.cluster.local", "bookinfo", "*")
+	registryService2 := data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*")
+
+	vals, valid := NoHostChecker{
+		RegistryServices:   append(registryService1, registryService2...),
+		K8sReferenceGrants: []*k8s_networking_v1beta1.ReferenceGrant{data.CreateReferenceGrant("grant", "bookinfo", "bookinfo2")},
+		Route:              FromHTTPRoute(data.AddServiceParentRefToHTTPRoute("reviews", "", data.AddBackendRefToHTTPRoute("reviews", "", data.CreateHTTPRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+	}.Check()
+
+	assert.False(valid)
+	assert.NotEmpty(vals)
+	assert.Len(vals, 2)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[0]))
+	assert.Equal("spec/parentRefs[1]/name", vals[0].Path)
+	assert.Equal(models.ErrorSeverity, vals[1].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[1]))
+	assert.Equal("spec/rules[0]/backendRefs[0]/name", vals[1].Path)
+}
+
+func TestNoValidRefHost(t *testing.T) {
+	c := config.Get()
+	c.ExternalServices.Istio.IstioIdentityDomain = "svc.cluster.local"
+	config.Set(c)
+
+	registryService1 := data.CreateFakeRegistryServices("other.bookinfo.svc.cluster.local", "bookinfo", "*")
+	registryService2 := data.CreateFakeRegistryServices("details.bookinfo.svc.cluster.local", "bookinfo", "*")
+
+	assert := assert.New(t)
+
+	vals, valid := NoHostChecker{
+		RegistryServices:   append(registryService1, registryService2...),
+		K8sReferenceGrants: []*k8s_networking_v1beta1.ReferenceGrant{data.CreateReferenceGrant("grant", "bookinfo", "bookinfo2")},
+		Route:              FromHTTPRoute(data.AddServiceParentRefToHTTPRoute("ratings", "bookinfo", data.AddBackendRefToHTTPRoute("ratings", "bookinfo", data.AddBackendRefToHTTPRoute("reviews", "bookinfo", data.CreateHTTPRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo2"}))))),
+	}.Check()
+
+	assert.False(valid)
+	assert.NotEmpty(vals)
+	assert.Len(vals, 3)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[0]))
+	assert.Equal("spec/parentRefs[1]/name", vals[0].Path)
+
+	assert.Equal(models.ErrorSeverity, vals[1].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[1]))
+	assert.Equal("spec/rules[0]/backendRefs[0]/name", vals[1].Path)
+
+	assert.Equal(models.ErrorSeverity, vals[2].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[2]))
+	assert.Equal("spec/rules[1]/backendRefs[0]/name", vals[2].Path)
+}
+
+func TestInvalidRefHostFQDN(t *testing.T) {
+	c := config.Get()
+	c.ExternalServices.Istio.IstioIdentityDomain = "svc.cluster.local"
+	config.Set(c)
+
+	assert := assert.New(t)
+
+	registryService1 := data.CreateFakeRegistryServices("other.bookinfo.svc.cluster.local", "bookinfo", "*")
+	registryService2 := data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*")
+
+	vals, valid := NoHostChecker{
+		RegistryServices:   append(registryService1, registryService2...),
+		K8sReferenceGrants: []*k8s_networking_v1beta1.ReferenceGrant{data.CreateReferenceGrant("grant", "bookinfo", "bookinfo2")},
+		Route:              FromHTTPRoute(data.AddServiceParentRefToHTTPRoute("reviews.bookinfo.svc.cluster.local", "", data.AddBackendRefToHTTPRoute("reviews.bookinfo.svc.cluster.local", "", data.CreateHTTPRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+	}.Check()
+
+	assert.False(valid)
+	assert.NotEmpty(vals)
+	assert.Len(vals, 2)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[0]))
+	assert.Equal("spec/parentRefs[1]/name", vals[0].Path)
+	assert.Equal(models.ErrorSeverity, vals[1].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[1]))
+	assert.Equal("spec/rules[0]/backendRefs[0]/name", vals[1].Path)
+}
+
+func TestNoHostOtherRouteKinds(t *testing.T) {
+	c := config.Get()
+	c.ExternalServices.Istio.IstioIdentityDomain = "svc.cluster.local"
+	config.Set(c)
+
+	registryServices := append(data.CreateFakeRegistryServices("other.bookinfo.svc.cluster.local", "bookinfo", "*"),
+		data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*")...)
+	grant := func(namespace, fromNamespace string, fromKind k8s_networking_v1.Kind) []*k8s_networking_v1beta1.ReferenceGrant {
+		return []*k8s_networking_v1beta1.ReferenceGrant{data.CreateReferenceGrantByKind("grant", namespace, fromNamespace, fromKind)}
+	}
+
+	cases := []struct {
+		name             string
+		registryServices []*kubernetes.RegistryService
+		grants           []*k8s_networking_v1beta1.ReferenceGrant
+		route            Route
+		invalidPaths     []string
+	}{
+		{
+			name:             "grpc valid ref host",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo2", kubernetes.K8sActualGRPCRouteType),
+			route:            FromGRPCRoute(data.AddServiceParentRefToGRPCRoute("reviews", "bookinfo", data.AddBackendRefToGRPCRoute("reviews", "bookinfo", data.CreateGRPCRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+		},
+		{
+			name:             "grpc missing grant",
+			registryServices: registryServices,
+			route:            FromGRPCRoute(data.AddServiceParentRefToGRPCRoute("reviews", "bookinfo", data.AddBackendRefToGRPCRoute("reviews", "bookinfo", data.CreateGRPCRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+			invalidPaths:     []string{"spec/parentRefs[1]/name", "spec/rules[0]/backendRefs[0]/name"},
+		},
+		{
+			name:             "grpc wrong grant",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo", kubernetes.K8sActualGRPCRouteType),
+			route:            FromGRPCRoute(data.AddServiceParentRefToGRPCRoute("reviews", "bookinfo", data.AddBackendRefToGRPCRoute("reviews", "bookinfo", data.CreateGRPCRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+			invalidPaths:     []string{"spec/parentRefs[1]/name", "spec/rules[0]/backendRefs[0]/name"},
+		},
+		{
+			name:             "grpc grant for another kind",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo2", kubernetes.K8sActualHTTPRouteType),
+			route:            FromGRPCRoute(data.AddServiceParentRefToGRPCRoute("reviews", "bookinfo", data.AddBackendRefToGRPCRoute("reviews", "bookinfo", data.CreateGRPCRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+			invalidPaths:     []string{"spec/parentRefs[1]/name", "spec/rules[0]/backendRefs[0]/name"},
+		},
+		{
+			name:             "grpc valid ref host default namespace",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo2", kubernetes.K8sActualGRPCRouteType),
+			route:            FromGRPCRoute(data.AddServiceParentRefToGRPCRoute("reviews", "", data.AddBackendRefToGRPCRoute("reviews", "", data.CreateGRPCRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo"})))),
+		},
+		{
+			name:             "grpc invalid ref host default namespace",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo2", kubernetes.K8sActualGRPCRouteType),
+			route:            FromGRPCRoute(data.AddServiceParentRefToGRPCRoute("reviews", "", data.AddBackendRefToGRPCRoute("reviews", "", data.CreateGRPCRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+			invalidPaths:     []string{"spec/parentRefs[1]/name", "spec/rules[0]/backendRefs[0]/name"},
+		},
+		{
+			name: "grpc no valid ref host",
+			registryServices: append(data.CreateFakeRegistryServices("other.bookinfo.svc.cluster.local", "bookinfo", "*"),
+				data.CreateFakeRegistryServices("details.bookinfo.svc.cluster.local", "bookinfo", "*")...),
+			grants:       grant("bookinfo", "bookinfo2", kubernetes.K8sActualGRPCRouteType),
+			route:        FromGRPCRoute(data.AddServiceParentRefToGRPCRoute("ratings", "bookinfo", data.AddBackendRefToGRPCRoute("ratings", "bookinfo", data.AddBackendRefToGRPCRoute("reviews", "bookinfo", data.CreateGRPCRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo2"}))))),
+			invalidPaths: []string{"spec/parentRefs[1]/name", "spec/rules[0]/backendRefs[0]/name", "spec/rules[1]/backendRefs[0]/name"},
+		},
+		{
+			name:             "grpc invalid ref host FQDN",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo2", kubernetes.K8sActualGRPCRouteType),
+			route:            FromGRPCRoute(data.AddServiceParentRefToGRPCRoute("reviews.bookinfo.svc.cluster.local", "", data.AddBackendRefToGRPCRoute("reviews.bookinfo.svc.cluster.local", "", data.CreateGRPCRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo"})))),
+			invalidPaths:     []string{"spec/parentRefs[1]/name", "spec/rules[0]/backendRefs[0]/name"},
+		},
+		{
+			name:             "tcp valid ref host",
+			registryServices: registryServices,
+			route:            FromTCPRoute(data.AddBackendRefToTCPRoute("reviews", "bookinfo", 9080, data.CreateTCPRoute("route", "bookinfo", "gatewayapi"))),
+		},
+		{
+			name:             "tcp missing backend",
+			registryServices: registryServices,
+			route:            FromTCPRoute(data.AddBackendRefToTCPRoute("ratings", "bookinfo", 9080, data.AddBackendRefToTCPRoute("reviews", "bookinfo", 9080, data.CreateTCPRoute("route", "bookinfo", "gatewayapi")))),
+			invalidPaths:     []string{"spec/rules[1]/backendRefs[0]/name"},
+		},
+		{
+			name:             "tcp missing grant",
+			registryServices: registryServices,
+			route:            FromTCPRoute(data.AddBackendRefToTCPRoute("reviews", "bookinfo", 9080, data.CreateTCPRoute("route", "bookinfo2", "gatewayapi"))),
+			invalidPaths:     []string{"spec/rules[0]/backendRefs[0]/name"},
+		},
+		{
+			name:             "tcp granted backend",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo2", kubernetes.K8sActualTCPRouteType),
+			route:            FromTCPRoute(data.AddBackendRefToTCPRoute("reviews", "bookinfo", 9080, data.CreateTCPRoute("route", "bookinfo2", "gatewayapi"))),
+		},
+		{
+			name:             "tls granted backend",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo2", kubernetes.K8sActualTLSRouteType),
+			route:            FromTLSRoute(data.AddBackendRefToTLSRoute("reviews", "bookinfo", 9080, data.CreateTLSRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo.example.com"}))),
+		},
+		{
+			name:             "tls grant for another kind",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo2", kubernetes.K8sActualTCPRouteType),
+			route:            FromTLSRoute(data.AddBackendRefToTLSRoute("reviews", "bookinfo", 9080, data.CreateTLSRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo.example.com"}))),
+			invalidPaths:     []string{"spec/rules[0]/backendRefs[0]/name"},
+		},
+		{
+			name:             "tls valid ref host default namespace",
+			registryServices: registryServices,
+			route:            FromTLSRoute(data.AddBackendRefToTLSRoute("reviews", "", 9080, data.CreateTLSRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo.example.com"}))),
+		},
+		{
+			name:             "tls invalid ref host FQDN",
+			registryServices: registryServices,
+			grants:           grant("bookinfo", "bookinfo2", kubernetes.K8sActualTLSRouteType),
+			route:            FromTLSRoute(data.AddBackendRefToTLSRoute("reviews.bookinfo.svc.cluster.local", "", 9080, data.CreateTLSRoute("route", "bookinfo2", "gatewayapi", []string{"bookinfo.example.com"}))),
+			invalidPaths:     []string{"spec/rules[0]/backendRefs[0]/name"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			vals, valid := NoHostChecker{
+				RegistryServices:   tc.registryServices,
+				K8sReferenceGrants: tc.grants,
+				Route:              tc.route,
+			}.Check()
+
+			assert.Equal(len(tc.invalidPaths) == 0, valid)
+			if !assert.Len(vals, len(tc.invalidPaths)) {
+				return
+			}
+			for i, path := range tc.invalidPaths {
+				assert.Equal(models.ErrorSeverity, vals[i].Severity)
+				assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", vals[i]))
+				assert.Equal(path, vals[i].Path)
+			}
+		})
+	}
+}
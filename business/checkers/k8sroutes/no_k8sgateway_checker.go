@@ -0,0 +1,40 @@
+package k8sroutes
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// NoK8sGatewayChecker validates that the route is pointing to existing K8s Gateways
+type NoK8sGatewayChecker struct {
+	GatewayNames map[string]struct{}
+	Route        Route
+}
+
+func (s NoK8sGatewayChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+
+	for index, ref := range s.Route.ParentRefs {
+		if string(ref.Name) == "" || !IsGatewayRef(ref) {
+			continue
+		}
+		if !s.hasGateway(string(ref.Name), s.Route.ParentNamespace(ref)) {
+			validations = append(validations, buildCheck("k8sroutes.nok8sgateway", fmt.Sprintf("spec/parentRefs[%d]/name/%s", index, string(ref.Name))))
+		}
+	}
+
+	return validations, len(validations) == 0
+}
+
+func (s NoK8sGatewayChecker) hasGateway(name, namespace string) bool {
+	hostname := kubernetes.ParseGatewayAsHost(name, namespace)
+	for gw := range s.GatewayNames {
+		gwHostname := kubernetes.ParseHost(gw, namespace)
+		if kubernetes.FilterByHost(hostname.String(), hostname.Namespace, gw, gwHostname.Namespace) {
+			return true
+		}
+	}
+	return false
+}
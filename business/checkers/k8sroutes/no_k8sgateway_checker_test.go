@@ -0,0 +1,171 @@
+package k8sroutes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func TestMissingK8sGateway(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	checker := NoK8sGatewayChecker{
+		Route:        FromHTTPRoute(data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo"})),
+		GatewayNames: make(map[string]struct{}),
+	}
+
+	vals, valid := checker.Check()
+	assert.False(valid)
+	assert.NotEmpty(vals)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nok8sgateway", vals[0]))
+}
+
+func TestMissingK8sGateways(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	checker := NoK8sGatewayChecker{
+		Route: FromHTTPRoute(data.AddGatewayParentRefToHTTPRoute("gateway2", "bookinfo2",
+			data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo"}))),
+		GatewayNames: make(map[string]struct{}),
+	}
+
+	vals, valid := checker.Check()
+	assert.False(valid)
+	assert.NotEmpty(vals)
+	assert.Len(vals, 2)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nok8sgateway", vals[0]))
+	assert.Equal(models.ErrorSeverity, vals[1].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nok8sgateway", vals[1]))
+}
+
+func TestValidAndMissingK8sGateway(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	var empty struct{}
+
+	checker := NoK8sGatewayChecker{
+		Route: FromHTTPRoute(data.AddGatewayParentRefToHTTPRoute("correctgw", "bookinfo2",
+			data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo"}))),
+		GatewayNames: map[string]struct{}{"correctgw": empty},
+	}
+
+	vals, valid := checker.Check()
+	assert.False(valid)
+	assert.NotEmpty(vals)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nok8sgateway", vals[0]))
+}
+
+func TestFoundK8sGateway(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	checker := NoK8sGatewayChecker{
+		Route: FromHTTPRoute(data.CreateHTTPRoute("route", "bookinfo", "my-gateway", []string{"bookinfo"})),
+		GatewayNames: kubernetes.K8sGatewayNames([]*k8s_networking_v1.Gateway{
+			data.CreateEmptyK8sGateway("my-gateway", "bookinfo"),
+		}),
+	}
+
+	vals, valid := checker.Check()
+	assert.True(valid)
+	assert.Empty(vals)
+}
+
+func TestNoK8sGatewayOtherRouteKinds(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	gatewayNames := kubernetes.K8sGatewayNames([]*k8s_networking_v1.Gateway{
+		data.CreateEmptyK8sGateway("my-gateway", "bookinfo"),
+	})
+
+	cases := []struct {
+		name         string
+		gatewayNames map[string]struct{}
+		route        Route
+		invalidPaths []string
+	}{
+		{
+			name:         "grpc missing gateway",
+			gatewayNames: map[string]struct{}{},
+			route:        FromGRPCRoute(data.CreateGRPCRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo"})),
+			invalidPaths: []string{"spec/parentRefs[0]/name/gatewayapi"},
+		},
+		{
+			name:         "grpc missing gateways",
+			gatewayNames: map[string]struct{}{},
+			route: FromGRPCRoute(data.AddGatewayParentRefToGRPCRoute("gateway2", "bookinfo2",
+				data.CreateGRPCRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo"}))),
+			invalidPaths: []string{"spec/parentRefs[0]/name/gatewayapi", "spec/parentRefs[1]/name/gateway2"},
+		},
+		{
+			name:         "grpc valid and missing gateway",
+			gatewayNames: map[string]struct{}{"correctgw": {}},
+			route: FromGRPCRoute(data.AddGatewayParentRefToGRPCRoute("correctgw", "bookinfo2",
+				data.CreateGRPCRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo"}))),
+			invalidPaths: []string{"spec/parentRefs[0]/name/gatewayapi"},
+		},
+		{
+			name:         "grpc found gateway",
+			gatewayNames: gatewayNames,
+			route:        FromGRPCRoute(data.CreateGRPCRoute("route", "bookinfo", "my-gateway", []string{"bookinfo"})),
+		},
+		{
+			name:         "tcp missing gateway",
+			gatewayNames: gatewayNames,
+			route:        FromTCPRoute(data.CreateTCPRoute("route", "bookinfo", "gatewayapi")),
+			invalidPaths: []string{"spec/parentRefs[0]/name/gatewayapi"},
+		},
+		{
+			name:         "tcp found gateway",
+			gatewayNames: gatewayNames,
+			route:        FromTCPRoute(data.CreateTCPRoute("route", "bookinfo", "my-gateway")),
+		},
+		{
+			name:         "tls missing gateway",
+			gatewayNames: gatewayNames,
+			route:        FromTLSRoute(data.CreateTLSRoute("route", "bookinfo", "gatewayapi", []string{"bookinfo.example.com"})),
+			invalidPaths: []string{"spec/parentRefs[0]/name/gatewayapi"},
+		},
+		{
+			name:         "tls found gateway",
+			gatewayNames: gatewayNames,
+			route:        FromTLSRoute(data.CreateTLSRoute("route", "bookinfo", "my-gateway", []string{"bookinfo.example.com"})),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			vals, valid := NoK8sGatewayChecker{Route: tc.route, GatewayNames: tc.gatewayNames}.Check()
+
+			assert.Equal(len(tc.invalidPaths) == 0, valid)
+			if !assert.Len(vals, len(tc.invalidPaths)) {
+				return
+			}
+			for i, path := range tc.invalidPaths {
+				assert.Equal(models.ErrorSeverity, vals[i].Severity)
+				assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nok8sgateway", vals[i]))
+				assert.Equal(path, vals[i].Path)
+			}
+		})
+	}
+}
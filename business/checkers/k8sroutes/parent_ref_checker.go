@@ -0,0 +1,173 @@
+package k8sroutes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// protocolRouteKinds are the route kinds a listener accepts by default for each protocol
+var protocolRouteKinds = map[k8s_networking_v1.ProtocolType][]string{
+	k8s_networking_v1.HTTPProtocolType:  {kubernetes.K8sActualHTTPRouteType, kubernetes.K8sActualGRPCRouteType},
+	k8s_networking_v1.HTTPSProtocolType: {kubernetes.K8sActualHTTPRouteType, kubernetes.K8sActualGRPCRouteType},
+	k8s_networking_v1.TLSProtocolType:   {kubernetes.K8sActualTLSRouteType, kubernetes.K8sActualTCPRouteType},
+	k8s_networking_v1.TCPProtocolType:   {kubernetes.K8sActualTCPRouteType},
+}
+
+// ParentRefChecker validates that the route can attach to a listener of each K8s Gateway it references:
+// the listener selected by sectionName and port must exist, accept the kind of the route, allow routes
+// from the namespace of the route and share a hostname with it.
+type ParentRefChecker struct {
+	K8sGateways []*k8s_networking_v1.Gateway
+	Namespaces  models.Namespaces
+	Route       Route
+}
+
+func (p ParentRefChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+
+	for i, ref := range p.Route.ParentRefs {
+		if !IsGatewayRef(ref) {
+			continue
+		}
+		gw := p.findGateway(string(ref.Name), p.Route.ParentNamespace(ref))
+		if gw == nil {
+			// Missing gateways are reported by the NoK8sGatewayChecker
+			continue
+		}
+		if check := p.checkListeners(ref, gw, i); check != nil {
+			validations = append(validations, check)
+		}
+	}
+
+	return validations, len(validations) == 0
+}
+
+func (p ParentRefChecker) checkListeners(ref k8s_networking_v1.ParentReference, gw *k8s_networking_v1.Gateway, index int) *models.IstioCheck {
+	listeners := gw.Spec.Listeners
+	if ref.SectionName != nil {
+		listeners = filterListeners(listeners, func(l k8s_networking_v1.Listener) bool { return l.Name == *ref.SectionName })
+		if len(listeners) == 0 {
+			return buildCheck("k8sroutes.parentref.sectionnotfound", fmt.Sprintf("spec/parentRefs[%d]/sectionName", index))
+		}
+	}
+	if ref.Port != nil {
+		listeners = filterListeners(listeners, func(l k8s_networking_v1.Listener) bool { return l.Port == *ref.Port })
+		if len(listeners) == 0 {
+			return buildCheck("k8sroutes.parentref.portnotfound", fmt.Sprintf("spec/parentRefs[%d]/port", index))
+		}
+	}
+	listeners = filterListeners(listeners, p.allowsKind)
+	if len(listeners) == 0 {
+		return buildCheck("k8sroutes.parentref.kindnotallowed", fmt.Sprintf("spec/parentRefs[%d]/name", index))
+	}
+	listeners = filterListeners(listeners, func(l k8s_networking_v1.Listener) bool { return p.allowsNamespace(l, gw.Namespace) })
+	if len(listeners) == 0 {
+		return buildCheck("k8sroutes.parentref.namespacenotallowed", fmt.Sprintf("spec/parentRefs[%d]/name", index))
+	}
+	if p.Route.HasHostnames() && len(p.Route.Hostnames) > 0 {
+		listeners = filterListeners(listeners, p.sharesHostname)
+		if len(listeners) == 0 {
+			return buildCheck("k8sroutes.hostname.nointersection", "spec/hostnames")
+		}
+	}
+	return nil
+}
+
+func (p ParentRefChecker) findGateway(name, namespace string) *k8s_networking_v1.Gateway {
+	for _, gw := range p.K8sGateways {
+		if gw.Name == name && gw.Namespace == namespace {
+			return gw
+		}
+	}
+	return nil
+}
+
+func (p ParentRefChecker) allowsKind(listener k8s_networking_v1.Listener) bool {
+	if listener.AllowedRoutes != nil && len(listener.AllowedRoutes.Kinds) > 0 {
+		for _, kind := range listener.AllowedRoutes.Kinds {
+			if string(kind.Kind) == p.Route.Kind && (kind.Group == nil || string(*kind.Group) == kubernetes.K8sNetworkingGroupVersionV1.Group) {
+				return true
+			}
+		}
+		return false
+	}
+	kinds, found := protocolRouteKinds[listener.Protocol]
+	// Implementation specific protocols can't be validated
+	return !found || slices.Contains(kinds, p.Route.Kind)
+}
+
+func (p ParentRefChecker) allowsNamespace(listener k8s_networking_v1.Listener, gwNamespace string) bool {
+	from := k8s_networking_v1.NamespacesFromSame
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil && listener.AllowedRoutes.Namespaces.From != nil {
+		from = *listener.AllowedRoutes.Namespaces.From
+	}
+
+	switch from {
+	case k8s_networking_v1.NamespacesFromAll:
+		return true
+	case k8s_networking_v1.NamespacesFromSelector:
+		if listener.AllowedRoutes.Namespaces.Selector == nil {
+			return false
+		}
+		selector, err := meta_v1.LabelSelectorAsSelector(listener.AllowedRoutes.Namespaces.Selector)
+		if err != nil {
+			return false
+		}
+		for _, ns := range p.Namespaces {
+			if ns.Name == p.Route.Namespace {
+				return selector.Matches(labels.Set(ns.Labels))
+			}
+		}
+		// The labels of a namespace not accessible by the user are unknown
+		return true
+	default:
+		return p.Route.Namespace == gwNamespace
+	}
+}
+
+func (p ParentRefChecker) sharesHostname(listener k8s_networking_v1.Listener) bool {
+	if listener.Hostname == nil || *listener.Hostname == "" {
+		return true
+	}
+	for _, hostname := range p.Route.Hostnames {
+		if HostnamesIntersect(string(hostname), string(*listener.Hostname)) {
+			return true
+		}
+	}
+	return false
+}
+
+// HostnamesIntersect returns true when both hostnames can match the same request, following the
+// wildcard rules of the Gateway API: a wildcard matches any hostname with the same suffix and more labels.
+func HostnamesIntersect(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if strings.HasPrefix(a, "*.") && strings.HasSuffix(b, a[1:]) {
+		return true
+	}
+	return strings.HasPrefix(b, "*.") && strings.HasSuffix(a, b[1:])
+}
+
+func filterListeners(listeners []k8s_networking_v1.Listener, keep func(k8s_networking_v1.Listener) bool) []k8s_networking_v1.Listener {
+	filtered := []k8s_networking_v1.Listener{}
+	for _, listener := range listeners {
+		if keep(listener) {
+			filtered = append(filtered, listener)
+		}
+	}
+	return filtered
+}
+
+func buildCheck(checkId, path string) *models.IstioCheck {
+	check := models.Build(checkId, path)
+	return &check
+}
@@ -0,0 +1,156 @@
+package k8sroutes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakeGateway(listeners ...k8s_networking_v1.Listener) *k8s_networking_v1.Gateway {
+	gw := data.CreateEmptyK8sGateway("gatewayapi", "bookinfo")
+	for _, listener := range listeners {
+		gw = data.AddListenerToK8sGateway(listener, gw)
+	}
+	return gw
+}
+
+func parentRefChecks(t *testing.T, gw *k8s_networking_v1.Gateway, route Route, namespaces models.Namespaces) []*models.IstioCheck {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	checks, valid := ParentRefChecker{
+		K8sGateways: []*k8s_networking_v1.Gateway{gw},
+		Namespaces:  namespaces,
+		Route:       route,
+	}.Check()
+	assert.Equal(t, len(checks) == 0, valid)
+	return checks
+}
+
+func TestParentRefAttached(t *testing.T) {
+	assert := assert.New(t)
+
+	gw := fakeGateway(data.CreateListener("http", "*.bookinfo.com", 80, "HTTP"))
+	route := FromHTTPRoute(data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"reviews.bookinfo.com"}))
+
+	assert.Empty(parentRefChecks(t, gw, route, nil))
+}
+
+func TestParentRefSectionNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	gw := fakeGateway(data.CreateListener("http", "*.bookinfo.com", 80, "HTTP"))
+	rt := data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"reviews.bookinfo.com"})
+	section := k8s_networking_v1.SectionName("https")
+	rt.Spec.ParentRefs[0].SectionName = &section
+
+	checks := parentRefChecks(t, gw, FromHTTPRoute(rt), nil)
+	assert.Len(checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.parentref.sectionnotfound", checks[0]))
+	assert.Equal("spec/parentRefs[0]/sectionName", checks[0].Path)
+}
+
+func TestParentRefPortNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	gw := fakeGateway(data.CreateListener("http", "*.bookinfo.com", 80, "HTTP"))
+	rt := data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"reviews.bookinfo.com"})
+	port := k8s_networking_v1.PortNumber(8080)
+	rt.Spec.ParentRefs[0].Port = &port
+
+	checks := parentRefChecks(t, gw, FromHTTPRoute(rt), nil)
+	assert.Len(checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.parentref.portnotfound", checks[0]))
+	assert.Equal("spec/parentRefs[0]/port", checks[0].Path)
+}
+
+func TestParentRefKindNotAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	gw := fakeGateway(data.CreateListener("tcp", "", 3306, "TCP"))
+	route := FromHTTPRoute(data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"reviews.bookinfo.com"}))
+
+	checks := parentRefChecks(t, gw, route, nil)
+	assert.Len(checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.parentref.kindnotallowed", checks[0]))
+
+	// The kinds of the listener override the defaults of the protocol
+	listener := data.CreateListener("http", "", 80, "HTTP")
+	listener.AllowedRoutes = &k8s_networking_v1.AllowedRoutes{Kinds: []k8s_networking_v1.RouteGroupKind{{Kind: "GRPCRoute"}}}
+	checks = parentRefChecks(t, fakeGateway(listener), route, nil)
+	assert.Len(checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.parentref.kindnotallowed", checks[0]))
+
+	tcpRoute := FromTCPRoute(data.CreateTCPRoute("route", "bookinfo", "gatewayapi"))
+	assert.Empty(parentRefChecks(t, gw, tcpRoute, nil))
+}
+
+func TestParentRefNamespaceNotAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	route := FromHTTPRoute(data.AddGatewayParentRefToHTTPRoute("gatewayapi", "bookinfo", data.CreateEmptyHTTPRoute("route", "bookinfo2", []string{"reviews.bookinfo.com"})))
+
+	// Only routes from the same namespace are allowed by default
+	checks := parentRefChecks(t, fakeGateway(data.CreateListener("http", "", 80, "HTTP")), route, nil)
+	assert.Len(checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.parentref.namespacenotallowed", checks[0]))
+
+	all := k8s_networking_v1.NamespacesFromAll
+	listener := data.CreateListener("http", "", 80, "HTTP")
+	listener.AllowedRoutes = &k8s_networking_v1.AllowedRoutes{Namespaces: &k8s_networking_v1.RouteNamespaces{From: &all}}
+	assert.Empty(parentRefChecks(t, fakeGateway(listener), route, nil))
+
+	selector := k8s_networking_v1.NamespacesFromSelector
+	listener.AllowedRoutes = &k8s_networking_v1.AllowedRoutes{Namespaces: &k8s_networking_v1.RouteNamespaces{
+		From:     &selector,
+		Selector: &meta_v1.LabelSelector{MatchLabels: map[string]string{"shared-gateway": "true"}},
+	}}
+	namespaces := models.Namespaces{{Name: "bookinfo2", Labels: map[string]string{"shared-gateway": "true"}}}
+	assert.Empty(parentRefChecks(t, fakeGateway(listener), route, namespaces))
+
+	namespaces = models.Namespaces{{Name: "bookinfo2", Labels: map[string]string{"shared-gateway": "false"}}}
+	checks = parentRefChecks(t, fakeGateway(listener), route, namespaces)
+	assert.Len(checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.parentref.namespacenotallowed", checks[0]))
+}
+
+func TestParentRefHostnameNoIntersection(t *testing.T) {
+	assert := assert.New(t)
+
+	gw := fakeGateway(data.CreateListener("http", "*.bookinfo.com", 80, "HTTP"), data.CreateListener("http2", "details.bookinfo.org", 80, "HTTP"))
+	route := FromHTTPRoute(data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"reviews.bookinfo.org"}))
+
+	checks := parentRefChecks(t, gw, route, nil)
+	assert.Len(checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.hostname.nointersection", checks[0]))
+	assert.Equal("spec/hostnames", checks[0].Path)
+
+	route = FromHTTPRoute(data.CreateHTTPRoute("route", "bookinfo", "gatewayapi", []string{"*.bookinfo.org"}))
+	assert.Empty(parentRefChecks(t, gw, route, nil))
+}
+
+func TestHostnamesIntersect(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(HostnamesIntersect("reviews.bookinfo.com", "reviews.bookinfo.com"))
+	assert.True(HostnamesIntersect("*.bookinfo.com", "reviews.bookinfo.com"))
+	assert.True(HostnamesIntersect("reviews.bookinfo.com", "*.bookinfo.com"))
+	assert.True(HostnamesIntersect("*.bookinfo.com", "*.reviews.bookinfo.com"))
+	assert.False(HostnamesIntersect("*.bookinfo.com", "bookinfo.com"))
+	assert.False(HostnamesIntersect("reviews.bookinfo.com", "details.bookinfo.com"))
+}
+
+func TestParentRefSkipsServices(t *testing.T) {
+	assert := assert.New(t)
+
+	rt := data.AddServiceParentRefToHTTPRoute("reviews", "bookinfo", data.CreateEmptyHTTPRoute("route", "bookinfo", []string{"reviews.bookinfo.com"}))
+
+	assert.Empty(parentRefChecks(t, fakeGateway(), FromHTTPRoute(rt), nil))
+}
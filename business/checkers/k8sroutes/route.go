@@ -0,0 +1,213 @@
+package k8sroutes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	k8s_networking_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// Route holds the parts of a Gateway API route which are validated in the same way for all the route kinds
+type Route struct {
+	Kind              string
+	Name              string
+	Namespace         string
+	CreationTimestamp meta_v1.Time
+	ParentRefs        []k8s_networking_v1.ParentReference
+	Hostnames         []k8s_networking_v1.Hostname
+	Rules             []Rule
+}
+
+// Rule holds the backends of a route rule and a key for each of its matches.
+// Two rules with the same match key select the same requests.
+type Rule struct {
+	Matches     []string
+	BackendRefs []k8s_networking_v1.BackendRef
+}
+
+// allRequests is the match key of the rules selecting all the requests of the route
+const allRequests = "*"
+
+func FromHTTPRoute(rt *k8s_networking_v1.HTTPRoute) Route {
+	route := newRoute(kubernetes.K8sActualHTTPRouteType, rt.ObjectMeta, rt.Spec.ParentRefs, rt.Spec.Hostnames)
+	for _, rule := range rt.Spec.Rules {
+		r := Rule{}
+		for _, match := range rule.Matches {
+			r.Matches = append(r.Matches, httpMatchKey(match))
+		}
+		if len(r.Matches) == 0 {
+			// A rule without matches gets a default prefix match on "/"
+			r.Matches = append(r.Matches, httpMatchKey(k8s_networking_v1.HTTPRouteMatch{}))
+		}
+		for _, ref := range rule.BackendRefs {
+			r.BackendRefs = append(r.BackendRefs, ref.BackendRef)
+		}
+		route.Rules = append(route.Rules, r)
+	}
+	return route
+}
+
+func FromGRPCRoute(rt *k8s_networking_v1.GRPCRoute) Route {
+	route := newRoute(kubernetes.K8sActualGRPCRouteType, rt.ObjectMeta, rt.Spec.ParentRefs, rt.Spec.Hostnames)
+	for _, rule := range rt.Spec.Rules {
+		r := Rule{}
+		for _, match := range rule.Matches {
+			r.Matches = append(r.Matches, grpcMatchKey(match))
+		}
+		if len(r.Matches) == 0 {
+			r.Matches = append(r.Matches, allRequests)
+		}
+		for _, ref := range rule.BackendRefs {
+			r.BackendRefs = append(r.BackendRefs, ref.BackendRef)
+		}
+		route.Rules = append(route.Rules, r)
+	}
+	return route
+}
+
+func FromTCPRoute(rt *k8s_networking_v1alpha2.TCPRoute) Route {
+	route := newRoute(kubernetes.K8sActualTCPRouteType, rt.ObjectMeta, rt.Spec.ParentRefs, nil)
+	for _, rule := range rt.Spec.Rules {
+		route.Rules = append(route.Rules, Rule{Matches: []string{allRequests}, BackendRefs: rule.BackendRefs})
+	}
+	return route
+}
+
+func FromTLSRoute(rt *k8s_networking_v1alpha2.TLSRoute) Route {
+	route := newRoute(kubernetes.K8sActualTLSRouteType, rt.ObjectMeta, rt.Spec.ParentRefs, rt.Spec.Hostnames)
+	for _, rule := range rt.Spec.Rules {
+		route.Rules = append(route.Rules, Rule{Matches: []string{allRequests}, BackendRefs: rule.BackendRefs})
+	}
+	return route
+}
+
+func newRoute(kind string, meta meta_v1.ObjectMeta, parentRefs []k8s_networking_v1.ParentReference, hostnames []k8s_networking_v1.Hostname) Route {
+	return Route{
+		Kind:              kind,
+		Name:              meta.Name,
+		Namespace:         meta.Namespace,
+		CreationTimestamp: meta.CreationTimestamp,
+		ParentRefs:        parentRefs,
+		Hostnames:         hostnames,
+	}
+}
+
+// ObjectType returns the object type used in the validations of the route
+func (r Route) ObjectType() string {
+	switch r.Kind {
+	case kubernetes.K8sActualHTTPRouteType:
+		return models.ObjectTypeSingular[kubernetes.K8sHTTPRoutes]
+	case kubernetes.K8sActualGRPCRouteType:
+		return models.ObjectTypeSingular[kubernetes.K8sGRPCRoutes]
+	case kubernetes.K8sActualTCPRouteType:
+		return models.ObjectTypeSingular[kubernetes.K8sTCPRoutes]
+	case kubernetes.K8sActualTLSRouteType:
+		return models.ObjectTypeSingular[kubernetes.K8sTLSRoutes]
+	}
+	return ""
+}
+
+// HasHostnames returns true when the route kind matches requests by hostname
+func (r Route) HasHostnames() bool {
+	return r.Kind != kubernetes.K8sActualTCPRouteType
+}
+
+// IsGatewayRef returns true when the parent reference points to a K8s Gateway, and not to a Service (GAMMA)
+func IsGatewayRef(ref k8s_networking_v1.ParentReference) bool {
+	return (ref.Kind == nil || string(*ref.Kind) == kubernetes.K8sActualGatewayType) &&
+		(ref.Group == nil || string(*ref.Group) == kubernetes.K8sNetworkingGroupVersionV1.Group)
+}
+
+// ParentNamespace returns the namespace of the parent reference, which defaults to the namespace of the route
+func (r Route) ParentNamespace(ref k8s_networking_v1.ParentReference) string {
+	if ref.Namespace != nil && string(*ref.Namespace) != "" {
+		return string(*ref.Namespace)
+	}
+	return r.Namespace
+}
+
+// BackendNamespace returns the namespace of the backend reference, which defaults to the namespace of the route
+func (r Route) BackendNamespace(ref k8s_networking_v1.BackendRef) string {
+	if ref.Namespace != nil && string(*ref.Namespace) != "" {
+		return string(*ref.Namespace)
+	}
+	return r.Namespace
+}
+
+// IsServiceRef returns true when the backend reference points to a Service, the default kind of a backend
+func IsServiceRef(ref k8s_networking_v1.BackendRef) bool {
+	return (ref.Kind == nil || string(*ref.Kind) == kubernetes.ServiceType) &&
+		(ref.Group == nil || string(*ref.Group) == "" || string(*ref.Group) == "core")
+}
+
+func httpMatchKey(match k8s_networking_v1.HTTPRouteMatch) string {
+	pathType, pathValue := string(k8s_networking_v1.PathMatchPathPrefix), "/"
+	if match.Path != nil {
+		if match.Path.Type != nil {
+			pathType = string(*match.Path.Type)
+		}
+		if match.Path.Value != nil {
+			pathValue = *match.Path.Value
+		}
+	}
+	method := ""
+	if match.Method != nil {
+		method = string(*match.Method)
+	}
+
+	headers := make([]string, 0, len(match.Headers))
+	for _, header := range match.Headers {
+		headers = append(headers, fmt.Sprintf("%s:%s=%s", matchType(header.Type), strings.ToLower(string(header.Name)), header.Value))
+	}
+	queryParams := make([]string, 0, len(match.QueryParams))
+	for _, param := range match.QueryParams {
+		queryParams = append(queryParams, fmt.Sprintf("%s:%s=%s", matchType(param.Type), param.Name, param.Value))
+	}
+	sort.Strings(headers)
+	sort.Strings(queryParams)
+
+	return fmt.Sprintf("path=%s:%s method=%s headers=%s query=%s", pathType, pathValue, method, strings.Join(headers, ","), strings.Join(queryParams, ","))
+}
+
+func grpcMatchKey(match k8s_networking_v1.GRPCRouteMatch) string {
+	method := ""
+	if match.Method != nil {
+		methodType := string(k8s_networking_v1.GRPCMethodMatchExact)
+		if match.Method.Type != nil {
+			methodType = string(*match.Method.Type)
+		}
+		service, name := "", ""
+		if match.Method.Service != nil {
+			service = *match.Method.Service
+		}
+		if match.Method.Method != nil {
+			name = *match.Method.Method
+		}
+		method = fmt.Sprintf("%s:%s/%s", methodType, service, name)
+	}
+
+	headers := make([]string, 0, len(match.Headers))
+	for _, header := range match.Headers {
+		headers = append(headers, fmt.Sprintf("%s:%s=%s", matchType(header.Type), strings.ToLower(string(header.Name)), header.Value))
+	}
+	sort.Strings(headers)
+
+	if method == "" && len(headers) == 0 {
+		return allRequests
+	}
+	return fmt.Sprintf("method=%s headers=%s", method, strings.Join(headers, ","))
+}
+
+// matchType returns the type of a header or query param match, Exact when not set
+func matchType[T ~string](t *T) string {
+	if t == nil {
+		return "Exact"
+	}
+	return string(*t)
+}
@@ -0,0 +1,91 @@
+package checkers
+
+import (
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	k8s_networking_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	k8s_networking_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kiali/kiali/business/checkers/k8sroutes"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const K8sTCPRouteCheckerType = "k8stcproute"
+
+type K8sTCPRouteChecker struct {
+	Cluster            string
+	K8sGateways        []*k8s_networking_v1.Gateway
+	K8sTCPRoutes       []*k8s_networking_v1alpha2.TCPRoute
+	K8sReferenceGrants []*k8s_networking_v1beta1.ReferenceGrant
+	Namespaces         models.Namespaces
+	RegistryServices   []*kubernetes.RegistryService
+}
+
+// Check runs checks for the all namespaces actions as well as for the single namespace validations
+func (in K8sTCPRouteChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	validations = validations.MergeValidations(in.runIndividualChecks())
+	validations = validations.MergeValidations(in.runGroupChecks())
+
+	return validations
+}
+
+// Runs individual checks for each TCP Route
+func (in K8sTCPRouteChecker) runIndividualChecks() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	gatewayNames := kubernetes.K8sGatewayNames(in.K8sGateways)
+
+	for _, rt := range in.K8sTCPRoutes {
+		validations.MergeValidations(in.runChecks(rt, gatewayNames))
+	}
+
+	return validations
+}
+
+// Runs checks across all the TCP Routes
+func (in K8sTCPRouteChecker) runGroupChecks() models.IstioValidations {
+	routes := make([]k8sroutes.Route, 0, len(in.K8sTCPRoutes))
+	for _, rt := range in.K8sTCPRoutes {
+		routes = append(routes, k8sroutes.FromTCPRoute(rt))
+	}
+
+	return k8sroutes.ConflictChecker{Cluster: in.Cluster, Routes: routes}.Check()
+}
+
+func (in K8sTCPRouteChecker) runChecks(rt *k8s_networking_v1alpha2.TCPRoute, gatewayNames map[string]struct{}) models.IstioValidations {
+	key, validations := EmptyValidValidation(rt.Name, rt.Namespace, K8sTCPRouteCheckerType, in.Cluster)
+	route := k8sroutes.FromTCPRoute(rt)
+
+	enabledCheckers := []Checker{
+		k8sroutes.NoK8sGatewayChecker{
+			GatewayNames: gatewayNames,
+			Route:        route,
+		},
+		k8sroutes.NoHostChecker{
+			K8sReferenceGrants: in.K8sReferenceGrants,
+			Namespaces:         in.Namespaces,
+			RegistryServices:   in.RegistryServices,
+			Route:              route,
+		},
+		k8sroutes.ParentRefChecker{
+			K8sGateways: in.K8sGateways,
+			Namespaces:  in.Namespaces,
+			Route:       route,
+		},
+		k8sroutes.BackendRefChecker{
+			Namespaces:       in.Namespaces,
+			RegistryServices: in.RegistryServices,
+			Route:            route,
+		},
+	}
+
+	for _, checker := range enabledCheckers {
+		checks, validChecker := checker.Check()
+		validations.Checks = append(validations.Checks, checks...)
+		validations.Valid = validations.Valid && validChecker
+	}
+
+	return models.IstioValidations{key: validations}
+}
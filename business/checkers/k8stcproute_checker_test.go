@@ -0,0 +1,71 @@
+package checkers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	k8s_networking_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func TestValidTCPRoute(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	assert := assert.New(t)
+
+	vals := K8sTCPRouteChecker{
+		K8sTCPRoutes: []*k8s_networking_v1alpha2.TCPRoute{
+			data.AddBackendRefToTCPRoute("mysqldb", "bookinfo", 3306, data.CreateTCPRoute("route", "bookinfo", "gatewayapi")),
+		},
+		K8sGateways:      []*k8s_networking_v1.Gateway{data.AddListenerToK8sGateway(data.CreateListener("tcp", "", 3306, "TCP"), data.CreateEmptyK8sGateway("gatewayapi", "bookinfo"))},
+		RegistryServices: data.CreateFakeRegistryServices("mysqldb.bookinfo.svc.cluster.local", "bookinfo", "*"),
+		Namespaces:       models.Namespaces{models.Namespace{Name: "bookinfo"}},
+	}.Check()
+
+	route := vals[models.IstioValidationKey{ObjectType: "k8stcproute", Namespace: "bookinfo", Name: "route"}]
+	assert.True(route.Valid)
+	assert.Empty(route.Checks)
+}
+
+func TestTCPRouteWithoutK8sGatewayAndService(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	assert := assert.New(t)
+
+	vals := K8sTCPRouteChecker{
+		K8sTCPRoutes: []*k8s_networking_v1alpha2.TCPRoute{
+			data.AddBackendRefToTCPRoute("mysqldb", "bookinfo", 3306, data.CreateTCPRoute("route", "bookinfo", "gatewayapi")),
+		},
+		K8sGateways: []*k8s_networking_v1.Gateway{data.CreateEmptyK8sGateway("gatewayapiwrong", "bookinfo")},
+		Namespaces:  models.Namespaces{models.Namespace{Name: "bookinfo"}},
+	}.Check()
+
+	route := vals[models.IstioValidationKey{ObjectType: "k8stcproute", Namespace: "bookinfo", Name: "route"}]
+	assert.False(route.Valid)
+	assert.Len(route.Checks, 2)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nok8sgateway", route.Checks[0]))
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.nohost.namenotfound", route.Checks[1]))
+}
+
+func TestTCPRouteOnHTTPListener(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	assert := assert.New(t)
+
+	vals := K8sTCPRouteChecker{
+		K8sTCPRoutes: []*k8s_networking_v1alpha2.TCPRoute{
+			data.CreateTCPRoute("route", "bookinfo", "gatewayapi"),
+		},
+		K8sGateways: []*k8s_networking_v1.Gateway{data.AddListenerToK8sGateway(data.CreateListener("http", "", 80, "HTTP"), data.CreateEmptyK8sGateway("gatewayapi", "bookinfo"))},
+	}.Check()
+
+	route := vals[models.IstioValidationKey{ObjectType: "k8stcproute", Namespace: "bookinfo", Name: "route"}]
+	assert.False(route.Valid)
+	assert.Len(route.Checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.parentref.kindnotallowed", route.Checks[0]))
+}
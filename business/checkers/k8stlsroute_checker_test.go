@@ -0,0 +1,60 @@
+package checkers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	k8s_networking_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func TestValidTLSRoute(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	assert := assert.New(t)
+
+	vals := K8sTLSRouteChecker{
+		K8sTLSRoutes: []*k8s_networking_v1alpha2.TLSRoute{
+			data.AddBackendRefToTLSRoute("reviews", "bookinfo", 9443, data.CreateTLSRoute("route", "bookinfo", "gatewayapi", []string{"reviews.bookinfo.com"})),
+		},
+		K8sGateways:      []*k8s_networking_v1.Gateway{data.AddListenerToK8sGateway(data.CreateListener("tls", "*.bookinfo.com", 443, "TLS"), data.CreateEmptyK8sGateway("gatewayapi", "bookinfo"))},
+		RegistryServices: data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*"),
+		Namespaces:       models.Namespaces{models.Namespace{Name: "bookinfo"}},
+	}.Check()
+
+	route := vals[models.IstioValidationKey{ObjectType: "k8stlsroute", Namespace: "bookinfo", Name: "route"}]
+	assert.True(route.Valid)
+	assert.Empty(route.Checks)
+}
+
+func TestTLSRouteHostnameConflict(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	assert := assert.New(t)
+
+	route1 := data.AddBackendRefToTLSRoute("reviews", "bookinfo", 9443, data.CreateTLSRoute("route1", "bookinfo", "gatewayapi", []string{"reviews.bookinfo.com"}))
+	route2 := data.AddBackendRefToTLSRoute("reviews", "bookinfo", 9443, data.CreateTLSRoute("route2", "bookinfo", "gatewayapi", []string{"reviews.bookinfo.com"}))
+
+	vals := K8sTLSRouteChecker{
+		K8sTLSRoutes:     []*k8s_networking_v1alpha2.TLSRoute{route1, route2},
+		K8sGateways:      []*k8s_networking_v1.Gateway{data.AddListenerToK8sGateway(data.CreateListener("tls", "*.bookinfo.org", 443, "TLS"), data.CreateEmptyK8sGateway("gatewayapi", "bookinfo"))},
+		RegistryServices: data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*"),
+		Namespaces:       models.Namespaces{models.Namespace{Name: "bookinfo"}},
+	}.Check()
+
+	first := vals[models.IstioValidationKey{ObjectType: "k8stlsroute", Namespace: "bookinfo", Name: "route1"}]
+	assert.False(first.Valid)
+	assert.Len(first.Checks, 1)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.hostname.nointersection", first.Checks[0]))
+
+	second := vals[models.IstioValidationKey{ObjectType: "k8stlsroute", Namespace: "bookinfo", Name: "route2"}]
+	assert.False(second.Valid)
+	assert.Len(second.Checks, 2)
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.hostname.nointersection", second.Checks[0]))
+	assert.NoError(validations.ConfirmIstioCheckMessage("k8sroutes.rule.conflict", second.Checks[1]))
+}
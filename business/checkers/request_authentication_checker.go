@@ -4,6 +4,7 @@ import (
 	security_v1 "istio.io/client-go/pkg/apis/security/v1"
 
 	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/requestauthentications"
 	"github.com/kiali/kiali/models"
 )
 
@@ -19,6 +20,7 @@ func (m RequestAuthenticationChecker) Check() models.IstioValidations {
 	validations := models.IstioValidations{}
 
 	validations.MergeValidations(common.RequestAuthenticationMultiMatchChecker(m.Cluster, RequestAuthenticationCheckerType, m.RequestAuthentications, m.WorkloadsPerNamespace).Check())
+	validations.MergeValidations(requestauthentications.DuplicateIssuerChecker{Cluster: m.Cluster, RequestAuthentications: m.RequestAuthentications}.Check())
 
 	for _, peerAuthn := range m.RequestAuthentications {
 		validations.MergeValidations(m.runChecks(peerAuthn))
@@ -37,6 +39,7 @@ func (m RequestAuthenticationChecker) runChecks(requestAuthn *security_v1.Reques
 	}
 	enabledCheckers := []Checker{
 		common.SelectorNoWorkloadFoundChecker(RequestAuthenticationCheckerType, matchLabels, m.WorkloadsPerNamespace),
+		requestauthentications.JwtRulesChecker{RequestAuthentication: requestAuthn},
 	}
 
 	for _, checker := range enabledCheckers {
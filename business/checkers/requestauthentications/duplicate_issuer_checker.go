@@ -0,0 +1,80 @@
+package requestauthentications
+
+import (
+	"fmt"
+	"slices"
+
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/models"
+)
+
+const RequestAuthenticationCheckerType = "requestauthentication"
+
+// DuplicateIssuerChecker validates that an issuer is defined once for the same workloads, by the JWT rules of the
+// RequestAuthentications of a namespace with the same selector
+type DuplicateIssuerChecker struct {
+	Cluster                string
+	RequestAuthentications []*security_v1.RequestAuthentication
+}
+
+type jwtRule struct {
+	key   models.IstioValidationKey
+	index int
+}
+
+func (dc DuplicateIssuerChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	// rules per namespace and selector, then per issuer
+	seen := map[string]map[string][]jwtRule{}
+	order := [][2]string{}
+	for _, ra := range dc.RequestAuthentications {
+		selector := ""
+		if ra.Spec.Selector != nil {
+			selector = labels.Set(ra.Spec.Selector.MatchLabels).String()
+		}
+		workloads := ra.Namespace + "/" + selector
+		if _, found := seen[workloads]; !found {
+			seen[workloads] = map[string][]jwtRule{}
+		}
+		key := models.IstioValidationKey{ObjectType: RequestAuthenticationCheckerType, Name: ra.Name, Namespace: ra.Namespace, Cluster: dc.Cluster}
+		for i, rule := range ra.Spec.JwtRules {
+			if rule == nil || rule.Issuer == "" {
+				continue
+			}
+			if _, found := seen[workloads][rule.Issuer]; !found {
+				order = append(order, [2]string{workloads, rule.Issuer})
+			}
+			seen[workloads][rule.Issuer] = append(seen[workloads][rule.Issuer], jwtRule{key: key, index: i})
+		}
+	}
+
+	for _, o := range order {
+		rules := seen[o[0]][o[1]]
+		if len(rules) < 2 {
+			continue
+		}
+		for _, rule := range rules {
+			check := models.Build("requestauthentication.issuer.duplicate", fmt.Sprintf("spec/jwtRules[%d]/issuer", rule.index))
+			references := make([]models.IstioValidationKey, 0, len(rules)-1)
+			for _, other := range rules {
+				if other.key != rule.key && !slices.Contains(references, other.key) {
+					references = append(references, other.key)
+				}
+			}
+			validations.MergeValidations(models.IstioValidations{rule.key: &models.IstioValidation{
+				Cluster:    dc.Cluster,
+				Name:       rule.key.Name,
+				Namespace:  rule.key.Namespace,
+				ObjectType: RequestAuthenticationCheckerType,
+				Valid:      true,
+				Checks:     []*models.IstioCheck{&check},
+				References: references,
+			}})
+		}
+	}
+
+	return validations
+}
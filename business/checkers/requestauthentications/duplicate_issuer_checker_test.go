@@ -0,0 +1,65 @@
+package requestauthentications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api_security_v1 "istio.io/api/security/v1"
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func TestDuplicateIssuerSameSelector(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals := DuplicateIssuerChecker{
+		Cluster: config.DefaultClusterID,
+		RequestAuthentications: []*security_v1.RequestAuthentication{
+			fakeRequestAuthentication("jwt-1", map[string]string{"app": "reviews"}, &api_security_v1.JWTRule{Issuer: "https://accounts.example.com"}),
+			fakeRequestAuthentication("jwt-2", map[string]string{"app": "reviews"},
+				&api_security_v1.JWTRule{Issuer: "testing@secure.istio.io"},
+				&api_security_v1.JWTRule{Issuer: "https://accounts.example.com"}),
+			// other workloads
+			fakeRequestAuthentication("jwt-3", map[string]string{"app": "ratings"}, &api_security_v1.JWTRule{Issuer: "https://accounts.example.com"}),
+			fakeRequestAuthentication("jwt-4", nil, &api_security_v1.JWTRule{Issuer: "https://accounts.example.com"}),
+		},
+	}.Check()
+
+	assert.Len(vals, 2)
+	validation, ok := vals[models.BuildKey(RequestAuthenticationCheckerType, "jwt-1", "bookinfo", config.DefaultClusterID)]
+	assert.True(ok)
+	assert.True(validation.Valid)
+	assert.Len(validation.Checks, 1)
+	assert.Equal(models.WarningSeverity, validation.Checks[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("requestauthentication.issuer.duplicate", validation.Checks[0]))
+	assert.Equal("spec/jwtRules[0]/issuer", validation.Checks[0].Path)
+	assert.Equal([]models.IstioValidationKey{models.BuildKey(RequestAuthenticationCheckerType, "jwt-2", "bookinfo", config.DefaultClusterID)}, validation.References)
+
+	validation, ok = vals[models.BuildKey(RequestAuthenticationCheckerType, "jwt-2", "bookinfo", config.DefaultClusterID)]
+	assert.True(ok)
+	assert.Len(validation.Checks, 1)
+	assert.Equal("spec/jwtRules[1]/issuer", validation.Checks[0].Path)
+}
+
+func TestDuplicateIssuerSameObject(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals := DuplicateIssuerChecker{
+		Cluster: config.DefaultClusterID,
+		RequestAuthentications: []*security_v1.RequestAuthentication{
+			fakeRequestAuthentication("jwt", nil,
+				&api_security_v1.JWTRule{Issuer: "https://accounts.example.com"},
+				&api_security_v1.JWTRule{Issuer: "https://accounts.example.com"}),
+		},
+	}.Check()
+
+	validation, ok := vals[models.BuildKey(RequestAuthenticationCheckerType, "jwt", "bookinfo", config.DefaultClusterID)]
+	assert.True(ok)
+	assert.Len(validation.Checks, 2)
+	assert.Empty(validation.References)
+}
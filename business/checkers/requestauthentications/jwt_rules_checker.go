@@ -0,0 +1,46 @@
+package requestauthentications
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+
+	"github.com/kiali/kiali/models"
+)
+
+// JwtRulesChecker validates the issuers and JWKS URIs of the JWT rules
+type JwtRulesChecker struct {
+	RequestAuthentication *security_v1.RequestAuthentication
+}
+
+func (jc JwtRulesChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	for i, rule := range jc.RequestAuthentication.Spec.JwtRules {
+		if rule == nil {
+			continue
+		}
+		if rule.Issuer == "" || strings.ContainsAny(rule.Issuer, " \t\r\n") {
+			check := models.Build("requestauthentication.issuer.invalid", fmt.Sprintf("spec/jwtRules[%d]/issuer", i))
+			checks = append(checks, &check)
+			valid = false
+		}
+		if rule.JwksUri != "" && !isValidJwksUri(rule.JwksUri) {
+			check := models.Build("requestauthentication.jwksuri.invalid", fmt.Sprintf("spec/jwtRules[%d]/jwksUri", i))
+			checks = append(checks, &check)
+			valid = false
+		}
+	}
+
+	return checks, valid
+}
+
+func isValidJwksUri(jwksUri string) bool {
+	u, err := url.Parse(jwksUri)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Hostname() != ""
+}
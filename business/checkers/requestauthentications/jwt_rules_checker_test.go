@@ -0,0 +1,61 @@
+package requestauthentications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api_security_v1 "istio.io/api/security/v1"
+	api_v1beta1 "istio.io/api/type/v1beta1"
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakeRequestAuthentication(name string, selector map[string]string, rules ...*api_security_v1.JWTRule) *security_v1.RequestAuthentication {
+	ra := &security_v1.RequestAuthentication{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo"}}
+	if selector != nil {
+		ra.Spec.Selector = &api_v1beta1.WorkloadSelector{MatchLabels: selector}
+	}
+	ra.Spec.JwtRules = rules
+	return ra
+}
+
+func TestValidJwtRules(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := JwtRulesChecker{
+		RequestAuthentication: fakeRequestAuthentication("jwt", nil,
+			&api_security_v1.JWTRule{Issuer: "https://accounts.example.com", JwksUri: "https://accounts.example.com/.well-known/jwks.json"},
+			&api_security_v1.JWTRule{Issuer: "testing@secure.istio.io", Jwks: `{"keys":[]}`}),
+	}.Check()
+
+	assert.Empty(vals)
+	assert.True(valid)
+}
+
+func TestInvalidJwtRules(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := JwtRulesChecker{
+		RequestAuthentication: fakeRequestAuthentication("jwt", nil,
+			&api_security_v1.JWTRule{Issuer: "", JwksUri: "accounts.example.com/jwks.json"},
+			&api_security_v1.JWTRule{Issuer: "https://accounts.example.com ", JwksUri: "ftp://accounts.example.com/jwks.json"},
+			&api_security_v1.JWTRule{Issuer: "https://other.example.com", JwksUri: "https:///jwks.json"}),
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(vals, 5)
+	assert.Equal(models.ErrorSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("requestauthentication.issuer.invalid", vals[0]))
+	assert.Equal("spec/jwtRules[0]/issuer", vals[0].Path)
+	assert.NoError(validations.ConfirmIstioCheckMessage("requestauthentication.jwksuri.invalid", vals[1]))
+	assert.Equal("spec/jwtRules[0]/jwksUri", vals[1].Path)
+	assert.NoError(validations.ConfirmIstioCheckMessage("requestauthentication.issuer.invalid", vals[2]))
+	assert.NoError(validations.ConfirmIstioCheckMessage("requestauthentication.jwksuri.invalid", vals[3]))
+	assert.Equal("spec/jwtRules[2]/jwksUri", vals[4].Path)
+}
@@ -7,7 +7,9 @@ import (
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/kiali/kiali/config"
@@ -37,6 +39,7 @@ func (p PortMappingChecker) Check() ([]*models.IstioCheck, bool) {
 				}
 			} else if !kubernetes.MatchPortNameWithValidProtocols(sp.Name) {
 				validation := models.Build("port.name.mismatch", fmt.Sprintf("spec/ports[%d]", portIndex))
+				p.addPortNameFix(&validation, portIndex)
 				validations = append(validations, &validation)
 			}
 		}
@@ -59,6 +62,53 @@ func (p PortMappingChecker) Check() ([]*models.IstioCheck, bool) {
 	return validations, len(validations) == 0
 }
 
+// namedProtocols are the protocols guessed from a port name, the prefixed ones first
+var namedProtocols = []string{"grpc-web", "grpc", "http2", "https", "http", "mongo", "mysql", "redis", "tls", "tcp"}
+
+// portNumberProtocols are the protocols guessed from a well known port number
+var portNumberProtocols = map[int32]string{80: "http", 443: "https", 3306: "mysql", 6379: "redis", 8080: "http", 8443: "https", 27017: "mongo"}
+
+// addPortNameFix suggests renaming the port to the <protocol>[-suffix] form. The protocol is guessed from the name or
+// the port number, it is tcp otherwise. A protocol found in the name is moved to the prefix, not repeated.
+func (p PortMappingChecker) addPortNameFix(check *models.IstioCheck, portIndex int) {
+	sp := p.Service.Spec.Ports[portIndex]
+	protocol := "tcp"
+	if numberProtocol, ok := portNumberProtocols[sp.Port]; ok {
+		protocol = numberProtocol
+	}
+	suffix := sp.Name
+	for _, namedProtocol := range namedProtocols {
+		if i := strings.Index(strings.ToLower(sp.Name), namedProtocol); i >= 0 {
+			protocol = namedProtocol
+			// the protocol moves to the prefix, keep only the rest of the old name
+			before, after := strings.Trim(sp.Name[:i], "-_."), strings.Trim(sp.Name[i+len(namedProtocol):], "-_.")
+			suffix = strings.Trim(before+"-"+after, "-")
+			break
+		}
+	}
+	name := protocol
+	if suffix != "" {
+		name = protocol + "-" + suffix
+	}
+
+	serviceObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&p.Service)
+	if err != nil {
+		return
+	}
+	// a merge patch replaces the whole list
+	ports, _, _ := unstructured.NestedSlice(serviceObject, "spec", "ports")
+	if portIndex >= len(ports) {
+		return
+	}
+	port, ok := ports[portIndex].(map[string]interface{})
+	if !ok {
+		return
+	}
+	port["name"] = name
+	patch := map[string]interface{}{"spec": map[string]interface{}{"ports": ports}}
+	check.AddFix(fmt.Sprintf("Rename port %d to %s", sp.Port, name), kubernetes.Services, p.Service.Namespace, p.Service.Name, patch)
+}
+
 func (p PortMappingChecker) hasMatchingPodsWithSidecar(service v1.Service) bool {
 	sPods := models.Pods{}
 	sPods.Parse(kubernetes.FilterPodsByService(&service, p.Pods))
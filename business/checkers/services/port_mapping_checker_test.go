@@ -1,9 +1,11 @@
 package services
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	apps_v1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -138,6 +140,37 @@ func TestServicePortNaming(t *testing.T) {
 	assert.NotEmpty(vals)
 	assert.NoError(validations.ConfirmIstioCheckMessage("port.name.mismatch", vals[0]))
 	assert.Equal("spec/ports[0]", vals[0].Path)
+
+	require.Len(t, vals[0].Fixes, 1)
+	fix := vals[0].Fixes[0]
+	assert.Equal("Rename port 9080 to http2-foo", fix.Description)
+	assert.Equal("services", fix.ObjectType)
+	assert.Equal("test-namespace", fix.Namespace)
+	assert.Equal("service1", fix.Name)
+	patch := v1.Service{}
+	require.NoError(t, json.Unmarshal([]byte(fix.Patch), &patch))
+	require.Len(t, patch.Spec.Ports, 1)
+	assert.Equal("http2-foo", patch.Spec.Ports[0].Name)
+	assert.Equal(int32(9080), patch.Spec.Ports[0].Port)
+}
+
+func TestServicePortNamingFixProtocol(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	for name, expected := range map[string]string{"": "http", "web": "http-web", "grpcapi": "grpc-api", "api_HTTP2": "http2-api", "my-mongo-db": "mongo-my-db"} {
+		pmc := PortMappingChecker{
+			Service:     getService(8080, name, nil, "test-namespace", "app", "labelName1"),
+			Deployments: getDeployment(8080),
+			Pods:        getPods(true),
+		}
+		vals, _ := pmc.Check()
+		require.Len(t, vals, 1)
+		require.Len(t, vals[0].Fixes, 1)
+		assert.Equal("Rename port 8080 to "+expected, vals[0].Fixes[0].Description)
+	}
 }
 
 func TestServicePortNamingIstioSystem(t *testing.T) {
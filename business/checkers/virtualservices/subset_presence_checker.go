@@ -4,7 +4,10 @@ import (
 	"fmt"
 
 	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
 )
@@ -38,6 +41,7 @@ func (checker SubsetPresenceChecker) Check() ([]*models.IstioCheck, bool) {
 			if !checker.subsetPresent(host, subset) {
 				path := fmt.Sprintf("spec/http[%d]/route[%d]/destination", routeIdx, destWeightIdx)
 				validation := models.Build("virtualservices.subsetpresent.subsetnotfound", path)
+				checker.addFixes(&validation, "http", routeIdx, destWeightIdx, host, subset)
 				validations = append(validations, &validation)
 			}
 		}
@@ -62,6 +66,7 @@ func (checker SubsetPresenceChecker) Check() ([]*models.IstioCheck, bool) {
 			if !checker.subsetPresent(host, subset) {
 				path := fmt.Sprintf("spec/tcp[%d]/route[%d]/destination", routeIdx, destWeightIdx)
 				validation := models.Build("virtualservices.subsetpresent.subsetnotfound", path)
+				checker.addFixes(&validation, "tcp", routeIdx, destWeightIdx, host, subset)
 				validations = append(validations, &validation)
 			}
 		}
@@ -87,6 +92,7 @@ func (checker SubsetPresenceChecker) Check() ([]*models.IstioCheck, bool) {
 			if !checker.subsetPresent(host, subset) {
 				path := fmt.Sprintf("spec/tls[%d]/route[%d]/destination", routeIdx, destWeightIdx)
 				validation := models.Build("virtualservices.subsetpresent.subsetnotfound", path)
+				checker.addFixes(&validation, "tls", routeIdx, destWeightIdx, host, subset)
 				validations = append(validations, &validation)
 			}
 		}
@@ -94,6 +100,48 @@ func (checker SubsetPresenceChecker) Check() ([]*models.IstioCheck, bool) {
 	return validations, valid
 }
 
+// addFixes suggests adding the subset to the DestinationRule of the host, or removing it from the route destination
+func (checker SubsetPresenceChecker) addFixes(check *models.IstioCheck, routes string, routeIdx, destWeightIdx int, host, subset string) {
+	if destinationRules, ok := checker.getDestinationRules(host); ok {
+		dr := destinationRules[0]
+		if drObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dr); err == nil {
+			// a merge patch replaces the whole list
+			subsets, _, _ := unstructured.NestedSlice(drObject, "spec", "subsets")
+			subsets = append(subsets, map[string]interface{}{
+				"name":   subset,
+				"labels": map[string]interface{}{config.Get().IstioLabels.VersionLabelName: subset},
+			})
+			patch := map[string]interface{}{"spec": map[string]interface{}{"subsets": subsets}}
+			check.AddFix(fmt.Sprintf("Add subset %s to DestinationRule %s", subset, dr.Name), kubernetes.DestinationRules, dr.Namespace, dr.Name, patch)
+		}
+	}
+
+	vsObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(checker.VirtualService)
+	if err != nil {
+		return
+	}
+	routeList, _, _ := unstructured.NestedSlice(vsObject, "spec", routes)
+	if routeIdx >= len(routeList) {
+		return
+	}
+	route, ok := routeList[routeIdx].(map[string]interface{})
+	if !ok {
+		return
+	}
+	destinations, _, _ := unstructured.NestedSlice(route, "route")
+	if destWeightIdx >= len(destinations) {
+		return
+	}
+	destination, ok := destinations[destWeightIdx].(map[string]interface{})
+	if !ok {
+		return
+	}
+	unstructured.RemoveNestedField(destination, "destination", "subset")
+	route["route"] = destinations
+	patch := map[string]interface{}{"spec": map[string]interface{}{routes: routeList}}
+	check.AddFix(fmt.Sprintf("Remove subset %s from the route destination", subset), kubernetes.VirtualServices, checker.VirtualService.Namespace, checker.VirtualService.Name, patch)
+}
+
 func (checker SubsetPresenceChecker) subsetPresent(host string, subset string) bool {
 	destinationRules, ok := checker.getDestinationRules(host)
 	if !ok || destinationRules == nil || len(destinationRules) == 0 {
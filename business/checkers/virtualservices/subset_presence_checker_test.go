@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	core_v1 "k8s.io/api/core/v1"
 
 	"github.com/kiali/kiali/config"
@@ -52,6 +54,25 @@ func TestSubsetsNotFound(t *testing.T) {
 	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-1.yaml", t)
 }
 
+func TestSubsetsNotFoundFixes(t *testing.T) {
+	assert := assert.New(t)
+
+	vals, _ := subsetPresenceCheckerPrep("subset-presence-no-matching-subsets-1.yaml", t)
+	require.Len(t, vals, 2)
+	fixes := vals[1].Fixes
+	require.Len(t, fixes, 2)
+
+	assert.Equal("Add subset not-v2 to DestinationRule testrule", fixes[0].Description)
+	assert.Equal("destinationrules", fixes[0].ObjectType)
+	assert.Equal("bookinfo", fixes[0].Namespace)
+	assert.Equal("testrule", fixes[0].Name)
+	assert.JSONEq(`{"spec":{"subsets":[{"name":"v1","labels":{"version":"v1"}},{"name":"v2","labels":{"version":"v2"}},{"name":"not-v2","labels":{"version":"not-v2"}}]}}`, fixes[0].Patch)
+
+	assert.Equal("virtualservices", fixes[1].ObjectType)
+	assert.Equal("reviews-vs", fixes[1].Name)
+	assert.JSONEq(`{"spec":{"http":[{"route":[{"destination":{"host":"reviews.bookinfo.svc.cluster.local","subset":"not-v1"},"weight":55}]},{"route":[{"destination":{"host":"reviews.bookinfo.svc.cluster.local"},"weight":45}]}]}}`, fixes[1].Patch)
+}
+
 func TestSubsetsNotFoundSVCNS(t *testing.T) {
 	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-2.yaml", t)
 }
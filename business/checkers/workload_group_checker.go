@@ -0,0 +1,43 @@
+package checkers
+
+import (
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+
+	"github.com/kiali/kiali/business/checkers/workloadgroups"
+	"github.com/kiali/kiali/models"
+)
+
+const WorkloadGroupCheckerType = "workloadgroup"
+
+type WorkloadGroupChecker struct {
+	Cluster        string
+	ServiceEntries []*networking_v1.ServiceEntry
+	WorkloadGroups []*networking_v1.WorkloadGroup
+}
+
+func (in WorkloadGroupChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, workloadGroup := range in.WorkloadGroups {
+		validations.MergeValidations(in.runChecks(workloadGroup))
+	}
+
+	return validations
+}
+
+// runChecks runs all the individual checks for a single workload group and appends the result into validations.
+func (in WorkloadGroupChecker) runChecks(workloadGroup *networking_v1.WorkloadGroup) models.IstioValidations {
+	key, rrValidation := EmptyValidValidation(workloadGroup.Name, workloadGroup.Namespace, WorkloadGroupCheckerType, in.Cluster)
+
+	enabledCheckers := []Checker{
+		workloadgroups.ServiceEntrySelectorChecker{WorkloadGroup: workloadGroup, ServiceEntries: in.ServiceEntries},
+	}
+
+	for _, checker := range enabledCheckers {
+		checks, validChecker := checker.Check()
+		rrValidation.Checks = append(rrValidation.Checks, checks...)
+		rrValidation.Valid = rrValidation.Valid && validChecker
+	}
+
+	return models.IstioValidations{key: rrValidation}
+}
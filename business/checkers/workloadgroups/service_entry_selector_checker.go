@@ -0,0 +1,37 @@
+package workloadgroups
+
+import (
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/models"
+)
+
+// ServiceEntrySelectorChecker validates that a ServiceEntry of the namespace selects the workloads of the template
+type ServiceEntrySelectorChecker struct {
+	WorkloadGroup  *networking_v1.WorkloadGroup
+	ServiceEntries []*networking_v1.ServiceEntry
+}
+
+func (sc ServiceEntrySelectorChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	templateLabels := map[string]string{}
+	if sc.WorkloadGroup.Spec.Metadata != nil {
+		templateLabels = sc.WorkloadGroup.Spec.Metadata.Labels
+	}
+
+	for _, se := range sc.ServiceEntries {
+		// ServiceEntries only select the WorkloadEntries of their own namespace
+		if se.Namespace != sc.WorkloadGroup.Namespace || se.Spec.WorkloadSelector == nil || len(se.Spec.WorkloadSelector.Labels) == 0 {
+			continue
+		}
+		if common.SelectorMatchesLabels(se.Spec.WorkloadSelector.Labels, templateLabels) {
+			return checks, true
+		}
+	}
+
+	check := models.Build("workloadgroup.template.noserviceentry", "spec/metadata/labels")
+	checks = append(checks, &check)
+	return checks, true
+}
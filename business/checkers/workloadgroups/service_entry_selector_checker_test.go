@@ -0,0 +1,63 @@
+package workloadgroups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/testutils/validations"
+)
+
+func fakeWorkloadGroup(labels map[string]string) *networking_v1.WorkloadGroup {
+	wg := &networking_v1.WorkloadGroup{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings-vm", Namespace: "bookinfo"}}
+	wg.Spec.Metadata = &api_networking_v1.WorkloadGroup_ObjectMeta{Labels: labels}
+	return wg
+}
+
+func fakeSelectorServiceEntry(namespace string, selector map[string]string) *networking_v1.ServiceEntry {
+	se := data.CreateEmptyMeshInternalServiceEntry("ratings-vm", namespace, []string{"ratings-vm.bookinfo.svc.cluster.local"})
+	se.Spec.WorkloadSelector = &api_networking_v1.WorkloadSelector{Labels: selector}
+	return se
+}
+
+func TestWorkloadGroupSelectedByServiceEntry(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := ServiceEntrySelectorChecker{
+		WorkloadGroup: fakeWorkloadGroup(map[string]string{"app": "ratings-vm", "class": "vm"}),
+		ServiceEntries: []*networking_v1.ServiceEntry{
+			fakeSelectorServiceEntry("bookinfo", map[string]string{"app": "ratings-vm"}),
+		},
+	}.Check()
+
+	assert.Empty(vals)
+	assert.True(valid)
+}
+
+func TestWorkloadGroupNotSelected(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vals, valid := ServiceEntrySelectorChecker{
+		WorkloadGroup: fakeWorkloadGroup(map[string]string{"app": "ratings-vm"}),
+		ServiceEntries: []*networking_v1.ServiceEntry{
+			// another namespace
+			fakeSelectorServiceEntry("default", map[string]string{"app": "ratings-vm"}),
+			fakeSelectorServiceEntry("bookinfo", map[string]string{"app": "ratings-vm", "class": "vm"}),
+			data.CreateEmptyMeshExternalServiceEntry("external", "bookinfo", []string{"www.example.com"}),
+		},
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(vals, 1)
+	assert.Equal(models.WarningSeverity, vals[0].Severity)
+	assert.NoError(validations.ConfirmIstioCheckMessage("workloadgroup.template.noserviceentry", vals[0]))
+	assert.Equal("spec/metadata/labels", vals[0].Path)
+}
@@ -0,0 +1,136 @@
+package confighistory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	core_v1 "k8s.io/api/core/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	configMapPrefix       = "kiali-istio-config-history-"
+	configMapRevisionsKey = "revisions"
+	configMapHistoryLabel = "kiali.io/istio-config-history"
+	configMapObjectPrefix = "kiali.io/istio-config-history-"
+	maxConfigMapDataSize  = 900 * 1024 // below the 1MiB limit of a ConfigMap, leaving room for its metadata
+)
+
+// ConfigMapStore stores the revisions of each object in its own ConfigMap. The ConfigMaps are named after a hash of
+// the object, which is set in their annotations.
+type ConfigMapStore struct {
+	client       kubernetes.ClientInterface
+	maxRevisions int
+	namespace    string
+}
+
+// NewConfigMapStore returns a ConfigMapStore creating its ConfigMaps in the provided namespace
+func NewConfigMapStore(client kubernetes.ClientInterface, namespace string, maxRevisions int) *ConfigMapStore {
+	return &ConfigMapStore{client: client, maxRevisions: maxRevisions, namespace: namespace}
+}
+
+// ConfigMapName returns the name of the ConfigMap holding the revisions of the object
+func ConfigMapName(key ObjectKey) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s/%s", key.Cluster, key.Namespace, key.ObjectType, key.Name)))
+	return configMapPrefix + hex.EncodeToString(hash[:])[:16]
+}
+
+func (s *ConfigMapStore) read(ctx context.Context, key ObjectKey) (*core_v1.ConfigMap, []models.IstioConfigRevision, error) {
+	cm, err := s.client.Kube().CoreV1().ConfigMaps(s.namespace).Get(ctx, ConfigMapName(key), meta_v1.GetOptions{})
+	if err != nil {
+		if api_errors.IsNotFound(err) {
+			return nil, []models.IstioConfigRevision{}, nil
+		}
+		return nil, nil, err
+	}
+	revisions := []models.IstioConfigRevision{}
+	if data := cm.Data[configMapRevisionsKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &revisions); err != nil {
+			return nil, nil, fmt.Errorf("unable to decode Istio config history [%s/%s]: %w", s.namespace, cm.Name, err)
+		}
+	}
+	return cm, revisions, nil
+}
+
+// Get implements Store
+func (s *ConfigMapStore) Get(ctx context.Context, key ObjectKey, id string) (*models.IstioConfigRevision, error) {
+	revisions, err := s.List(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return find(revisions, id)
+}
+
+// List implements Store
+func (s *ConfigMapStore) List(ctx context.Context, key ObjectKey) ([]models.IstioConfigRevision, error) {
+	_, revisions, err := s.read(ctx, key)
+	return revisions, err
+}
+
+// Record implements Store. The ConfigMap is updated with optimistic locking, concurrent changes are retried.
+// The oldest revisions are also removed when the ConfigMap would be too large.
+func (s *ConfigMapStore) Record(ctx context.Context, revision models.IstioConfigRevision) error {
+	key := Key(revision)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, revisions, err := s.read(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		revisions = prepend(revisions, revision, s.maxRevisions)
+		data, err := json.Marshal(revisions)
+		if err != nil {
+			return err
+		}
+		for len(data) > maxConfigMapDataSize && len(revisions) > 1 {
+			revisions = revisions[:len(revisions)-1]
+			if data, err = json.Marshal(revisions); err != nil {
+				return err
+			}
+		}
+		if len(data) > maxConfigMapDataSize {
+			return fmt.Errorf("Istio config revision of [%s/%s/%s] is too large to be recorded", key.Namespace, key.ObjectType, key.Name)
+		}
+
+		if cm == nil {
+			cm = &core_v1.ConfigMap{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      ConfigMapName(key),
+					Namespace: s.namespace,
+					Labels: map[string]string{
+						"app.kubernetes.io/part-of": "kiali",
+						configMapHistoryLabel:       "true",
+					},
+					Annotations: map[string]string{
+						configMapObjectPrefix + "cluster":    key.Cluster,
+						configMapObjectPrefix + "namespace":  key.Namespace,
+						configMapObjectPrefix + "objecttype": key.ObjectType,
+						configMapObjectPrefix + "name":       key.Name,
+					},
+				},
+				Data: map[string]string{configMapRevisionsKey: string(data)},
+			}
+			_, err = s.client.Kube().CoreV1().ConfigMaps(s.namespace).Create(ctx, cm, meta_v1.CreateOptions{})
+			if api_errors.IsAlreadyExists(err) {
+				// created concurrently, retried as a conflict
+				return api_errors.NewConflict(core_v1.Resource("configmaps"), cm.Name, err)
+			}
+			return err
+		}
+
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[configMapRevisionsKey] = string(data)
+		_, err = s.client.Kube().CoreV1().ConfigMaps(s.namespace).Update(ctx, cm, meta_v1.UpdateOptions{})
+		return err
+	})
+}
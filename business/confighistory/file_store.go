@@ -0,0 +1,112 @@
+package confighistory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// FileStore stores the revisions of each object in its own JSON file, in a directory per cluster, namespace and
+// object type
+type FileStore struct {
+	dir          string
+	maxRevisions int
+	mutex        sync.Mutex
+}
+
+// NewFileStore returns a FileStore keeping at most maxRevisions revisions per object under dir. The directory is
+// created if missing.
+func NewFileStore(dir string, maxRevisions int) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create Istio config history directory [%s]: %w", dir, err)
+	}
+	return &FileStore{dir: dir, maxRevisions: maxRevisions}, nil
+}
+
+// path returns the file of the object. The segments are escaped, such that they can't point outside the directory.
+func (s *FileStore) path(key ObjectKey) (string, error) {
+	segments := []string{key.Cluster, key.Namespace, key.ObjectType, key.Name}
+	for i, segment := range segments {
+		if segment == "" && i == 0 {
+			// the cluster can be unknown
+			segment = "_"
+		}
+		segment = url.PathEscape(segment)
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("invalid Istio config history object [%s/%s/%s/%s]", key.Cluster, key.Namespace, key.ObjectType, key.Name)
+		}
+		segments[i] = segment
+	}
+	return filepath.Join(s.dir, filepath.Join(segments...)+".json"), nil
+}
+
+func (s *FileStore) read(path string) ([]models.IstioConfigRevision, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []models.IstioConfigRevision{}, nil
+		}
+		return nil, err
+	}
+	revisions := []models.IstioConfigRevision{}
+	if err := json.Unmarshal(content, &revisions); err != nil {
+		return nil, fmt.Errorf("unable to decode Istio config history [%s]: %w", path, err)
+	}
+	return revisions, nil
+}
+
+// Get implements Store
+func (s *FileStore) Get(ctx context.Context, key ObjectKey, id string) (*models.IstioConfigRevision, error) {
+	revisions, err := s.List(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return find(revisions, id)
+}
+
+// List implements Store
+func (s *FileStore) List(_ context.Context, key ObjectKey) ([]models.IstioConfigRevision, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.read(path)
+}
+
+// Record implements Store. The history file of the object is replaced atomically, readers never see a partial
+// history.
+func (s *FileStore) Record(_ context.Context, revision models.IstioConfigRevision) error {
+	path, err := s.path(Key(revision))
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	revisions, err := s.read(path)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(prepend(revisions, revision, s.maxRevisions))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(path, content)
+}
@@ -0,0 +1,128 @@
+// Package confighistory records the changes done to the Istio config through Kiali, such that the previous
+// revisions of an object can be reviewed and restored.
+//
+// The revisions of an object are stored together, most recent first, and only the configured number of revisions
+// is kept per object.
+package confighistory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// ErrNotFound is returned by a Store when the requested revision does not exist
+var ErrNotFound = errors.New("Istio config revision not found")
+
+// ObjectKey identifies the object of the revisions
+type ObjectKey struct {
+	Cluster    string
+	Namespace  string
+	ObjectType string
+	Name       string
+}
+
+// Key returns the key of the object of a revision
+func Key(revision models.IstioConfigRevision) ObjectKey {
+	return ObjectKey{Cluster: revision.Cluster, Namespace: revision.Namespace, ObjectType: revision.ObjectType, Name: revision.Name}
+}
+
+// Store persists revisions. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns a revision of an object, or ErrNotFound
+	Get(ctx context.Context, key ObjectKey, id string) (*models.IstioConfigRevision, error)
+	// List returns the revisions of an object, most recent first. It is empty when the object has no revisions.
+	List(ctx context.Context, key ObjectKey) ([]models.IstioConfigRevision, error)
+	// Record stores a new revision of its object, the oldest revisions beyond the max revisions are removed
+	Record(ctx context.Context, revision models.IstioConfigRevision) error
+}
+
+// ValidateID returns an error if the ID is not a revision ID
+func ValidateID(id string) error {
+	if !util.IsTimeSortableID(id) {
+		return fmt.Errorf("invalid Istio config revision id [%s]", id)
+	}
+	return nil
+}
+
+// prepend returns the revisions with the new revision first, limited to max revisions
+func prepend(revisions []models.IstioConfigRevision, revision models.IstioConfigRevision, maxRevisions int) []models.IstioConfigRevision {
+	revisions = append([]models.IstioConfigRevision{revision}, revisions...)
+	if maxRevisions > 0 && len(revisions) > maxRevisions {
+		revisions = revisions[:maxRevisions]
+	}
+	return revisions
+}
+
+func find(revisions []models.IstioConfigRevision, id string) (*models.IstioConfigRevision, error) {
+	for i := range revisions {
+		if revisions[i].ID == id {
+			return &revisions[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+type userContextKey struct{}
+
+// WithUser returns a context carrying the user doing the changes, recorded in their revisions
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the user set by WithUser, or an empty string
+func UserFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userContextKey{}).(string)
+	return user
+}
+
+var (
+	defaultStore      Store
+	defaultStoreMutex sync.Mutex
+)
+
+// NewStore returns the Store configured by the provided settings. The configmap store uses the given client, and
+// the Kiali namespace when no namespace is configured.
+func NewStore(conf *config.Config, client kubernetes.ClientInterface) (Store, error) {
+	history := conf.IstioConfigHistory
+	switch history.Store {
+	case config.IstioConfigHistoryStoreConfigMap:
+		if client == nil {
+			return nil, errors.New("no client available for the Istio config history")
+		}
+		namespace := history.Namespace
+		if namespace == "" {
+			namespace = conf.Deployment.Namespace
+		}
+		return NewConfigMapStore(client, namespace, history.MaxRevisions), nil
+	case config.IstioConfigHistoryStoreFilesystem:
+		return NewFileStore(history.Path, history.MaxRevisions)
+	default:
+		return nil, fmt.Errorf("unsupported Istio config history store [%s]", history.Store)
+	}
+}
+
+// DefaultStore returns the configured Store shared by the server, creating it on first use. It returns nil when the
+// history is disabled.
+func DefaultStore(conf *config.Config, client kubernetes.ClientInterface) (Store, error) {
+	defaultStoreMutex.Lock()
+	defer defaultStoreMutex.Unlock()
+
+	if !conf.IstioConfigHistory.Enabled {
+		return nil, nil
+	}
+	if defaultStore == nil {
+		store, err := NewStore(conf, client)
+		if err != nil {
+			return nil, err
+		}
+		defaultStore = store
+	}
+	return defaultStore, nil
+}
@@ -0,0 +1,152 @@
+package confighistory
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func testRevision(name, operation string, at time.Time) models.IstioConfigRevision {
+	return models.IstioConfigRevision{
+		ID:         util.NewTimeSortableID(at),
+		Cluster:    "east",
+		Namespace:  "bookinfo",
+		ObjectType: "virtualservices",
+		Name:       name,
+		Operation:  operation,
+		User:       "admin",
+		Timestamp:  at,
+		After:      json.RawMessage(`{"metadata":{"name":"` + name + `"}}`),
+	}
+}
+
+func testStore(t *testing.T, store Store) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.TODO()
+
+	key := ObjectKey{Cluster: "east", Namespace: "bookinfo", ObjectType: "virtualservices", Name: "reviews"}
+	revisions, err := store.List(ctx, key)
+	require.NoError(err)
+	assert.Empty(revisions)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	created := testRevision("reviews", models.RevisionCreate, now.Add(-3*time.Minute))
+	require.NoError(store.Record(ctx, created))
+	require.NoError(store.Record(ctx, testRevision("reviews", models.RevisionUpdate, now.Add(-2*time.Minute))))
+	require.NoError(store.Record(ctx, testRevision("ratings", models.RevisionCreate, now.Add(-2*time.Minute))))
+	updated := testRevision("reviews", models.RevisionUpdate, now.Add(-time.Minute))
+	require.NoError(store.Record(ctx, updated))
+
+	// most recent first, limited to the max revisions
+	revisions, err = store.List(ctx, key)
+	require.NoError(err)
+	require.Len(revisions, 2)
+	assert.Equal(updated.ID, revisions[0].ID)
+	assert.Equal(models.RevisionUpdate, revisions[1].Operation)
+	assert.Equal("admin", revisions[0].User)
+	assert.True(updated.Timestamp.Equal(revisions[0].Timestamp))
+	assert.JSONEq(string(updated.After), string(revisions[0].After))
+
+	revision, err := store.Get(ctx, key, updated.ID)
+	require.NoError(err)
+	assert.Equal(updated.ID, revision.ID)
+
+	// the oldest revision was removed
+	_, err = store.Get(ctx, key, created.ID)
+	assert.ErrorIs(err, ErrNotFound)
+
+	key.Name = "ratings"
+	revisions, err = store.List(ctx, key)
+	require.NoError(err)
+	assert.Len(revisions, 1)
+
+	key.Cluster = "west"
+	revisions, err = store.List(ctx, key)
+	require.NoError(err)
+	assert.Empty(revisions)
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir(), 2)
+	require.NoError(t, err)
+	testStore(t, store)
+
+	// the object segments can't escape the directory
+	err = store.Record(context.TODO(), testRevision("..", models.RevisionCreate, time.Now()))
+	assert.Error(t, err)
+	revision := testRevision("reviews", models.RevisionCreate, time.Now())
+	revision.Namespace = "../../etc"
+	path, err := store.path(Key(revision))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(path, store.dir))
+	assert.NotContains(t, strings.TrimPrefix(path, store.dir), "/../")
+}
+
+func TestConfigMapStore(t *testing.T) {
+	client := kubetest.NewFakeK8sClient()
+	store := NewConfigMapStore(client, "istio-system", 2)
+	testStore(t, store)
+
+	key := ObjectKey{Cluster: "east", Namespace: "bookinfo", ObjectType: "virtualservices", Name: "reviews"}
+	cm, err := client.Kube().CoreV1().ConfigMaps("istio-system").Get(context.TODO(), ConfigMapName(key), meta_v1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "reviews", cm.Annotations["kiali.io/istio-config-history-name"])
+	assert.Equal(t, "true", cm.Labels["kiali.io/istio-config-history"])
+}
+
+func TestConfigMapStoreSizeLimit(t *testing.T) {
+	require := require.New(t)
+
+	store := NewConfigMapStore(kubetest.NewFakeK8sClient(), "istio-system", 10)
+	large := testRevision("reviews", models.RevisionUpdate, time.Now())
+	large.After = json.RawMessage(`{"data":"` + strings.Repeat("x", maxConfigMapDataSize/2) + `"}`)
+
+	require.NoError(store.Record(context.TODO(), large))
+	large.ID = util.NewTimeSortableID(time.Now())
+	require.NoError(store.Record(context.TODO(), large))
+
+	// the oldest revision is removed to stay within the ConfigMap size
+	revisions, err := store.List(context.TODO(), Key(large))
+	require.NoError(err)
+	require.Len(revisions, 1)
+	assert.Equal(t, large.ID, revisions[0].ID)
+
+	large.After = json.RawMessage(`{"data":"` + strings.Repeat("x", maxConfigMapDataSize) + `"}`)
+	assert.Error(t, store.Record(context.TODO(), large))
+}
+
+func TestNewStore(t *testing.T) {
+	conf := config.NewConfig()
+	conf.Deployment.Namespace = "kiali"
+
+	store, err := NewStore(conf, kubetest.NewFakeK8sClient())
+	require.NoError(t, err)
+	assert.Equal(t, "kiali", store.(*ConfigMapStore).namespace)
+
+	conf.IstioConfigHistory.Store = config.IstioConfigHistoryStoreFilesystem
+	conf.IstioConfigHistory.Path = t.TempDir()
+	store, err = NewStore(conf, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &FileStore{}, store)
+
+	conf.IstioConfigHistory.Store = "etcd"
+	_, err = NewStore(conf, nil)
+	assert.Error(t, err)
+}
+
+func TestUserFromContext(t *testing.T) {
+	assert.Equal(t, "", UserFromContext(context.TODO()))
+	assert.Equal(t, "admin", UserFromContext(WithUser(context.TODO(), "admin")))
+}
@@ -3,6 +3,7 @@ package business
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -48,6 +49,50 @@ func (in *HealthService) GetServiceHealth(ctx context.Context, namespace, cluste
 	return models.ServiceHealth{Requests: rqHealth}, err
 }
 
+// GetServiceVersionHealth returns the health of the requests to a version of a service, telling the versions apart
+// with the destination version of the telemetry: request rate, error ratio and 95th percentile latency
+func (in *HealthService) GetServiceVersionHealth(ctx context.Context, namespace, cluster, service, version, rateInterval string, queryTime time.Time) (models.ServiceVersionHealth, error) {
+	var end observability.EndFunc
+	_, end = observability.StartSpan(ctx, "GetServiceVersionHealth",
+		observability.Attribute("package", "business"),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("service", service),
+		observability.Attribute("version", version),
+		observability.Attribute("rateInterval", rateInterval),
+		observability.Attribute("queryTime", queryTime),
+	)
+	defer end()
+
+	health := models.ServiceVersionHealth{Version: version, Requests: models.NewEmptyRequestHealth()}
+	inbound, err := in.prom.GetServiceRequestRates(namespace, cluster, service, rateInterval, queryTime)
+	if err != nil {
+		return health, errors.NewServiceUnavailable(err.Error())
+	}
+	for _, sample := range inbound {
+		if string(sample.Metric["destination_version"]) == version {
+			health.Requests.AggregateInbound(sample)
+		}
+	}
+	health.Requests.CombineReporters()
+	health.RequestRate, health.ErrorRatio = health.Requests.InboundErrorRatio()
+	if health.RequestRate == 0 {
+		return health, nil
+	}
+
+	labels := fmt.Sprintf(`{reporter="destination",destination_service_name="%s",destination_service_namespace="%s",destination_cluster="%s",destination_version="%s"}`, service, namespace, cluster, version)
+	latency, err := in.prom.FetchHistogramValues("istio_request_duration_milliseconds", labels, "", rateInterval, false, []string{"0.95"}, queryTime)
+	if err != nil {
+		return health, errors.NewServiceUnavailable(err.Error())
+	}
+	for _, sample := range latency["0.95"] {
+		if value := float64(sample.Value); !math.IsNaN(value) {
+			health.LatencyP95 = value
+		}
+	}
+	return health, nil
+}
+
 // GetAppHealth returns an app health from just Namespace and app name (thus, it fetches data from K8S and Prometheus)
 func (in *HealthService) GetAppHealth(ctx context.Context, namespace, cluster, app, rateInterval string, queryTime time.Time, appD *appDetails) (models.AppHealth, error) {
 	var end observability.EndFunc
@@ -67,6 +67,42 @@ func TestGetServiceHealth(t *testing.T) {
 	assert.Equal(emptyResult, health.Requests.Outbound)
 }
 
+func TestGetServiceVersionHealth(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	prom := new(prometheustest.PromClientMock)
+	queryTime := time.Date(2017, 1, 15, 0, 0, 0, 0, time.UTC)
+	rates := model.Vector{}
+	for _, sample := range []model.Sample{sampleUnknownToHttpbin200, sampleUnknownToHttpbin500, sampleUnknownToHttpbinGrpc7} {
+		versioned := sample
+		versioned.Metric = sample.Metric.Clone()
+		versioned.Metric["destination_version"] = "v2"
+		rates = append(rates, &versioned)
+	}
+	// requests to other versions are not counted
+	rates = append(rates, &sampleUnknownToHttpbin404)
+	prom.MockServiceRequestRates("ns", "east", "httpbin", rates)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", `{reporter="destination",destination_service_name="httpbin",destination_service_namespace="ns",destination_cluster="east",destination_version="v2"}`, "", "1m", false, []string{"0.95"}, queryTime).
+		Return(map[string]model.Vector{"0.95": {&model.Sample{Value: 42}}}, nil)
+
+	hs := HealthService{prom: prom}
+	health, err := hs.GetServiceVersionHealth(context.TODO(), "ns", "east", "httpbin", "v2", "1m", queryTime)
+	require.NoError(err)
+	assert.Equal("v2", health.Version)
+	assert.InDelta(17.0, health.RequestRate, 0.0001)
+	assert.InDelta(3.0/17, health.ErrorRatio, 0.0001)
+	assert.Equal(42.0, health.LatencyP95)
+
+	// no latency without requests
+	health, err = hs.GetServiceVersionHealth(context.TODO(), "ns", "east", "httpbin", "v3", "1m", queryTime)
+	require.NoError(err)
+	assert.Zero(health.RequestRate)
+	assert.Zero(health.ErrorRatio)
+	assert.Zero(health.LatencyP95)
+	prom.AssertNumberOfCalls(t, "FetchHistogramValues", 1)
+}
+
 func TestGetAppHealth(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
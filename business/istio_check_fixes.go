@@ -0,0 +1,166 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api_types "k8s.io/apimachinery/pkg/types"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+)
+
+// PreviewIstioCheckFix returns the target object of the fix as it is now and as it would be once patched.
+// The object is not modified.
+func (in *IstioConfigService) PreviewIstioCheckFix(ctx context.Context, cluster string, fix models.IstioCheckFix) (models.IstioCheckFixResult, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "PreviewIstioCheckFix",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", fix.Namespace),
+		observability.Attribute("objectType", fix.ObjectType),
+		observability.Attribute("object", fix.Name),
+	)
+	defer end()
+
+	result := models.IstioCheckFixResult{Fix: fix}
+	object, err := in.getIstioCheckFixObject(ctx, cluster, fix)
+	if err != nil {
+		return result, err
+	}
+	patched, err := jsonpatch.MergePatch(object, []byte(fix.Patch))
+	if err != nil {
+		return result, api_errors.NewBadRequest(fmt.Sprintf("Invalid patch of fix [%s]: %s", fix.Description, err))
+	}
+	result.Object = object
+	result.Patched = patched
+	return result, nil
+}
+
+// ApplyIstioCheckFix patches the target object of the fix with the user's token. Istio objects are patched with
+// UpdateIstioConfigDetail, Services with the same JSON merge patch.
+func (in *IstioConfigService) ApplyIstioCheckFix(ctx context.Context, cluster string, fix models.IstioCheckFix) (models.IstioCheckFixResult, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "ApplyIstioCheckFix",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", fix.Namespace),
+		observability.Attribute("objectType", fix.ObjectType),
+		observability.Attribute("object", fix.Name),
+	)
+	defer end()
+
+	result, err := in.PreviewIstioCheckFix(ctx, cluster, fix)
+	if err != nil {
+		return result, err
+	}
+
+	var patched interface{}
+	if fix.ObjectType == kubernetes.Services {
+		userClient := in.userClients[cluster]
+		if userClient == nil {
+			return result, fmt.Errorf("K8s Client [%s] is not found or is not accessible for Kiali", cluster)
+		}
+		patched, err = userClient.Kube().CoreV1().Services(fix.Namespace).Patch(ctx, fix.Name, api_types.MergePatchType, []byte(fix.Patch), meta_v1.PatchOptions{})
+		if err != nil {
+			return result, err
+		}
+		if kubeCache, err := in.kialiCache.GetKubeCache(cluster); err == nil {
+			kubeCache.Refresh(fix.Namespace)
+		}
+	} else {
+		details, err := in.UpdateIstioConfigDetail(ctx, cluster, fix.Namespace, fix.ObjectType, fix.Name, fix.Patch)
+		if err != nil {
+			return result, err
+		}
+		patched = istioConfigDetailsObject(details)
+	}
+
+	if result.Patched, err = json.Marshal(patched); err != nil {
+		return result, err
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// getIstioCheckFixObject returns the JSON of the target object of the fix, read from the cache when the user has
+// access to its namespace
+func (in *IstioConfigService) getIstioCheckFixObject(ctx context.Context, cluster string, fix models.IstioCheckFix) ([]byte, error) {
+	if fix.Namespace == "" || fix.Name == "" {
+		return nil, api_errors.NewBadRequest("The namespace and name of the fixed object are required")
+	}
+
+	var object interface{}
+	switch {
+	case fix.ObjectType == kubernetes.Services:
+		if _, err := in.businessLayer.Namespace.GetClusterNamespace(ctx, fix.Namespace, cluster); err != nil {
+			return nil, err
+		}
+		kubeCache, err := in.kialiCache.GetKubeCache(cluster)
+		if err != nil {
+			return nil, err
+		}
+		if object, err = kubeCache.GetService(fix.Namespace, fix.Name); err != nil {
+			return nil, err
+		}
+	case GetIstioAPI(fix.ObjectType):
+		details, err := in.GetIstioConfigDetails(ctx, cluster, fix.Namespace, fix.ObjectType, fix.Name)
+		if err != nil {
+			return nil, err
+		}
+		object = istioConfigDetailsObject(details)
+	default:
+		return nil, api_errors.NewBadRequest("Object type not managed: " + fix.ObjectType)
+	}
+	return json.Marshal(object)
+}
+
+// istioConfigDetailsObject returns the object of the type of the details
+func istioConfigDetailsObject(details models.IstioConfigDetails) interface{} {
+	switch details.ObjectType {
+	case kubernetes.AuthorizationPolicies:
+		return details.AuthorizationPolicy
+	case kubernetes.DestinationRules:
+		return details.DestinationRule
+	case kubernetes.EnvoyFilters:
+		return details.EnvoyFilter
+	case kubernetes.Gateways:
+		return details.Gateway
+	case kubernetes.K8sGateways:
+		return details.K8sGateway
+	case kubernetes.K8sGRPCRoutes:
+		return details.K8sGRPCRoute
+	case kubernetes.K8sHTTPRoutes:
+		return details.K8sHTTPRoute
+	case kubernetes.K8sReferenceGrants:
+		return details.K8sReferenceGrant
+	case kubernetes.K8sTCPRoutes:
+		return details.K8sTCPRoute
+	case kubernetes.K8sTLSRoutes:
+		return details.K8sTLSRoute
+	case kubernetes.PeerAuthentications:
+		return details.PeerAuthentication
+	case kubernetes.RequestAuthentications:
+		return details.RequestAuthentication
+	case kubernetes.ServiceEntries:
+		return details.ServiceEntry
+	case kubernetes.Sidecars:
+		return details.Sidecar
+	case kubernetes.Telemetries:
+		return details.Telemetry
+	case kubernetes.VirtualServices:
+		return details.VirtualService
+	case kubernetes.WasmPlugins:
+		return details.WasmPlugin
+	case kubernetes.WorkloadEntries:
+		return details.WorkloadEntry
+	case kubernetes.WorkloadGroups:
+		return details.WorkloadGroup
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package business
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func setupIstioCheckFixes(t *testing.T, objects ...runtime.Object) (*Layer, *kubetest.FakeK8sClient) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	objects = append(objects, &core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "test"}})
+	k8s := kubetest.NewFakeK8sClient(objects...)
+	SetupBusinessLayer(t, k8s, *conf)
+
+	clients := map[string]kubernetes.ClientInterface{conf.KubernetesConfig.ClusterName: k8s}
+	return NewWithBackends(clients, clients, new(prometheustest.PromClientMock), nil), k8s
+}
+
+func TestPreviewIstioCheckFix(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dr := data.AddTrafficPolicyToDestinationRule(data.CreateMTLSTrafficPolicyForDestinationRules(),
+		data.CreateEmptyDestinationRule("test", "reviews", "reviews"))
+	layer, k8s := setupIstioCheckFixes(t, dr)
+
+	fix := models.IstioCheckFix{
+		Description: "Disable mTLS in DestinationRule reviews",
+		ObjectType:  kubernetes.DestinationRules,
+		Namespace:   "test",
+		Name:        "reviews",
+		Patch:       `{"spec":{"trafficPolicy":{"tls":null}}}`,
+	}
+	result, err := layer.IstioConfig.PreviewIstioCheckFix(context.TODO(), config.Get().KubernetesConfig.ClusterName, fix)
+	require.NoError(err)
+	assert.False(result.Applied)
+	assert.Contains(string(result.Object), `"tls":{"mode":"ISTIO_MUTUAL"}`)
+	assert.NotContains(string(result.Patched), `"tls"`)
+	assert.Contains(string(result.Patched), `"host":"reviews"`)
+
+	// the preview doesn't modify the object
+	current, err := k8s.Istio().NetworkingV1().DestinationRules("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.NotNil(current.Spec.TrafficPolicy.Tls)
+
+	fix.Patch = "not a patch"
+	_, err = layer.IstioConfig.PreviewIstioCheckFix(context.TODO(), config.Get().KubernetesConfig.ClusterName, fix)
+	assert.Error(err)
+
+	fix.ObjectType = "configmaps"
+	_, err = layer.IstioConfig.PreviewIstioCheckFix(context.TODO(), config.Get().KubernetesConfig.ClusterName, fix)
+	assert.Error(err)
+}
+
+func TestApplyIstioCheckFix(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	service := &core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "test"},
+		Spec:       core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Name: "web", Port: 9080}}},
+	}
+	layer, k8s := setupIstioCheckFixes(t, service)
+
+	fix := models.IstioCheckFix{
+		Description: "Rename port 9080 to http-web",
+		ObjectType:  kubernetes.Services,
+		Namespace:   "test",
+		Name:        "reviews",
+		Patch:       `{"spec":{"ports":[{"name":"http-web","port":9080}]}}`,
+	}
+	result, err := layer.IstioConfig.ApplyIstioCheckFix(context.TODO(), config.Get().KubernetesConfig.ClusterName, fix)
+	require.NoError(err)
+	assert.True(result.Applied)
+	assert.Contains(string(result.Object), `"name":"web"`)
+	assert.Contains(string(result.Patched), `"name":"http-web"`)
+
+	current, err := k8s.Kube().CoreV1().Services("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Equal("http-web", current.Spec.Ports[0].Name)
+}
@@ -19,6 +19,7 @@ import (
 	k8s_networking_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	k8s_networking_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/kiali/kiali/business/confighistory"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/cache"
@@ -35,6 +36,7 @@ type IstioConfigService struct {
 	kialiCache          cache.KialiCache
 	businessLayer       *Layer
 	controlPlaneMonitor ControlPlaneMonitor
+	history             confighistory.Store
 }
 
 type IstioConfigCriteria struct {
@@ -613,6 +615,15 @@ func GetIstioAPI(resourceType string) bool {
 
 // DeleteIstioConfigDetail deletes the given Istio resource
 func (in *IstioConfigService) DeleteIstioConfigDetail(ctx context.Context, cluster, namespace, resourceType, name string) error {
+	before := in.istioConfigHistoryObject(ctx, cluster, namespace, resourceType, name)
+	if err := in.deleteIstioConfigDetail(ctx, cluster, namespace, resourceType, name); err != nil {
+		return err
+	}
+	in.recordIstioConfigRevision(ctx, cluster, namespace, resourceType, name, models.RevisionDelete, "", before, nil)
+	return nil
+}
+
+func (in *IstioConfigService) deleteIstioConfigDetail(ctx context.Context, cluster, namespace, resourceType, name string) error {
 	var err error
 	delOpts := meta_v1.DeleteOptions{}
 
@@ -686,11 +697,25 @@ func (in *IstioConfigService) DeleteIstioConfigDetail(ctx context.Context, clust
 }
 
 func (in *IstioConfigService) UpdateIstioConfigDetail(ctx context.Context, cluster, namespace, resourceType, name, jsonPatch string) (models.IstioConfigDetails, error) {
+	before := in.istioConfigHistoryObject(ctx, cluster, namespace, resourceType, name)
+	istioConfigDetail, err := in.updateIstioConfigDetail(ctx, cluster, namespace, resourceType, name, jsonPatch, false)
+	if err != nil {
+		return istioConfigDetail, err
+	}
+	in.recordIstioConfigRevision(ctx, cluster, namespace, resourceType, name, models.RevisionUpdate, "", before, istioConfigDetailsObject(istioConfigDetail))
+	return istioConfigDetail, nil
+}
+
+// updateIstioConfigDetail patches the object, the patched object is only admitted and not persisted when dryRun is true
+func (in *IstioConfigService) updateIstioConfigDetail(ctx context.Context, cluster, namespace, resourceType, name, jsonPatch string, dryRun bool) (models.IstioConfigDetails, error) {
 	istioConfigDetail := models.IstioConfigDetails{}
 	istioConfigDetail.Namespace = models.Namespace{Name: namespace}
 	istioConfigDetail.ObjectType = resourceType
 
 	patchOpts := meta_v1.PatchOptions{}
+	if dryRun {
+		patchOpts.DryRun = []string{meta_v1.DryRunAll}
+	}
 	patchType := api_types.MergePatchType
 	bytePatch := []byte(jsonPatch)
 
@@ -766,7 +791,7 @@ func (in *IstioConfigService) UpdateIstioConfigDetail(ctx context.Context, clust
 	default:
 		err = fmt.Errorf("object type not found: %v", resourceType)
 	}
-	if err != nil {
+	if err != nil || dryRun {
 		return istioConfigDetail, err
 	}
 
@@ -777,11 +802,24 @@ func (in *IstioConfigService) UpdateIstioConfigDetail(ctx context.Context, clust
 }
 
 func (in *IstioConfigService) CreateIstioConfigDetail(ctx context.Context, cluster, namespace, resourceType string, body []byte) (models.IstioConfigDetails, error) {
+	istioConfigDetail, err := in.createIstioConfigDetail(ctx, cluster, namespace, resourceType, body, false)
+	if err != nil {
+		return istioConfigDetail, err
+	}
+	in.recordIstioConfigRevision(ctx, cluster, namespace, resourceType, "", models.RevisionCreate, "", nil, istioConfigDetailsObject(istioConfigDetail))
+	return istioConfigDetail, nil
+}
+
+// createIstioConfigDetail creates the object, the object is only admitted and not persisted when dryRun is true
+func (in *IstioConfigService) createIstioConfigDetail(ctx context.Context, cluster, namespace, resourceType string, body []byte, dryRun bool) (models.IstioConfigDetails, error) {
 	istioConfigDetail := models.IstioConfigDetails{}
 	istioConfigDetail.Namespace = models.Namespace{Name: namespace}
 	istioConfigDetail.ObjectType = resourceType
 
 	createOpts := meta_v1.CreateOptions{}
+	if dryRun {
+		createOpts.DryRun = []string{meta_v1.DryRunAll}
+	}
 
 	userClient := in.userClients[cluster]
 	if userClient == nil {
@@ -843,6 +881,20 @@ func (in *IstioConfigService) CreateIstioConfigDetail(ctx context.Context, clust
 			return istioConfigDetail, api_errors.NewBadRequest(err.Error())
 		}
 		istioConfigDetail.K8sReferenceGrant, err = userClient.GatewayAPI().GatewayV1beta1().ReferenceGrants(namespace).Create(ctx, istioConfigDetail.K8sReferenceGrant, createOpts)
+	case kubernetes.K8sTCPRoutes:
+		istioConfigDetail.K8sTCPRoute = &k8s_networking_v1alpha2.TCPRoute{}
+		err = json.Unmarshal(body, istioConfigDetail.K8sTCPRoute)
+		if err != nil {
+			return istioConfigDetail, api_errors.NewBadRequest(err.Error())
+		}
+		istioConfigDetail.K8sTCPRoute, err = userClient.GatewayAPI().GatewayV1alpha2().TCPRoutes(namespace).Create(ctx, istioConfigDetail.K8sTCPRoute, createOpts)
+	case kubernetes.K8sTLSRoutes:
+		istioConfigDetail.K8sTLSRoute = &k8s_networking_v1alpha2.TLSRoute{}
+		err = json.Unmarshal(body, istioConfigDetail.K8sTLSRoute)
+		if err != nil {
+			return istioConfigDetail, api_errors.NewBadRequest(err.Error())
+		}
+		istioConfigDetail.K8sTLSRoute, err = userClient.GatewayAPI().GatewayV1alpha2().TLSRoutes(namespace).Create(ctx, istioConfigDetail.K8sTLSRoute, createOpts)
 	case kubernetes.ServiceEntries:
 		istioConfigDetail.ServiceEntry = &networking_v1.ServiceEntry{}
 		err = json.Unmarshal(body, istioConfigDetail.ServiceEntry)
@@ -916,6 +968,9 @@ func (in *IstioConfigService) CreateIstioConfigDetail(ctx context.Context, clust
 	default:
 		err = fmt.Errorf("object type not found: %v", resourceType)
 	}
+	if dryRun {
+		return istioConfigDetail, err
+	}
 
 	if in.config.ExternalServices.Istio.IstioAPIEnabled {
 		// Refreshing the istio cache in case something has changed with the registry services. Not sure if this is really needed.
@@ -0,0 +1,231 @@
+package business
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+)
+
+// bulkApplyOrder ranks the object types so that objects are created after the objects they refer to: gateways
+// before the routes attached to them, hosts and subsets before the routes sending traffic to them.
+// Types not listed are created last.
+var bulkApplyOrder = map[string]int{
+	kubernetes.Gateways:           0,
+	kubernetes.K8sGateways:        0,
+	kubernetes.K8sReferenceGrants: 0,
+	kubernetes.ServiceEntries:     1,
+	kubernetes.WorkloadGroups:     1,
+	kubernetes.DestinationRules:   2,
+	kubernetes.WorkloadEntries:    2,
+	kubernetes.VirtualServices:    3,
+	kubernetes.K8sGRPCRoutes:      3,
+	kubernetes.K8sHTTPRoutes:      3,
+	kubernetes.K8sTCPRoutes:       3,
+	kubernetes.K8sTLSRoutes:       3,
+}
+
+// bulkObject is an object read from the documents of a bulk apply
+type bulkObject struct {
+	namespace    string
+	resourceType string
+	name         string
+	body         []byte
+}
+
+func bulkApplyRank(resourceType string) int {
+	if rank, ok := bulkApplyOrder[resourceType]; ok {
+		return rank
+	}
+	return len(bulkApplyOrder)
+}
+
+// ApplyIstioConfigBulk creates the Istio objects of a multi-document YAML (or JSON) in each of the given clusters,
+// the home cluster when none is given. Objects without a namespace are created in the given namespace.
+// All the objects are first created in dry-run mode, nothing is created when any of them is rejected. They are then
+// created in dependency order and, when one fails, the objects already created are deleted.
+// Errors are returned when the documents can't be read, the outcome of each object is set in the result.
+func (in *IstioConfigService) ApplyIstioConfigBulk(ctx context.Context, clusters []string, namespace string, body []byte, dryRun bool) (*models.IstioConfigBulkApply, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "ApplyIstioConfigBulk",
+		observability.Attribute("package", "business"),
+		observability.Attribute("clusters", clusters),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("dryRun", dryRun),
+	)
+	defer end()
+
+	objects, err := parseIstioConfigBulk(body, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, api_errors.NewBadRequest("No Istio object to apply")
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return bulkApplyRank(objects[i].resourceType) < bulkApplyRank(objects[j].resourceType)
+	})
+
+	if len(clusters) == 0 {
+		clusters = []string{in.config.KubernetesConfig.ClusterName}
+	}
+	for _, cluster := range clusters {
+		if _, ok := in.userClients[cluster]; !ok {
+			return nil, api_errors.NewBadRequest(fmt.Sprintf("Cluster [%s] is not found or is not accessible for Kiali", cluster))
+		}
+	}
+
+	result := &models.IstioConfigBulkApply{
+		Clusters: clusters,
+		Objects:  make([]models.IstioConfigBulkObject, 0, len(clusters)*len(objects)),
+	}
+	for _, cluster := range clusters {
+		for _, object := range objects {
+			result.Objects = append(result.Objects, models.IstioConfigBulkObject{
+				Cluster:    cluster,
+				Namespace:  object.namespace,
+				ObjectType: object.resourceType,
+				Name:       object.name,
+				Status:     models.BulkObjectSkipped,
+			})
+		}
+	}
+
+	valid := true
+	for i := range result.Objects {
+		applied := &result.Objects[i]
+		// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+		// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+		_, err := in.businessLayer.Namespace.GetClusterNamespace(ctx, applied.Namespace, applied.Cluster)
+		if err == nil {
+			_, err = in.createIstioConfigDetail(ctx, applied.Cluster, applied.Namespace, applied.ObjectType, objects[i%len(objects)].body, true)
+		}
+		if err != nil {
+			valid = false
+			applied.Status = models.BulkObjectFailed
+			applied.Error = err.Error()
+			continue
+		}
+		applied.Status = models.BulkObjectValid
+	}
+	if !valid || dryRun {
+		return result, nil
+	}
+
+	for i := range result.Objects {
+		applied := &result.Objects[i]
+		if _, err := in.CreateIstioConfigDetail(ctx, applied.Cluster, applied.Namespace, applied.ObjectType, objects[i%len(objects)].body); err != nil {
+			applied.Status = models.BulkObjectFailed
+			applied.Error = err.Error()
+			for j := i + 1; j < len(result.Objects); j++ {
+				result.Objects[j].Status = models.BulkObjectSkipped
+			}
+			in.rollbackIstioConfigBulk(ctx, result.Objects[:i])
+			return result, nil
+		}
+		applied.Status = models.BulkObjectCreated
+	}
+	result.Applied = true
+
+	return result, nil
+}
+
+// rollbackIstioConfigBulk deletes the objects created by a bulk apply, in the reverse order of their creation.
+// Objects that can't be deleted are left created, with the error.
+func (in *IstioConfigService) rollbackIstioConfigBulk(ctx context.Context, created []models.IstioConfigBulkObject) {
+	for i := len(created) - 1; i >= 0; i-- {
+		object := &created[i]
+		if err := in.DeleteIstioConfigDetail(ctx, object.Cluster, object.Namespace, object.ObjectType, object.Name); err != nil {
+			log.Errorf("Unable to roll back the creation of [%s/%s/%s/%s]: %v", object.Cluster, object.Namespace, object.ObjectType, object.Name, err)
+			object.Error = "rollback failed: " + err.Error()
+			continue
+		}
+		object.Status = models.BulkObjectRolledBack
+	}
+}
+
+// parseIstioConfigBulk reads the Istio objects of a multi-document YAML or JSON. Empty documents are ignored.
+func parseIstioConfigBulk(body []byte, defaultNamespace string) ([]bulkObject, error) {
+	objects := []bulkObject{}
+	seen := map[string]bool{}
+
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(body), 4096)
+	for document := 1; ; document++ {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, api_errors.NewBadRequest(fmt.Sprintf("Document %d could not be read: %v", document, err))
+		}
+		if len(object) == 0 {
+			continue
+		}
+
+		apiVersion, _ := object["apiVersion"].(string)
+		kind, _ := object["kind"].(string)
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil, api_errors.NewBadRequest(fmt.Sprintf("Document %d: %v", document, err))
+		}
+		resourceType := bulkResourceType(gv.Group, kind)
+		if resourceType == "" {
+			return nil, api_errors.NewBadRequest(fmt.Sprintf("Document %d: %s [%s] is not an Istio config type", document, kind, apiVersion))
+		}
+
+		metadata, _ := object["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		if name == "" {
+			return nil, api_errors.NewBadRequest(fmt.Sprintf("Document %d: %s has no name", document, kind))
+		}
+		namespace, _ := metadata["namespace"].(string)
+		if namespace == "" {
+			if defaultNamespace == "" {
+				return nil, api_errors.NewBadRequest(fmt.Sprintf("Document %d: %s [%s] has no namespace", document, kind, name))
+			}
+			namespace = defaultNamespace
+			metadata["namespace"] = namespace
+		}
+
+		key := namespace + "/" + resourceType + "/" + name
+		if seen[key] {
+			return nil, api_errors.NewBadRequest(fmt.Sprintf("Document %d: %s [%s/%s] is duplicated", document, kind, namespace, name))
+		}
+		seen[key] = true
+
+		// The typed clients set the version they use, the documents may use any version of the group
+		delete(object, "apiVersion")
+		delete(object, "kind")
+		objectBody, err := json.Marshal(object)
+		if err != nil {
+			return nil, api_errors.NewBadRequest(fmt.Sprintf("Document %d: %v", document, err))
+		}
+		objects = append(objects, bulkObject{namespace: namespace, resourceType: resourceType, name: name, body: objectBody})
+	}
+
+	return objects, nil
+}
+
+// bulkResourceType returns the Istio config type of a kind, empty when the kind is not managed by Kiali
+func bulkResourceType(group, kind string) string {
+	for resourceType, api := range kubernetes.ResourceTypesToAPI {
+		// Gateway API types are prefixed to tell them apart from the Istio types of the same kind
+		if api == group && strings.TrimPrefix(kubernetes.PluralType[resourceType], "K8s") == kind {
+			return resourceType
+		}
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	istiofake "istio.io/client-go/pkg/clientset/versioned/fake"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeclienttesting "k8s.io/client-go/testing"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+const bulkBookinfo = `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: reviews
+spec:
+  hosts: ["reviews"]
+  gateways: ["bookinfo-gateway"]
+  http:
+  - route:
+    - destination:
+        host: reviews
+        subset: v1
+---
+apiVersion: networking.istio.io/v1beta1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: test
+spec:
+  host: reviews
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+---
+---
+apiVersion: networking.istio.io/v1
+kind: Gateway
+metadata:
+  name: bookinfo-gateway
+spec:
+  selector:
+    istio: ingressgateway
+`
+
+// fakeDryRunCreates makes the fake client admit the first n objects created without persisting them, as the API
+// server does in dry-run mode. A bulk apply creates all its objects in dry-run mode before creating them.
+func fakeDryRunCreates(k8s *kubetest.FakeK8sClient, n int) {
+	created := 0
+	k8s.IstioClientset.(*istiofake.Clientset).PrependReactor("create", "*", func(action kubeclienttesting.Action) (bool, runtime.Object, error) {
+		created++
+		return created <= n, action.(kubeclienttesting.CreateAction).GetObject(), nil
+	})
+}
+
+func TestParseIstioConfigBulk(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	objects, err := parseIstioConfigBulk([]byte(bulkBookinfo+`---
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: bookinfo-gateway
+  namespace: istio-system
+`), "test")
+	require.NoError(err)
+	require.Len(objects, 4)
+	assert.Equal(kubernetes.VirtualServices, objects[0].resourceType)
+	assert.Equal("test", objects[0].namespace)
+	var vs map[string]interface{}
+	require.NoError(json.Unmarshal(objects[0].body, &vs))
+	assert.Equal(map[string]interface{}{"name": "reviews", "namespace": "test"}, vs["metadata"])
+	assert.NotContains(string(objects[0].body), "apiVersion")
+	assert.Equal(kubernetes.DestinationRules, objects[1].resourceType)
+	assert.Equal(kubernetes.Gateways, objects[2].resourceType)
+	assert.Equal(kubernetes.K8sGateways, objects[3].resourceType)
+	assert.Equal("istio-system", objects[3].namespace)
+
+	for _, invalid := range []string{
+		"kind: Deployment\napiVersion: apps/v1\nmetadata:\n  name: reviews",
+		"kind: VirtualService\napiVersion: networking.istio.io/v1\nspec: {}",
+		bulkBookinfo + "---\n" + bulkBookinfo,
+		"kind: VirtualService\napiVersion: networking.istio.io/v1\nmetadata: [",
+	} {
+		_, err := parseIstioConfigBulk([]byte(invalid), "test")
+		assert.True(api_errors.IsBadRequest(err), invalid)
+	}
+	_, err = parseIstioConfigBulk([]byte(bulkBookinfo), "")
+	assert.True(api_errors.IsBadRequest(err))
+}
+
+func TestApplyIstioConfigBulk(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer, k8s := setupIstioCheckFixes(t)
+	fakeDryRunCreates(k8s, 3)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	result, err := layer.IstioConfig.ApplyIstioConfigBulk(context.TODO(), nil, "test", []byte(bulkBookinfo), false)
+	require.NoError(err)
+	assert.True(result.Applied)
+	assert.Equal([]string{cluster}, result.Clusters)
+
+	// in dependency order
+	require.Len(result.Objects, 3)
+	assert.Equal(models.IstioConfigBulkObject{Cluster: cluster, Namespace: "test", ObjectType: kubernetes.Gateways, Name: "bookinfo-gateway", Status: models.BulkObjectCreated}, result.Objects[0])
+	assert.Equal(kubernetes.DestinationRules, result.Objects[1].ObjectType)
+	assert.Equal(kubernetes.VirtualServices, result.Objects[2].ObjectType)
+	for _, object := range result.Objects {
+		assert.Equal(models.BulkObjectCreated, object.Status)
+	}
+
+	vs, err := k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Equal([]string{"bookinfo-gateway"}, vs.Spec.Gateways)
+	_, err = k8s.Istio().NetworkingV1().Gateways("test").Get(context.TODO(), "bookinfo-gateway", meta_v1.GetOptions{})
+	assert.NoError(err)
+}
+
+func TestApplyIstioConfigBulkRollback(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer, k8s := setupIstioCheckFixes(t)
+	// admitted in dry-run mode, rejected when created
+	k8s.IstioClientset.(*istiofake.Clientset).PrependReactor("create", "virtualservices", func(action kubeclienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, api_errors.NewServiceUnavailable("webhook unavailable")
+	})
+	fakeDryRunCreates(k8s, 3)
+
+	result, err := layer.IstioConfig.ApplyIstioConfigBulk(context.TODO(), nil, "test", []byte(bulkBookinfo), false)
+	require.NoError(err)
+	assert.False(result.Applied)
+	require.Len(result.Objects, 3)
+	assert.Equal(models.BulkObjectRolledBack, result.Objects[0].Status)
+	assert.Equal(models.BulkObjectRolledBack, result.Objects[1].Status)
+	assert.Equal(models.BulkObjectFailed, result.Objects[2].Status)
+	assert.NotEmpty(result.Objects[2].Error)
+
+	_, err = k8s.Istio().NetworkingV1().Gateways("test").Get(context.TODO(), "bookinfo-gateway", meta_v1.GetOptions{})
+	assert.True(api_errors.IsNotFound(err))
+	_, err = k8s.Istio().NetworkingV1().DestinationRules("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	assert.True(api_errors.IsNotFound(err))
+}
+
+func TestApplyIstioConfigBulkValidation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer, k8s := setupIstioCheckFixes(t)
+	fakeDryRunCreates(k8s, 6)
+
+	// the DestinationRule namespace doesn't exist, nothing is created
+	body := []byte(bulkBookinfo + "---\napiVersion: networking.istio.io/v1\nkind: DestinationRule\nmetadata:\n  name: ratings\n  namespace: missing\nspec:\n  host: ratings\n")
+	result, err := layer.IstioConfig.ApplyIstioConfigBulk(context.TODO(), nil, "test", body, false)
+	require.NoError(err)
+	assert.False(result.Applied)
+	require.Len(result.Objects, 4)
+	assert.Equal(models.BulkObjectValid, result.Objects[0].Status)
+	assert.Equal("ratings", result.Objects[2].Name)
+	assert.Equal(models.BulkObjectFailed, result.Objects[2].Status)
+
+	_, err = k8s.Istio().NetworkingV1().Gateways("test").Get(context.TODO(), "bookinfo-gateway", meta_v1.GetOptions{})
+	assert.True(api_errors.IsNotFound(err))
+
+	// only validated
+	result, err = layer.IstioConfig.ApplyIstioConfigBulk(context.TODO(), nil, "test", []byte(bulkBookinfo), true)
+	require.NoError(err)
+	assert.False(result.Applied)
+	for _, object := range result.Objects {
+		assert.Equal(models.BulkObjectValid, object.Status)
+	}
+	_, err = k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	assert.True(api_errors.IsNotFound(err))
+
+	_, err = layer.IstioConfig.ApplyIstioConfigBulk(context.TODO(), []string{"west"}, "test", []byte(bulkBookinfo), true)
+	assert.True(api_errors.IsBadRequest(err))
+}
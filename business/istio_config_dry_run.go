@@ -0,0 +1,209 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+)
+
+// serverManagedFields are the JSON pointers of the fields maintained by the API server. They change on every write,
+// they are left out of the diffs and of the objects restored from a previous revision.
+var serverManagedFields = map[string]bool{
+	"/apiVersion":                 true,
+	"/kind":                       true,
+	"/metadata/creationTimestamp": true,
+	"/metadata/generation":        true,
+	"/metadata/managedFields":     true,
+	"/metadata/resourceVersion":   true,
+	"/metadata/uid":               true,
+	"/status":                     true,
+}
+
+// DryRunUpdateIstioConfigDetail submits the merge patch of an Istio object in dry-run mode. The API server admits the
+// patched object without persisting it. The result is diffed against the current object and validated with the rest
+// of the Istio config of the cluster.
+func (in *IstioConfigService) DryRunUpdateIstioConfigDetail(ctx context.Context, cluster, namespace, resourceType, name, jsonPatch string) (*models.IstioConfigDryRun, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "DryRunUpdateIstioConfigDetail",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("resourceType", resourceType),
+		observability.Attribute("name", name),
+	)
+	defer end()
+
+	current, err := in.GetIstioConfigDetails(ctx, cluster, namespace, resourceType, name)
+	if err != nil {
+		return nil, err
+	}
+	currentObject, err := json.Marshal(istioConfigDetailsObject(current))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := in.updateIstioConfigDetail(ctx, cluster, namespace, resourceType, name, jsonPatch, true)
+	if err != nil {
+		return nil, err
+	}
+	return in.dryRunResult(ctx, cluster, namespace, resourceType, currentObject, result)
+}
+
+// DryRunCreateIstioConfigDetail submits the creation of an Istio object in dry-run mode. The API server admits the
+// object without persisting it. The result is validated with the rest of the Istio config of the cluster.
+func (in *IstioConfigService) DryRunCreateIstioConfigDetail(ctx context.Context, cluster, namespace, resourceType string, body []byte) (*models.IstioConfigDryRun, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "DryRunCreateIstioConfigDetail",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("resourceType", resourceType),
+	)
+	defer end()
+
+	result, err := in.createIstioConfigDetail(ctx, cluster, namespace, resourceType, body, true)
+	if err != nil {
+		return nil, err
+	}
+	return in.dryRunResult(ctx, cluster, namespace, resourceType, nil, result)
+}
+
+func (in *IstioConfigService) dryRunResult(ctx context.Context, cluster, namespace, resourceType string, currentObject []byte, result models.IstioConfigDetails) (*models.IstioConfigDryRun, error) {
+	resultObject, err := json.Marshal(istioConfigDetailsObject(result))
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := IstioConfigDiff(currentObject, resultObject)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun := &models.IstioConfigDryRun{
+		Result: resultObject,
+		Diff:   diff,
+	}
+	if currentObject != nil {
+		dryRun.Current = currentObject
+	}
+
+	current, proposed, err := in.businessLayer.Validations.GetProposedValidations(ctx, cluster, namespace, resourceType, resultObject, false)
+	if err != nil {
+		return nil, err
+	}
+	dryRun.NewChecks = proposed.NewChecks(current)
+
+	var meta struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(resultObject, &meta); err != nil {
+		return nil, err
+	}
+	if meta.Metadata.Namespace == "" {
+		meta.Metadata.Namespace = namespace
+	}
+	key := models.IstioValidationKey{
+		ObjectType: models.ObjectTypeSingular[resourceType],
+		Name:       meta.Metadata.Name,
+		Namespace:  meta.Metadata.Namespace,
+		Cluster:    cluster,
+	}
+	dryRun.Validation = proposed[key]
+
+	return dryRun, nil
+}
+
+// IstioConfigDiff returns the changes between two JSON objects, sorted by path. A nil or empty before is an object
+// created, its top level fields are added. The fields maintained by the API server are left out.
+func IstioConfigDiff(before, after []byte) ([]models.IstioConfigChange, error) {
+	var from, to interface{}
+	if len(before) == 0 {
+		from = map[string]interface{}{}
+	} else if err := json.Unmarshal(before, &from); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(after, &to); err != nil {
+		return nil, err
+	}
+
+	changes := []models.IstioConfigChange{}
+	diffValues("", from, to, &changes)
+	return changes, nil
+}
+
+func diffValues(path string, from, to interface{}, changes *[]models.IstioConfigChange) {
+	if serverManagedFields[path] {
+		return
+	}
+
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		toValue, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(fromValue)+len(toValue))
+		for k := range fromValue {
+			keys = append(keys, k)
+		}
+		for k := range toValue {
+			if _, found := fromValue[k]; !found {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childPath := path + "/" + escapeJSONPointer(k)
+			f, inFrom := fromValue[k]
+			t, inTo := toValue[k]
+			switch {
+			case !inTo:
+				if !serverManagedFields[childPath] {
+					*changes = append(*changes, models.IstioConfigChange{Path: childPath, Op: models.ChangeRemove, From: f})
+				}
+			case !inFrom:
+				if !serverManagedFields[childPath] {
+					*changes = append(*changes, models.IstioConfigChange{Path: childPath, Op: models.ChangeAdd, To: t})
+				}
+			default:
+				diffValues(childPath, f, t, changes)
+			}
+		}
+		return
+	case []interface{}:
+		toValue, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(fromValue) || i < len(toValue); i++ {
+			childPath := fmt.Sprintf("%s/%d", path, i)
+			switch {
+			case i >= len(toValue):
+				*changes = append(*changes, models.IstioConfigChange{Path: childPath, Op: models.ChangeRemove, From: fromValue[i]})
+			case i >= len(fromValue):
+				*changes = append(*changes, models.IstioConfigChange{Path: childPath, Op: models.ChangeAdd, To: toValue[i]})
+			default:
+				diffValues(childPath, fromValue[i], toValue[i], changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, models.IstioConfigChange{Path: path, Op: models.ChangeReplace, From: from, To: to})
+	}
+}
+
+// escapeJSONPointer escapes a key as a reference token of a JSON pointer (RFC 6901)
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
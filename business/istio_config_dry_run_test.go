@@ -0,0 +1,154 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	istiofake "istio.io/client-go/pkg/clientset/versioned/fake"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeclienttesting "k8s.io/client-go/testing"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+// fakeDryRunVirtualServices makes the fake client admit the VirtualServices created or patched without persisting
+// them, as the API server does in dry-run mode. The fake client doesn't honor the dry-run option.
+func fakeDryRunVirtualServices(t *testing.T, k8s *kubetest.FakeK8sClient) {
+	fake := k8s.IstioClientset.(*istiofake.Clientset)
+	fake.PrependReactor("create", "virtualservices", func(action kubeclienttesting.Action) (bool, runtime.Object, error) {
+		created := action.(kubeclienttesting.CreateAction).GetObject().(*networking_v1.VirtualService)
+		created.Namespace = action.GetNamespace()
+		return true, created, nil
+	})
+	fake.PrependReactor("patch", "virtualservices", func(action kubeclienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(kubeclienttesting.PatchAction)
+		current, err := fake.Tracker().Get(patch.GetResource(), patch.GetNamespace(), patch.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		currentJSON, err := json.Marshal(current)
+		require.NoError(t, err)
+		patchedJSON, err := jsonpatch.MergePatch(currentJSON, patch.GetPatch())
+		require.NoError(t, err)
+		patched := &networking_v1.VirtualService{}
+		require.NoError(t, json.Unmarshal(patchedJSON, patched))
+		return true, patched, nil
+	})
+}
+
+func TestIstioConfigDiff(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	before := `{
+		"apiVersion": "networking.istio.io/v1",
+		"metadata": {"name": "reviews", "resourceVersion": "1", "labels": {"app/name": "reviews", "version": "v1"}},
+		"spec": {"hosts": ["reviews"], "gateways": ["mesh"], "http": [{"route": [{"weight": 80}, {"weight": 20}]}]}
+	}`
+	after := `{
+		"metadata": {"name": "reviews", "resourceVersion": "2", "labels": {"app/name": "reviews", "team": "bookinfo"}},
+		"spec": {"hosts": ["reviews"], "http": [{"route": [{"weight": 50}, {"weight": 30}, {"weight": 20}]}], "exportTo": ["."]}
+	}`
+
+	diff, err := IstioConfigDiff([]byte(before), []byte(after))
+	require.NoError(err)
+	assert.Equal([]models.IstioConfigChange{
+		{Path: "/metadata/labels/team", Op: models.ChangeAdd, To: "bookinfo"},
+		{Path: "/metadata/labels/version", Op: models.ChangeRemove, From: "v1"},
+		{Path: "/spec/exportTo", Op: models.ChangeAdd, To: []interface{}{"."}},
+		{Path: "/spec/gateways", Op: models.ChangeRemove, From: []interface{}{"mesh"}},
+		{Path: "/spec/http/0/route/0/weight", Op: models.ChangeReplace, From: float64(80), To: float64(50)},
+		{Path: "/spec/http/0/route/1/weight", Op: models.ChangeReplace, From: float64(20), To: float64(30)},
+		{Path: "/spec/http/0/route/2", Op: models.ChangeAdd, To: map[string]interface{}{"weight": float64(20)}},
+	}, diff)
+
+	diff, err = IstioConfigDiff([]byte(before), []byte(before))
+	require.NoError(err)
+	assert.Empty(diff)
+
+	// the keys are escaped in the paths
+	diff, err = IstioConfigDiff([]byte(`{"metadata":{"labels":{"app/name":"reviews"}}}`), []byte(`{"metadata":{"labels":{"app/name":"ratings"}}}`))
+	require.NoError(err)
+	assert.Equal("/metadata/labels/app~1name", diff[0].Path)
+
+	// an object created adds its top level fields
+	diff, err = IstioConfigDiff(nil, []byte(after))
+	require.NoError(err)
+	assert.Len(diff, 2)
+	assert.Equal("/metadata", diff[0].Path)
+	assert.Equal(models.ChangeAdd, diff[0].Op)
+	assert.Equal("/spec", diff[1].Path)
+
+	_, err = IstioConfigDiff([]byte(before), []byte("not json"))
+	assert.Error(err)
+}
+
+func TestDryRunUpdateIstioConfigDetail(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	vs := data.AddHttpRoutesToVirtualService(data.CreateHttpRouteDestination("reviews", "", 100),
+		data.CreateEmptyVirtualService("reviews", "test", []string{"reviews"}))
+	layer, k8s := setupIstioCheckFixes(t, vs)
+	fakeDryRunVirtualServices(t, k8s)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	// the subset v2 has no DestinationRule
+	patch := `{"spec":{"http":[{"route":[{"destination":{"host":"reviews","subset":"v2"}}]}]}}`
+	dryRun, err := layer.IstioConfig.DryRunUpdateIstioConfigDetail(context.TODO(), cluster, "test", kubernetes.VirtualServices, "reviews", patch)
+	require.NoError(err)
+	assert.Contains(string(dryRun.Current), `"weight":100`)
+	assert.Contains(string(dryRun.Result), `"subset":"v2"`)
+	assert.Equal([]models.IstioConfigChange{
+		{Path: "/spec/http/0/route/0/destination/subset", Op: models.ChangeAdd, To: "v2"},
+		{Path: "/spec/http/0/route/0/weight", Op: models.ChangeRemove, From: float64(100)},
+	}, dryRun.Diff)
+
+	require.NotNil(dryRun.Validation)
+	assert.Equal("reviews", dryRun.Validation.Name)
+	require.Len(dryRun.Validation.Checks, 1)
+	assert.Equal("KIA1107", dryRun.Validation.Checks[0].Code)
+
+	key := models.IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "test", Cluster: cluster}
+	require.Contains(dryRun.NewChecks, key)
+	assert.Equal("spec/http[0]/route[0]/destination", dryRun.NewChecks[key].Checks[0].Path)
+
+	_, err = layer.IstioConfig.DryRunUpdateIstioConfigDetail(context.TODO(), cluster, "test", kubernetes.VirtualServices, "ratings", `{"spec":{}}`)
+	assert.Error(err)
+}
+
+func TestDryRunCreateIstioConfigDetail(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer, k8s := setupIstioCheckFixes(t)
+	fakeDryRunVirtualServices(t, k8s)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	body := `{"metadata":{"name":"reviews"},"spec":{"hosts":["reviews"],"http":[{"route":[{"destination":{"host":"reviews","subset":"v2"}}]}]}}`
+	dryRun, err := layer.IstioConfig.DryRunCreateIstioConfigDetail(context.TODO(), cluster, "test", kubernetes.VirtualServices, []byte(body))
+	require.NoError(err)
+	assert.Nil(dryRun.Current)
+	assert.Contains(string(dryRun.Result), `"namespace":"test"`)
+	assert.NotEmpty(dryRun.Diff)
+
+	require.NotNil(dryRun.Validation)
+	assert.Equal("test", dryRun.Validation.Namespace)
+	require.Len(dryRun.Validation.Checks, 1)
+	assert.Equal("KIA1107", dryRun.Validation.Checks[0].Code)
+	assert.Contains(dryRun.NewChecks, models.IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "test", Cluster: cluster})
+
+	// the object is not created
+	_, err = k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	assert.Error(err)
+}
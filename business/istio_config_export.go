@@ -0,0 +1,333 @@
+package business
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kiali/kiali/business/references"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+)
+
+// Archive formats of an Istio config export
+const (
+	IstioConfigExportTar = "tar"
+	IstioConfigExportZip = "zip"
+)
+
+// KustomizationFile is the name of the Kustomization file listing the files of an export
+const KustomizationFile = "kustomization.yaml"
+
+// lastAppliedAnnotation is set by kubectl apply, it is left out of the exports as the GitOps tool manages it
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// istioConfigTypeMeta is the apiVersion and kind of the objects of each Istio config type, as served by the clients
+var istioConfigTypeMeta = map[string]meta_v1.TypeMeta{
+	kubernetes.AuthorizationPolicies:  {APIVersion: kubernetes.ApiSecurityVersionV1, Kind: kubernetes.AuthorizationPoliciesType},
+	kubernetes.DestinationRules:       {APIVersion: kubernetes.ApiNetworkingVersionV1, Kind: kubernetes.DestinationRuleType},
+	kubernetes.EnvoyFilters:           {APIVersion: kubernetes.ApiNetworkingVersionV1Alpha3, Kind: kubernetes.EnvoyFilterType},
+	kubernetes.Gateways:               {APIVersion: kubernetes.ApiNetworkingVersionV1, Kind: kubernetes.GatewayType},
+	kubernetes.K8sGateways:            {APIVersion: kubernetes.K8sApiNetworkingVersionV1, Kind: kubernetes.K8sActualGatewayType},
+	kubernetes.K8sGRPCRoutes:          {APIVersion: kubernetes.K8sApiNetworkingVersionV1, Kind: kubernetes.K8sActualGRPCRouteType},
+	kubernetes.K8sHTTPRoutes:          {APIVersion: kubernetes.K8sApiNetworkingVersionV1, Kind: kubernetes.K8sActualHTTPRouteType},
+	kubernetes.K8sReferenceGrants:     {APIVersion: kubernetes.K8sApiNetworkingVersionV1Beta1, Kind: kubernetes.K8sActualReferenceGrantType},
+	kubernetes.K8sTCPRoutes:           {APIVersion: kubernetes.K8sApiNetworkingVersionV1Alpha2, Kind: kubernetes.K8sActualTCPRouteType},
+	kubernetes.K8sTLSRoutes:           {APIVersion: kubernetes.K8sApiNetworkingVersionV1Alpha2, Kind: kubernetes.K8sActualTLSRouteType},
+	kubernetes.PeerAuthentications:    {APIVersion: kubernetes.ApiSecurityVersionV1, Kind: kubernetes.PeerAuthenticationsType},
+	kubernetes.RequestAuthentications: {APIVersion: kubernetes.ApiSecurityVersionV1, Kind: kubernetes.RequestAuthenticationsType},
+	kubernetes.ServiceEntries:         {APIVersion: kubernetes.ApiNetworkingVersionV1, Kind: kubernetes.ServiceEntryType},
+	kubernetes.Sidecars:               {APIVersion: kubernetes.ApiNetworkingVersionV1, Kind: kubernetes.SidecarType},
+	kubernetes.Telemetries:            {APIVersion: kubernetes.ApiTelemetryV1, Kind: kubernetes.TelemetryType},
+	kubernetes.VirtualServices:        {APIVersion: kubernetes.ApiNetworkingVersionV1, Kind: kubernetes.VirtualServiceType},
+	kubernetes.WasmPlugins:            {APIVersion: kubernetes.ApiExtensionV1Alpha1, Kind: kubernetes.WasmPluginType},
+	kubernetes.WorkloadEntries:        {APIVersion: kubernetes.ApiNetworkingVersionV1, Kind: kubernetes.WorkloadEntryType},
+	kubernetes.WorkloadGroups:         {APIVersion: kubernetes.ApiNetworkingVersionV1, Kind: kubernetes.WorkloadGroupType},
+}
+
+// kustomization is the Kustomization file of an export
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Resources  []string `json:"resources"`
+}
+
+// ExportIstioConfig returns an archive with the Istio config of a namespace, ready to be committed to a GitOps
+// repository: one YAML file per object, named <object type>/<name>.yaml, and a Kustomization file listing them.
+// The fields maintained by the API server are left out. When includeServices is set, the Services of the namespace
+// referenced by the Istio config are exported too. The archive is a gzip compressed tar or a zip.
+func (in *IstioConfigService) ExportIstioConfig(ctx context.Context, cluster, namespace, format string, includeServices bool) ([]byte, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "ExportIstioConfig",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("format", format),
+		observability.Attribute("includeServices", includeServices),
+	)
+	defer end()
+
+	if format != IstioConfigExportTar && format != IstioConfigExportZip {
+		return nil, api_errors.NewBadRequest(fmt.Sprintf("Export format [%s] is not supported, use %s or %s", format, IstioConfigExportTar, IstioConfigExportZip))
+	}
+
+	istioConfigList, err := in.GetIstioConfigListForNamespace(ctx, cluster, namespace, ParseIstioConfigCriteria("", "", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for resourceType, objects := range istioConfigListObjects(istioConfigList) {
+		for _, object := range objects {
+			content, err := exportObject(object, istioConfigTypeMeta[resourceType])
+			if err != nil {
+				return nil, err
+			}
+			files[path.Join(resourceType, object.GetName()+".yaml")] = content
+		}
+	}
+
+	if includeServices {
+		services, err := in.referencedServices(ctx, cluster, namespace, istioConfigList)
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range services {
+			// The cluster IPs are allocated by the cluster the Service is created in
+			service.Spec.ClusterIP = ""
+			service.Spec.ClusterIPs = nil
+			content, err := exportObject(service, meta_v1.TypeMeta{APIVersion: "v1", Kind: kubernetes.ServiceType})
+			if err != nil {
+				return nil, err
+			}
+			files[path.Join(kubernetes.Services, service.Name+".yaml")] = content
+		}
+	}
+
+	content, err := yaml.Marshal(kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  sortedFileNames(files),
+	})
+	if err != nil {
+		return nil, err
+	}
+	files[KustomizationFile] = content
+
+	if format == IstioConfigExportZip {
+		return zipArchive(files)
+	}
+	return tarArchive(files)
+}
+
+// referencedServices returns the Services of the namespace referenced by its routes and DestinationRules
+func (in *IstioConfigService) referencedServices(ctx context.Context, cluster, namespace string, istioConfigList *models.IstioConfigList) ([]*core_v1.Service, error) {
+	namespaces, err := in.businessLayer.Namespace.GetClusterNamespaces(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	var registryServices []*kubernetes.RegistryService
+	if in.config.ExternalServices.Istio.IstioAPIEnabled {
+		registryServices = in.businessLayer.RegistryStatus.GetRegistryServices(RegistryCriteria{AllNamespaces: true, Cluster: cluster})
+	}
+
+	istioReferences := models.IstioReferencesMap{}
+	for _, referenceChecker := range []ReferenceChecker{
+		references.VirtualServiceReferences{Namespace: namespace, Namespaces: namespaces, VirtualServices: istioConfigList.VirtualServices, DestinationRules: istioConfigList.DestinationRules},
+		references.DestinationRuleReferences{Namespace: namespace, Namespaces: namespaces, DestinationRules: istioConfigList.DestinationRules, VirtualServices: istioConfigList.VirtualServices, ServiceEntries: istioConfigList.ServiceEntries, RegistryServices: registryServices},
+		references.K8sHTTPRouteReferences{K8sHTTPRoutes: istioConfigList.K8sHTTPRoutes, K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces},
+		references.K8sGRPCRouteReferences{K8sGRPCRoutes: istioConfigList.K8sGRPCRoutes, K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces},
+	} {
+		istioReferences.MergeReferencesMap(referenceChecker.References())
+	}
+
+	kubeCache, err := in.kialiCache.GetKubeCache(cluster)
+	if err != nil {
+		return nil, err
+	}
+	services := []*core_v1.Service{}
+	exported := map[string]bool{}
+	for _, objectReferences := range istioReferences {
+		for _, serviceReference := range objectReferences.ServiceReferences {
+			// Services of other namespaces are exported with their own namespace
+			if serviceReference.Namespace != namespace || exported[serviceReference.Name] {
+				continue
+			}
+			exported[serviceReference.Name] = true
+			service, err := kubeCache.GetService(namespace, serviceReference.Name)
+			if err != nil {
+				if api_errors.IsNotFound(err) {
+					log.Debugf("Referenced service [%s/%s] is not found, it is not exported", namespace, serviceReference.Name)
+					continue
+				}
+				return nil, err
+			}
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
+
+// exportObject returns the YAML of an object without the fields maintained by the API server
+func exportObject(object interface{}, typeMeta meta_v1.TypeMeta) ([]byte, error) {
+	content, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	if content, err = withoutServerManagedFields(content); err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil, err
+	}
+	fields["apiVersion"] = typeMeta.APIVersion
+	fields["kind"] = typeMeta.Kind
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedAnnotation)
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+
+	content, err = json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(content)
+}
+
+// istioConfigListObjects returns the objects of an IstioConfigList per Istio config type
+func istioConfigListObjects(istioConfigList *models.IstioConfigList) map[string][]meta_v1.Object {
+	objects := map[string][]meta_v1.Object{}
+	add := func(resourceType string, object meta_v1.Object) {
+		objects[resourceType] = append(objects[resourceType], object)
+	}
+	for _, o := range istioConfigList.AuthorizationPolicies {
+		add(kubernetes.AuthorizationPolicies, o)
+	}
+	for _, o := range istioConfigList.DestinationRules {
+		add(kubernetes.DestinationRules, o)
+	}
+	for _, o := range istioConfigList.EnvoyFilters {
+		add(kubernetes.EnvoyFilters, o)
+	}
+	for _, o := range istioConfigList.Gateways {
+		add(kubernetes.Gateways, o)
+	}
+	for _, o := range istioConfigList.K8sGateways {
+		add(kubernetes.K8sGateways, o)
+	}
+	for _, o := range istioConfigList.K8sGRPCRoutes {
+		add(kubernetes.K8sGRPCRoutes, o)
+	}
+	for _, o := range istioConfigList.K8sHTTPRoutes {
+		add(kubernetes.K8sHTTPRoutes, o)
+	}
+	for _, o := range istioConfigList.K8sReferenceGrants {
+		add(kubernetes.K8sReferenceGrants, o)
+	}
+	for _, o := range istioConfigList.K8sTCPRoutes {
+		add(kubernetes.K8sTCPRoutes, o)
+	}
+	for _, o := range istioConfigList.K8sTLSRoutes {
+		add(kubernetes.K8sTLSRoutes, o)
+	}
+	for _, o := range istioConfigList.PeerAuthentications {
+		add(kubernetes.PeerAuthentications, o)
+	}
+	for _, o := range istioConfigList.RequestAuthentications {
+		add(kubernetes.RequestAuthentications, o)
+	}
+	for _, o := range istioConfigList.ServiceEntries {
+		add(kubernetes.ServiceEntries, o)
+	}
+	for _, o := range istioConfigList.Sidecars {
+		add(kubernetes.Sidecars, o)
+	}
+	for _, o := range istioConfigList.Telemetries {
+		add(kubernetes.Telemetries, o)
+	}
+	for _, o := range istioConfigList.VirtualServices {
+		add(kubernetes.VirtualServices, o)
+	}
+	for _, o := range istioConfigList.WasmPlugins {
+		add(kubernetes.WasmPlugins, o)
+	}
+	for _, o := range istioConfigList.WorkloadEntries {
+		add(kubernetes.WorkloadEntries, o)
+	}
+	for _, o := range istioConfigList.WorkloadGroups {
+		add(kubernetes.WorkloadGroups, o)
+	}
+	return objects
+}
+
+// sortedFileNames returns the names of the files, so archives list them in a stable order
+func sortedFileNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func tarArchive(files map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, name := range sortedFileNames(files) {
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name])), ModTime: now, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func zipArchive(files map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	now := time.Now()
+	for _, name := range sortedFileNames(files) {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
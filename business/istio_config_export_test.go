@@ -0,0 +1,125 @@
+package business
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func readTarGz(t *testing.T, archive []byte) map[string]string {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+	return files
+}
+
+func readZip(t *testing.T, archive []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func setupIstioConfigExport(t *testing.T) *Layer {
+	vs := data.AddHttpRoutesToVirtualService(data.CreateHttpRouteDestination("reviews", "v1", 100), data.CreateEmptyVirtualService("reviews", "test", []string{"reviews"}))
+	vs.ResourceVersion = "42"
+	vs.UID = "1234"
+	vs.ManagedFields = []meta_v1.ManagedFieldsEntry{{Manager: "kubectl"}}
+	vs.Annotations = map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"}
+	dr := data.CreateEmptyDestinationRule("test", "reviews", "reviews")
+	reviews := &core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "test", ResourceVersion: "7"},
+		Spec:       core_v1.ServiceSpec{ClusterIP: "10.0.0.1", ClusterIPs: []string{"10.0.0.1"}, Ports: []core_v1.ServicePort{{Name: "http", Port: 9080}}},
+	}
+	ratings := &core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings", Namespace: "test"}}
+
+	layer, _ := setupIstioCheckFixes(t, vs, dr, reviews, ratings)
+	return layer
+}
+
+func TestExportIstioConfig(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer := setupIstioConfigExport(t)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	archive, err := layer.IstioConfig.ExportIstioConfig(context.TODO(), cluster, "test", IstioConfigExportTar, true)
+	require.NoError(err)
+	files := readTarGz(t, archive)
+	require.Len(files, 4)
+
+	var kustomization map[string]interface{}
+	require.NoError(yaml.Unmarshal([]byte(files[KustomizationFile]), &kustomization))
+	assert.Equal("Kustomization", kustomization["kind"])
+	assert.Equal([]interface{}{"destinationrules/reviews.yaml", "services/reviews.yaml", "virtualservices/reviews.yaml"}, kustomization["resources"])
+
+	vs := files["virtualservices/reviews.yaml"]
+	assert.Contains(vs, "apiVersion: networking.istio.io/v1\n")
+	assert.Contains(vs, "kind: VirtualService\n")
+	assert.Contains(vs, "namespace: test\n")
+	assert.Contains(vs, "subset: v1")
+	for _, field := range []string{"resourceVersion", "uid", "managedFields", "status", "last-applied-configuration", "annotations"} {
+		assert.NotContains(vs, field)
+	}
+	assert.Contains(files["destinationrules/reviews.yaml"], "kind: DestinationRule\n")
+
+	// the unreferenced Service is not exported, the cluster IPs are left out
+	service := files["services/reviews.yaml"]
+	assert.Contains(service, "apiVersion: v1\n")
+	assert.Contains(service, "kind: Service\n")
+	assert.Contains(service, "port: 9080")
+	assert.NotContains(service, "10.0.0.1")
+	assert.NotContains(service, "resourceVersion")
+}
+
+func TestExportIstioConfigZip(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer := setupIstioConfigExport(t)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	archive, err := layer.IstioConfig.ExportIstioConfig(context.TODO(), cluster, "test", IstioConfigExportZip, false)
+	require.NoError(err)
+	files := readZip(t, archive)
+	assert.Len(files, 3)
+	assert.Contains(files, "virtualservices/reviews.yaml")
+	assert.NotContains(files, "services/reviews.yaml")
+
+	_, err = layer.IstioConfig.ExportIstioConfig(context.TODO(), cluster, "test", "rar", false)
+	assert.True(api_errors.IsBadRequest(err))
+}
@@ -0,0 +1,218 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/business/confighistory"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+	"github.com/kiali/kiali/util"
+)
+
+var errIstioConfigHistoryDisabled = errors.New("Istio config history is disabled")
+
+// istioConfigHistory returns the shared store of the Istio config history, nil when the history is disabled or the
+// store is not available
+func istioConfigHistory(conf *config.Config, client kubernetes.ClientInterface) confighistory.Store {
+	store, err := confighistory.DefaultStore(conf, client)
+	if err != nil {
+		log.Errorf("Istio config history is not available: %v", err)
+		return nil
+	}
+	return store
+}
+
+// GetIstioConfigHistory returns the revisions of an Istio object recorded by Kiali, most recent first
+func (in *IstioConfigService) GetIstioConfigHistory(ctx context.Context, cluster, namespace, resourceType, name string) ([]models.IstioConfigRevision, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "GetIstioConfigHistory",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("resourceType", resourceType),
+		observability.Attribute("name", name),
+	)
+	defer end()
+
+	if in.history == nil {
+		return nil, errIstioConfigHistoryDisabled
+	}
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetClusterNamespace(ctx, namespace, cluster); err != nil {
+		return nil, err
+	}
+	return in.history.List(ctx, confighistory.ObjectKey{Cluster: cluster, Namespace: namespace, ObjectType: resourceType, Name: name})
+}
+
+// RollbackIstioConfigDetail restores an Istio object to its state after the given revision. The object is patched,
+// created again when it was deleted since, or deleted when the revision deleted it. The changes are done with the
+// user's token and the rollback is recorded as a new revision, which is returned (nil when it could not be recorded).
+func (in *IstioConfigService) RollbackIstioConfigDetail(ctx context.Context, cluster, namespace, resourceType, name, revisionID string) (*models.IstioConfigRevision, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "RollbackIstioConfigDetail",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("resourceType", resourceType),
+		observability.Attribute("name", name),
+		observability.Attribute("revision", revisionID),
+	)
+	defer end()
+
+	if in.history == nil {
+		return nil, errIstioConfigHistoryDisabled
+	}
+	// The revisions are read with the Kiali service account, check the user's access to the namespace first
+	if _, err := in.businessLayer.Namespace.GetClusterNamespace(ctx, namespace, cluster); err != nil {
+		return nil, err
+	}
+	revision, err := in.history.Get(ctx, confighistory.ObjectKey{Cluster: cluster, Namespace: namespace, ObjectType: resourceType, Name: name}, revisionID)
+	if err != nil {
+		if errors.Is(err, confighistory.ErrNotFound) {
+			return nil, api_errors.NewNotFound(schema.GroupResource{Resource: "revisions"}, revisionID)
+		}
+		return nil, err
+	}
+
+	var before []byte
+	current, err := in.GetIstioConfigDetails(ctx, cluster, namespace, resourceType, name)
+	if err == nil {
+		if before, err = json.Marshal(istioConfigDetailsObject(current)); err != nil {
+			return nil, err
+		}
+	} else if !api_errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	var after interface{}
+	switch {
+	case isDeletedObject(revision.After) && before == nil:
+		return nil, api_errors.NewBadRequest("Object " + name + " is already deleted")
+	case isDeletedObject(revision.After):
+		if err := in.deleteIstioConfigDetail(ctx, cluster, namespace, resourceType, name); err != nil {
+			return nil, err
+		}
+	default:
+		target, err := withoutServerManagedFields(revision.After)
+		if err != nil {
+			return nil, err
+		}
+		var details models.IstioConfigDetails
+		if before == nil {
+			details, err = in.createIstioConfigDetail(ctx, cluster, namespace, resourceType, target, false)
+		} else {
+			var currentObject, patch []byte
+			if currentObject, err = withoutServerManagedFields(before); err != nil {
+				return nil, err
+			}
+			if patch, err = jsonpatch.CreateMergePatch(currentObject, target); err != nil {
+				return nil, err
+			}
+			details, err = in.updateIstioConfigDetail(ctx, cluster, namespace, resourceType, name, string(patch), false)
+		}
+		if err != nil {
+			return nil, err
+		}
+		after = istioConfigDetailsObject(details)
+	}
+
+	return in.recordIstioConfigRevision(ctx, cluster, namespace, resourceType, name, models.RevisionRollback, revisionID, before, after), nil
+}
+
+// istioConfigHistoryObject returns the current object to record as the state before a change, nil when the history
+// is disabled or the object is not found
+func (in *IstioConfigService) istioConfigHistoryObject(ctx context.Context, cluster, namespace, resourceType, name string) []byte {
+	if in.history == nil {
+		return nil
+	}
+	details, err := in.GetIstioConfigDetails(ctx, cluster, namespace, resourceType, name)
+	if err != nil {
+		log.Debugf("Istio config history: unable to get the object [%s/%s/%s]: %v", namespace, resourceType, name, err)
+		return nil
+	}
+	object, err := json.Marshal(istioConfigDetailsObject(details))
+	if err != nil {
+		return nil
+	}
+	return object
+}
+
+// recordIstioConfigRevision records a change done to an Istio object by the user of the context, when the history
+// is enabled. The change is already done, failures are only logged.
+func (in *IstioConfigService) recordIstioConfigRevision(ctx context.Context, cluster, namespace, resourceType, name, operation, rolledBackTo string, before []byte, after interface{}) *models.IstioConfigRevision {
+	if in.history == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	revision := models.IstioConfigRevision{
+		ID:           util.NewTimeSortableID(now),
+		Cluster:      cluster,
+		Namespace:    namespace,
+		ObjectType:   resourceType,
+		Name:         name,
+		Operation:    operation,
+		RolledBackTo: rolledBackTo,
+		User:         confighistory.UserFromContext(ctx),
+		Timestamp:    now,
+		Before:       before,
+	}
+	if after != nil {
+		if object, ok := after.(meta_v1.Object); ok && revision.Name == "" {
+			revision.Name = object.GetName()
+		}
+		object, err := json.Marshal(after)
+		if err != nil {
+			log.Errorf("Istio config history: unable to encode the object [%s/%s/%s]: %v", namespace, resourceType, revision.Name, err)
+			return nil
+		}
+		revision.After = object
+	}
+
+	if err := in.history.Record(ctx, revision); err != nil {
+		log.Errorf("Istio config history: unable to record the %s of [%s/%s/%s]: %v", operation, namespace, resourceType, revision.Name, err)
+		return nil
+	}
+	return &revision
+}
+
+func isDeletedObject(object json.RawMessage) bool {
+	return len(object) == 0 || strings.TrimSpace(string(object)) == "null"
+}
+
+// withoutServerManagedFields returns the JSON object without the fields maintained by the API server
+func withoutServerManagedFields(object []byte) ([]byte, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(object, &fields); err != nil {
+		return nil, err
+	}
+	for pointer := range serverManagedFields {
+		tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+		parent := fields
+		for _, token := range tokens[:len(tokens)-1] {
+			child, ok := parent[token].(map[string]interface{})
+			if !ok {
+				parent = nil
+				break
+			}
+			parent = child
+		}
+		if parent != nil {
+			delete(parent, tokens[len(tokens)-1])
+		}
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,146 @@
+package business
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/business/confighistory"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func TestIstioConfigHistory(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer, k8s := setupIstioCheckFixes(t)
+	store, err := confighistory.NewFileStore(t.TempDir(), 10)
+	require.NoError(err)
+	layer.IstioConfig.history = store
+	cluster := config.Get().KubernetesConfig.ClusterName
+	ctx := confighistory.WithUser(context.TODO(), "alice")
+
+	_, err = layer.IstioConfig.CreateIstioConfigDetail(ctx, cluster, "test", kubernetes.VirtualServices, []byte(`{"metadata":{"name":"reviews"},"spec":{"hosts":["reviews"]}}`))
+	require.NoError(err)
+	_, err = layer.IstioConfig.UpdateIstioConfigDetail(ctx, cluster, "test", kubernetes.VirtualServices, "reviews", `{"spec":{"gateways":["bookinfo-gateway"]}}`)
+	require.NoError(err)
+
+	revisions, err := layer.IstioConfig.GetIstioConfigHistory(context.TODO(), cluster, "test", kubernetes.VirtualServices, "reviews")
+	require.NoError(err)
+	require.Len(revisions, 2)
+	updated, created := revisions[0], revisions[1]
+	assert.Equal(models.RevisionUpdate, updated.Operation)
+	assert.Equal("alice", updated.User)
+	assert.NotContains(string(updated.Before), "bookinfo-gateway")
+	assert.Contains(string(updated.After), "bookinfo-gateway")
+	assert.Equal(models.RevisionCreate, created.Operation)
+	assert.Equal("reviews", created.Name)
+	assert.JSONEq("null", string(created.Before))
+
+	// the VirtualService is patched back to its created state
+	rollback, err := layer.IstioConfig.RollbackIstioConfigDetail(ctx, cluster, "test", kubernetes.VirtualServices, "reviews", created.ID)
+	require.NoError(err)
+	require.NotNil(rollback)
+	assert.Equal(models.RevisionRollback, rollback.Operation)
+	assert.Equal(created.ID, rollback.RolledBackTo)
+	vs, err := k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Empty(vs.Spec.Gateways)
+	assert.Equal([]string{"reviews"}, vs.Spec.Hosts)
+
+	// the deleted VirtualService is created again
+	require.NoError(layer.IstioConfig.DeleteIstioConfigDetail(ctx, cluster, "test", kubernetes.VirtualServices, "reviews"))
+	revisions, err = layer.IstioConfig.GetIstioConfigHistory(context.TODO(), cluster, "test", kubernetes.VirtualServices, "reviews")
+	require.NoError(err)
+	require.Len(revisions, 4)
+	deleted := revisions[0]
+	assert.Equal(models.RevisionDelete, deleted.Operation)
+	assert.JSONEq("null", string(deleted.After))
+
+	_, err = layer.IstioConfig.RollbackIstioConfigDetail(ctx, cluster, "test", kubernetes.VirtualServices, "reviews", updated.ID)
+	require.NoError(err)
+	vs, err = k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	require.NoError(err)
+	assert.Equal([]string{"bookinfo-gateway"}, vs.Spec.Gateways)
+
+	// the VirtualService is deleted again, only once
+	_, err = layer.IstioConfig.RollbackIstioConfigDetail(ctx, cluster, "test", kubernetes.VirtualServices, "reviews", deleted.ID)
+	require.NoError(err)
+	_, err = k8s.Istio().NetworkingV1().VirtualServices("test").Get(context.TODO(), "reviews", meta_v1.GetOptions{})
+	assert.True(api_errors.IsNotFound(err))
+	_, err = layer.IstioConfig.RollbackIstioConfigDetail(ctx, cluster, "test", kubernetes.VirtualServices, "reviews", deleted.ID)
+	assert.Error(err)
+
+	_, err = layer.IstioConfig.RollbackIstioConfigDetail(ctx, cluster, "test", kubernetes.VirtualServices, "reviews", "20240101T000000Z-00000000")
+	assert.True(api_errors.IsNotFound(err))
+}
+
+// getCountingStore counts the revisions read from the store
+type getCountingStore struct {
+	confighistory.Store
+	gets int
+}
+
+func (s *getCountingStore) Get(ctx context.Context, key confighistory.ObjectKey, id string) (*models.IstioConfigRevision, error) {
+	s.gets++
+	return s.Store.Get(ctx, key, id)
+}
+
+func TestRollbackIstioConfigNotAccessible(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer, _ := setupIstioCheckFixes(t)
+	fileStore, err := confighistory.NewFileStore(t.TempDir(), 10)
+	require.NoError(err)
+	store := &getCountingStore{Store: fileStore}
+	layer.IstioConfig.history = store
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	revision := models.IstioConfigRevision{
+		ID:         util.NewTimeSortableID(time.Now()),
+		Cluster:    cluster,
+		Namespace:  "restricted",
+		ObjectType: kubernetes.VirtualServices,
+		Name:       "reviews",
+		Operation:  models.RevisionCreate,
+		Timestamp:  time.Now(),
+	}
+	require.NoError(store.Record(context.TODO(), revision))
+
+	// the namespace is not accessible, the revision is never read
+	_, err = layer.IstioConfig.RollbackIstioConfigDetail(context.TODO(), cluster, "restricted", kubernetes.VirtualServices, "reviews", revision.ID)
+	assert.Error(err)
+	assert.Zero(store.gets)
+}
+
+func TestIstioConfigHistoryDisabled(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	layer, _ := setupIstioCheckFixes(t)
+	cluster := config.Get().KubernetesConfig.ClusterName
+
+	// the changes are still done
+	_, err := layer.IstioConfig.CreateIstioConfigDetail(context.TODO(), cluster, "test", kubernetes.VirtualServices, []byte(`{"metadata":{"name":"reviews"},"spec":{"hosts":["reviews"]}}`))
+	require.NoError(err)
+
+	_, err = layer.IstioConfig.GetIstioConfigHistory(context.TODO(), cluster, "test", kubernetes.VirtualServices, "reviews")
+	assert.Error(err)
+	_, err = layer.IstioConfig.RollbackIstioConfigDetail(context.TODO(), cluster, "test", kubernetes.VirtualServices, "reviews", "20240101T000000Z-00000000")
+	assert.Error(err)
+}
+
+func TestWithoutServerManagedFields(t *testing.T) {
+	object, err := withoutServerManagedFields([]byte(`{"kind":"VirtualService","metadata":{"name":"reviews","resourceVersion":"10","labels":{"app":"reviews"}},"spec":{"hosts":["reviews"]},"status":{}}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"metadata":{"name":"reviews","labels":{"app":"reviews"}},"spec":{"hosts":["reviews"]}}`, string(object))
+}
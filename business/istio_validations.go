@@ -15,6 +15,7 @@ import (
 	"github.com/kiali/kiali/business/references"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/observability"
@@ -24,6 +25,7 @@ import (
 type IstioValidationsService struct {
 	businessLayer *Layer
 	discovery     meshDiscovery
+	kialiCache    cache.KialiCache
 	userClients   map[string]kubernetes.ClientInterface
 }
 
@@ -122,7 +124,7 @@ func (in *IstioValidationsService) GetValidations(ctx context.Context, cluster,
 	objectCheckers := in.getAllObjectCheckers(istioConfigList, workloadsPerNamespace, mtlsDetails, rbacDetails, namespaces, registryServices, cluster, serviceAccounts)
 
 	// Get group validations for same kind istio objects
-	validations := runObjectCheckers(objectCheckers)
+	validations := RunObjectCheckers(objectCheckers)
 
 	if service != "" {
 		// in.businessLayer.Svc.GetServiceList(criteria) on fetchServices performs the validations on the service
@@ -139,23 +141,34 @@ func (in *IstioValidationsService) GetValidations(ctx context.Context, cluster,
 }
 
 func (in *IstioValidationsService) getAllObjectCheckers(istioConfigList models.IstioConfigList, workloadsPerNamespace map[string]models.WorkloadList, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace, registryServices []*kubernetes.RegistryService, cluster string, serviceAccounts map[string][]string) []ObjectChecker {
+	return AllObjectCheckers(istioConfigList, workloadsPerNamespace, mtlsDetails, rbacDetails, namespaces, registryServices, cluster, serviceAccounts, in.isPolicyAllowAny(), in.isGatewayToNamespace(), in.businessLayer.IstioConfig.GatewayAPIClasses(cluster), in.proxyVersions(cluster))
+}
+
+// AllObjectCheckers returns the checkers used to validate all the given Istio objects. The mesh settings are passed
+// in rather than read from the control plane, so it can also validate objects that don't come from a cluster.
+func AllObjectCheckers(istioConfigList models.IstioConfigList, workloadsPerNamespace map[string]models.WorkloadList, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace, registryServices []*kubernetes.RegistryService, cluster string, serviceAccounts map[string][]string, policyAllowAny, isGatewayToNamespace bool, gatewayClasses []config.GatewayAPIClass, proxyVersions []string) []ObjectChecker {
 	return []ObjectChecker{
-		checkers.NoServiceChecker{Namespaces: namespaces, IstioConfigList: &istioConfigList, WorkloadsPerNamespace: workloadsPerNamespace, AuthorizationDetails: &rbacDetails, RegistryServices: registryServices, PolicyAllowAny: in.isPolicyAllowAny(), Cluster: cluster},
+		checkers.NoServiceChecker{Namespaces: namespaces, IstioConfigList: &istioConfigList, WorkloadsPerNamespace: workloadsPerNamespace, AuthorizationDetails: &rbacDetails, RegistryServices: registryServices, PolicyAllowAny: policyAllowAny, Cluster: cluster},
 		checkers.VirtualServiceChecker{Namespaces: namespaces, VirtualServices: istioConfigList.VirtualServices, DestinationRules: istioConfigList.DestinationRules, Cluster: cluster},
 		checkers.DestinationRulesChecker{Namespaces: namespaces, DestinationRules: istioConfigList.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioConfigList.ServiceEntries, Cluster: cluster},
-		checkers.GatewayChecker{Gateways: istioConfigList.Gateways, WorkloadsPerNamespace: workloadsPerNamespace, IsGatewayToNamespace: in.isGatewayToNamespace(), Cluster: cluster},
+		checkers.GatewayChecker{Gateways: istioConfigList.Gateways, WorkloadsPerNamespace: workloadsPerNamespace, IsGatewayToNamespace: isGatewayToNamespace, Cluster: cluster},
 		checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadsPerNamespace: workloadsPerNamespace, Cluster: cluster},
 		checkers.ServiceEntryChecker{ServiceEntries: istioConfigList.ServiceEntries, Namespaces: namespaces, WorkloadEntries: istioConfigList.WorkloadEntries, Cluster: cluster},
-		checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, Namespaces: namespaces, ServiceEntries: istioConfigList.ServiceEntries, WorkloadsPerNamespace: workloadsPerNamespace, MtlsDetails: mtlsDetails, VirtualServices: istioConfigList.VirtualServices, RegistryServices: registryServices, PolicyAllowAny: in.isPolicyAllowAny(), Cluster: cluster, ServiceAccounts: serviceAccounts},
+		checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, Namespaces: namespaces, ServiceEntries: istioConfigList.ServiceEntries, WorkloadsPerNamespace: workloadsPerNamespace, MtlsDetails: mtlsDetails, VirtualServices: istioConfigList.VirtualServices, RegistryServices: registryServices, PolicyAllowAny: policyAllowAny, Cluster: cluster, ServiceAccounts: serviceAccounts},
 		checkers.SidecarChecker{Sidecars: istioConfigList.Sidecars, Namespaces: namespaces, WorkloadsPerNamespace: workloadsPerNamespace, ServiceEntries: istioConfigList.ServiceEntries, RegistryServices: registryServices, Cluster: cluster},
 		checkers.RequestAuthenticationChecker{RequestAuthentications: istioConfigList.RequestAuthentications, WorkloadsPerNamespace: workloadsPerNamespace, Cluster: cluster},
 		checkers.WorkloadChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, WorkloadsPerNamespace: workloadsPerNamespace, Cluster: cluster},
-		checkers.K8sGatewayChecker{K8sGateways: istioConfigList.K8sGateways, Cluster: cluster, GatewayClasses: in.businessLayer.IstioConfig.GatewayAPIClasses(cluster)},
+		checkers.K8sGatewayChecker{K8sGateways: istioConfigList.K8sGateways, Cluster: cluster, GatewayClasses: gatewayClasses},
 		checkers.K8sGRPCRouteChecker{K8sGRPCRoutes: istioConfigList.K8sGRPCRoutes, K8sGateways: istioConfigList.K8sGateways, K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces, RegistryServices: registryServices, Cluster: cluster},
 		checkers.K8sHTTPRouteChecker{K8sHTTPRoutes: istioConfigList.K8sHTTPRoutes, K8sGateways: istioConfigList.K8sGateways, K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces, RegistryServices: registryServices, Cluster: cluster},
 		checkers.K8sReferenceGrantChecker{K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces, Cluster: cluster},
+		checkers.K8sTCPRouteChecker{K8sTCPRoutes: istioConfigList.K8sTCPRoutes, K8sGateways: istioConfigList.K8sGateways, K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces, RegistryServices: registryServices, Cluster: cluster},
+		checkers.K8sTLSRouteChecker{K8sTLSRoutes: istioConfigList.K8sTLSRoutes, K8sGateways: istioConfigList.K8sGateways, K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces, RegistryServices: registryServices, Cluster: cluster},
 		checkers.WasmPluginChecker{WasmPlugins: istioConfigList.WasmPlugins, Namespaces: namespaces},
 		checkers.TelemetryChecker{Telemetries: istioConfigList.Telemetries, Namespaces: namespaces},
+		checkers.EnvoyFilterChecker{EnvoyFilters: istioConfigList.EnvoyFilters, WorkloadsPerNamespace: workloadsPerNamespace, ProxyVersions: proxyVersions, Cluster: cluster},
+		checkers.WorkloadGroupChecker{WorkloadGroups: istioConfigList.WorkloadGroups, ServiceEntries: istioConfigList.ServiceEntries, Cluster: cluster},
+		checkers.CustomRulesChecker{Cluster: cluster, IstioConfigList: istioConfigList, MTLSDetails: mtlsDetails, RBACDetails: rbacDetails, Rules: config.Get().KialiFeatureFlags.Validations.CustomRules},
 	}
 }
 
@@ -178,9 +191,6 @@ func (in *IstioValidationsService) GetIstioObjectValidations(ctx context.Context
 	var rbacDetails kubernetes.RBACDetails
 	var registryServices []*kubernetes.RegistryService
 	var serviceAccounts map[string][]string
-	var err error
-	var objectCheckers []ObjectChecker
-	var referenceChecker ReferenceChecker
 	istioReferences := models.IstioReferencesMap{}
 
 	istioApiEnabled := config.Get().ExternalServices.Istio.IstioAPIEnabled
@@ -215,6 +225,55 @@ func (in *IstioValidationsService) GetIstioObjectValidations(ctx context.Context
 
 	wg.Wait()
 
+	objectCheckers, referenceChecker, err := in.objectTypeCheckers(cluster, namespace, objectType, validationInputs{
+		istioConfigList:       istioConfigList,
+		mtlsDetails:           mtlsDetails,
+		namespaces:            namespaces,
+		rbacDetails:           rbacDetails,
+		registryServices:      registryServices,
+		serviceAccounts:       serviceAccounts,
+		workloadsPerNamespace: workloadsPerNamespace,
+	})
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			return nil, istioReferences, err
+		}
+	}
+
+	if referenceChecker != nil {
+		istioReferences = runObjectReferenceChecker(referenceChecker)
+	}
+
+	if objectCheckers == nil {
+		return models.IstioValidations{}, istioReferences, err
+	}
+
+	return RunObjectCheckers(objectCheckers).FilterByKey(models.ObjectTypeSingular[objectType], object), istioReferences, nil
+}
+
+// validationInputs holds everything the Istio objects of a cluster are validated against
+type validationInputs struct {
+	istioConfigList       models.IstioConfigList
+	mtlsDetails           kubernetes.MTLSDetails
+	namespaces            models.Namespaces
+	rbacDetails           kubernetes.RBACDetails
+	registryServices      []*kubernetes.RegistryService
+	serviceAccounts       map[string][]string
+	workloadsPerNamespace map[string]models.WorkloadList
+}
+
+// objectTypeCheckers returns the checkers validating the objects of the given type, and the checker of their
+// references. The namespace is the one of the validated object.
+func (in *IstioValidationsService) objectTypeCheckers(cluster, namespace, objectType string, inputs validationInputs) ([]ObjectChecker, ReferenceChecker, error) {
+	istioConfigList, mtlsDetails, namespaces, rbacDetails := inputs.istioConfigList, inputs.mtlsDetails, inputs.namespaces, inputs.rbacDetails
+	registryServices, serviceAccounts, workloadsPerNamespace := inputs.registryServices, inputs.serviceAccounts, inputs.workloadsPerNamespace
+
+	var err error
+	var objectCheckers []ObjectChecker
+	var referenceChecker ReferenceChecker
+
 	noServiceChecker := checkers.NoServiceChecker{Cluster: cluster, Namespaces: namespaces, IstioConfigList: &istioConfigList, WorkloadsPerNamespace: workloadsPerNamespace, AuthorizationDetails: &rbacDetails, RegistryServices: registryServices, PolicyAllowAny: in.isPolicyAllowAny()}
 
 	switch objectType {
@@ -258,13 +317,16 @@ func (in *IstioValidationsService) GetIstioObjectValidations(ctx context.Context
 	case kubernetes.WorkloadEntries:
 		// Validation on WorkloadEntries are not yet in place
 	case kubernetes.WorkloadGroups:
-		// Validation on WorkloadGroups are not yet in place
+		objectCheckers = []ObjectChecker{
+			checkers.WorkloadGroupChecker{Cluster: cluster, WorkloadGroups: istioConfigList.WorkloadGroups, ServiceEntries: istioConfigList.ServiceEntries},
+		}
 	case kubernetes.RequestAuthentications:
-		// Validation on RequestAuthentications are not yet in place
 		requestAuthnChecker := checkers.RequestAuthenticationChecker{Cluster: cluster, RequestAuthentications: istioConfigList.RequestAuthentications, WorkloadsPerNamespace: workloadsPerNamespace}
 		objectCheckers = []ObjectChecker{requestAuthnChecker}
 	case kubernetes.EnvoyFilters:
-		// Validation on EnvoyFilters are not yet in place
+		objectCheckers = []ObjectChecker{
+			checkers.EnvoyFilterChecker{Cluster: cluster, EnvoyFilters: istioConfigList.EnvoyFilters, WorkloadsPerNamespace: workloadsPerNamespace, ProxyVersions: in.proxyVersions(cluster)},
+		}
 	case kubernetes.WasmPlugins:
 		// Validation on WasmPlugins is not expected
 	case kubernetes.Telemetries:
@@ -288,32 +350,27 @@ func (in *IstioValidationsService) GetIstioObjectValidations(ctx context.Context
 			checkers.K8sReferenceGrantChecker{Cluster: cluster, K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces},
 		}
 	case kubernetes.K8sTCPRoutes:
-		// Validation on K8sTCPRoutes is not expected
+		objectCheckers = []ObjectChecker{
+			checkers.K8sTCPRouteChecker{Cluster: cluster, K8sTCPRoutes: istioConfigList.K8sTCPRoutes, K8sGateways: istioConfigList.K8sGateways, K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces, RegistryServices: registryServices},
+		}
 	case kubernetes.K8sTLSRoutes:
-		// Validation on K8sTLSRoutes is not expected
+		objectCheckers = []ObjectChecker{
+			checkers.K8sTLSRouteChecker{Cluster: cluster, K8sTLSRoutes: istioConfigList.K8sTLSRoutes, K8sGateways: istioConfigList.K8sGateways, K8sReferenceGrants: istioConfigList.K8sReferenceGrants, Namespaces: namespaces, RegistryServices: registryServices},
+		}
 	default:
 		err = fmt.Errorf("object type not found: %v", objectType)
 	}
 
-	close(errChan)
-	for e := range errChan {
-		if e != nil { // Check that default value wasn't returned
-			return nil, istioReferences, err
-		}
+	// The custom rules apply to any kind
+	if customRules := config.Get().KialiFeatureFlags.Validations.CustomRules; err == nil && len(customRules) > 0 {
+		objectCheckers = append(objectCheckers, checkers.CustomRulesChecker{Cluster: cluster, IstioConfigList: istioConfigList, MTLSDetails: mtlsDetails, RBACDetails: rbacDetails, Rules: customRules})
 	}
 
-	if referenceChecker != nil {
-		istioReferences = runObjectReferenceChecker(referenceChecker)
-	}
-
-	if objectCheckers == nil {
-		return models.IstioValidations{}, istioReferences, err
-	}
-
-	return runObjectCheckers(objectCheckers).FilterByKey(models.ObjectTypeSingular[objectType], object), istioReferences, nil
+	return objectCheckers, referenceChecker, err
 }
 
-func runObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
+// RunObjectCheckers runs the checkers and merges their validations, ignored checks are removed
+func RunObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
 	objectTypeValidations := models.IstioValidations{}
 
 	// Run checks for each IstioObject type
@@ -471,17 +528,23 @@ func (in *IstioValidationsService) fetchIstioConfigList(ctx context.Context, rVa
 	criteria := IstioConfigCriteria{
 		IncludeGateways:               true,
 		IncludeDestinationRules:       true,
+		IncludeEnvoyFilters:           true,
 		IncludeServiceEntries:         true,
 		IncludeVirtualServices:        true,
 		IncludeSidecars:               true,
 		IncludeRequestAuthentications: true,
 		IncludeWorkloadEntries:        true,
+		IncludeWorkloadGroups:         true,
 		IncludeAuthorizationPolicies:  true,
 		IncludePeerAuthentications:    true,
 		IncludeK8sHTTPRoutes:          true,
 		IncludeK8sGRPCRoutes:          true,
 		IncludeK8sGateways:            true,
 		IncludeK8sReferenceGrants:     true,
+		IncludeK8sTCPRoutes:           true,
+		IncludeK8sTLSRoutes:           true,
+		IncludeTelemetry:              true,
+		IncludeWasmPlugins:            true,
 	}
 	istioConfigMap, err := in.businessLayer.IstioConfig.GetIstioConfigMap(ctx, meta_v1.NamespaceAll, criteria)
 	if err != nil {
@@ -518,6 +581,12 @@ func (in *IstioValidationsService) fetchIstioConfigList(ctx context.Context, rVa
 	// All K8sReferenceGrants
 	rValue.K8sReferenceGrants = append(rValue.K8sReferenceGrants, istioConfigList.K8sReferenceGrants...)
 
+	// All K8sTCPRoutes
+	rValue.K8sTCPRoutes = append(rValue.K8sTCPRoutes, istioConfigList.K8sTCPRoutes...)
+
+	// All K8sTLSRoutes
+	rValue.K8sTLSRoutes = append(rValue.K8sTLSRoutes, istioConfigList.K8sTLSRoutes...)
+
 	// All Sidecars
 	rValue.Sidecars = append(rValue.Sidecars, istioConfigList.Sidecars...)
 
@@ -527,6 +596,16 @@ func (in *IstioValidationsService) fetchIstioConfigList(ctx context.Context, rVa
 	// All WorkloadEntries
 	rValue.WorkloadEntries = append(rValue.WorkloadEntries, istioConfigList.WorkloadEntries...)
 
+	// All WorkloadGroups
+	rValue.WorkloadGroups = append(rValue.WorkloadGroups, istioConfigList.WorkloadGroups...)
+
+	// All EnvoyFilters
+	rValue.EnvoyFilters = append(rValue.EnvoyFilters, istioConfigList.EnvoyFilters...)
+
+	// All Telemetries and WasmPlugins, only validated by the custom rules
+	rValue.Telemetries = append(rValue.Telemetries, istioConfigList.Telemetries...)
+	rValue.WasmPlugins = append(rValue.WasmPlugins, istioConfigList.WasmPlugins...)
+
 	in.filterPeerAuths(namespace, mtlsDetails, istioConfigList.PeerAuthentications)
 
 	in.filterAuthPolicies(namespace, rbacDetails, istioConfigList.AuthorizationPolicies)
@@ -664,6 +743,29 @@ func (in *IstioValidationsService) isPolicyAllowAny() bool {
 	return false
 }
 
+// proxyVersions returns the versions of the control planes managing the cluster, the proxies are expected to run them
+func (in *IstioValidationsService) proxyVersions(cluster string) []string {
+	mesh, err := in.discovery.Mesh(context.TODO())
+	if err != nil {
+		log.Errorf("Error getting mesh config: %s", err)
+		return nil
+	}
+
+	versions := []string{}
+	for _, controlPlane := range mesh.ControlPlanes {
+		if controlPlane.Version == nil || controlPlane.Version.Version == "" {
+			continue
+		}
+		for _, managedCluster := range controlPlane.ManagedClusters {
+			if managedCluster.Name == cluster {
+				versions = append(versions, controlPlane.Version.Version)
+				break
+			}
+		}
+	}
+	return versions
+}
+
 func checkExportTo(exportToNs string, namespace string, ownNs string, allNamespaces models.Namespaces) bool {
 	// check if namespaces where it is exported to, or if it is exported to all namespaces, or export to own namespace
 	// when exported to non-existing namespace, consider it to show validation error
@@ -0,0 +1,150 @@
+package business
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// validationsSample holds the counts of a background validation
+type validationsSample struct {
+	timestamp      time.Time
+	checks         map[internalmetrics.ValidationCheckLabels]int
+	invalidObjects map[internalmetrics.ValidationObjectLabels]int
+}
+
+// validationsHistory keeps the samples of the background validations, oldest first, and the validations of the
+// last run. It is only kept in memory: it starts empty when Kiali restarts and each replica validates and keeps
+// its own history, the trend is not persisted nor shared between pods.
+type validationsHistory struct {
+	lock    sync.RWMutex
+	latest  map[string]models.IstioValidations
+	samples []validationsSample
+}
+
+var backgroundValidations = &validationsHistory{}
+
+// RunBackgroundValidations validates the Istio config of all the clusters every interval until the context is done.
+// The check counts are exported as internal metrics and kept for the retention period, see GetValidationsTrend.
+func RunBackgroundValidations(ctx context.Context, conf *config.Config, getBusiness func() *Layer) {
+	backgroundConf := conf.KialiFeatureFlags.Validations.Background
+	// the durations are validated with the config
+	interval, _ := time.ParseDuration(backgroundConf.Interval)
+	retention, _ := time.ParseDuration(backgroundConf.Retention)
+	if interval <= 0 {
+		return
+	}
+	log.Infof("Background validations: interval [%v], retention [%v]", interval, retention)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		validations := map[string]models.IstioValidations{}
+		layer := getBusiness()
+		clusters := maps.Keys(layer.Validations.userClients)
+		sort.Strings(clusters)
+		for _, cluster := range clusters {
+			clusterValidations, err := layer.Validations.GetValidations(ctx, cluster, "", "", "")
+			if err != nil {
+				log.Errorf("Background validations: unable to validate cluster [%s]: %v", cluster, err)
+				continue
+			}
+			validations[cluster] = clusterValidations
+		}
+		backgroundValidations.record(time.Now(), validations, retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record adds a sample with the counts of the validations per cluster, updates the metrics and removes the samples
+// older than the retention
+func (h *validationsHistory) record(now time.Time, validations map[string]models.IstioValidations, retention time.Duration) {
+	sample := validationsSample{
+		timestamp:      now,
+		checks:         map[internalmetrics.ValidationCheckLabels]int{},
+		invalidObjects: map[internalmetrics.ValidationObjectLabels]int{},
+	}
+	for cluster, clusterValidations := range validations {
+		for key, validation := range clusterValidations {
+			for _, check := range validation.Checks {
+				sample.checks[internalmetrics.ValidationCheckLabels{Cluster: cluster, Namespace: key.Namespace, Kind: key.ObjectType, Severity: string(check.Severity), Code: check.Code}]++
+			}
+			if !validation.Valid {
+				sample.invalidObjects[internalmetrics.ValidationObjectLabels{Cluster: cluster, Namespace: key.Namespace, Kind: key.ObjectType}]++
+			}
+		}
+	}
+	internalmetrics.SetValidationCounts(sample.checks, sample.invalidObjects)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.latest = validations
+	h.samples = append(h.samples, sample)
+	oldest := now.Add(-retention)
+	expired := 0
+	for expired < len(h.samples) && h.samples[expired].timestamp.Before(oldest) {
+		expired++
+	}
+	h.samples = slices.Clone(h.samples[expired:])
+}
+
+// latestValidations returns the validations of the cluster found by the last background validation, if any
+func (h *validationsHistory) latestValidations(cluster string) (models.IstioValidations, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	validations, found := h.latest[cluster]
+	return validations, found
+}
+
+// GetValidationsTrend returns the counts of the background validations of the given cluster and namespaces
+// since the given time, oldest first. It is empty when the background validations are disabled. The samples are the
+// ones recorded by this Kiali pod since it started.
+func (in *IstioValidationsService) GetValidationsTrend(cluster string, namespaces []string, since time.Time) models.ValidationsTrend {
+	return backgroundValidations.trend(cluster, namespaces, since)
+}
+
+func (h *validationsHistory) trend(cluster string, namespaces []string, since time.Time) models.ValidationsTrend {
+	trend := models.ValidationsTrend{Cluster: cluster, Namespaces: namespaces, Samples: []models.ValidationsTrendSample{}}
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	for _, sample := range h.samples {
+		if sample.timestamp.Before(since) {
+			continue
+		}
+		trendSample := models.ValidationsTrendSample{Timestamp: sample.timestamp.Unix(), Codes: map[string]int{}}
+		for l, count := range sample.checks {
+			if l.Cluster != cluster || !slices.Contains(namespaces, l.Namespace) {
+				continue
+			}
+			switch models.SeverityLevel(l.Severity) {
+			case models.ErrorSeverity:
+				trendSample.Errors += count
+			case models.WarningSeverity:
+				trendSample.Warnings += count
+			}
+			trendSample.Codes[l.Code] += count
+		}
+		for l, count := range sample.invalidObjects {
+			if l.Cluster == cluster && slices.Contains(namespaces, l.Namespace) {
+				trendSample.InvalidObjects += count
+			}
+		}
+		trend.Samples = append(trend.Samples, trendSample)
+	}
+	return trend
+}
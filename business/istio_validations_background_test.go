@@ -0,0 +1,90 @@
+package business
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+func fakeClusterValidations(cluster string) models.IstioValidations {
+	return models.IstioValidations{
+		models.BuildKey("virtualservice", "reviews", "bookinfo", cluster): &models.IstioValidation{
+			Valid: false,
+			Checks: []*models.IstioCheck{
+				{Code: "KIA1101", Severity: models.ErrorSeverity},
+				{Code: "KIA1105", Severity: models.WarningSeverity},
+			},
+		},
+		models.BuildKey("destinationrule", "reviews", "bookinfo", cluster): &models.IstioValidation{
+			Valid: true,
+			Checks: []*models.IstioCheck{
+				{Code: "KIA0203", Severity: models.WarningSeverity},
+			},
+		},
+		models.BuildKey("destinationrule", "details", "other", cluster): &models.IstioValidation{
+			Valid: false,
+			Checks: []*models.IstioCheck{
+				{Code: "KIA0202", Severity: models.ErrorSeverity},
+			},
+		},
+	}
+}
+
+func TestValidationsHistory(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	history := &validationsHistory{}
+	start := time.Now()
+	history.record(start, map[string]models.IstioValidations{"east": fakeClusterValidations("east")}, time.Hour)
+	assert.Equal(1.0, testutil.ToFloat64(internalmetrics.Metrics.ValidationChecks.WithLabelValues("east", "bookinfo", "virtualservice", "error", "KIA1101")))
+	assert.Equal(1.0, testutil.ToFloat64(internalmetrics.Metrics.ValidationInvalidObjects.WithLabelValues("east", "bookinfo", "virtualservice")))
+
+	history.record(start.Add(time.Minute), map[string]models.IstioValidations{"east": {}}, time.Hour)
+	assert.Equal(0, testutil.CollectAndCount(internalmetrics.Metrics.ValidationChecks), "the metrics reflect the last validation")
+
+	trend := history.trend("east", []string{"bookinfo"}, time.Time{})
+	require.Len(trend.Samples, 2)
+	assert.Equal(start.Unix(), trend.Samples[0].Timestamp)
+	assert.Equal(1, trend.Samples[0].Errors)
+	assert.Equal(2, trend.Samples[0].Warnings)
+	assert.Equal(1, trend.Samples[0].InvalidObjects)
+	assert.Equal(map[string]int{"KIA1101": 1, "KIA1105": 1, "KIA0203": 1}, trend.Samples[0].Codes)
+	assert.Zero(trend.Samples[1].Errors)
+
+	assert.Len(history.trend("east", []string{"bookinfo"}, start.Add(time.Second)).Samples, 1)
+	assert.Zero(history.trend("west", []string{"bookinfo"}, time.Time{}).Samples[0].Errors)
+
+	// the samples older than the retention are removed
+	history.record(start.Add(2*time.Hour), map[string]models.IstioValidations{}, time.Hour)
+	assert.Len(history.trend("east", []string{"bookinfo"}, time.Time{}).Samples, 1)
+}
+
+func TestRunBackgroundValidations(t *testing.T) {
+	require := require.New(t)
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.Validations.Background.Enabled = true
+	config.Set(conf)
+
+	vs := mockCombinedValidationService(t, fakeIstioConfigList(), []string{"product.test.svc.cluster.local", "customer.test.svc.cluster.local"})
+	original := backgroundValidations
+	backgroundValidations = &validationsHistory{}
+	t.Cleanup(func() { backgroundValidations = original })
+
+	// the config is validated once before the context is checked
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	RunBackgroundValidations(ctx, conf, func() *Layer { return vs.businessLayer })
+
+	trend := vs.GetValidationsTrend(conf.KubernetesConfig.ClusterName, []string{"test"}, time.Time{})
+	require.Len(trend.Samples, 1)
+	require.NotZero(trend.Samples[0].Errors + trend.Samples[0].Warnings)
+}
@@ -0,0 +1,126 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/business/checkers/crosscluster"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+)
+
+// GetMeshValidations validates the config of the clusters of the mesh against each other, which the validations of
+// a single cluster can't see: the DestinationRules and PeerAuthentications differing between clusters, the trust
+// domains and the services missing in the clusters routing to them. Only the clusters and namespaces accessible by
+// the user are validated.
+func (in *IstioValidationsService) GetMeshValidations(ctx context.Context) (models.MeshValidations, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "GetMeshValidations",
+		observability.Attribute("package", "business"),
+	)
+	defer end()
+
+	criteria := IstioConfigCriteria{
+		IncludeDestinationRules:    true,
+		IncludePeerAuthentications: true,
+		IncludeVirtualServices:     true,
+	}
+	istioDomain := config.Get().ExternalServices.Istio.IstioIdentityDomain
+
+	clusters := []crosscluster.Cluster{}
+	for _, kubeCluster := range in.kialiCache.GetClusters() {
+		if _, ok := in.userClients[kubeCluster.Name]; !ok {
+			continue
+		}
+		namespaces, err := in.businessLayer.Namespace.GetClusterNamespaces(ctx, kubeCluster.Name)
+		if err != nil {
+			return models.MeshValidations{}, err
+		}
+		cluster := crosscluster.Cluster{Name: kubeCluster.Name, Namespaces: make([]string, 0, len(namespaces)), Services: []string{}}
+		for _, ns := range namespaces {
+			cluster.Namespaces = append(cluster.Namespaces, ns.Name)
+		}
+
+		istioConfigList, err := in.businessLayer.IstioConfig.GetIstioConfigList(ctx, kubeCluster.Name, criteria)
+		if err != nil {
+			return models.MeshValidations{}, err
+		}
+		cluster.IstioConfigList = *istioConfigList
+
+		kubeCache, err := in.kialiCache.GetKubeCache(kubeCluster.Name)
+		if err != nil {
+			return models.MeshValidations{}, err
+		}
+		services, err := kubeCache.GetServices(meta_v1.NamespaceAll, "")
+		if err != nil {
+			return models.MeshValidations{}, err
+		}
+		for _, svc := range services {
+			if slices.Contains(cluster.Namespaces, svc.Namespace) {
+				cluster.Services = append(cluster.Services, fmt.Sprintf("%s.%s.%s", svc.Name, svc.Namespace, istioDomain))
+			}
+		}
+
+		cluster.TrustDomain, cluster.TrustDomainAliases = in.trustDomain(ctx, kubeCluster.Name)
+		clusters = append(clusters, cluster)
+	}
+
+	return meshValidations(clusters), nil
+}
+
+func meshValidations(clusters []crosscluster.Cluster) models.MeshValidations {
+	objectCheckers := []ObjectChecker{
+		crosscluster.DestinationRuleTrafficPolicyChecker{Clusters: clusters},
+		crosscluster.PeerAuthenticationModeChecker{Clusters: clusters},
+		crosscluster.ServicePresenceChecker{Clusters: clusters},
+	}
+	validations := RunObjectCheckers(objectCheckers)
+
+	result := models.MeshValidations{Clusters: []models.ClusterValidation{}, Validations: []*models.IstioValidation{}}
+	for _, cluster := range clusters {
+		clusterValidation := models.ClusterValidation{Cluster: cluster.Name, TrustDomain: cluster.TrustDomain}
+		clusterValidation.Checks, _ = crosscluster.TrustDomainChecker{Cluster: cluster, Clusters: clusters}.Check()
+		result.Clusters = append(result.Clusters, clusterValidation)
+	}
+	for _, validation := range validations {
+		result.Validations = append(result.Validations, validation)
+	}
+	sort.Slice(result.Validations, func(i, j int) bool {
+		vi, vj := result.Validations[i], result.Validations[j]
+		if vi.Cluster != vj.Cluster {
+			return vi.Cluster < vj.Cluster
+		}
+		if vi.ObjectType != vj.ObjectType {
+			return vi.ObjectType < vj.ObjectType
+		}
+		if vi.Namespace != vj.Namespace {
+			return vi.Namespace < vj.Namespace
+		}
+		return vi.Name < vj.Name
+	})
+	return result
+}
+
+// trustDomain returns the trust domain and its aliases of the control plane managing the cluster
+func (in *IstioValidationsService) trustDomain(ctx context.Context, cluster string) (string, []string) {
+	mesh, err := in.discovery.Mesh(ctx)
+	if err != nil {
+		log.Errorf("Error getting mesh config: %s", err)
+		return defaultTrustDomain, nil
+	}
+
+	for _, controlPlane := range mesh.ControlPlanes {
+		for _, managedCluster := range controlPlane.ManagedClusters {
+			if managedCluster.Name == cluster && controlPlane.Config.TrustDomain != "" {
+				return controlPlane.Config.TrustDomain, controlPlane.Config.TrustDomainAliases
+			}
+		}
+	}
+	return defaultTrustDomain, nil
+}
@@ -0,0 +1,79 @@
+package business
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/business/checkers/crosscluster"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestMeshValidations(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	config.Set(config.NewConfig())
+
+	dr := func(lb api_networking_v1.LoadBalancerSettings_SimpleLB) *networking_v1.DestinationRule {
+		dr := &networking_v1.DestinationRule{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}}
+		dr.Spec.Host = "reviews"
+		dr.Spec.TrafficPolicy = &api_networking_v1.TrafficPolicy{
+			LoadBalancer: &api_networking_v1.LoadBalancerSettings{LbPolicy: &api_networking_v1.LoadBalancerSettings_Simple{Simple: lb}},
+		}
+		return dr
+	}
+	clusters := []crosscluster.Cluster{
+		{Name: "west", Namespaces: []string{"bookinfo"}, TrustDomain: "west.example.com", IstioConfigList: models.IstioConfigList{
+			DestinationRules: []*networking_v1.DestinationRule{dr(api_networking_v1.LoadBalancerSettings_LEAST_REQUEST)},
+		}},
+		{Name: "east", Namespaces: []string{"bookinfo"}, TrustDomain: "cluster.local", IstioConfigList: models.IstioConfigList{
+			DestinationRules: []*networking_v1.DestinationRule{dr(api_networking_v1.LoadBalancerSettings_ROUND_ROBIN)},
+		}},
+	}
+
+	result := meshValidations(clusters)
+	require.Len(result.Clusters, 2)
+	assert.Equal("west", result.Clusters[0].Cluster)
+	assert.Equal("west.example.com", result.Clusters[0].TrustDomain)
+	require.Len(result.Clusters[0].Checks, 1)
+	assert.Equal("KIA2004", result.Clusters[0].Checks[0].Code)
+	require.Len(result.Validations, 2)
+	assert.Equal("east", result.Validations[0].Cluster)
+	assert.Equal("west", result.Validations[1].Cluster)
+	assert.Equal("KIA2001", result.Validations[1].Checks[0].Code)
+}
+
+func TestGetMeshValidations(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	k8s := kubetest.NewFakeK8sClient(
+		&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		&core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}},
+		fakeReviewsVirtualService(),
+		fakeReviewsDestinationRule(),
+	)
+	cache := SetupBusinessLayer(t, k8s, *conf)
+	cache.SetClusters([]models.KubeCluster{{Name: conf.KubernetesConfig.ClusterName}})
+	clients := map[string]kubernetes.ClientInterface{conf.KubernetesConfig.ClusterName: k8s}
+	layer := NewWithBackends(clients, clients, new(prometheustest.PromClientMock), nil)
+
+	result, err := layer.Validations.GetMeshValidations(context.TODO())
+	require.NoError(err)
+	require.Len(result.Clusters, 1)
+	assert.Equal(conf.KubernetesConfig.ClusterName, result.Clusters[0].Cluster)
+	assert.Empty(result.Clusters[0].Checks)
+	assert.Empty(result.Validations)
+}
@@ -0,0 +1,323 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	extentions_v1alpha1 "istio.io/client-go/pkg/apis/extensions/v1alpha1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	networking_v1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+	telemetry_v1 "istio.io/client-go/pkg/apis/telemetry/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	k8s_networking_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	k8s_networking_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+)
+
+// GetProposedValidations validates the Istio config of a cluster as it is now and as it would be after the given
+// change: the object is created or replaced, or deleted when remove is true. The object is the JSON of an object of
+// the given resource type, namespace is used when the object doesn't set one. The cached config is never modified.
+// Only the changed object and the objects it references, before or after the change, are validated. The current
+// validations come from the last background validation when it is enabled.
+func (in *IstioValidationsService) GetProposedValidations(ctx context.Context, cluster, namespace, resourceType string, object []byte, remove bool) (current models.IstioValidations, proposed models.IstioValidations, err error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "GetProposedValidations",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", namespace),
+		observability.Attribute("resourceType", resourceType),
+	)
+	defer end()
+
+	inputs := validationInputs{}
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	wg.Add(3)
+	go in.fetchIstioConfigList(ctx, &inputs.istioConfigList, &inputs.mtlsDetails, &inputs.rbacDetails, cluster, "", errChan, &wg)
+	go in.fetchAllWorkloads(ctx, &inputs.workloadsPerNamespace, cluster, &inputs.namespaces, errChan, &wg)
+	go in.fetchServiceAccounts(ctx, &inputs.serviceAccounts, errChan, &wg)
+	if err := in.fetchNonLocalmTLSConfigs(&inputs.mtlsDetails, cluster); err != nil {
+		return nil, nil, err
+	}
+
+	if config.Get().ExternalServices.Istio.IstioAPIEnabled {
+		criteria := RegistryCriteria{AllNamespaces: true, Cluster: cluster}
+		inputs.registryServices = in.businessLayer.RegistryStatus.GetRegistryServices(criteria)
+	}
+
+	wg.Wait()
+	close(errChan)
+	for e := range errChan {
+		if e != nil {
+			return nil, nil, e
+		}
+	}
+
+	// the proposed config shares the cached objects, only its slices are rebuilt
+	proposedInputs := inputs
+	changed, err := proposeObject(&proposedInputs.istioConfigList, &proposedInputs.mtlsDetails, &proposedInputs.rbacDetails, resourceType, namespace, object, remove)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys, err := in.proposedValidationKeys(cluster, resourceType, changed, inputs, proposedInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if background, found := backgroundValidations.latestValidations(cluster); found {
+		current = models.IstioValidations{}
+		for key := range keys {
+			if validation, ok := background[key]; ok {
+				current[key] = validation
+			}
+		}
+	} else {
+		current = in.validateKeys(cluster, keys, inputs)
+	}
+	return current, in.validateKeys(cluster, keys, proposedInputs), nil
+}
+
+// proposedValidationKeys returns the keys of the changed object and of the objects it references, before or after
+// the change
+func (in *IstioValidationsService) proposedValidationKeys(cluster, resourceType string, changed meta_v1.Object, inputs ...validationInputs) (map[models.IstioValidationKey]bool, error) {
+	objectType := models.ObjectTypeSingular[resourceType]
+	keys := map[models.IstioValidationKey]bool{
+		{ObjectType: objectType, Name: changed.GetName(), Namespace: changed.GetNamespace(), Cluster: cluster}: true,
+	}
+	for _, i := range inputs {
+		_, referenceChecker, err := in.objectTypeCheckers(cluster, changed.GetNamespace(), resourceType, i)
+		if err != nil {
+			return nil, err
+		}
+		if referenceChecker == nil {
+			continue
+		}
+		references, found := runObjectReferenceChecker(referenceChecker)[models.IstioReferenceKey{ObjectType: objectType, Name: changed.GetName(), Namespace: changed.GetNamespace()}]
+		if !found {
+			continue
+		}
+		for _, ref := range references.ObjectReferences {
+			keys[models.IstioValidationKey{ObjectType: ref.ObjectType, Name: ref.Name, Namespace: ref.Namespace, Cluster: cluster}] = true
+		}
+	}
+	return keys, nil
+}
+
+// validateKeys returns the validations of the objects with the given keys, only the checkers of their types are run.
+// The objects of types without checkers are skipped.
+func (in *IstioValidationsService) validateKeys(cluster string, keys map[models.IstioValidationKey]bool, inputs validationInputs) models.IstioValidations {
+	validations := models.IstioValidations{}
+	validatedTypes := map[string]bool{}
+	for key := range keys {
+		if validatedTypes[key.ObjectType] {
+			continue
+		}
+		validatedTypes[key.ObjectType] = true
+
+		resourceType := ""
+		for plural, singular := range models.ObjectTypeSingular {
+			if singular == key.ObjectType {
+				resourceType = plural
+				break
+			}
+		}
+		objectCheckers, _, err := in.objectTypeCheckers(cluster, key.Namespace, resourceType, inputs)
+		if err != nil {
+			continue
+		}
+		// the checkers of a type can validate other types too, i.e. the NoServiceChecker, those are partial
+		for k, validation := range RunObjectCheckers(objectCheckers) {
+			if keys[k] && k.ObjectType == key.ObjectType {
+				validations[k] = validation
+			}
+		}
+	}
+	return validations
+}
+
+// proposeObject decodes the object of the given resource type, merges it into the validated config and returns it
+func proposeObject(istioConfigList *models.IstioConfigList, mtlsDetails *kubernetes.MTLSDetails, rbacDetails *kubernetes.RBACDetails, resourceType, namespace string, object []byte, remove bool) (meta_v1.Object, error) {
+	var changed meta_v1.Object
+	switch resourceType {
+	case kubernetes.AuthorizationPolicies:
+		ap, err := decodeProposedObject(object, namespace, &security_v1.AuthorizationPolicy{})
+		if err != nil {
+			return nil, err
+		}
+		changed = ap
+		rbacDetails.AuthorizationPolicies = mergeProposedObject(rbacDetails.AuthorizationPolicies, ap, remove)
+	case kubernetes.DestinationRules:
+		dr, err := decodeProposedObject(object, namespace, &networking_v1.DestinationRule{})
+		if err != nil {
+			return nil, err
+		}
+		changed = dr
+		istioConfigList.DestinationRules = mergeProposedObject(istioConfigList.DestinationRules, dr, remove)
+		mtlsDetails.DestinationRules = mergeProposedObject(mtlsDetails.DestinationRules, dr, remove)
+	case kubernetes.EnvoyFilters:
+		ef, err := decodeProposedObject(object, namespace, &networking_v1alpha3.EnvoyFilter{})
+		if err != nil {
+			return nil, err
+		}
+		changed = ef
+		istioConfigList.EnvoyFilters = mergeProposedObject(istioConfigList.EnvoyFilters, ef, remove)
+	case kubernetes.Gateways:
+		gw, err := decodeProposedObject(object, namespace, &networking_v1.Gateway{})
+		if err != nil {
+			return nil, err
+		}
+		changed = gw
+		istioConfigList.Gateways = mergeProposedObject(istioConfigList.Gateways, gw, remove)
+	case kubernetes.K8sGateways:
+		gw, err := decodeProposedObject(object, namespace, &k8s_networking_v1.Gateway{})
+		if err != nil {
+			return nil, err
+		}
+		changed = gw
+		istioConfigList.K8sGateways = mergeProposedObject(istioConfigList.K8sGateways, gw, remove)
+	case kubernetes.K8sGRPCRoutes:
+		route, err := decodeProposedObject(object, namespace, &k8s_networking_v1.GRPCRoute{})
+		if err != nil {
+			return nil, err
+		}
+		changed = route
+		istioConfigList.K8sGRPCRoutes = mergeProposedObject(istioConfigList.K8sGRPCRoutes, route, remove)
+	case kubernetes.K8sHTTPRoutes:
+		route, err := decodeProposedObject(object, namespace, &k8s_networking_v1.HTTPRoute{})
+		if err != nil {
+			return nil, err
+		}
+		changed = route
+		istioConfigList.K8sHTTPRoutes = mergeProposedObject(istioConfigList.K8sHTTPRoutes, route, remove)
+	case kubernetes.K8sReferenceGrants:
+		rg, err := decodeProposedObject(object, namespace, &k8s_networking_v1beta1.ReferenceGrant{})
+		if err != nil {
+			return nil, err
+		}
+		changed = rg
+		istioConfigList.K8sReferenceGrants = mergeProposedObject(istioConfigList.K8sReferenceGrants, rg, remove)
+	case kubernetes.K8sTCPRoutes:
+		route, err := decodeProposedObject(object, namespace, &k8s_networking_v1alpha2.TCPRoute{})
+		if err != nil {
+			return nil, err
+		}
+		changed = route
+		istioConfigList.K8sTCPRoutes = mergeProposedObject(istioConfigList.K8sTCPRoutes, route, remove)
+	case kubernetes.K8sTLSRoutes:
+		route, err := decodeProposedObject(object, namespace, &k8s_networking_v1alpha2.TLSRoute{})
+		if err != nil {
+			return nil, err
+		}
+		changed = route
+		istioConfigList.K8sTLSRoutes = mergeProposedObject(istioConfigList.K8sTLSRoutes, route, remove)
+	case kubernetes.PeerAuthentications:
+		pa, err := decodeProposedObject(object, namespace, &security_v1.PeerAuthentication{})
+		if err != nil {
+			return nil, err
+		}
+		changed = pa
+		mtlsDetails.PeerAuthentications = mergeProposedObject(mtlsDetails.PeerAuthentications, pa, remove)
+		if pa.Namespace == config.Get().ExternalServices.Istio.RootNamespace {
+			mtlsDetails.MeshPeerAuthentications = mergeProposedObject(mtlsDetails.MeshPeerAuthentications, pa, remove)
+		}
+	case kubernetes.RequestAuthentications:
+		ra, err := decodeProposedObject(object, namespace, &security_v1.RequestAuthentication{})
+		if err != nil {
+			return nil, err
+		}
+		changed = ra
+		istioConfigList.RequestAuthentications = mergeProposedObject(istioConfigList.RequestAuthentications, ra, remove)
+	case kubernetes.ServiceEntries:
+		se, err := decodeProposedObject(object, namespace, &networking_v1.ServiceEntry{})
+		if err != nil {
+			return nil, err
+		}
+		changed = se
+		istioConfigList.ServiceEntries = mergeProposedObject(istioConfigList.ServiceEntries, se, remove)
+	case kubernetes.Sidecars:
+		sc, err := decodeProposedObject(object, namespace, &networking_v1.Sidecar{})
+		if err != nil {
+			return nil, err
+		}
+		changed = sc
+		istioConfigList.Sidecars = mergeProposedObject(istioConfigList.Sidecars, sc, remove)
+	case kubernetes.Telemetries:
+		tm, err := decodeProposedObject(object, namespace, &telemetry_v1.Telemetry{})
+		if err != nil {
+			return nil, err
+		}
+		changed = tm
+		istioConfigList.Telemetries = mergeProposedObject(istioConfigList.Telemetries, tm, remove)
+	case kubernetes.VirtualServices:
+		vs, err := decodeProposedObject(object, namespace, &networking_v1.VirtualService{})
+		if err != nil {
+			return nil, err
+		}
+		changed = vs
+		istioConfigList.VirtualServices = mergeProposedObject(istioConfigList.VirtualServices, vs, remove)
+	case kubernetes.WasmPlugins:
+		wp, err := decodeProposedObject(object, namespace, &extentions_v1alpha1.WasmPlugin{})
+		if err != nil {
+			return nil, err
+		}
+		changed = wp
+		istioConfigList.WasmPlugins = mergeProposedObject(istioConfigList.WasmPlugins, wp, remove)
+	case kubernetes.WorkloadEntries:
+		we, err := decodeProposedObject(object, namespace, &networking_v1.WorkloadEntry{})
+		if err != nil {
+			return nil, err
+		}
+		changed = we
+		istioConfigList.WorkloadEntries = mergeProposedObject(istioConfigList.WorkloadEntries, we, remove)
+	case kubernetes.WorkloadGroups:
+		wg, err := decodeProposedObject(object, namespace, &networking_v1.WorkloadGroup{})
+		if err != nil {
+			return nil, err
+		}
+		changed = wg
+		istioConfigList.WorkloadGroups = mergeProposedObject(istioConfigList.WorkloadGroups, wg, remove)
+	default:
+		return nil, api_errors.NewBadRequest(fmt.Sprintf("Object type not found: %s", resourceType))
+	}
+	return changed, nil
+}
+
+func decodeProposedObject[T meta_v1.Object](object []byte, namespace string, into T) (T, error) {
+	if err := json.Unmarshal(object, into); err != nil {
+		return into, api_errors.NewBadRequest(err.Error())
+	}
+	if into.GetNamespace() == "" {
+		into.SetNamespace(namespace)
+	}
+	if into.GetName() == "" {
+		return into, api_errors.NewBadRequest("Object name is required")
+	}
+	return into, nil
+}
+
+// mergeProposedObject returns a new slice where the object with the same name and namespace is replaced by the given
+// object, or removed when remove is true. The given slice is not modified.
+func mergeProposedObject[T meta_v1.Object](objects []T, object T, remove bool) []T {
+	merged := make([]T, 0, len(objects)+1)
+	for _, o := range objects {
+		if o.GetName() != object.GetName() || o.GetNamespace() != object.GetNamespace() {
+			merged = append(merged, o)
+		}
+	}
+	if !remove {
+		merged = append(merged, object)
+	}
+	return merged
+}
@@ -0,0 +1,87 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestGetProposedValidations(t *testing.T) {
+	require := require.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	istioConfigList := fakeIstioConfigList()
+	vs := mockCombinedValidationService(t, istioConfigList, []string{"product.test.svc.cluster.local", "product2.test.svc.cluster.local", "customer.test.svc.cluster.local"})
+	vsKey := models.BuildKey("virtualservice", "product-vs", "test", conf.KubernetesConfig.ClusterName)
+
+	// the subset v1 used by the virtual service is removed
+	dr, err := json.Marshal(data.CreateEmptyDestinationRule("test", "product-dr", "product"))
+	require.NoError(err)
+	current, proposed, err := vs.GetProposedValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", kubernetes.DestinationRules, dr, false)
+	require.NoError(err)
+	newChecks := proposed.NewChecks(current)
+	require.Contains(newChecks, vsKey)
+	assert.Equal(t, "KIA1107", newChecks[vsKey].Checks[0].Code)
+	assert.Len(t, istioConfigList.DestinationRules[0].Spec.Subsets, 1, "the cached destination rule is not modified")
+	// only the changed object and the objects it references are validated
+	assert.NotContains(t, current, models.BuildKey("destinationrule", "customer-dr", "test", conf.KubernetesConfig.ClusterName))
+	assert.NotContains(t, proposed, models.BuildKey("destinationrule", "customer-dr", "test", conf.KubernetesConfig.ClusterName))
+
+	// the same happens when the destination rule is deleted
+	current, proposed, err = vs.GetProposedValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", kubernetes.DestinationRules, dr, true)
+	require.NoError(err)
+	assert.Contains(t, proposed.NewChecks(current), vsKey)
+
+	// a valid change adds no check
+	dr, err = json.Marshal(data.AddSubsetToDestinationRule(data.CreateSubset("v1", "v1"), data.CreateEmptyDestinationRule("test", "product-dr", "product")))
+	require.NoError(err)
+	current, proposed, err = vs.GetProposedValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", kubernetes.DestinationRules, dr, false)
+	require.NoError(err)
+	assert.Empty(t, proposed.NewChecks(current))
+
+	// the workload selector of the envoy filter matches no workload
+	current, proposed, err = vs.GetProposedValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", kubernetes.EnvoyFilters, []byte(`{"metadata":{"name":"filter"},"spec":{"workloadSelector":{"labels":{"app":"product"}}}}`), false)
+	require.NoError(err)
+	assert.Contains(t, proposed.NewChecks(current), models.BuildKey("envoyfilter", "filter", "test", conf.KubernetesConfig.ClusterName))
+
+	_, _, err = vs.GetProposedValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", "foos", []byte(`{}`), false)
+	assert.Error(t, err)
+	_, _, err = vs.GetProposedValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", kubernetes.DestinationRules, []byte(`{"metadata":{}}`), false)
+	assert.Error(t, err)
+}
+
+func TestGetProposedValidationsBackgroundCurrent(t *testing.T) {
+	require := require.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	original := backgroundValidations
+	backgroundValidations = &validationsHistory{}
+	t.Cleanup(func() { backgroundValidations = original })
+
+	vs := mockCombinedValidationService(t, fakeIstioConfigList(), []string{"product.test.svc.cluster.local", "product2.test.svc.cluster.local", "customer.test.svc.cluster.local"})
+	vsKey := models.BuildKey("virtualservice", "product-vs", "test", conf.KubernetesConfig.ClusterName)
+	dr, err := json.Marshal(data.CreateEmptyDestinationRule("test", "product-dr", "product"))
+	require.NoError(err)
+
+	// the last background validation already found the missing subsets, they are not new
+	_, proposed, err := vs.GetProposedValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", kubernetes.DestinationRules, dr, false)
+	require.NoError(err)
+	require.Contains(proposed, vsKey)
+	backgroundValidations.record(time.Now(), map[string]models.IstioValidations{conf.KubernetesConfig.ClusterName: {vsKey: proposed[vsKey]}}, time.Hour)
+
+	current, proposed, err := vs.GetProposedValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", kubernetes.DestinationRules, dr, false)
+	require.NoError(err)
+	assert.Len(t, current, 1)
+	assert.Empty(t, proposed.NewChecks(current))
+}
@@ -706,3 +706,30 @@ func yamlFixtureLoaderFor(file string) *validations.YamlFixtureLoader {
 	path := fmt.Sprintf("../tests/data/validations/exportto/cns/%s", file)
 	return &validations.YamlFixtureLoader{Filename: path}
 }
+
+func TestGetValidationsCustomRules(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.Validations.CustomRules = []config.CustomValidationRule{{
+		Code:       "ACME001",
+		Expression: "has(spec.trafficPolicy) && has(spec.trafficPolicy.outlierDetection)",
+		Kinds:      []string{"destinationrule"},
+		Message:    "DestinationRule must set outlierDetection",
+		Severity:   "warning",
+	}}
+	config.Set(conf)
+
+	vs := mockCombinedValidationService(t, fakeIstioConfigList(), []string{"product.test.svc.cluster.local", "customer.test.svc.cluster.local"})
+
+	validations, err := vs.GetValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", "", "")
+	require.NoError(err)
+	drValidation := validations[models.BuildKey("destinationrule", "customer-dr", "test", conf.KubernetesConfig.ClusterName)]
+	require.NotNil(drValidation)
+	assert.Contains(drValidation.Checks, &models.IstioCheck{Code: "ACME001", Message: "DestinationRule must set outlierDetection", Severity: models.WarningSeverity})
+
+	validations, _, err = vs.GetIstioObjectValidations(context.TODO(), conf.KubernetesConfig.ClusterName, "test", kubernetes.DestinationRules, "customer-dr")
+	require.NoError(err)
+	require.Len(validations, 1)
+	assert.Contains(validations[models.BuildKey("destinationrule", "customer-dr", "test", conf.KubernetesConfig.ClusterName)].Checks, &models.IstioCheck{Code: "ACME001", Message: "DestinationRule must set outlierDetection", Severity: models.WarningSeverity})
+}
@@ -17,6 +17,8 @@ import (
 // needs to be saved across layers is saved in the Kiali Cache.
 type Layer struct {
 	App            AppService
+	Authorization  AuthorizationSimulatorService
+	CanaryRollout  CanaryRolloutService
 	Health         HealthService
 	IstioConfig    IstioConfigService
 	IstioStatus    IstioStatusService
@@ -114,8 +116,10 @@ func newLayer(
 
 	// TODO: Modify the k8s argument to other services to pass the whole k8s map if needed
 	temporaryLayer.App = NewAppService(temporaryLayer, conf, prom, grafana, userClients)
+	temporaryLayer.Authorization = AuthorizationSimulatorService{businessLayer: temporaryLayer, conf: conf, discovery: discovery, kialiCache: cache}
+	temporaryLayer.CanaryRollout = CanaryRolloutService{businessLayer: temporaryLayer, conf: conf, kialiCache: cache, userClients: userClients}
 	temporaryLayer.Health = HealthService{prom: prom, businessLayer: temporaryLayer, userClients: userClients}
-	temporaryLayer.IstioConfig = IstioConfigService{config: *conf, userClients: userClients, kialiCache: cache, businessLayer: temporaryLayer, controlPlaneMonitor: poller}
+	temporaryLayer.IstioConfig = IstioConfigService{config: *conf, userClients: userClients, kialiCache: cache, businessLayer: temporaryLayer, controlPlaneMonitor: poller, history: istioConfigHistory(conf, kialiSAClients[homeClusterName])}
 	temporaryLayer.IstioCerts = NewIstioCertsService(conf, discovery, userClients[homeClusterName])
 	temporaryLayer.Namespace = NewNamespaceService(userClients, kialiSAClients, cache, conf, discovery)
 	temporaryLayer.Mesh = NewMeshService(kialiSAClients, cache, temporaryLayer.Namespace, conf, discovery)
@@ -125,7 +129,7 @@ func newLayer(
 	temporaryLayer.RegistryStatus = RegistryStatusService{kialiCache: cache}
 	temporaryLayer.TLS = TLSService{discovery: discovery, userClients: userClients, kialiCache: cache, businessLayer: temporaryLayer}
 	temporaryLayer.Svc = SvcService{config: *conf, kialiCache: cache, businessLayer: temporaryLayer, prom: prom, userClients: userClients}
-	temporaryLayer.Validations = IstioValidationsService{discovery: discovery, userClients: userClients, businessLayer: temporaryLayer}
+	temporaryLayer.Validations = IstioValidationsService{discovery: discovery, kialiCache: cache, userClients: userClients, businessLayer: temporaryLayer}
 	temporaryLayer.Workload = *NewWorkloadService(userClients, prom, cache, temporaryLayer, conf, grafana)
 
 	temporaryLayer.Tracing = NewTracingService(conf, traceClient, &temporaryLayer.Svc, &temporaryLayer.Workload)
@@ -0,0 +1,410 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/observability"
+)
+
+// SimulateRoute returns where the VirtualServices send the given HTTP request: the chosen http route of the
+// VirtualService of the host, visible from the source namespace and bound to the gateway of the request, and its
+// destinations with their DestinationRule subsets. Only the config of the namespaces accessible by the user is used.
+func (in *IstioConfigService) SimulateRoute(ctx context.Context, cluster string, request models.RouteSimulationRequest) (models.RouteSimulation, error) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "SimulateRoute",
+		observability.Attribute("package", "business"),
+		observability.Attribute("cluster", cluster),
+		observability.Attribute("namespace", request.SourceNamespace),
+		observability.Attribute("host", request.Host),
+	)
+	defer end()
+
+	if request.Host == "" {
+		return models.RouteSimulation{}, api_errors.NewBadRequest("The host of the request is required")
+	}
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetClusterNamespace(ctx, request.SourceNamespace, cluster); err != nil {
+		return models.RouteSimulation{}, err
+	}
+	namespaces, err := in.businessLayer.Namespace.GetClusterNamespaces(ctx, cluster)
+	if err != nil {
+		return models.RouteSimulation{}, err
+	}
+	namespaceNames := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		namespaceNames = append(namespaceNames, ns.Name)
+	}
+
+	kubeCache, err := in.kialiCache.GetKubeCache(cluster)
+	if err != nil {
+		return models.RouteSimulation{}, err
+	}
+	virtualServices, err := kubeCache.GetVirtualServices(meta_v1.NamespaceAll, "")
+	if err != nil {
+		return models.RouteSimulation{}, err
+	}
+	destinationRules, err := kubeCache.GetDestinationRules(meta_v1.NamespaceAll, "")
+	if err != nil {
+		return models.RouteSimulation{}, err
+	}
+	inNamespaces := func(namespace string) bool { return slices.Contains(namespaceNames, namespace) }
+	virtualServices = kubernetes.FilterByNamespaceNames(virtualServices, namespaceNames)
+	destinationRules = slices.DeleteFunc(slices.Clone(destinationRules), func(dr *networking_v1.DestinationRule) bool { return !inNamespaces(dr.Namespace) })
+
+	return simulateRoute(request, virtualServices, destinationRules, namespaceNames), nil
+}
+
+// routeSimulator holds the attributes of the simulated request
+type routeSimulator struct {
+	request          models.RouteSimulationRequest
+	destinationRules []*networking_v1.DestinationRule
+	gateway          string
+	headers          map[string]string
+	host             string
+	namespaces       []string
+	path             string
+	queryParams      map[string]string
+	notes            []string
+}
+
+func simulateRoute(request models.RouteSimulationRequest, virtualServices []*networking_v1.VirtualService, destinationRules []*networking_v1.DestinationRule, namespaces []string) models.RouteSimulation {
+	s := &routeSimulator{
+		request:          request,
+		destinationRules: destinationRules,
+		headers:          map[string]string{},
+		namespaces:       namespaces,
+		notes:            []string{},
+		queryParams:      map[string]string{},
+	}
+	s.gateway = models.MeshGateway
+	if request.Gateway != "" {
+		s.gateway = normalizeGateway(request.Gateway, request.SourceNamespace)
+	}
+	s.host = kubernetes.GetHost(request.Host, request.SourceNamespace, namespaces).String()
+	// header names are case insensitive
+	for name, value := range request.Headers {
+		s.headers[strings.ToLower(name)] = value
+	}
+	path, query, _ := strings.Cut(request.Path, "?")
+	s.path = path
+	if values, err := url.ParseQuery(query); err == nil {
+		for name := range values {
+			s.queryParams[name] = values.Get(name)
+		}
+	}
+	for name, value := range request.QueryParams {
+		s.queryParams[name] = value
+	}
+
+	simulation := models.RouteSimulation{Request: request, RouteIndex: -1, MatchIndex: -1, Destinations: []models.RouteSimulationDestination{}}
+	candidates := s.virtualServicesOfHost(virtualServices)
+	if len(candidates) == 0 {
+		simulation.Destinations = append(simulation.Destinations, models.RouteSimulationDestination{Host: s.host, Port: uint32(request.Port), Weight: 100})
+		simulation.Reason = "No VirtualService routes the host, the request goes to the host"
+		simulation.Notes = s.notes
+		return simulation
+	}
+	vs := candidates[0]
+	for _, other := range candidates[1:] {
+		s.note("VirtualService %s/%s also routes the host, it is ignored", other.Namespace, other.Name)
+	}
+	simulation.VirtualService = &models.IstioReference{ObjectType: models.ObjectTypeSingular[kubernetes.VirtualServices], Name: vs.Name, Namespace: vs.Namespace}
+
+	routeIdx, matchIdx := s.chooseRoute(vs)
+	if routeIdx < 0 {
+		simulation.Reason = fmt.Sprintf("No http route of VirtualService %s/%s matches the request, it gets a 404 response", vs.Namespace, vs.Name)
+		simulation.Notes = s.notes
+		return simulation
+	}
+
+	route := vs.Spec.Http[routeIdx]
+	if route.Delegate != nil {
+		delegateNamespace := route.Delegate.Namespace
+		if delegateNamespace == "" {
+			delegateNamespace = vs.Namespace
+		}
+		simulation.Delegate = &models.IstioReference{ObjectType: models.ObjectTypeSingular[kubernetes.VirtualServices], Name: route.Delegate.Name, Namespace: delegateNamespace}
+		delegateIdx := slices.IndexFunc(virtualServices, func(d *networking_v1.VirtualService) bool {
+			return d.Name == route.Delegate.Name && d.Namespace == delegateNamespace
+		})
+		if delegateIdx < 0 {
+			simulation.Reason = fmt.Sprintf("The http route %d of VirtualService %s/%s delegates to VirtualService %s/%s, which is not found", routeIdx, vs.Namespace, vs.Name, delegateNamespace, route.Delegate.Name)
+			simulation.Notes = s.notes
+			return simulation
+		}
+		vs = virtualServices[delegateIdx]
+		if routeIdx, matchIdx = s.chooseRoute(vs); routeIdx < 0 {
+			simulation.Reason = fmt.Sprintf("No http route of the delegate VirtualService %s/%s matches the request, it gets a 404 response", vs.Namespace, vs.Name)
+			simulation.Notes = s.notes
+			return simulation
+		}
+		route = vs.Spec.Http[routeIdx]
+	}
+
+	simulation.RouteIndex, simulation.MatchIndex, simulation.RouteName = routeIdx, matchIdx, route.Name
+	simulation.Redirect = route.Redirect
+	simulation.DirectResponse = route.DirectResponse
+	simulation.Rewrite = route.Rewrite
+	simulation.Retries = route.Retries
+	simulation.Fault = route.Fault
+	if route.Timeout != nil {
+		simulation.Timeout = route.Timeout.AsDuration().String()
+	}
+	for _, rd := range route.Route {
+		if rd == nil || rd.Destination == nil {
+			continue
+		}
+		simulation.Destinations = append(simulation.Destinations, s.destination(vs, rd, len(route.Route)))
+	}
+	switch {
+	case route.Redirect != nil:
+		simulation.Reason = fmt.Sprintf("The http route %d of VirtualService %s/%s redirects the request", routeIdx, vs.Namespace, vs.Name)
+	case route.DirectResponse != nil:
+		simulation.Reason = fmt.Sprintf("The http route %d of VirtualService %s/%s responds directly", routeIdx, vs.Namespace, vs.Name)
+	default:
+		simulation.Reason = fmt.Sprintf("The http route %d of VirtualService %s/%s matches the request", routeIdx, vs.Namespace, vs.Name)
+	}
+	simulation.Notes = s.notes
+	return simulation
+}
+
+func (s *routeSimulator) note(format string, args ...interface{}) {
+	if note := fmt.Sprintf(format, args...); !slices.Contains(s.notes, note) {
+		s.notes = append(s.notes, note)
+	}
+}
+
+// virtualServicesOfHost returns the VirtualServices routing the host for the gateway, visible from the namespace of
+// the gateway or the source. Exact hosts are preferred over wildcards, then the oldest VirtualService.
+func (s *routeSimulator) virtualServicesOfHost(virtualServices []*networking_v1.VirtualService) []*networking_v1.VirtualService {
+	viewer := s.request.SourceNamespace
+	if s.gateway != models.MeshGateway {
+		viewer, _, _ = strings.Cut(s.gateway, "/")
+	}
+
+	type candidate struct {
+		vs    *networking_v1.VirtualService
+		score int
+	}
+	candidates := []candidate{}
+	for _, vs := range virtualServices {
+		if !isExportedTo(vs.Spec.ExportTo, vs.Namespace, viewer) {
+			continue
+		}
+		gateways := []string{models.MeshGateway}
+		if len(vs.Spec.Gateways) > 0 {
+			gateways = normalizeGateways(vs.Spec.Gateways, vs.Namespace)
+		}
+		if !slices.Contains(gateways, s.gateway) {
+			continue
+		}
+		if score := s.hostScore(vs); score > 0 {
+			candidates = append(candidates, candidate{vs: vs, score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		ti, tj := candidates[i].vs.CreationTimestamp, candidates[j].vs.CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return candidates[i].vs.Namespace+"/"+candidates[i].vs.Name < candidates[j].vs.Namespace+"/"+candidates[j].vs.Name
+	})
+
+	result := make([]*networking_v1.VirtualService, 0, len(candidates))
+	for _, c := range candidates {
+		result = append(result, c.vs)
+	}
+	return result
+}
+
+// hostScore is 2 when a host of the VirtualService is the requested host, 1 when a wildcard host matches it
+func (s *routeSimulator) hostScore(vs *networking_v1.VirtualService) int {
+	score := 0
+	for _, host := range vs.Spec.Hosts {
+		switch {
+		case kubernetes.GetHost(host, vs.Namespace, s.namespaces).String() == s.host:
+			return 2
+		case host == "*" || kubernetes.HostWithinWildcardHost(s.host, host):
+			score = 1
+		}
+	}
+	return score
+}
+
+// chooseRoute returns the index of the first http route matching the request and the index of its matching
+// condition, -1 when the route has no conditions
+func (s *routeSimulator) chooseRoute(vs *networking_v1.VirtualService) (int, int) {
+	for i, route := range vs.Spec.Http {
+		if route == nil {
+			continue
+		}
+		if len(route.Match) == 0 {
+			return i, -1
+		}
+		for j, match := range route.Match {
+			if match != nil && s.requestMatches(vs, match) {
+				return i, j
+			}
+		}
+	}
+	return -1, -1
+}
+
+func (s *routeSimulator) requestMatches(vs *networking_v1.VirtualService, match *api_networking_v1.HTTPMatchRequest) bool {
+	authority := s.request.Authority
+	if authority == "" {
+		authority = s.request.Host
+	}
+	if !s.stringMatches(vs, match.Uri, s.path, match.IgnoreUriCase) ||
+		!s.stringMatches(vs, match.Scheme, s.request.Scheme, false) ||
+		!s.stringMatches(vs, match.Method, s.request.Method, false) ||
+		!s.stringMatches(vs, match.Authority, authority, false) {
+		return false
+	}
+	if match.Port != 0 && match.Port != uint32(s.request.Port) {
+		return false
+	}
+	if match.SourceNamespace != "" && match.SourceNamespace != s.request.SourceNamespace {
+		return false
+	}
+	for name, value := range match.SourceLabels {
+		if s.request.SourceLabels[name] != value {
+			return false
+		}
+	}
+	if len(match.Gateways) > 0 && !slices.Contains(normalizeGateways(match.Gateways, vs.Namespace), s.gateway) {
+		return false
+	}
+	for name, sm := range match.Headers {
+		value, found := s.headers[strings.ToLower(name)]
+		if !found || !s.stringMatches(vs, sm, value, false) {
+			return false
+		}
+	}
+	for name, sm := range match.WithoutHeaders {
+		if value, found := s.headers[strings.ToLower(name)]; found && s.stringMatches(vs, sm, value, false) {
+			return false
+		}
+	}
+	for name, sm := range match.QueryParams {
+		value, found := s.queryParams[name]
+		if !found || !s.stringMatches(vs, sm, value, false) {
+			return false
+		}
+	}
+	return true
+}
+
+// stringMatches returns true when there is no match or the value matches it. Regexes must match the whole value.
+func (s *routeSimulator) stringMatches(vs *networking_v1.VirtualService, sm *api_networking_v1.StringMatch, value string, ignoreCase bool) bool {
+	if sm == nil {
+		return true
+	}
+	if ignoreCase {
+		value = strings.ToLower(value)
+	}
+	switch matchType := sm.MatchType.(type) {
+	case *api_networking_v1.StringMatch_Exact:
+		if ignoreCase {
+			return value == strings.ToLower(matchType.Exact)
+		}
+		return value == matchType.Exact
+	case *api_networking_v1.StringMatch_Prefix:
+		if ignoreCase {
+			return strings.HasPrefix(value, strings.ToLower(matchType.Prefix))
+		}
+		return strings.HasPrefix(value, matchType.Prefix)
+	case *api_networking_v1.StringMatch_Regex:
+		re, err := regexp.Compile("^(?:" + matchType.Regex + ")$")
+		if err != nil {
+			s.note("Regex %s of VirtualService %s/%s is invalid, it is assumed not matching", matchType.Regex, vs.Namespace, vs.Name)
+			return false
+		}
+		return re.MatchString(value)
+	}
+	// an empty match only checks the presence
+	return true
+}
+
+// destination returns the destination of the route with the subset labels of its DestinationRule
+func (s *routeSimulator) destination(vs *networking_v1.VirtualService, rd *api_networking_v1.HTTPRouteDestination, destinations int) models.RouteSimulationDestination {
+	host := kubernetes.GetHost(rd.Destination.Host, vs.Namespace, s.namespaces)
+	destination := models.RouteSimulationDestination{Host: host.String(), Subset: rd.Destination.Subset, Weight: rd.Weight}
+	if rd.Destination.Port != nil {
+		destination.Port = rd.Destination.Port.Number
+	}
+	// a single destination gets all the traffic
+	if destinations == 1 && destination.Weight == 0 {
+		destination.Weight = 100
+	}
+	if destination.Subset == "" {
+		return destination
+	}
+
+	for _, dr := range s.destinationRules {
+		drHost := kubernetes.GetHost(dr.Spec.Host, dr.Namespace, s.namespaces)
+		if !kubernetes.FilterByHost(host.String(), host.Namespace, drHost.Service, drHost.Namespace) {
+			continue
+		}
+		for _, subset := range dr.Spec.Subsets {
+			if subset != nil && subset.Name == destination.Subset {
+				destination.SubsetLabels = subset.Labels
+				destination.DestinationRule = &models.IstioReference{ObjectType: models.ObjectTypeSingular[kubernetes.DestinationRules], Name: dr.Name, Namespace: dr.Namespace}
+				return destination
+			}
+		}
+	}
+	s.note("Subset %s of host %s is not defined in a DestinationRule, the request fails", destination.Subset, destination.Host)
+	return destination
+}
+
+// isExportedTo returns true when the object of the namespace with the exportTo list is visible from the viewer
+// namespace
+func isExportedTo(exportTo []string, namespace, viewer string) bool {
+	if len(exportTo) == 0 {
+		return true
+	}
+	for _, to := range exportTo {
+		if to == "*" || to == viewer || (to == "." && namespace == viewer) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeGateways returns the gateways of a VirtualService as <namespace>/<name>, or mesh
+func normalizeGateways(gateways []string, namespace string) []string {
+	normalized := make([]string, 0, len(gateways))
+	for _, gw := range gateways {
+		normalized = append(normalized, normalizeGateway(gw, namespace))
+	}
+	return normalized
+}
+
+func normalizeGateway(gateway, namespace string) string {
+	switch {
+	case gateway == models.MeshGateway || strings.Contains(gateway, "/"):
+		return gateway
+	case strings.Contains(gateway, "."):
+		host := kubernetes.ParseGatewayAsHost(gateway, namespace)
+		return host.Namespace + "/" + host.Service
+	}
+	return namespace + "/" + gateway
+}
@@ -0,0 +1,268 @@
+package business
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	api_networking_v1 "istio.io/api/networking/v1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+var routeNamespaces = []string{"bookinfo", "istio-system"}
+
+func fakeRouteVirtualService(name, namespace string, hosts []string, routes ...*api_networking_v1.HTTPRoute) *networking_v1.VirtualService {
+	vs := &networking_v1.VirtualService{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace}}
+	vs.Spec.Hosts = hosts
+	vs.Spec.Http = routes
+	return vs
+}
+
+func routeTo(host, subset string, weight int32) *api_networking_v1.HTTPRouteDestination {
+	return &api_networking_v1.HTTPRouteDestination{Destination: &api_networking_v1.Destination{Host: host, Subset: subset}, Weight: weight}
+}
+
+func exactMatch(value string) *api_networking_v1.StringMatch {
+	return &api_networking_v1.StringMatch{MatchType: &api_networking_v1.StringMatch_Exact{Exact: value}}
+}
+
+func prefixMatch(value string) *api_networking_v1.StringMatch {
+	return &api_networking_v1.StringMatch{MatchType: &api_networking_v1.StringMatch_Prefix{Prefix: value}}
+}
+
+func regexMatch(value string) *api_networking_v1.StringMatch {
+	return &api_networking_v1.StringMatch{MatchType: &api_networking_v1.StringMatch_Regex{Regex: value}}
+}
+
+func fakeReviewsDestinationRule() *networking_v1.DestinationRule {
+	dr := &networking_v1.DestinationRule{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}}
+	dr.Spec.Host = "reviews"
+	dr.Spec.Subsets = []*api_networking_v1.Subset{
+		{Name: "v1", Labels: map[string]string{"version": "v1"}},
+		{Name: "v2", Labels: map[string]string{"version": "v2"}},
+	}
+	return dr
+}
+
+func fakeReviewsVirtualService() *networking_v1.VirtualService {
+	return fakeRouteVirtualService("reviews", "bookinfo", []string{"reviews"},
+		&api_networking_v1.HTTPRoute{
+			Name: "jason",
+			Match: []*api_networking_v1.HTTPMatchRequest{
+				{Uri: prefixMatch("/admin")},
+				{Headers: map[string]*api_networking_v1.StringMatch{"end-user": exactMatch("jason")}},
+			},
+			Route:   []*api_networking_v1.HTTPRouteDestination{routeTo("reviews", "v2", 0)},
+			Timeout: durationpb.New(10 * time.Second),
+		},
+		&api_networking_v1.HTTPRoute{
+			Name: "canary",
+			Match: []*api_networking_v1.HTTPMatchRequest{
+				{Uri: regexMatch("/reviews/[0-9]+"), QueryParams: map[string]*api_networking_v1.StringMatch{"canary": exactMatch("true")}},
+			},
+			Route: []*api_networking_v1.HTTPRouteDestination{routeTo("reviews", "v1", 80), routeTo("reviews", "v2", 20)},
+		},
+		&api_networking_v1.HTTPRoute{
+			Name:  "default",
+			Route: []*api_networking_v1.HTTPRouteDestination{routeTo("reviews", "v1", 0)},
+		},
+	)
+}
+
+func TestSimulateRouteMatchOrder(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	config.Set(config.NewConfig())
+
+	vss := []*networking_v1.VirtualService{fakeReviewsVirtualService()}
+	drs := []*networking_v1.DestinationRule{fakeReviewsDestinationRule()}
+
+	request := models.RouteSimulationRequest{Host: "reviews", SourceNamespace: "bookinfo", Path: "/reviews/1", Headers: map[string]string{"end-user": "jason"}}
+	simulation := simulateRoute(request, vss, drs, routeNamespaces)
+	require.NotNil(simulation.VirtualService)
+	assert.Equal("reviews", simulation.VirtualService.Name)
+	assert.Equal(0, simulation.RouteIndex)
+	assert.Equal(1, simulation.MatchIndex)
+	assert.Equal("jason", simulation.RouteName)
+	assert.Equal("10s", simulation.Timeout)
+	require.Len(simulation.Destinations, 1)
+	assert.Equal("reviews.bookinfo.svc.cluster.local", simulation.Destinations[0].Host)
+	assert.Equal("v2", simulation.Destinations[0].Subset)
+	assert.Equal(int32(100), simulation.Destinations[0].Weight)
+	assert.Equal(map[string]string{"version": "v2"}, simulation.Destinations[0].SubsetLabels)
+	require.NotNil(simulation.Destinations[0].DestinationRule)
+	assert.Equal("reviews", simulation.Destinations[0].DestinationRule.Name)
+	assert.Empty(simulation.Notes)
+
+	request = models.RouteSimulationRequest{Host: "reviews.bookinfo.svc.cluster.local", SourceNamespace: "bookinfo", Path: "/reviews/12?canary=true"}
+	simulation = simulateRoute(request, vss, drs, routeNamespaces)
+	assert.Equal(1, simulation.RouteIndex)
+	assert.Equal(0, simulation.MatchIndex)
+	require.Len(simulation.Destinations, 2)
+	assert.Equal(int32(80), simulation.Destinations[0].Weight)
+	assert.Equal(int32(20), simulation.Destinations[1].Weight)
+
+	// the regex must match the whole path
+	request = models.RouteSimulationRequest{Host: "reviews", SourceNamespace: "bookinfo", Path: "/reviews/12/ratings", QueryParams: map[string]string{"canary": "true"}}
+	simulation = simulateRoute(request, vss, drs, routeNamespaces)
+	assert.Equal(2, simulation.RouteIndex)
+	assert.Equal(-1, simulation.MatchIndex)
+	require.Len(simulation.Destinations, 1)
+	assert.Equal("v1", simulation.Destinations[0].Subset)
+}
+
+func TestSimulateRouteHeaderNameCase(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	config.Set(config.NewConfig())
+
+	vs := fakeReviewsVirtualService()
+	vs.Spec.Http[2].Match = []*api_networking_v1.HTTPMatchRequest{
+		{WithoutHeaders: map[string]*api_networking_v1.StringMatch{"X-Canary": exactMatch("true")}},
+	}
+	vss := []*networking_v1.VirtualService{vs}
+
+	request := models.RouteSimulationRequest{Host: "reviews", SourceNamespace: "bookinfo", Path: "/reviews/1", Headers: map[string]string{"End-User": "jason"}}
+	simulation := simulateRoute(request, vss, nil, routeNamespaces)
+	assert.Equal(0, simulation.RouteIndex)
+	assert.Equal(1, simulation.MatchIndex)
+	assert.Equal(map[string]string{"End-User": "jason"}, simulation.Request.Headers)
+
+	request.Headers = map[string]string{"x-canary": "true"}
+	simulation = simulateRoute(request, vss, nil, routeNamespaces)
+	assert.Equal(-1, simulation.RouteIndex)
+	require.Empty(simulation.Destinations)
+}
+
+func TestSimulateRouteNoVirtualService(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	config.Set(config.NewConfig())
+
+	request := models.RouteSimulationRequest{Host: "details", SourceNamespace: "bookinfo", Port: 9080}
+	simulation := simulateRoute(request, []*networking_v1.VirtualService{fakeReviewsVirtualService()}, nil, routeNamespaces)
+	assert.Nil(simulation.VirtualService)
+	assert.Equal(-1, simulation.RouteIndex)
+	require.Len(simulation.Destinations, 1)
+	assert.Equal("details.bookinfo.svc.cluster.local", simulation.Destinations[0].Host)
+	assert.Equal(uint32(9080), simulation.Destinations[0].Port)
+	assert.Equal(int32(100), simulation.Destinations[0].Weight)
+}
+
+func TestSimulateRouteNoMatchingRoute(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := fakeRouteVirtualService("reviews", "bookinfo", []string{"reviews"}, &api_networking_v1.HTTPRoute{
+		Match: []*api_networking_v1.HTTPMatchRequest{{Method: exactMatch("POST")}, {Uri: regexMatch("[")}},
+		Route: []*api_networking_v1.HTTPRouteDestination{routeTo("reviews", "v3", 0)},
+	})
+	request := models.RouteSimulationRequest{Host: "reviews", SourceNamespace: "bookinfo", Method: "GET", Path: "/"}
+	simulation := simulateRoute(request, []*networking_v1.VirtualService{vs}, nil, routeNamespaces)
+	assert.NotNil(simulation.VirtualService)
+	assert.Equal(-1, simulation.RouteIndex)
+	assert.Empty(simulation.Destinations)
+	assert.Contains(simulation.Reason, "404")
+	assert.Equal([]string{"Regex [ of VirtualService bookinfo/reviews is invalid, it is assumed not matching"}, simulation.Notes)
+
+	request.Method = "POST"
+	simulation = simulateRoute(request, []*networking_v1.VirtualService{vs}, nil, routeNamespaces)
+	assert.Equal(0, simulation.RouteIndex)
+	assert.Equal([]string{"Subset v3 of host reviews.bookinfo.svc.cluster.local is not defined in a DestinationRule, the request fails"}, simulation.Notes)
+}
+
+func TestSimulateRouteGateway(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	config.Set(config.NewConfig())
+
+	ingress := fakeRouteVirtualService("bookinfo", "bookinfo", []string{"*.example.com"},
+		&api_networking_v1.HTTPRoute{
+			Match:   []*api_networking_v1.HTTPMatchRequest{{Uri: exactMatch("/PRODUCTPAGE"), IgnoreUriCase: true}},
+			Rewrite: &api_networking_v1.HTTPRewrite{Uri: "/"},
+			Route:   []*api_networking_v1.HTTPRouteDestination{routeTo("productpage", "", 0)},
+		},
+		&api_networking_v1.HTTPRoute{
+			Delegate: &api_networking_v1.Delegate{Name: "reviews"},
+		},
+	)
+	ingress.Spec.Gateways = []string{"bookinfo-gateway.istio-system.svc.cluster.local"}
+	exact := fakeRouteVirtualService("exact", "bookinfo", []string{"bookinfo.example.com"},
+		&api_networking_v1.HTTPRoute{Route: []*api_networking_v1.HTTPRouteDestination{routeTo("details", "", 0)}})
+	exact.Spec.Gateways = []string{"istio-system/other-gateway"}
+	vss := []*networking_v1.VirtualService{ingress, exact, fakeReviewsVirtualService()}
+
+	request := models.RouteSimulationRequest{Host: "bookinfo.example.com", Gateway: "istio-system/bookinfo-gateway", SourceNamespace: "bookinfo", Path: "/productpage"}
+	simulation := simulateRoute(request, vss, nil, routeNamespaces)
+	require.NotNil(simulation.VirtualService)
+	assert.Equal("bookinfo", simulation.VirtualService.Name)
+	assert.Equal(0, simulation.RouteIndex)
+	require.NotNil(simulation.Rewrite)
+	assert.Equal("/", simulation.Rewrite.Uri)
+	require.Len(simulation.Destinations, 1)
+	assert.Equal("productpage.bookinfo.svc.cluster.local", simulation.Destinations[0].Host)
+
+	request.Path = "/reviews/1"
+	request.Headers = map[string]string{"end-user": "jason"}
+	simulation = simulateRoute(request, vss, []*networking_v1.DestinationRule{fakeReviewsDestinationRule()}, routeNamespaces)
+	require.NotNil(simulation.Delegate)
+	assert.Equal("reviews", simulation.Delegate.Name)
+	assert.Equal(0, simulation.RouteIndex)
+	require.Len(simulation.Destinations, 1)
+	assert.Equal("v2", simulation.Destinations[0].Subset)
+
+	// not bound to the mesh
+	request.Gateway = ""
+	simulation = simulateRoute(request, vss, nil, routeNamespaces)
+	assert.Nil(simulation.VirtualService)
+}
+
+func TestSimulateRouteExportTo(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := fakeReviewsVirtualService()
+	vs.Spec.ExportTo = []string{"."}
+	request := models.RouteSimulationRequest{Host: "reviews.bookinfo.svc.cluster.local", SourceNamespace: "istio-system"}
+	assert.Nil(simulateRoute(request, []*networking_v1.VirtualService{vs}, nil, routeNamespaces).VirtualService)
+
+	vs.Spec.ExportTo = []string{"istio-system"}
+	assert.NotNil(simulateRoute(request, []*networking_v1.VirtualService{vs}, nil, routeNamespaces).VirtualService)
+}
+
+func TestSimulateRouteService(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	k8s := kubetest.NewFakeK8sClient(
+		&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		fakeReviewsVirtualService(),
+		fakeReviewsDestinationRule(),
+	)
+	SetupBusinessLayer(t, k8s, *conf)
+	clients := map[string]kubernetes.ClientInterface{conf.KubernetesConfig.ClusterName: k8s}
+	layer := NewWithBackends(clients, clients, new(prometheustest.PromClientMock), nil)
+
+	request := models.RouteSimulationRequest{Host: "reviews", SourceNamespace: "bookinfo", Path: "/admin"}
+	simulation, err := layer.IstioConfig.SimulateRoute(context.TODO(), conf.KubernetesConfig.ClusterName, request)
+	require.NoError(err)
+	assert.Equal(0, simulation.RouteIndex)
+	require.Len(simulation.Destinations, 1)
+	assert.Equal(map[string]string{"version": "v2"}, simulation.Destinations[0].SubsetLabels)
+
+	_, err = layer.IstioConfig.SimulateRoute(context.TODO(), conf.KubernetesConfig.ClusterName, models.RouteSimulationRequest{SourceNamespace: "bookinfo"})
+	assert.Error(err)
+}
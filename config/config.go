@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/cel-go/cel"
 	"gopkg.in/yaml.v2"
 
 	"github.com/kiali/kiali/config/dashboards"
@@ -467,6 +469,64 @@ type GraphTraffic struct {
 	Tcp  string `yaml:"tcp,omitempty" json:"tcp,omitempty"`
 }
 
+// Graph snapshot store types
+const (
+	GraphSnapshotStoreBolt       = "bolt"
+	GraphSnapshotStoreFilesystem = "filesystem"
+)
+
+// GraphSnapshots defines the persistence of generated graphs, such that they can be served after the
+// underlying telemetry is no longer available. Snapshots can always be requested on demand when enabled.
+// Durations are expressed as Go durations (e.g. "10m", "720h").
+// Duration: the query range of the periodic snapshots
+// GraphType: the graph type of the periodic snapshots
+// Interval: how often periodic snapshots are taken, periodic snapshots are disabled when empty or no namespaces are set
+// Namespaces: the namespaces included in the periodic snapshots (a single graph for all of them)
+// Path: the directory (filesystem store) or database file (bolt store) holding the snapshots
+// Retention: how long snapshots are kept, snapshots are never removed when empty
+// Store: filesystem | bolt
+type GraphSnapshots struct {
+	Duration   string   `yaml:"duration,omitempty" json:"duration,omitempty"`
+	Enabled    bool     `yaml:"enabled,omitempty" json:"enabled"`
+	GraphType  string   `yaml:"graph_type,omitempty" json:"graphType,omitempty"`
+	Interval   string   `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Namespaces []string `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
+	Path       string   `yaml:"path,omitempty" json:"path,omitempty"`
+	Retention  string   `yaml:"retention,omitempty" json:"retention,omitempty"`
+	Store      string   `yaml:"store,omitempty" json:"store,omitempty"`
+}
+
+// CanaryRollouts defines the progression of the canary rollouts started through Kiali. The rollouts are progressed
+// by Kiali itself, using its service account, the progress is kept in the VirtualService of the rolled out service.
+// Durations are expressed as Go durations (e.g. "30s", "5m").
+// Interval: how often the rollouts are checked and progressed
+// RateInterval: the interval of the request rates and latency compared to the rollout thresholds
+type CanaryRollouts struct {
+	Enabled      bool   `yaml:"enabled,omitempty" json:"enabled"`
+	Interval     string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	RateInterval string `yaml:"rate_interval,omitempty" json:"rateInterval,omitempty"`
+}
+
+// Istio config history store types
+const (
+	IstioConfigHistoryStoreConfigMap  = "configmap"
+	IstioConfigHistoryStoreFilesystem = "filesystem"
+)
+
+// IstioConfigHistory defines the recording of the changes done to the Istio config through Kiali, such that
+// an object can be rolled back to a recorded revision.
+// MaxRevisions: the number of revisions kept per object, the oldest revisions are removed
+// Namespace: the namespace of the ConfigMaps holding the revisions (configmap store), the Kiali namespace when empty
+// Path: the directory holding the revisions (filesystem store)
+// Store: configmap | filesystem
+type IstioConfigHistory struct {
+	Enabled      bool   `yaml:"enabled,omitempty" json:"enabled"`
+	MaxRevisions int    `yaml:"max_revisions,omitempty" json:"maxRevisions,omitempty"`
+	Namespace    string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	Path         string `yaml:"path,omitempty" json:"path,omitempty"`
+	Store        string `yaml:"store,omitempty" json:"store,omitempty"`
+}
+
 // GraphUIDefaults defines UI Defaults specific to the UI Graph
 type GraphUIDefaults struct {
 	FindOptions []GraphFindOption `yaml:"find_options,omitempty" json:"findOptions,omitempty"`
@@ -522,8 +582,105 @@ type UIDefaults struct {
 
 // Validations defines default settings configured for the Validations subsystem
 type Validations struct {
-	Ignore                   []string `yaml:"ignore,omitempty" json:"ignore,omitempty"`
-	SkipWildcardGatewayHosts bool     `yaml:"skip_wildcard_gateway_hosts,omitempty"`
+	AdmissionWebhook         AdmissionWebhook       `yaml:"admission_webhook,omitempty" json:"-"`
+	Background               BackgroundValidations  `yaml:"background,omitempty" json:"background"`
+	CustomRules              []CustomValidationRule `yaml:"custom_rules,omitempty" json:"-"`
+	Ignore                   []string               `yaml:"ignore,omitempty" json:"ignore,omitempty"`
+	SkipWildcardGatewayHosts bool                   `yaml:"skip_wildcard_gateway_hosts,omitempty"`
+}
+
+// BackgroundValidations periodically validates the Istio config of all the clusters, exporting the check counts
+// as metrics and keeping their history for the validations trend API.
+// The history is kept in memory by each Kiali pod, it is lost on restart and may differ between replicas.
+// Durations are expressed as Go durations (e.g. "1m", "24h").
+// Interval: how often the config is validated
+// Retention: how long the counts are kept for the trend
+type BackgroundValidations struct {
+	Enabled   bool   `yaml:"enabled,omitempty" json:"enabled"`
+	Interval  string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Retention string `yaml:"retention,omitempty" json:"retention,omitempty"`
+}
+
+// CustomValidationRule is a validation rule defined by a CEL expression, evaluated on every object of the given kinds.
+// The object is valid when the expression is true, otherwise a check with the rule code, message and severity is added.
+// The expression can use the "object" variable, the whole object, and the "spec" variable, the object spec.
+// i.e. has(spec.trafficPolicy) && has(spec.trafficPolicy.outlierDetection)
+// Code: the check code, it must be unique and it can't use the KIA prefix of the Kiali checks
+// Kinds: the validated object types the rule applies to, i.e. destinationrule, virtualservice, k8shttproute
+// Path: optional path of the check in the object, i.e. spec/trafficPolicy
+// Severity: error | warning
+type CustomValidationRule struct {
+	Code       string   `yaml:"code"`
+	Expression string   `yaml:"expression"`
+	Kinds      []string `yaml:"kinds"`
+	Message    string   `yaml:"message"`
+	Path       string   `yaml:"path,omitempty"`
+	Severity   string   `yaml:"severity"`
+}
+
+// CustomValidationRuleKinds are the object types custom validation rules can apply to
+var CustomValidationRuleKinds = []string{
+	"authorizationpolicy",
+	"destinationrule",
+	"envoyfilter",
+	"gateway",
+	"k8sgateway",
+	"k8sgrpcroute",
+	"k8shttproute",
+	"k8sreferencegrant",
+	"k8stcproute",
+	"k8stlsroute",
+	"peerauthentication",
+	"requestauthentication",
+	"serviceentry",
+	"sidecar",
+	"telemetry",
+	"virtualservice",
+	"wasmplugin",
+	"workloadentry",
+	"workloadgroup",
+}
+
+// CompileCustomValidationExpression compiles a custom validation rule expression, with its "object" and "spec"
+// variables. It fails when the expression doesn't return a bool.
+func CompileCustomValidationExpression(expression string) (*cel.Env, *cel.Ast, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("spec", cel.DynType),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, nil, fmt.Errorf("the expression must return a bool, not %s", ast.OutputType())
+	}
+	return env, ast, nil
+}
+
+// Admission webhook actions
+const (
+	AdmissionActionDeny   = "deny"
+	AdmissionActionIgnore = "ignore"
+	AdmissionActionWarn   = "warn"
+)
+
+// AdmissionWebhook defines the validating admission webhook server, rejecting or warning about Istio and Gateway API
+// objects that would introduce new validation checks. It must be registered with a ValidatingWebhookConfiguration
+// pointing to the /validate path, the "clusterName" query parameter selects the cluster (default is the home cluster).
+// CertFile/PrivateKeyFile: the serving certificate, the Kiali identity is used when empty
+// ErrorAction/WarningAction: what to do on new checks of that severity: deny | warn | ignore
+// Port: the port of the webhook server, it is always served with TLS
+type AdmissionWebhook struct {
+	CertFile       string `yaml:"cert_file,omitempty"`
+	Enabled        bool   `yaml:"enabled,omitempty"`
+	ErrorAction    string `yaml:"error_action,omitempty"`
+	Port           int    `yaml:"port,omitempty"`
+	PrivateKeyFile string `yaml:"private_key_file,omitempty"`
+	WarningAction  string `yaml:"warning_action,omitempty"`
 }
 
 // CertificatesInformationIndicators defines configuration to enable the feature and to grant read permissions to a list of secrets
@@ -604,14 +761,17 @@ type Config struct {
 	AdditionalDisplayDetails []AdditionalDisplayItem             `yaml:"additional_display_details,omitempty"`
 	API                      ApiConfig                           `yaml:"api,omitempty"`
 	Auth                     AuthConfig                          `yaml:"auth,omitempty"`
+	CanaryRollouts           CanaryRollouts                      `yaml:"canary_rollouts,omitempty"`
 	Clustering               Clustering                          `yaml:"clustering,omitempty"`
 	CustomDashboards         dashboards.MonitoringDashboardsList `yaml:"custom_dashboards,omitempty"`
 	Deployment               DeploymentConfig                    `yaml:"deployment,omitempty"`
 	ExternalServices         ExternalServices                    `yaml:"external_services,omitempty"`
+	GraphSnapshots           GraphSnapshots                      `yaml:"graph_snapshots,omitempty"`
 	HealthConfig             HealthConfig                        `yaml:"health_config,omitempty" json:"healthConfig,omitempty"`
 	Identity                 security.Identity                   `yaml:",omitempty"`
 	InCluster                bool                                `yaml:"in_cluster,omitempty"`
 	InstallationTag          string                              `yaml:"installation_tag,omitempty"`
+	IstioConfigHistory       IstioConfigHistory                  `yaml:"istio_config_history,omitempty"`
 	IstioLabels              IstioLabels                         `yaml:"istio_labels,omitempty"`
 	IstioNamespace           string                              `yaml:"istio_namespace,omitempty"` // default component namespace
 	KialiFeatureFlags        KialiFeatureFlags                   `yaml:"kiali_feature_flags,omitempty"`
@@ -657,6 +817,11 @@ func NewConfig() (c *Config) {
 				UsernameClaim:           "sub",
 			},
 		},
+		CanaryRollouts: CanaryRollouts{
+			Enabled:      false,
+			Interval:     "30s",
+			RateInterval: "1m",
+		},
 		CustomDashboards: dashboards.GetBuiltInMonitoringDashboards(),
 		Deployment: DeploymentConfig{
 			AccessibleNamespaces: []string{"**"},
@@ -748,6 +913,23 @@ func NewConfig() (c *Config) {
 				WhiteListIstioSystem: []string{"jaeger-query", "istio-ingressgateway"},
 			},
 		},
+		GraphSnapshots: GraphSnapshots{
+			Duration:   "10m",
+			Enabled:    false,
+			GraphType:  "versionedApp",
+			Interval:   "",
+			Namespaces: []string{},
+			Path:       "/tmp/kiali/graph-snapshots",
+			Retention:  "720h",
+			Store:      GraphSnapshotStoreFilesystem,
+		},
+		IstioConfigHistory: IstioConfigHistory{
+			Enabled:      false,
+			MaxRevisions: 20,
+			Namespace:    "",
+			Path:         "/tmp/kiali/istio-config-history",
+			Store:        IstioConfigHistoryStoreConfigMap,
+		},
 		IstioLabels: IstioLabels{
 			AmbientNamespaceLabel:      "istio.io/dataplane-mode",
 			AmbientNamespaceLabelValue: "ambient",
@@ -850,6 +1032,17 @@ func NewConfig() (c *Config) {
 				RefreshInterval:   "60s",
 			},
 			Validations: Validations{
+				AdmissionWebhook: AdmissionWebhook{
+					Enabled:       false,
+					ErrorAction:   AdmissionActionDeny,
+					Port:          9443,
+					WarningAction: AdmissionActionWarn,
+				},
+				Background: BackgroundValidations{
+					Enabled:   false,
+					Interval:  "1m",
+					Retention: "24h",
+				},
 				Ignore: make([]string, 0),
 			},
 		},
@@ -1256,6 +1449,30 @@ func Validate(cfg Config) error {
 		return fmt.Errorf("error in configuration options for the external services tracing provider. Invalid provider type [%s]", cfgTracing.Provider)
 	}
 
+	if err := validateCanaryRollouts(cfg.CanaryRollouts); err != nil {
+		return err
+	}
+
+	if err := validateGraphSnapshots(cfg.GraphSnapshots); err != nil {
+		return err
+	}
+
+	if err := validateIstioConfigHistory(cfg.IstioConfigHistory); err != nil {
+		return err
+	}
+
+	if err := validateAdmissionWebhook(cfg.KialiFeatureFlags.Validations.AdmissionWebhook); err != nil {
+		return err
+	}
+
+	if err := validateCustomValidationRules(cfg.KialiFeatureFlags.Validations.CustomRules); err != nil {
+		return err
+	}
+
+	if err := validateBackgroundValidations(cfg.KialiFeatureFlags.Validations.Background); err != nil {
+		return err
+	}
+
 	if len(cfg.GatewayLabel(cfg.IstioLabels.IngressGatewayLabel)) != 2 {
 		return fmt.Errorf("error parsing key=value configuration. Invalid ingress gateway label [%s]", cfg.IstioLabels.IngressGatewayLabel)
 	}
@@ -1267,6 +1484,113 @@ func Validate(cfg Config) error {
 	return nil
 }
 
+func validateAdmissionWebhook(webhook AdmissionWebhook) error {
+	if !webhook.Enabled {
+		return nil
+	}
+	for name, action := range map[string]string{"error": webhook.ErrorAction, "warning": webhook.WarningAction} {
+		if action != AdmissionActionDeny && action != AdmissionActionIgnore && action != AdmissionActionWarn {
+			return fmt.Errorf("error in configuration options for the admission webhook. Invalid %s action [%s]", name, action)
+		}
+	}
+	if webhook.Port <= 0 {
+		return fmt.Errorf("error in configuration options for the admission webhook. Invalid port [%d]", webhook.Port)
+	}
+	return nil
+}
+
+func validateBackgroundValidations(background BackgroundValidations) error {
+	if !background.Enabled {
+		return nil
+	}
+	for name, value := range map[string]string{"interval": background.Interval, "retention": background.Retention} {
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("error in configuration options for the background validations. Invalid %s [%s]", name, value)
+		}
+	}
+	return nil
+}
+
+func validateCanaryRollouts(rollouts CanaryRollouts) error {
+	if !rollouts.Enabled {
+		return nil
+	}
+	for name, value := range map[string]string{"interval": rollouts.Interval, "rate interval": rollouts.RateInterval} {
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("error in configuration options for the canary rollouts. Invalid %s [%s]", name, value)
+		}
+	}
+	return nil
+}
+
+func validateCustomValidationRules(rules []CustomValidationRule) error {
+	codes := map[string]bool{}
+	for i, rule := range rules {
+		if rule.Code == "" || rule.Expression == "" || rule.Message == "" || len(rule.Kinds) == 0 {
+			return fmt.Errorf("error in configuration options for the custom validation rule [%d]. The code, expression, kinds and message are required", i)
+		}
+		if strings.HasPrefix(rule.Code, "KIA") {
+			return fmt.Errorf("error in configuration options for the custom validation rule [%s]. The KIA code prefix is reserved", rule.Code)
+		}
+		if codes[rule.Code] {
+			return fmt.Errorf("error in configuration options for the custom validation rule [%s]. The code is duplicated", rule.Code)
+		}
+		codes[rule.Code] = true
+		if rule.Severity != "error" && rule.Severity != "warning" {
+			return fmt.Errorf("error in configuration options for the custom validation rule [%s]. Invalid severity [%s]", rule.Code, rule.Severity)
+		}
+		for _, kind := range rule.Kinds {
+			if !slices.Contains(CustomValidationRuleKinds, kind) {
+				return fmt.Errorf("error in configuration options for the custom validation rule [%s]. Unsupported kind [%s], supported kinds are %v", rule.Code, kind, CustomValidationRuleKinds)
+			}
+		}
+		if _, _, err := CompileCustomValidationExpression(rule.Expression); err != nil {
+			return fmt.Errorf("error in configuration options for the custom validation rule [%s]. Invalid expression: %w", rule.Code, err)
+		}
+	}
+	return nil
+}
+
+func validateGraphSnapshots(snapshots GraphSnapshots) error {
+	if !snapshots.Enabled {
+		return nil
+	}
+	if snapshots.Store != GraphSnapshotStoreBolt && snapshots.Store != GraphSnapshotStoreFilesystem {
+		return fmt.Errorf("error in configuration options for graph snapshots. Invalid store [%s]", snapshots.Store)
+	}
+	if snapshots.Path == "" {
+		return errors.New("error in configuration options for graph snapshots. The path must be set")
+	}
+	for name, value := range map[string]string{"duration": snapshots.Duration, "interval": snapshots.Interval, "retention": snapshots.Retention} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("error in configuration options for graph snapshots. Invalid %s [%s]", name, value)
+		}
+	}
+	return nil
+}
+
+func validateIstioConfigHistory(history IstioConfigHistory) error {
+	if !history.Enabled {
+		return nil
+	}
+	switch history.Store {
+	case IstioConfigHistoryStoreConfigMap:
+	case IstioConfigHistoryStoreFilesystem:
+		if history.Path == "" {
+			return errors.New("error in configuration options for the Istio config history. The path must be set")
+		}
+	default:
+		return fmt.Errorf("error in configuration options for the Istio config history. Invalid store [%s]", history.Store)
+	}
+	if history.MaxRevisions <= 0 {
+		return fmt.Errorf("error in configuration options for the Istio config history. Invalid max revisions [%d]", history.MaxRevisions)
+	}
+	return nil
+}
+
 func validateSigningKey(signingKey string, authStrategy string) error {
 	if authStrategy != AuthStrategyAnonymous {
 		if len(signingKey) != 16 && len(signingKey) != 24 && len(signingKey) != 32 {
@@ -366,3 +366,152 @@ func TestValidateAuthStrategy(t *testing.T) {
 		}
 	}
 }
+
+// validBaseConfig returns a config that we know is valid
+func validBaseConfig() *Config {
+	rand.New(rand.NewSource(time.Now().UnixNano()))
+	conf := NewConfig()
+	conf.LoginToken.SigningKey = util.RandomString(16)
+	conf.Server.StaticContentRootDirectory = "."
+	conf.Auth.Strategy = "anonymous"
+	return conf
+}
+
+// TestValidateFeatureSections checks that the config sections of the optional features are valid with their
+// defaults once enabled, that each invalid value is rejected, and that nothing is validated when disabled
+func TestValidateFeatureSections(t *testing.T) {
+	cases := []struct {
+		name    string
+		enable  func(c *Config, enabled bool)
+		valid   []func(c *Config)
+		invalid []func(c *Config)
+	}{
+		{
+			name:   "graph snapshots",
+			enable: func(c *Config, enabled bool) { c.GraphSnapshots.Enabled = enabled },
+			invalid: []func(c *Config){
+				func(c *Config) { c.GraphSnapshots.Store = "s3" },
+				func(c *Config) { c.GraphSnapshots.Path = "" },
+				func(c *Config) { c.GraphSnapshots.Interval = "hourly" },
+				func(c *Config) { c.GraphSnapshots.Retention = "-1h" },
+			},
+		},
+		{
+			name:   "admission webhook",
+			enable: func(c *Config, enabled bool) { c.KialiFeatureFlags.Validations.AdmissionWebhook.Enabled = enabled },
+			invalid: []func(c *Config){
+				func(c *Config) { c.KialiFeatureFlags.Validations.AdmissionWebhook.ErrorAction = "reject" },
+				func(c *Config) { c.KialiFeatureFlags.Validations.AdmissionWebhook.WarningAction = "" },
+				func(c *Config) { c.KialiFeatureFlags.Validations.AdmissionWebhook.Port = 0 },
+			},
+		},
+		{
+			name:   "background validations",
+			enable: func(c *Config, enabled bool) { c.KialiFeatureFlags.Validations.Background.Enabled = enabled },
+			invalid: []func(c *Config){
+				func(c *Config) { c.KialiFeatureFlags.Validations.Background.Interval = "" },
+				func(c *Config) { c.KialiFeatureFlags.Validations.Background.Interval = "-1m" },
+				func(c *Config) { c.KialiFeatureFlags.Validations.Background.Retention = "daily" },
+			},
+		},
+		{
+			name:   "canary rollouts",
+			enable: func(c *Config, enabled bool) { c.CanaryRollouts.Enabled = enabled },
+			invalid: []func(c *Config){
+				func(c *Config) { c.CanaryRollouts.Interval = "" },
+				func(c *Config) { c.CanaryRollouts.Interval = "0s" },
+				func(c *Config) { c.CanaryRollouts.RateInterval = "1 minute" },
+			},
+		},
+		{
+			name:   "istio config history",
+			enable: func(c *Config, enabled bool) { c.IstioConfigHistory.Enabled = enabled },
+			valid: []func(c *Config){
+				func(c *Config) { c.IstioConfigHistory.Store = IstioConfigHistoryStoreFilesystem },
+			},
+			invalid: []func(c *Config){
+				func(c *Config) { c.IstioConfigHistory.Store = "etcd" },
+				func(c *Config) {
+					c.IstioConfigHistory.Store = IstioConfigHistoryStoreFilesystem
+					c.IstioConfigHistory.Path = ""
+				},
+				func(c *Config) { c.IstioConfigHistory.MaxRevisions = 0 },
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			conf := validBaseConfig()
+			tc.enable(conf, true)
+			if err := Validate(*conf); err != nil {
+				t.Errorf("Validation should have succeeded for the defaults: %v", err)
+			}
+
+			for i, validate := range tc.valid {
+				c := *conf
+				validate(&c)
+				if err := Validate(c); err != nil {
+					t.Errorf("Validation should have succeeded [%d]: %v", i, err)
+				}
+			}
+
+			for i, invalidate := range tc.invalid {
+				c := *conf
+				invalidate(&c)
+				if err := Validate(c); err == nil {
+					t.Errorf("Validation should have failed [%d]", i)
+				}
+
+				// nothing is validated when disabled
+				tc.enable(&c, false)
+				if err := Validate(c); err != nil {
+					t.Errorf("Validation should have succeeded when disabled [%d]: %v", i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCustomValidationRules(t *testing.T) {
+	conf := validBaseConfig()
+
+	valid := CustomValidationRule{
+		Code:       "ACME001",
+		Expression: "has(spec.trafficPolicy)",
+		Kinds:      []string{"destinationrule"},
+		Message:    "DestinationRule must set a traffic policy",
+		Severity:   "warning",
+	}
+	conf.KialiFeatureFlags.Validations.CustomRules = []CustomValidationRule{valid}
+	if err := Validate(*conf); err != nil {
+		t.Errorf("Custom validation rules validation should have succeeded: %v", err)
+	}
+
+	invalid := []func(r *CustomValidationRule){
+		func(r *CustomValidationRule) { r.Code = "" },
+		func(r *CustomValidationRule) { r.Code = "KIA9999" },
+		func(r *CustomValidationRule) { r.Expression = "" },
+		func(r *CustomValidationRule) { r.Kinds = nil },
+		func(r *CustomValidationRule) { r.Severity = "info" },
+		func(r *CustomValidationRule) { r.Kinds = []string{"destinationrules"} },
+		func(r *CustomValidationRule) { r.Expression = "has(spec.trafficPolicy" },
+		func(r *CustomValidationRule) { r.Expression = "spec.trafficPolicy" },
+		func(r *CustomValidationRule) { r.Expression = "size(spec.subsets)" },
+	}
+	for i, invalidate := range invalid {
+		c := *conf
+		rule := valid
+		invalidate(&rule)
+		c.KialiFeatureFlags.Validations.CustomRules = []CustomValidationRule{rule}
+		if err := Validate(c); err == nil {
+			t.Errorf("Custom validation rules validation should have failed [%d]: %+v", i, rule)
+		}
+	}
+
+	c := *conf
+	c.KialiFeatureFlags.Validations.CustomRules = []CustomValidationRule{valid, valid}
+	if err := Validate(c); err == nil {
+		t.Errorf("Custom validation rules validation should have failed for duplicated codes")
+	}
+}
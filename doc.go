@@ -1,7 +1,9 @@
 package main
 
 import (
+	"github.com/kiali/kiali/graph/analysis"
 	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/graph/snapshot"
 	"github.com/kiali/kiali/handlers/authentication"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
@@ -78,7 +80,7 @@ type LoggingParam struct {
 	Level ProxyLogLevel `json:"level"`
 }
 
-// swagger:parameters istioConfigList workloadDetails workloadUpdate serviceDetails serviceUpdate appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations podProxyDump podProxyResource podProxyLogging namespaceInfo
+// swagger:parameters istioConfigList workloadDetails workloadUpdate serviceDetails serviceUpdate appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceSLO namespaceValidations podProxyDump podProxyResource podProxyLogging namespaceInfo istioRouteSimulate istioConfigHistory istioConfigRollback istioConfigExport canaryRolloutStart canaryRollout canaryRolloutRollback
 type NamespacePathParam struct {
 	// The namespace name.
 	//
@@ -96,7 +98,7 @@ type NamespaceQueryParam struct {
 	Name string `json:"namespace"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigHistory istioConfigRollback
 type ObjectNameParam struct {
 	// The Istio object name.
 	//
@@ -105,7 +107,7 @@ type ObjectNameParam struct {
 	Name string `json:"object"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype istioConfigHistory istioConfigRollback
 type ObjectTypeParam struct {
 	// The Istio object type.
 	//
@@ -142,7 +144,7 @@ type ResourceParam struct {
 	Name string `json:"resource"`
 }
 
-// swagger:parameters serviceDetails serviceUpdate serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces
+// swagger:parameters serviceDetails serviceUpdate serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces canaryRolloutStart canaryRollout canaryRolloutRollback
 type ServiceParam struct {
 	// The service name.
 	//
@@ -160,6 +162,165 @@ type SinceTimeParam struct {
 	Name string `json:"sinceTime"`
 }
 
+// swagger:parameters validationsTrend
+type ValidationsTrendParams struct {
+	// Duration of the trend period, in seconds. All the kept counts are returned when not set.
+	//
+	// in: query
+	// required: false
+	Duration int `json:"duration"`
+
+	// Comma-separated list of namespaces to include in the counts. Default is all the accessible namespaces.
+	//
+	// in: query
+	// required: false
+	Namespaces string `json:"namespaces"`
+}
+
+// swagger:parameters istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype istioConfigBulkApply
+type DryRunParam struct {
+	// Whether the change is only submitted in dry-run mode, it is admitted but not persisted. Default is false.
+	//
+	// in: query
+	// required: false
+	Name bool `json:"dryRun"`
+}
+
+// swagger:parameters istioConfigBulkApply
+type IstioConfigBulkApplyParams struct {
+	// The Istio objects, as a multi-document YAML or a stream of JSON objects.
+	//
+	// in: body
+	// required: true
+	Body string
+
+	// Comma-separated list of the clusters where the objects are created. Default is the home cluster.
+	//
+	// in: query
+	// required: false
+	Clusters string `json:"clusters"`
+
+	// The namespace of the objects without a namespace.
+	//
+	// in: query
+	// required: false
+	Namespace string `json:"namespace"`
+}
+
+// swagger:parameters istioConfigExport
+type IstioConfigExportParams struct {
+	// The archive format: tar (gzip compressed) or zip. Default is tar.
+	//
+	// in: query
+	// required: false
+	Format string `json:"format"`
+
+	// Whether the Services of the namespace referenced by the Istio config are exported too. Default is false.
+	//
+	// in: query
+	// required: false
+	IncludeServices bool `json:"includeServices"`
+}
+
+// swagger:parameters istioConfigRollback
+type RevisionParam struct {
+	// The id of the revision restored.
+	//
+	// in: path
+	// required: true
+	Name string `json:"revision"`
+}
+
+// swagger:parameters istioCheckFix
+type IstioCheckFixParams struct {
+	// The fix of a validation check to preview or apply.
+	//
+	// in: body
+	// required: true
+	Body models.IstioCheckFix
+
+	// Whether the fix is only previewed. Default is true.
+	//
+	// in: query
+	// required: false
+	Preview bool `json:"preview"`
+}
+
+// swagger:parameters canaryRolloutStart
+type CanaryRolloutStartParams struct {
+	// The versions rolled out, the weights and the health thresholds.
+	//
+	// in: body
+	// required: true
+	Body models.CanaryRolloutSpec
+}
+
+// swagger:parameters istioRouteSimulate
+type IstioRouteSimulateParams struct {
+	// The HTTP request to route.
+	//
+	// in: body
+	// required: true
+	Body models.RouteSimulationRequest
+}
+
+// swagger:parameters workloadAuthorization
+type WorkloadAuthorizationParams struct {
+	// Namespace of the source workload.
+	//
+	// in: query
+	// required: false
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// Service account of the source workload. Default is the default service account.
+	//
+	// in: query
+	// required: false
+	SourceServiceAccount string `json:"sourceServiceAccount"`
+
+	// Principal of the source, instead of its namespace and service account.
+	//
+	// in: query
+	// required: false
+	SourcePrincipal string `json:"sourcePrincipal"`
+
+	// IP of the source.
+	//
+	// in: query
+	// required: false
+	SourceIP string `json:"sourceIP"`
+
+	// Principal of the request, from its JWT.
+	//
+	// in: query
+	// required: false
+	RequestPrincipal string `json:"requestPrincipal"`
+
+	// Host of the request.
+	//
+	// in: query
+	// required: false
+	Host string `json:"host"`
+
+	// Destination port.
+	//
+	// in: query
+	// required: false
+	Port int `json:"port"`
+
+	// HTTP method of the request, not set for TCP traffic.
+	//
+	// in: query
+	// required: false
+	Method string `json:"method"`
+
+	// HTTP path of the request, not set for TCP traffic.
+	//
+	// in: query
+	// required: false
+	Path string `json:"path"`
+}
+
 // swagger:parameters podLogs
 type DurationLogParam struct {
 	// Query time-range duration (Golang string duration). Duration starts on
@@ -202,9 +363,9 @@ type WorkloadParam struct {
 // - keep this alphabetized
 /////////////////////
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type AppendersParam struct {
-	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, deadNode, healthConfig, idleNode, istio, responseTime, securityPolicy, serviceEntry, sidecarsCheck, throughput].
+	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, anomaly, deadNode, healthConfig, idleNode, istio, resilience, responseTime, securityPolicy, serviceEntry, sidecarsCheck, slo, throughput].
 	//
 	// in: query
 	// required: false
@@ -212,7 +373,47 @@ type AppendersParam struct {
 	Name string `json:"appenders"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesStream graphService graphWorkload
+type AnomalyOffsetParam struct {
+	// Used only with anomaly appender. How far back the baseline is (Prometheus duration).
+	//
+	// in: query
+	// required: false
+	// default: 1w
+	Name string `json:"anomalyOffset"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesStream graphService graphWorkload
+type AnomalyThresholdParam struct {
+	// Used only with anomaly appender. The ratio to the baseline considered anomalous, must be > 1.
+	//
+	// in: query
+	// required: false
+	// default: 2
+	Name string `json:"anomalyThreshold"`
+}
+
+// swagger:parameters graphNamespacesDiff
+type BaselineDurationParam struct {
+	// Baseline query time-range duration (Golang string duration).
+	//
+	// in: query
+	// required: false
+	// default: duration
+	Name string `json:"baselineDuration"`
+}
+
+// swagger:parameters graphNamespacesDiff
+type BaselineQueryTimeParam struct {
+	// Unix time (seconds) for the baseline query such that time range is [baselineQueryTime-baselineDuration..baselineQueryTime]. Default is queryTime-duration.
+	//
+	// in: query
+	// required: false
+	// default: queryTime-duration
+	Name string `json:"baselineQueryTime"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesStream graphService graphWorkload
 type BoxByParam struct {
 	// Comma-separated list of desired node boxing. Available boxings: [app, cluster, namespace].
 	//
@@ -221,7 +422,27 @@ type BoxByParam struct {
 	Name string `json:"boxBy"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesStream graphService graphWorkload
+type CollapseThresholdParam struct {
+	// Used only with the collapse layout hint. Percent of a parent's outbound traffic under which a leaf node is folded.
+	//
+	// in: query
+	// required: false
+	// default: 1
+	Name string `json:"collapseThreshold"`
+}
+
+// swagger:parameters graphNamespacesDiff
+type DiffToleranceParam struct {
+	// Percent difference tolerated before a rate, error rate, response time or throughput is considered changed.
+	//
+	// in: query
+	// required: false
+	// default: 10
+	Name string `json:"diffTolerance"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type DurationGraphParam struct {
 	// Query time-range duration (Golang string duration).
 	//
@@ -231,7 +452,16 @@ type DurationGraphParam struct {
 	Name string `json:"duration"`
 }
 
-// swagger:parameters graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesStream graphService graphWorkload
+type FindParam struct {
+	// Graph find expression, using the graph toolbar's find/hide language. Matching nodes and edges are flagged with isFindHit.
+	//
+	// in: query
+	// required: false
+	Name string `json:"find"`
+}
+
+// swagger:parameters graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphService graphSnapshotCreate graphWorkload namespaceSLO
 type GraphTypeParam struct {
 	// Graph type. Available graph types: [app, service, versionedApp, workload].
 	//
@@ -250,7 +480,16 @@ type AppGraphTypeParam struct {
 	Name string `json:"graphType"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesStream graphService graphWorkload
+type HideParam struct {
+	// Graph hide expression, using the graph toolbar's find/hide language. Matching nodes and edges are removed from the graph.
+	//
+	// in: query
+	// required: false
+	Name string `json:"hide"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphSnapshotCreate graphWorkload
 type IncludeIdleEdges struct {
 	// Flag for including edges that have no request traffic for the time period.
 	//
@@ -260,7 +499,7 @@ type IncludeIdleEdges struct {
 	Name string `json:"includeIdleEdges"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphSnapshotCreate graphWorkload
 type InjectServiceNodes struct {
 	// Flag for injecting the requested service node between source and destination nodes.
 	//
@@ -270,7 +509,16 @@ type InjectServiceNodes struct {
 	Name string `json:"injectServiceNodes"`
 }
 
-// swagger:parameters graphNamespaces
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesStream graphService graphWorkload
+type LayoutHintsParam struct {
+	// Comma-separated list of layout hints to compute server-side. Available layout hints: [collapse, communities].
+	//
+	// in: query
+	// required: false
+	Name string `json:"layoutHints"`
+}
+
+// swagger:parameters graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphSnapshotCreate
 type NamespacesParam struct {
 	// Comma-separated list of namespaces to include in the graph. The namespaces must be accessible to the client.
 	//
@@ -279,7 +527,16 @@ type NamespacesParam struct {
 	Name string `json:"namespaces"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphNamespacesBlastRadius
+type NodeParam struct {
+	// The ID of the node to analyze, either the traffic map node ID or the cytoscape node ID.
+	//
+	// in: query
+	// required: true
+	Name string `json:"node"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphService graphSnapshotCreate graphWorkload namespaceSLO
 type QueryTimeParam struct {
 	// Unix time (seconds) for query such that time range is [queryTime-duration..queryTime]. Default is now.
 	//
@@ -289,7 +546,7 @@ type QueryTimeParam struct {
 	Name string `json:"queryTime"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphService graphSnapshotCreate graphWorkload namespaceSLO
 type RateGrpcParam struct {
 	// How to calculate gRPC traffic rate. One of: none | received (i.e. response_messages) | requests | sent (i.e. request_messages) | total (i.e. sent+received).
 	//
@@ -299,7 +556,7 @@ type RateGrpcParam struct {
 	Name string `json:"rateGrpc"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphService graphSnapshotCreate graphWorkload namespaceSLO
 type RateHttpParam struct {
 	// How to calculate HTTP traffic rate. One of: none | requests.
 	//
@@ -309,7 +566,7 @@ type RateHttpParam struct {
 	Name string `json:"rateHttp"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesBlastRadius graphNamespacesDiff graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type RateTcpParam struct {
 	// How to calculate TCP traffic rate. One of: none | received (i.e. received_bytes) | sent (i.e. sent_bytes) | total (i.e. sent+received).
 	//
@@ -319,7 +576,17 @@ type RateTcpParam struct {
 	Name string `json:"rateTcp"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphNamespacesStream
+type RefreshIntervalParam struct {
+	// Time between graph regenerations (Golang string duration). Minimum is 5s.
+	//
+	// in: query
+	// required: false
+	// default: 15s
+	Name string `json:"refreshInterval"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesStream graphService graphWorkload
 type ResponseTimeParam struct {
 	// Used only with responseTime appender. One of: avg | 50 | 95 | 99.
 	//
@@ -329,7 +596,16 @@ type ResponseTimeParam struct {
 	Name string `json:"responseTime"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphNamespaces graphNamespacesBlastRadius
+type SnapshotParam struct {
+	// The ID of a stored graph snapshot. The graph is served from the snapshot instead of generated from telemetry, using the snapshot's namespaces, graph type and time range.
+	//
+	// in: query
+	// required: false
+	Name string `json:"snapshot"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesStream graphService graphWorkload
 type ThroughputParam struct {
 	// Used only with throughput appender. One of: request | response.
 	//
@@ -590,6 +866,34 @@ type GraphResponse struct {
 	Body cytoscape.Config
 }
 
+// HTTP status code 200 and the blast radius analysis in data
+// swagger:response blastRadiusResponse
+type BlastRadiusResponse struct {
+	// in:body
+	Body analysis.BlastRadius
+}
+
+// HTTP status code 200 and the namespace error budget summary in data
+// swagger:response sloResponse
+type SLOResponse struct {
+	// in:body
+	Body analysis.SLOSummary
+}
+
+// HTTP status code 201 and the stored graph snapshot info in data
+// swagger:response graphSnapshotResponse
+type GraphSnapshotResponse struct {
+	// in:body
+	Body snapshot.Info
+}
+
+// HTTP status code 200 and the accessible graph snapshots in data
+// swagger:response graphSnapshotsResponse
+type GraphSnapshotsResponse struct {
+	// in:body
+	Body []snapshot.Info
+}
+
 // HTTP status code 200 and IstioConfigList model in data
 // swagger:response istioConfigList
 type IstioConfigResponse struct {
@@ -744,6 +1048,74 @@ type NamespaceValidationSummaryResponse struct {
 	Body models.IstioValidationSummary
 }
 
+// swagger:response validationsTrendResponse
+type ValidationsTrendResponse struct {
+	// in:body
+	Body models.ValidationsTrend
+}
+
+// swagger:response meshValidationsResponse
+type MeshValidationsResponse struct {
+	// in:body
+	Body models.MeshValidations
+}
+
+// swagger:response istioConfigDryRunResponse
+type IstioConfigDryRunResponse struct {
+	// in:body
+	Body models.IstioConfigDryRun
+}
+
+// swagger:response istioConfigHistoryResponse
+type IstioConfigHistoryResponse struct {
+	// in:body
+	Body []models.IstioConfigRevision
+}
+
+// swagger:response istioConfigRevisionResponse
+type IstioConfigRevisionResponse struct {
+	// in:body
+	Body models.IstioConfigRevision
+}
+
+// swagger:response istioConfigBulkApplyResponse
+type IstioConfigBulkApplyResponse struct {
+	// in:body
+	Body models.IstioConfigBulkApply
+}
+
+// swagger:response canaryRolloutResponse
+type CanaryRolloutResponse struct {
+	// in:body
+	Body models.CanaryRollout
+}
+
+// swagger:response istioConfigExportResponse
+type IstioConfigExportResponse struct {
+	// The archive of the Istio config
+	//
+	// in:body
+	Body []byte
+}
+
+// swagger:response istioCheckFixResponse
+type IstioCheckFixResponse struct {
+	// in:body
+	Body models.IstioCheckFixResult
+}
+
+// swagger:response authorizationSimulationResponse
+type AuthorizationSimulationResponse struct {
+	// in:body
+	Body models.AuthorizationSimulation
+}
+
+// swagger:response routeSimulationResponse
+type RouteSimulationResponse struct {
+	// in:body
+	Body models.RouteSimulation
+}
+
 // Return a dump of the configuration of a given envoy proxy
 // swagger:response configDump
 type ConfigDumpResponse struct {
@@ -0,0 +1,302 @@
+// Package analysis provides analyses performed on a computed graph.TrafficMap
+package analysis
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+// NodeReference identifies a graph node
+type NodeReference struct {
+	// ID is the cytoscape node ID, matching the node ID in the cytoscape graph
+	ID        string `json:"id"`
+	NodeType  string `json:"nodeType"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload,omitempty"`
+	App       string `json:"app,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Service   string `json:"service,omitempty"`
+}
+
+// Node identifies a node related to the analyzed node
+type Node struct {
+	NodeReference
+
+	// Depth is the minimum number of hops from the analyzed node
+	Depth int `json:"depth"`
+	// IsEntryPoint is true for traffic generators and ingress gateways
+	IsEntryPoint bool `json:"isEntryPoint,omitempty"`
+	// Weight is the fraction [0..1] of traffic on the paths between the analyzed node and this node.
+	// For a downstream node it is the fraction of the analyzed node's outbound traffic reaching the node,
+	// for an upstream node it is the fraction of the analyzed node's inbound traffic sent via the node.
+	Weight float64 `json:"weight"`
+}
+
+// BlastRadius describes the nodes affected by a degradation of the analyzed node
+type BlastRadius struct {
+	Node Node `json:"node"`
+	// AffectedEntryPoints are the upstream entry points (or the node itself, if it is an entry point)
+	AffectedEntryPoints []Node `json:"affectedEntryPoints"`
+	// Downstream are the nodes (transitively) called by the analyzed node
+	Downstream []Node `json:"downstream"`
+	// Upstream are the nodes (transitively) calling the analyzed node
+	Upstream []Node `json:"upstream"`
+	// IngressRate is the total request rate entering the graph at the entry points
+	IngressRate float64 `json:"ingressRate"`
+	// NodeIngressRate is the portion of IngressRate traversing the analyzed node
+	NodeIngressRate float64 `json:"nodeIngressRate"`
+	// PercentIngress is NodeIngressRate as a percentage of IngressRate
+	PercentIngress float64 `json:"percentIngress"`
+}
+
+// AnalyzeBlastRadius returns the upstream callers and downstream dependencies of the node, along with
+// the percentage of ingress traffic traversing the node. The node ID can be either the traffic map node ID
+// or the cytoscape node ID. An error is returned if the node is not found in the traffic map.
+//
+// Path weights are derived from the edge rates, traffic is assumed to be split proportionally over a
+// node's edges. Rates are only compared to rates of the same unit (i.e. request rates are not compared
+// to TCP throughput). Cycles are broken by ignoring edges back to a node already on the walked path.
+func AnalyzeBlastRadius(trafficMap graph.TrafficMap, nodeID string) (*BlastRadius, error) {
+	node := findNode(trafficMap, nodeID)
+	if node == nil {
+		return nil, fmt.Errorf("node [%s] not found in the graph", nodeID)
+	}
+
+	inEdges := make(map[string][]*graph.Edge, len(trafficMap))
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			inEdges[e.Dest.ID] = append(inEdges[e.Dest.ID], e)
+		}
+	}
+
+	outbound := walker{
+		edges: func(n *graph.Node) []*graph.Edge { return n.Edges },
+		next:  func(e *graph.Edge) *graph.Node { return e.Dest },
+	}
+	inbound := walker{
+		edges: func(n *graph.Node) []*graph.Edge { return inEdges[n.ID] },
+		next:  func(e *graph.Edge) *graph.Node { return e.Source },
+	}
+
+	ingressRate, ingressFlow := ingress(trafficMap, outbound)
+
+	result := &BlastRadius{
+		Node:                toNode(node, 0, 1.0),
+		AffectedEntryPoints: []Node{},
+		Downstream:          outbound.related(node),
+		Upstream:            inbound.related(node),
+		IngressRate:         ingressRate,
+		NodeIngressRate:     ingressFlow[node.ID],
+	}
+	if ingressRate > 0 {
+		result.PercentIngress = min(100.0, result.NodeIngressRate/ingressRate*100.0)
+	}
+	if result.Node.IsEntryPoint {
+		result.AffectedEntryPoints = append(result.AffectedEntryPoints, result.Node)
+	}
+	for _, n := range result.Upstream {
+		if n.IsEntryPoint {
+			result.AffectedEntryPoints = append(result.AffectedEntryPoints, n)
+		}
+	}
+
+	return result, nil
+}
+
+// walker walks the traffic map in one direction
+type walker struct {
+	edges func(n *graph.Node) []*graph.Edge
+	next  func(e *graph.Edge) *graph.Node
+}
+
+// order returns the nodes reachable from the start nodes in topological order, along with the set of
+// back edges that were ignored to make the walk acyclic
+func (w walker) order(start []*graph.Node) (ordered []*graph.Node, backEdges map[*graph.Edge]bool) {
+	backEdges = make(map[*graph.Edge]bool)
+	visited := make(map[string]bool)
+	onPath := make(map[string]bool)
+	postOrder := []*graph.Node{}
+
+	var visit func(n *graph.Node)
+	visit = func(n *graph.Node) {
+		visited[n.ID] = true
+		onPath[n.ID] = true
+		for _, e := range w.edges(n) {
+			next := w.next(e)
+			switch {
+			case onPath[next.ID]:
+				backEdges[e] = true
+			case !visited[next.ID]:
+				visit(next)
+			}
+		}
+		onPath[n.ID] = false
+		postOrder = append(postOrder, n)
+	}
+	for _, n := range start {
+		if !visited[n.ID] {
+			visit(n)
+		}
+	}
+
+	ordered = make([]*graph.Node, len(postOrder))
+	for i, n := range postOrder {
+		ordered[len(postOrder)-1-i] = n
+	}
+	return ordered, backEdges
+}
+
+// related returns the nodes reachable from the start node, weighted by the fraction of the start
+// node's traffic reaching them
+func (w walker) related(start *graph.Node) []Node {
+	ordered, backEdges := w.order([]*graph.Node{start})
+
+	weights := map[string]float64{start.ID: 1.0}
+	depths := map[string]int{start.ID: 0}
+	queue := []*graph.Node{start}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, e := range w.edges(n) {
+			if next := w.next(e); next != nil {
+				if _, ok := depths[next.ID]; !ok {
+					depths[next.ID] = depths[n.ID] + 1
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	for _, n := range ordered {
+		totals := unitTotals(w.edges(n))
+		for _, e := range w.edges(n) {
+			if backEdges[e] {
+				continue
+			}
+			rate, unit := graph.EdgeTotalRate(e)
+			if totals[unit] > 0 {
+				weights[w.next(e).ID] += weights[n.ID] * rate / totals[unit]
+			}
+		}
+	}
+
+	related := make([]Node, 0, len(ordered)-1)
+	for _, n := range ordered {
+		if n == start {
+			continue
+		}
+		related = append(related, toNode(n, depths[n.ID], min(1.0, weights[n.ID])))
+	}
+	sort.Slice(related, func(i, j int) bool {
+		switch {
+		case related[i].Depth != related[j].Depth:
+			return related[i].Depth < related[j].Depth
+		case related[i].Weight != related[j].Weight:
+			return related[i].Weight > related[j].Weight
+		default:
+			return related[i].ID < related[j].ID
+		}
+	})
+	return related
+}
+
+// ingress returns the total request rate entering the graph at the entry points, and for each node
+// the portion of that rate flowing through the node. An entry point downstream of another entry point
+// (e.g. an ingress gateway called by the unknown node) only injects the rate not already flowing into it.
+func ingress(trafficMap graph.TrafficMap, outbound walker) (float64, map[string]float64) {
+	flow := make(map[string]float64, len(trafficMap))
+
+	entryPoints := []*graph.Node{}
+	for _, n := range trafficMap {
+		if isEntryPoint(n) {
+			entryPoints = append(entryPoints, n)
+		}
+	}
+	sort.Slice(entryPoints, func(i, j int) bool {
+		return entryPoints[i].ID < entryPoints[j].ID
+	})
+
+	ordered, backEdges := outbound.order(entryPoints)
+
+	total := 0.0
+	for _, n := range ordered {
+		requestTotal := unitTotals(n.Edges)[requestsPerSecond]
+		if isEntryPoint(n) && requestTotal > flow[n.ID] {
+			total += requestTotal - flow[n.ID]
+			flow[n.ID] = requestTotal
+		}
+		if requestTotal == 0 {
+			continue
+		}
+		for _, e := range n.Edges {
+			if backEdges[e] {
+				continue
+			}
+			if rate, unit := graph.EdgeTotalRate(e); unit == requestsPerSecond {
+				flow[e.Dest.ID] += flow[n.ID] * rate / requestTotal
+			}
+		}
+	}
+
+	return total, flow
+}
+
+// requestsPerSecond is the unit shared by the request protocols
+var requestsPerSecond = graph.HTTP.Unit
+
+// unitTotals returns the summed edge rates, keyed by unit
+func unitTotals(edges []*graph.Edge) map[string]float64 {
+	totals := map[string]float64{}
+	for _, e := range edges {
+		rate, unit := graph.EdgeTotalRate(e)
+		totals[unit] += rate
+	}
+	return totals
+}
+
+func isEntryPoint(n *graph.Node) bool {
+	for _, k := range []graph.MetadataKey{graph.IsRoot, graph.IsIngressGateway, graph.IsGatewayAPI} {
+		if _, ok := n.Metadata[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func findNode(trafficMap graph.TrafficMap, nodeID string) *graph.Node {
+	if n, ok := trafficMap[nodeID]; ok {
+		return n
+	}
+	for id, n := range trafficMap {
+		if cytoscape.NodeID(id) == nodeID {
+			return n
+		}
+	}
+	return nil
+}
+
+func toNode(n *graph.Node, depth int, weight float64) Node {
+	return Node{
+		NodeReference: toNodeReference(n),
+		Depth:         depth,
+		IsEntryPoint:  isEntryPoint(n),
+		Weight:        weight,
+	}
+}
+
+func toNodeReference(n *graph.Node) NodeReference {
+	return NodeReference{
+		ID:        cytoscape.NodeID(n.ID),
+		NodeType:  n.NodeType,
+		Cluster:   n.Cluster,
+		Namespace: n.Namespace,
+		Workload:  n.Workload,
+		App:       n.App,
+		Version:   n.Version,
+		Service:   n.Service,
+	}
+}
@@ -0,0 +1,155 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+func addEdge(source, dest *graph.Node, protocol string, rate float64) {
+	e := source.AddEdge(dest)
+	e.Metadata[graph.ProtocolKey] = protocol
+	e.Metadata[graph.MetadataKey(protocol)] = rate
+}
+
+// unknown -> istio-ingressgateway -> productpage -> details, reviews-v1 and reviews-v2. Both reviews versions
+// call ratings, which calls back to reviews-v1 (a cycle). reviews-v2 also calls mysql (tcp).
+func testTrafficMap(t *testing.T) graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+	newNode := func(namespace, workload, app, version string) *graph.Node {
+		n, err := graph.NewNode("east", "", "", namespace, workload, app, version, graph.GraphTypeVersionedApp)
+		require.NoError(t, err)
+		trafficMap[n.ID] = n
+		return n
+	}
+
+	unknown, err := graph.NewNode(graph.Unknown, graph.Unknown, "", graph.Unknown, graph.Unknown, graph.Unknown, graph.Unknown, graph.GraphTypeVersionedApp)
+	require.NoError(t, err)
+	unknown.Metadata[graph.IsRoot] = true
+	trafficMap[unknown.ID] = unknown
+	gateway := newNode("istio-system", "istio-ingressgateway", "istio-ingressgateway", "latest")
+	gateway.Metadata[graph.IsIngressGateway] = map[string][]string{"bookinfo-gateway": {"*"}}
+	productpage := newNode("bookinfo", "productpage-v1", "productpage", "v1")
+	details := newNode("bookinfo", "details-v1", "details", "v1")
+	reviewsV1 := newNode("bookinfo", "reviews-v1", "reviews", "v1")
+	reviewsV2 := newNode("bookinfo", "reviews-v2", "reviews", "v2")
+	ratings := newNode("bookinfo", "ratings-v1", "ratings", "v1")
+	mysql := newNode("bookinfo", "mysql-v1", "mysql", "v1")
+
+	addEdge(unknown, gateway, "http", 100.0)
+	addEdge(gateway, productpage, "http", 100.0)
+	addEdge(productpage, details, "http", 50.0)
+	addEdge(productpage, reviewsV1, "http", 25.0)
+	addEdge(productpage, reviewsV2, "http", 25.0)
+	addEdge(reviewsV1, ratings, "http", 10.0)
+	addEdge(reviewsV2, ratings, "http", 20.0)
+	addEdge(reviewsV2, mysql, "tcp", 500.0)
+	addEdge(ratings, reviewsV1, "http", 5.0)
+
+	return trafficMap
+}
+
+func findByWorkload(nodes []Node, workload string) *Node {
+	for i := range nodes {
+		if nodes[i].Workload == workload {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+func findID(t *testing.T, trafficMap graph.TrafficMap, workload string) string {
+	for id, n := range trafficMap {
+		if n.Workload == workload {
+			return id
+		}
+	}
+	require.Failf(t, "workload not found", "%s", workload)
+	return ""
+}
+
+func TestBlastRadiusDownstream(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	trafficMap := testTrafficMap(t)
+	result, err := AnalyzeBlastRadius(trafficMap, findID(t, trafficMap, "productpage-v1"))
+	require.NoError(err)
+
+	assert.Equal("productpage", result.Node.App)
+	require.Len(result.Downstream, 5)
+	assert.Equal("details-v1", result.Downstream[0].Workload)
+
+	expected := map[string]struct {
+		depth  int
+		weight float64
+	}{
+		"details-v1": {1, 0.5},
+		"reviews-v1": {1, 0.25},
+		"reviews-v2": {1, 0.25},
+		"ratings-v1": {2, 0.5},
+		"mysql-v1":   {2, 0.25},
+	}
+	for workload, e := range expected {
+		n := findByWorkload(result.Downstream, workload)
+		require.NotNil(n, workload)
+		assert.Equal(e.depth, n.Depth, workload)
+		assert.InDelta(e.weight, n.Weight, 0.0001, workload)
+	}
+
+	require.Len(result.Upstream, 2)
+	assert.Equal("istio-ingressgateway", result.Upstream[0].Workload)
+	assert.True(result.Upstream[0].IsEntryPoint)
+	assert.Equal(1.0, result.Upstream[0].Weight)
+	assert.Equal(graph.Unknown, result.Upstream[1].Workload)
+	assert.Equal(2, result.Upstream[1].Depth)
+	assert.Len(result.AffectedEntryPoints, 2)
+
+	// the gateway is called by the unknown node, its traffic is not counted twice
+	assert.Equal(100.0, result.IngressRate)
+	assert.Equal(100.0, result.NodeIngressRate)
+	assert.Equal(100.0, result.PercentIngress)
+}
+
+func TestBlastRadiusUpstream(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	trafficMap := testTrafficMap(t)
+	reviewsV2ID := findID(t, trafficMap, "reviews-v2")
+
+	// the cytoscape node ID is also accepted
+	result, err := AnalyzeBlastRadius(trafficMap, cytoscape.NodeID(reviewsV2ID))
+	require.NoError(err)
+	assert.Equal(cytoscape.NodeID(reviewsV2ID), result.Node.ID)
+
+	require.Len(result.Downstream, 3)
+	assert.InDelta(1.0, findByWorkload(result.Downstream, "ratings-v1").Weight, 0.0001)
+	assert.InDelta(1.0, findByWorkload(result.Downstream, "mysql-v1").Weight, 0.0001)
+	// reached through the ratings -> reviews-v1 edge
+	assert.Equal(2, findByWorkload(result.Downstream, "reviews-v1").Depth)
+
+	require.Len(result.Upstream, 3)
+	assert.Equal("productpage-v1", result.Upstream[0].Workload)
+	assert.Len(result.AffectedEntryPoints, 2)
+
+	assert.Equal(100.0, result.IngressRate)
+	assert.InDelta(25.0, result.NodeIngressRate, 0.0001)
+	assert.InDelta(25.0, result.PercentIngress, 0.0001)
+
+	// no request traffic reaches mysql
+	result, err = AnalyzeBlastRadius(trafficMap, findID(t, trafficMap, "mysql-v1"))
+	require.NoError(err)
+	assert.Empty(result.Downstream)
+	assert.Equal(0.0, result.PercentIngress)
+	assert.Equal(1.0, findByWorkload(result.Upstream, "reviews-v2").Weight)
+}
+
+func TestBlastRadiusNodeNotFound(t *testing.T) {
+	_, err := AnalyzeBlastRadius(testTrafficMap(t), "missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// SLOStatus is the error budget status of a service node, or of a request edge
+type SLOStatus struct {
+	// Source is the calling node, set only for edges
+	Source *NodeReference `json:"source,omitempty"`
+	// Target is the service node, or the edge's destination node
+	Target NodeReference `json:"target"`
+	// Protocol is the edge protocol, set only for edges
+	Protocol string `json:"protocol,omitempty"`
+	// BudgetRemaining is the percentage of the error budget remaining over the longest window
+	BudgetRemaining float64 `json:"budgetRemaining"`
+	// BurnRates are keyed by window, a burn rate of 1 consumes the error budget exactly
+	BurnRates map[string]float64 `json:"burnRates"`
+	// IsBurning is true if both windows of any window pair exceed the pair's threshold
+	IsBurning bool `json:"isBurning"`
+}
+
+// SLOSummary summarizes the error budget burn rates of a namespace
+type SLOSummary struct {
+	Namespace string `json:"namespace"`
+	// Windows are the burn rate windows, shortest first
+	Windows []string `json:"windows"`
+	// Services are the namespace's service nodes
+	Services []SLOStatus `json:"services"`
+	// Edges are the request edges into, or out of, the namespace
+	Edges []SLOStatus `json:"edges"`
+}
+
+// SummarizeSLO returns the error budget status of the namespace's service nodes and of the request edges into,
+// or out of, the namespace. Only nodes and edges with graph.BurnRate metadata, set by the slo appender, are
+// included. Burning elements are listed first, then by lowest budget remaining.
+func SummarizeSLO(trafficMap graph.TrafficMap, namespace string, windows []string) *SLOSummary {
+	summary := &SLOSummary{
+		Namespace: namespace,
+		Windows:   windows,
+		Services:  []SLOStatus{},
+		Edges:     []SLOStatus{},
+	}
+
+	for _, n := range trafficMap {
+		if n.NodeType == graph.NodeTypeService && n.Namespace == namespace {
+			if status, ok := toSLOStatus(n.Metadata); ok {
+				status.Target = toNodeReference(n)
+				summary.Services = append(summary.Services, status)
+			}
+		}
+		for _, e := range n.Edges {
+			if e.Source.Namespace != namespace && e.Dest.Namespace != namespace {
+				continue
+			}
+			if status, ok := toSLOStatus(e.Metadata); ok {
+				source := toNodeReference(e.Source)
+				status.Source = &source
+				status.Target = toNodeReference(e.Dest)
+				status.Protocol, _ = e.Metadata[graph.ProtocolKey].(string)
+				summary.Edges = append(summary.Edges, status)
+			}
+		}
+	}
+
+	sortSLOStatus(summary.Services)
+	sortSLOStatus(summary.Edges)
+
+	return summary
+}
+
+func toSLOStatus(md graph.Metadata) (SLOStatus, bool) {
+	info, ok := md[graph.BurnRate].(*graph.BurnRateInfo)
+	if !ok {
+		return SLOStatus{}, false
+	}
+	budgetRemaining, _ := md[graph.BudgetRemaining].(float64)
+	return SLOStatus{
+		BudgetRemaining: budgetRemaining,
+		BurnRates:       info.Rates,
+		IsBurning:       info.IsBurning,
+	}, true
+}
+
+// sortSLOStatus sorts burning first, then by lowest budget remaining, then by ID for a stable order
+func sortSLOStatus(statuses []SLOStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		si, sj := statuses[i], statuses[j]
+		if si.IsBurning != sj.IsBurning {
+			return si.IsBurning
+		}
+		if si.BudgetRemaining != sj.BudgetRemaining {
+			return si.BudgetRemaining < sj.BudgetRemaining
+		}
+		if si.Target.ID != sj.Target.ID {
+			return si.Target.ID < sj.Target.ID
+		}
+		return si.Source != nil && sj.Source != nil && si.Source.ID < sj.Source.ID
+	})
+}
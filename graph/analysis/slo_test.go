@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestSummarizeSLO(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	trafficMap := graph.NewTrafficMap()
+	newNode := func(namespace, service, workload, app string) *graph.Node {
+		workloadNamespace := namespace
+		if workload == "" {
+			workloadNamespace = ""
+		}
+		n, err := graph.NewNode("east", namespace, service, workloadNamespace, workload, app, "v1", graph.GraphTypeVersionedApp)
+		require.NoError(err)
+		trafficMap[n.ID] = n
+		return n
+	}
+	setBurnRate := func(md graph.Metadata, burning bool, budgetRemaining float64) {
+		md[graph.BurnRate] = &graph.BurnRateInfo{IsBurning: burning, Rates: map[string]float64{"5m": 1.0}}
+		md[graph.BudgetRemaining] = budgetRemaining
+	}
+
+	productpage := newNode("bookinfo", "", "productpage-v1", "productpage")
+	reviews := newNode("bookinfo", "reviews", "", "")
+	details := newNode("bookinfo", "details", "", "")
+	ratings := newNode("bookinfo", "ratings", "", "")
+	external := newNode("travels", "travels", "", "")
+
+	setBurnRate(reviews.Metadata, false, 20.0)
+	setBurnRate(details.Metadata, true, 80.0)
+	setBurnRate(external.Metadata, true, 0.0) // another namespace
+	// ratings has no burn rate (e.g. no matching tolerance)
+
+	e := productpage.AddEdge(reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	setBurnRate(e.Metadata, false, 10.0)
+	e = productpage.AddEdge(details)
+	e.Metadata[graph.ProtocolKey] = "grpc"
+	setBurnRate(e.Metadata, false, 90.0)
+	e = productpage.AddEdge(ratings)
+	e.Metadata[graph.ProtocolKey] = "http"
+	e = external.AddEdge(reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	setBurnRate(e.Metadata, false, 50.0)
+
+	summary := SummarizeSLO(trafficMap, "bookinfo", []string{"5m"})
+	assert.Equal("bookinfo", summary.Namespace)
+	assert.Equal([]string{"5m"}, summary.Windows)
+
+	require.Len(summary.Services, 2)
+	assert.Equal("details", summary.Services[0].Target.Service, "burning first")
+	assert.Equal("reviews", summary.Services[1].Target.Service)
+	assert.Nil(summary.Services[0].Source)
+	assert.Equal(1.0, summary.Services[0].BurnRates["5m"])
+
+	require.Len(summary.Edges, 3)
+	assert.Equal("productpage-v1", summary.Edges[0].Source.Workload, "lowest budget remaining first")
+	assert.Equal("reviews", summary.Edges[0].Target.Service)
+	assert.Equal("http", summary.Edges[0].Protocol)
+	assert.Equal(10.0, summary.Edges[0].BudgetRemaining)
+	assert.Equal("travels", summary.Edges[1].Source.Namespace, "edges into the namespace are included")
+	assert.Equal("grpc", summary.Edges[2].Protocol)
+}
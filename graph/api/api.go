@@ -6,9 +6,15 @@ import (
 	"net/http"
 
 	"github.com/kiali/kiali/business"
+	kialiConfig "github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/analysis"
 	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/graph/config/export"
+	"github.com/kiali/kiali/graph/find"
+	"github.com/kiali/kiali/graph/snapshot"
 	"github.com/kiali/kiali/graph/telemetry/istio"
+	"github.com/kiali/kiali/graph/telemetry/istio/appender"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/observability"
 	"github.com/kiali/kiali/prometheus"
@@ -22,6 +28,16 @@ func GraphNamespaces(ctx context.Context, business *business.Layer, o graph.Opti
 		observability.Attribute("package", "api"),
 	)
 	defer end()
+
+	if o.Snapshot != "" {
+		store, err := snapshot.DefaultStore(kialiConfig.Get())
+		if err != nil {
+			graph.BadRequest(err.Error())
+		}
+		trafficMap := graphSnapshot(store, &o)
+		return generateGraph(trafficMap, o)
+	}
+
 	// time how long it takes to generate this graph
 	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
 	defer promtimer.ObserveDuration()
@@ -55,11 +71,165 @@ func graphNamespacesIstio(ctx context.Context, business *business.Layer, prom *p
 	return code, config
 }
 
+// GraphNamespacesDiff generates a namespaces graph for the requested time window, and another for the
+// baseline time window, and returns the union of the two with every node and edge marked as added,
+// removed, changed or unchanged.
+func GraphNamespacesDiff(ctx context.Context, business *business.Layer, o graph.Options) (code int, config interface{}) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "GraphNamespacesDiff",
+		observability.Attribute("package", "api"),
+	)
+	defer end()
+
+	if o.Snapshot != "" {
+		graph.BadRequest("Diff graph does not support the 'snapshot' query parameter")
+	}
+	// time how long it takes to generate this graph
+	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
+	defer promtimer.ObserveDuration()
+
+	switch o.TelemetryVendor {
+	case graph.VendorIstio:
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		code, config = graphNamespacesDiffIstio(ctx, business, prom, o)
+	default:
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+
+	// update metrics
+	internalmetrics.SetGraphNodes(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes, 0)
+
+	return code, config
+}
+
+// graphNamespacesDiffIstio provides a test hook that accepts mock clients
+func graphNamespacesDiffIstio(ctx context.Context, business *business.Layer, prom *prometheus.Client, o graph.Options) (code int, config interface{}) {
+	baseline := o.BaselineOptions()
+
+	// Each graph gets its own 'global' object, appender caches are specific to the query time window.
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = business
+	globalInfo.Context = ctx
+	trafficMap := istio.BuildNamespacesTrafficMap(ctx, o.TelemetryOptions, prom, globalInfo)
+
+	baselineGlobalInfo := graph.NewAppenderGlobalInfo()
+	baselineGlobalInfo.Business = business
+	baselineGlobalInfo.Context = ctx
+	baselineTrafficMap := istio.BuildNamespacesTrafficMap(ctx, baseline.TelemetryOptions, prom, baselineGlobalInfo)
+
+	diffTrafficMap := graph.DiffTrafficMaps(trafficMap, baselineTrafficMap, o.DiffOptions.Tolerance)
+	code, config = generateGraph(diffTrafficMap, o)
+
+	return code, config
+}
+
+// GraphNamespacesBlastRadius generates a namespaces graph and returns the blast radius analysis for the
+// specified node. The node ID may be the cytoscape node ID.
+func GraphNamespacesBlastRadius(ctx context.Context, business *business.Layer, o graph.Options, nodeID string) (code int, result *analysis.BlastRadius) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "GraphNamespacesBlastRadius",
+		observability.Attribute("package", "api"),
+	)
+	defer end()
+
+	if o.Snapshot != "" {
+		store, err := snapshot.DefaultStore(kialiConfig.Get())
+		if err != nil {
+			graph.BadRequest(err.Error())
+		}
+		trafficMap := graphSnapshot(store, &o)
+		return analyzeBlastRadius(trafficMap, nodeID)
+	}
+	// time how long it takes to generate this graph
+	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
+	defer promtimer.ObserveDuration()
+
+	switch o.TelemetryVendor {
+	case graph.VendorIstio:
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		code, result = graphNamespacesBlastRadiusIstio(ctx, business, prom, o, nodeID)
+	default:
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+
+	return code, result
+}
+
+// graphNamespacesBlastRadiusIstio provides a test hook that accepts mock clients
+func graphNamespacesBlastRadiusIstio(ctx context.Context, business *business.Layer, prom *prometheus.Client, o graph.Options, nodeID string) (code int, result *analysis.BlastRadius) {
+
+	// Create a 'global' object to store the business. Global only to the request.
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = business
+	globalInfo.Context = ctx
+
+	trafficMap := istio.BuildNamespacesTrafficMap(ctx, o.TelemetryOptions, prom, globalInfo)
+
+	return analyzeBlastRadius(trafficMap, nodeID)
+}
+
+func analyzeBlastRadius(trafficMap graph.TrafficMap, nodeID string) (int, *analysis.BlastRadius) {
+	result, err := analysis.AnalyzeBlastRadius(trafficMap, nodeID)
+	if err != nil {
+		graph.Panic(err.Error(), http.StatusNotFound)
+	}
+
+	return http.StatusOK, result
+}
+
+// GraphNamespaceSLO generates the namespace graph with the slo appender, and returns the error budget status of
+// the namespace's services and request edges. Service nodes are always injected, to evaluate service traffic.
+func GraphNamespaceSLO(ctx context.Context, business *business.Layer, o graph.Options) (code int, result *analysis.SLOSummary) {
+	var end observability.EndFunc
+	ctx, end = observability.StartSpan(ctx, "GraphNamespaceSLO",
+		observability.Attribute("package", "api"),
+	)
+	defer end()
+
+	if o.Snapshot != "" {
+		graph.BadRequest("SLO does not support the 'snapshot' query parameter")
+	}
+	// time how long it takes to generate this graph
+	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, true)
+	defer promtimer.ObserveDuration()
+
+	switch o.TelemetryVendor {
+	case graph.VendorIstio:
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		code, result = graphNamespaceSLOIstio(ctx, business, prom, o)
+	default:
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+
+	return code, result
+}
+
+// graphNamespaceSLOIstio provides a test hook that accepts mock clients
+func graphNamespaceSLOIstio(ctx context.Context, business *business.Layer, prom *prometheus.Client, o graph.Options) (code int, result *analysis.SLOSummary) {
+	o.TelemetryOptions.Appenders = graph.RequestedAppenders{All: false, AppenderNames: []string{appender.SLOAppenderName}}
+	o.TelemetryOptions.InjectServiceNodes = true
+
+	// Create a 'global' object to store the business. Global only to the request.
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = business
+	globalInfo.Context = ctx
+
+	trafficMap := istio.BuildNamespacesTrafficMap(ctx, o.TelemetryOptions, prom, globalInfo)
+
+	return http.StatusOK, analysis.SummarizeSLO(trafficMap, o.NodeOptions.Namespace, appender.SLOWindowKeys())
+}
+
 // GraphNode generates a node graph using the provided options
 func GraphNode(ctx context.Context, business *business.Layer, o graph.Options) (code int, config interface{}) {
 	if len(o.Namespaces) != 1 {
 		graph.Error("Node graph does not support the 'namespaces' query parameter or the 'all' namespace")
 	}
+	if o.Snapshot != "" {
+		graph.BadRequest("Node graph does not support the 'snapshot' query parameter")
+	}
 
 	// time how long it takes to generate this graph
 	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
@@ -99,10 +269,32 @@ func generateGraph(trafficMap graph.TrafficMap, o graph.Options) (int, interface
 	promtimer := internalmetrics.GetGraphMarshalTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
 	defer promtimer.ObserveDuration()
 
+	// apply the find and hide expressions before generating the config, hidden elements are not returned
+	if o.Hide != "" {
+		hide, err := find.Parse(o.Hide)
+		if err != nil {
+			graph.BadRequest(fmt.Sprintf("Invalid hide expression [%s]: %v", o.Hide, err))
+		}
+		find.Hide(trafficMap, hide)
+	}
+	if o.Find != "" {
+		expression, err := find.Parse(o.Find)
+		if err != nil {
+			graph.BadRequest(fmt.Sprintf("Invalid find expression [%s]: %v", o.Find, err))
+		}
+		find.Find(trafficMap, expression)
+	}
+
 	var vendorConfig interface{}
 	switch o.ConfigVendor {
 	case graph.VendorCytoscape:
 		vendorConfig = cytoscape.NewConfig(trafficMap, o.ConfigOptions)
+	case graph.VendorDOT:
+		vendorConfig = export.NewDOTConfig(trafficMap, o.ConfigOptions)
+	case graph.VendorGraphML:
+		vendorConfig = export.NewGraphMLConfig(trafficMap, o.ConfigOptions)
+	case graph.VendorMermaid:
+		vendorConfig = export.NewMermaidConfig(trafficMap, o.ConfigOptions)
 	default:
 		graph.Error(fmt.Sprintf("ConfigVendor [%s] not supported", o.ConfigVendor))
 	}
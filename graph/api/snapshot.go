@@ -0,0 +1,225 @@
+package api
+
+// Snapshot.go supports graph snapshots. A snapshot is the TrafficMap of a namespaces graph, persisted such that
+// the graph can be served (via the 'snapshot' query param) after the telemetry used to generate it is no longer
+// available. Snapshots are taken on demand, and periodically for the configured namespaces.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/snapshot"
+	"github.com/kiali/kiali/graph/telemetry/istio"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// graphSnapshotPruneInterval is how often expired snapshots are removed when periodic snapshots are not configured
+const graphSnapshotPruneInterval = time.Hour
+
+// CreateGraphSnapshot generates a namespaces graph using the provided options and stores its TrafficMap
+func CreateGraphSnapshot(ctx context.Context, business *business.Layer, store snapshot.Store, o graph.Options) (code int, info *snapshot.Info) {
+	if o.Snapshot != "" {
+		graph.BadRequest("A graph snapshot can not be created from a snapshot")
+	}
+
+	switch o.TelemetryVendor {
+	case graph.VendorIstio:
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		code, info = createGraphSnapshotIstio(ctx, business, prom, store, o)
+	default:
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+
+	return code, info
+}
+
+// createGraphSnapshotIstio provides a test hook that accepts mock clients
+func createGraphSnapshotIstio(ctx context.Context, business *business.Layer, prom *prometheus.Client, store snapshot.Store, o graph.Options) (code int, info *snapshot.Info) {
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = business
+	globalInfo.Context = ctx
+
+	trafficMap := istio.BuildNamespacesTrafficMap(ctx, o.TelemetryOptions, prom, globalInfo)
+
+	// the graph includes the requested namespaces of every cluster where they are accessible
+	namespaces := []snapshot.Namespace{}
+	for _, an := range o.AccessibleNamespaces {
+		if _, ok := o.Namespaces[an.Name]; ok {
+			namespaces = append(namespaces, snapshot.Namespace{Cluster: an.Cluster, Name: an.Name})
+		}
+	}
+	s := snapshot.NewSnapshot(trafficMap, o.TelemetryOptions.GraphType, o.InjectServiceNodes, namespaces, o.TelemetryOptions.QueryTime, o.TelemetryOptions.Duration)
+	graph.CheckError(store.Save(s))
+	log.Debugf("Stored graph snapshot [%s] for namespaces %v", s.ID, s.Namespaces)
+
+	return http.StatusCreated, &s.Info
+}
+
+// ListGraphSnapshots returns the stored snapshots whose namespaces are all accessible, most recent first
+func ListGraphSnapshots(store snapshot.Store, accessibleNamespaces graph.AccessibleNamespaces) (code int, infos []snapshot.Info) {
+	all, err := store.List()
+	graph.CheckError(err)
+
+	infos = []snapshot.Info{}
+	for _, info := range all {
+		if isGraphSnapshotAccessible(info, accessibleNamespaces) {
+			infos = append(infos, info)
+		}
+	}
+	return http.StatusOK, infos
+}
+
+// graphSnapshot returns the TrafficMap of the snapshot requested by the options. The options are updated with
+// the snapshot's graph type, namespaces and time window, such that the graph is generated for the snapshot.
+func graphSnapshot(store snapshot.Store, o *graph.Options) graph.TrafficMap {
+	if err := snapshot.ValidateID(o.Snapshot); err != nil {
+		graph.BadRequest(err.Error())
+	}
+	s, err := store.Get(o.Snapshot)
+	if errors.Is(err, snapshot.ErrNotFound) {
+		graph.Panic(fmt.Sprintf("Graph snapshot [%s] not found", o.Snapshot), http.StatusNotFound)
+	}
+	graph.CheckError(err)
+
+	if !isGraphSnapshotAccessible(s.Info, o.AccessibleNamespaces) {
+		graph.Forbidden(fmt.Sprintf("Graph snapshot [%s] includes namespaces that are not accessible", o.Snapshot))
+	}
+
+	duration := time.Duration(s.Duration) * time.Second
+	namespaces := graph.NewNamespaceInfoMap()
+	for _, ns := range s.Namespaces {
+		namespaces[ns.Name] = graph.NamespaceInfo{
+			Name:     ns.Name,
+			Duration: duration,
+			IsIstio:  config.IsIstioNamespace(ns.Name),
+		}
+	}
+	o.ConfigOptions.Duration = duration
+	o.ConfigOptions.GraphType = s.GraphType
+	o.ConfigOptions.QueryTime = s.QueryTime
+	o.TelemetryOptions.Duration = duration
+	o.TelemetryOptions.GraphType = s.GraphType
+	o.TelemetryOptions.InjectServiceNodes = s.InjectServiceNodes
+	o.TelemetryOptions.Namespaces = namespaces
+	o.TelemetryOptions.QueryTime = s.QueryTime
+
+	return s.TrafficMap
+}
+
+// isGraphSnapshotAccessible returns true if every namespace of the snapshot is accessible, in its cluster
+func isGraphSnapshotAccessible(info snapshot.Info, accessibleNamespaces graph.AccessibleNamespaces) bool {
+	for _, ns := range info.Namespaces {
+		if _, ok := accessibleNamespaces[graph.GetClusterSensitiveKey(ns.Cluster, ns.Name)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// RunGraphSnapshots takes the configured periodic snapshots, and removes the snapshots older than the configured
+// retention, until the context is done. The provided function returns the business layer used to generate the
+// graphs, it should be backed by the Kiali service account.
+func RunGraphSnapshots(ctx context.Context, conf *config.Config, getBusiness func() *business.Layer) {
+	store, err := snapshot.DefaultStore(conf)
+	if err != nil {
+		log.Errorf("Graph snapshots are not available: %v", err)
+		return
+	}
+
+	snapshotsConf := conf.GraphSnapshots
+	var interval, retention time.Duration
+	if snapshotsConf.Interval != "" && len(snapshotsConf.Namespaces) > 0 {
+		// the durations are validated with the config
+		interval, _ = time.ParseDuration(snapshotsConf.Interval)
+	}
+	if snapshotsConf.Retention != "" {
+		retention, _ = time.ParseDuration(snapshotsConf.Retention)
+	}
+	if interval == 0 && retention == 0 {
+		return
+	}
+
+	period := interval
+	if period == 0 {
+		period = graphSnapshotPruneInterval
+	}
+	log.Infof("Graph snapshots: periodic snapshot interval [%v] namespaces %v, retention [%v]", interval, snapshotsConf.Namespaces, retention)
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		if interval > 0 {
+			takePeriodicGraphSnapshot(ctx, snapshotsConf, getBusiness(), store)
+		}
+		if retention > 0 {
+			pruneGraphSnapshots(store, time.Now().Add(-retention))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// takePeriodicGraphSnapshot stores a snapshot of the configured namespaces graph, converting graph panics to errors
+func takePeriodicGraphSnapshot(ctx context.Context, conf config.GraphSnapshots, business *business.Layer, store snapshot.Store) {
+	defer func() {
+		if r := recover(); r != nil {
+			var message string
+			switch err := r.(type) {
+			case graph.Response:
+				message = err.Message
+			case error:
+				message = err.Error()
+			default:
+				message = fmt.Sprintf("%v", r)
+			}
+			log.Errorf("Failed to take periodic graph snapshot of namespaces %v: %s", conf.Namespaces, message)
+		}
+	}()
+
+	params := url.Values{}
+	params.Set("duration", conf.Duration)
+	params.Set("graphType", conf.GraphType)
+	params.Set("namespaces", strings.Join(conf.Namespaces, ","))
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/?"+params.Encode(), nil)
+	graph.CheckError(err)
+
+	o := graph.NewOptions(r, &business.Namespace)
+	CreateGraphSnapshot(ctx, business, store, o)
+}
+
+// pruneGraphSnapshots removes the snapshots created before the provided time
+func pruneGraphSnapshots(store snapshot.Store, before time.Time) {
+	infos, err := store.List()
+	if err != nil {
+		log.Errorf("Failed to list graph snapshots: %v", err)
+		return
+	}
+	expired := []string{}
+	for _, info := range infos {
+		if info.Created.Before(before) {
+			expired = append(expired, info.ID)
+		}
+	}
+	for _, id := range expired {
+		if err := store.Delete(id); err != nil && !errors.Is(err, snapshot.ErrNotFound) {
+			log.Errorf("Failed to remove expired graph snapshot [%s]: %v", id, err)
+		}
+	}
+	if len(expired) > 0 {
+		log.Debugf("Removed [%d] expired graph snapshots", len(expired))
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/snapshot"
+	"github.com/kiali/kiali/util"
+)
+
+func TestGraphSnapshotReplay(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	client, _, err, biz := mockNamespaceRatesGraph(t)
+	require.NoError(err)
+
+	store, err := snapshot.NewFileStore(t.TempDir())
+	require.NoError(err)
+
+	r := httptest.NewRequest(http.MethodGet, "/api/namespaces/graph?namespaces=bookinfo&graphType=app&appenders&queryTime=1523364075", nil)
+	o := graph.NewOptions(r, &biz.Namespace)
+	_, expected := graphNamespacesIstio(r.Context(), biz, client, o)
+
+	code, info := createGraphSnapshotIstio(r.Context(), biz, client, store, o)
+	assert.Equal(http.StatusCreated, code)
+	assert.Equal([]snapshot.Namespace{{Cluster: config.Get().KubernetesConfig.ClusterName, Name: "bookinfo"}}, info.Namespaces)
+	assert.Equal(graph.GraphTypeApp, info.GraphType)
+	assert.Equal(int64(1523364075), info.QueryTime)
+	assert.Greater(info.NodeCount, 0)
+
+	code, infos := ListGraphSnapshots(store, o.AccessibleNamespaces)
+	assert.Equal(http.StatusOK, code)
+	require.Len(infos, 1)
+	assert.Equal(info.ID, infos[0].ID)
+
+	// the replayed graph, requested with other options, is the graph at the time of the snapshot
+	r = httptest.NewRequest(http.MethodGet, "/api/namespaces/graph?graphType=workload&duration=1h&snapshot="+info.ID, nil)
+	replay := graph.NewOptions(r, &biz.Namespace)
+	assert.Empty(replay.Namespaces)
+	trafficMap := graphSnapshot(store, &replay)
+	assert.Equal(graph.GraphTypeApp, replay.TelemetryOptions.GraphType)
+	assert.Equal(10*time.Minute, replay.ConfigOptions.Duration)
+	assert.Contains(replay.Namespaces, "bookinfo")
+	_, actual := generateGraph(trafficMap, replay)
+
+	expectedJSON, _ := json.Marshal(expected)
+	actualJSON, _ := json.Marshal(actual)
+	assert.JSONEq(string(expectedJSON), string(actualJSON))
+}
+
+func TestGraphSnapshotAccess(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	store, err := snapshot.NewFileStore(t.TempDir())
+	require.NoError(err)
+	s := snapshot.NewSnapshot(graph.NewTrafficMap(), graph.GraphTypeWorkload, false, []snapshot.Namespace{{Cluster: "east", Name: "bookinfo"}, {Cluster: "west", Name: "travels"}}, time.Now().Unix(), time.Minute)
+	require.NoError(store.Save(s))
+
+	accessible := graph.AccessibleNamespaces{
+		graph.GetClusterSensitiveKey("east", "bookinfo"): &graph.AccessibleNamespace{Cluster: "east", Name: "bookinfo"},
+	}
+	_, infos := ListGraphSnapshots(store, accessible)
+	assert.Empty(infos)
+
+	o := graph.Options{Snapshot: s.ID}
+	o.AccessibleNamespaces = accessible
+	assert.PanicsWithValue(graph.Response{Message: "Graph snapshot [" + s.ID + "] includes namespaces that are not accessible", Code: http.StatusForbidden}, func() {
+		graphSnapshot(store, &o)
+	})
+
+	// the namespace is only accessible in another cluster
+	accessible[graph.GetClusterSensitiveKey("east", "travels")] = &graph.AccessibleNamespace{Cluster: "east", Name: "travels"}
+	_, infos = ListGraphSnapshots(store, accessible)
+	assert.Empty(infos)
+	assert.Panics(func() { graphSnapshot(store, &o) })
+
+	accessible[graph.GetClusterSensitiveKey("west", "travels")] = &graph.AccessibleNamespace{Cluster: "west", Name: "travels"}
+	_, infos = ListGraphSnapshots(store, accessible)
+	assert.Len(infos, 1)
+	assert.NotPanics(func() { graphSnapshot(store, &o) })
+
+	o.Snapshot = util.NewTimeSortableID(time.Now())
+	assert.PanicsWithValue(graph.Response{Message: "Graph snapshot [" + o.Snapshot + "] not found", Code: http.StatusNotFound}, func() {
+		graphSnapshot(store, &o)
+	})
+
+	o.Snapshot = "../../etc/passwd"
+	assert.Panics(func() { graphSnapshot(store, &o) })
+}
@@ -0,0 +1,361 @@
+package api
+
+// Stream.go supports streaming graph updates. Rather than having each client poll for a full graph,
+// a client subscribes to a graph and is sent the full graph once, followed by patches describing
+// only the node and edge changes.  Subscribers requesting an identical graph share a single
+// subscription, which regenerates the graph on an interval, so the cost of graph generation (i.e.
+// the Prometheus queries) is paid once per interval regardless of the number of subscribers.
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// The streamed event types
+const (
+	GraphStreamEventError string = "graphError" // Data is the error message, "error" is reserved by EventSource
+	GraphStreamEventGraph string = "graph"      // Data is the full cytoscape.Config
+	GraphStreamEventPatch string = "patch"      // Data is a GraphPatch
+)
+
+// The graph patch operations, modeled after JSON Patch (RFC 6902)
+const (
+	GraphPatchAdd     string = "add"
+	GraphPatchRemove  string = "remove"
+	GraphPatchReplace string = "replace"
+)
+
+// subscriberBufferSize is the number of events that can be pending for a subscriber before it is
+// considered too slow, and is dropped.
+const subscriberBufferSize = 16
+
+// GraphStreamEvent is a single event sent to a graph stream subscriber
+type GraphStreamEvent struct {
+	Type string
+	Data interface{}
+}
+
+// GraphPatchOperation describes a single change to the previously sent graph. Paths are of the form
+// /elements/nodes/<nodeID>, /elements/edges/<edgeID> or /timestamp.
+type GraphPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// GraphPatch is the list of operations to apply, in order, to the previously sent graph
+type GraphPatch struct {
+	Timestamp  int64                 `json:"timestamp"`
+	Operations []GraphPatchOperation `json:"operations"`
+}
+
+// GraphSubscriber receives the events for a single streaming client. Close must be called when the
+// client is done.
+type GraphSubscriber struct {
+	Events <-chan GraphStreamEvent
+
+	business *business.Layer
+	events   chan GraphStreamEvent
+	seq      uint64
+	streamer *GraphStreamer
+	sub      *graphSubscription
+}
+
+// Close unsubscribes, it is safe to call more than once
+func (s *GraphSubscriber) Close() {
+	s.streamer.unsubscribe(s)
+}
+
+// graphGenerator generates the cytoscape config for the provided options
+type graphGenerator func(ctx context.Context, business *business.Layer, o graph.Options) cytoscape.Config
+
+// graphSubscription is shared by all subscribers to an identical graph. It is guarded by the
+// streamer lock. The graph is generated with the business layer, and so the credentials, of the
+// owner, which is always a live subscriber: the most recent one, holding the freshest token.
+type graphSubscription struct {
+	cancel      context.CancelFunc
+	interval    time.Duration
+	key         string
+	latest      *cytoscape.Config
+	options     graph.Options
+	owner       *GraphSubscriber
+	subscribers map[*GraphSubscriber]bool
+}
+
+// GraphStreamer manages the graph subscriptions
+type GraphStreamer struct {
+	generate      graphGenerator
+	lock          sync.Mutex
+	seq           uint64
+	subscriptions map[string]*graphSubscription
+}
+
+// NewGraphStreamer returns a GraphStreamer generating Istio telemetry graphs
+func NewGraphStreamer() *GraphStreamer {
+	return newGraphStreamer(func(ctx context.Context, business *business.Layer, o graph.Options) cytoscape.Config {
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		_, config := graphNamespacesIstio(ctx, business, prom, o)
+		return config.(cytoscape.Config)
+	})
+}
+
+func newGraphStreamer(generate graphGenerator) *GraphStreamer {
+	return &GraphStreamer{
+		generate:      generate,
+		subscriptions: make(map[string]*graphSubscription),
+	}
+}
+
+// Subscribe returns a subscriber for the namespaces graph described by the options, regenerated at
+// the given interval.  The first event is the full graph, subsequent events are patches. The options
+// must have been generated for the requesting user, the subscription is shared only with subscribers
+// having the same accessible namespaces. The business layer is only used to generate the graph while
+// its subscriber is the newest one of the subscription.
+func (s *GraphStreamer) Subscribe(business *business.Layer, o graph.Options, interval time.Duration) *GraphSubscriber {
+	key := subscriptionKey(o, interval)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sub, ok := s.subscriptions[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		sub = &graphSubscription{
+			cancel:      cancel,
+			interval:    interval,
+			key:         key,
+			options:     o,
+			subscribers: make(map[*GraphSubscriber]bool),
+		}
+		s.subscriptions[key] = sub
+		log.Debugf("Starting graph subscription [%s]", key)
+		go s.run(ctx, sub)
+	}
+
+	s.seq++
+	events := make(chan GraphStreamEvent, subscriberBufferSize)
+	subscriber := &GraphSubscriber{
+		Events:   events,
+		business: business,
+		events:   events,
+		seq:      s.seq,
+		streamer: s,
+		sub:      sub,
+	}
+	sub.subscribers[subscriber] = true
+	sub.owner = subscriber
+
+	// a late subscriber immediately gets the current graph
+	if sub.latest != nil {
+		subscriber.events <- GraphStreamEvent{Type: GraphStreamEventGraph, Data: *sub.latest}
+	}
+
+	return subscriber
+}
+
+// unsubscribe removes the subscriber and stops the subscription when there are no more subscribers.
+// Must be called without holding the lock.
+func (s *GraphStreamer) unsubscribe(subscriber *GraphSubscriber) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.removeSubscriber(subscriber)
+}
+
+// removeSubscriber must be called while holding the lock
+func (s *GraphStreamer) removeSubscriber(subscriber *GraphSubscriber) {
+	sub := subscriber.sub
+	if _, ok := sub.subscribers[subscriber]; !ok {
+		return
+	}
+	delete(sub.subscribers, subscriber)
+	close(subscriber.events)
+
+	if len(sub.subscribers) == 0 {
+		log.Debugf("Stopping graph subscription [%s]", sub.key)
+		sub.owner = nil
+		sub.cancel()
+		delete(s.subscriptions, sub.key)
+		return
+	}
+
+	// never keep generating with the credentials of a gone subscriber, hand over to the newest one
+	if sub.owner == subscriber {
+		sub.owner = nil
+		for remaining := range sub.subscribers {
+			if sub.owner == nil || remaining.seq > sub.owner.seq {
+				sub.owner = remaining
+			}
+		}
+	}
+}
+
+func (s *GraphStreamer) run(ctx context.Context, sub *graphSubscription) {
+	ticker := time.NewTicker(sub.interval)
+	defer ticker.Stop()
+
+	for {
+		s.refresh(ctx, sub)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh regenerates the graph and sends the changes to every subscriber
+func (s *GraphStreamer) refresh(ctx context.Context, sub *graphSubscription) {
+	s.lock.Lock()
+	if ctx.Err() != nil {
+		s.lock.Unlock()
+		return
+	}
+	business := sub.owner.business
+	s.lock.Unlock()
+
+	config, errMessage := s.safeGenerate(ctx, sub, business)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	var event GraphStreamEvent
+	switch {
+	case errMessage != "":
+		event = GraphStreamEvent{Type: GraphStreamEventError, Data: errMessage}
+	case sub.latest == nil:
+		event = GraphStreamEvent{Type: GraphStreamEventGraph, Data: config}
+		sub.latest = &config
+	default:
+		patch := diffConfigs(sub.latest, &config)
+		sub.latest = &config
+		if len(patch.Operations) == 0 {
+			return
+		}
+		event = GraphStreamEvent{Type: GraphStreamEventPatch, Data: patch}
+	}
+
+	for subscriber := range sub.subscribers {
+		select {
+		case subscriber.events <- event:
+		default:
+			// the subscriber can no longer apply patches, drop it and let the client re-subscribe
+			log.Debugf("Dropping slow graph subscriber for subscription [%s]", sub.key)
+			s.removeSubscriber(subscriber)
+		}
+	}
+}
+
+// safeGenerate generates the graph for the current time, converting graph panics to an error message
+func (s *GraphStreamer) safeGenerate(ctx context.Context, sub *graphSubscription, business *business.Layer) (config cytoscape.Config, errMessage string) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch err := r.(type) {
+			case graph.Response:
+				errMessage = err.Message
+			case error:
+				errMessage = err.Error()
+			default:
+				errMessage = fmt.Sprintf("%v", r)
+			}
+			log.Errorf("Failed to generate graph for subscription [%s]: %s", sub.key, errMessage)
+		}
+	}()
+
+	o := sub.options
+	queryTime := time.Now().Unix()
+	o.ConfigOptions.QueryTime = queryTime
+	o.TelemetryOptions.QueryTime = queryTime
+
+	return s.generate(ctx, business, o), ""
+}
+
+// subscriptionKey identifies identical graph requests, it is made up of the request parameters
+// (excluding queryTime, which is always 'now'), the interval, and the user's accessible namespaces.
+func subscriptionKey(o graph.Options, interval time.Duration) string {
+	params := url.Values{}
+	for k, v := range o.ConfigOptions.Params {
+		if k != "queryTime" {
+			params[k] = v
+		}
+	}
+
+	accessibleNamespaces := make([]string, 0, len(o.AccessibleNamespaces))
+	for k := range o.AccessibleNamespaces {
+		accessibleNamespaces = append(accessibleNamespaces, k)
+	}
+	sort.Strings(accessibleNamespaces)
+
+	return fmt.Sprintf("%s|%v|%s", params.Encode(), interval, strings.Join(accessibleNamespaces, ","))
+}
+
+// diffConfigs returns the operations needed to transform the previous config into the current config
+func diffConfigs(previous, current *cytoscape.Config) GraphPatch {
+	patch := GraphPatch{Timestamp: current.Timestamp, Operations: []GraphPatchOperation{}}
+
+	if previous.Timestamp != current.Timestamp {
+		patch.Operations = append(patch.Operations, GraphPatchOperation{Op: GraphPatchReplace, Path: "/timestamp", Value: current.Timestamp})
+	}
+
+	previousNodes := make(map[string]*cytoscape.NodeData, len(previous.Elements.Nodes))
+	for _, nw := range previous.Elements.Nodes {
+		previousNodes[nw.Data.ID] = nw.Data
+	}
+	currentNodes := make(map[string]bool, len(current.Elements.Nodes))
+	for _, nw := range current.Elements.Nodes {
+		currentNodes[nw.Data.ID] = true
+		path := "/elements/nodes/" + nw.Data.ID
+		if prev, ok := previousNodes[nw.Data.ID]; !ok {
+			patch.Operations = append(patch.Operations, GraphPatchOperation{Op: GraphPatchAdd, Path: path, Value: nw.Data})
+		} else if !reflect.DeepEqual(prev, nw.Data) {
+			patch.Operations = append(patch.Operations, GraphPatchOperation{Op: GraphPatchReplace, Path: path, Value: nw.Data})
+		}
+	}
+
+	previousEdges := make(map[string]*cytoscape.EdgeData, len(previous.Elements.Edges))
+	for _, ew := range previous.Elements.Edges {
+		previousEdges[ew.Data.ID] = ew.Data
+	}
+	currentEdges := make(map[string]bool, len(current.Elements.Edges))
+	for _, ew := range current.Elements.Edges {
+		currentEdges[ew.Data.ID] = true
+		path := "/elements/edges/" + ew.Data.ID
+		if prev, ok := previousEdges[ew.Data.ID]; !ok {
+			patch.Operations = append(patch.Operations, GraphPatchOperation{Op: GraphPatchAdd, Path: path, Value: ew.Data})
+		} else if !reflect.DeepEqual(prev, ew.Data) {
+			patch.Operations = append(patch.Operations, GraphPatchOperation{Op: GraphPatchReplace, Path: path, Value: ew.Data})
+		}
+	}
+
+	// removals are applied last, edges before nodes, so clients never hold an edge to a missing node
+	for _, ew := range previous.Elements.Edges {
+		if !currentEdges[ew.Data.ID] {
+			patch.Operations = append(patch.Operations, GraphPatchOperation{Op: GraphPatchRemove, Path: "/elements/edges/" + ew.Data.ID})
+		}
+	}
+	for _, nw := range previous.Elements.Nodes {
+		if !currentNodes[nw.Data.ID] {
+			patch.Operations = append(patch.Operations, GraphPatchOperation{Op: GraphPatchRemove, Path: "/elements/nodes/" + nw.Data.ID})
+		}
+	}
+
+	return patch
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+func streamTestConfig(timestamp int64, nodeIDs []string, edges map[string][2]string) cytoscape.Config {
+	config := cytoscape.Config{Timestamp: timestamp}
+	for _, id := range nodeIDs {
+		config.Elements.Nodes = append(config.Elements.Nodes, &cytoscape.NodeWrapper{Data: &cytoscape.NodeData{ID: id}})
+	}
+	for id, ends := range edges {
+		config.Elements.Edges = append(config.Elements.Edges, &cytoscape.EdgeWrapper{Data: &cytoscape.EdgeData{ID: id, Source: ends[0], Target: ends[1]}})
+	}
+	return config
+}
+
+func TestDiffConfigs(t *testing.T) {
+	assert := assert.New(t)
+
+	previous := streamTestConfig(100, []string{"a", "b", "c"}, map[string][2]string{"ab": {"a", "b"}, "bc": {"b", "c"}})
+	current := streamTestConfig(200, []string{"a", "b", "d"}, map[string][2]string{"ab": {"a", "b"}, "bd": {"b", "d"}})
+	current.Elements.Nodes[1].Data.HasHealthConfig = map[string]string{"rate": "1"}
+
+	patch := diffConfigs(&previous, &current)
+	assert.Equal(int64(200), patch.Timestamp)
+
+	ops := make([]string, len(patch.Operations))
+	for i, op := range patch.Operations {
+		ops[i] = op.Op + " " + op.Path
+	}
+	assert.Equal([]string{
+		"replace /timestamp",
+		"replace /elements/nodes/b",
+		"add /elements/nodes/d",
+		"add /elements/edges/bd",
+		"remove /elements/edges/bc",
+		"remove /elements/nodes/c",
+	}, ops)
+
+	// no changes other than the timestamp
+	same := streamTestConfig(300, []string{"a", "b", "d"}, map[string][2]string{"ab": {"a", "b"}, "bd": {"b", "d"}})
+	same.Elements.Nodes[1].Data.HasHealthConfig = map[string]string{"rate": "1"}
+	patch = diffConfigs(&current, &same)
+	assert.Len(patch.Operations, 1)
+	assert.Equal("/timestamp", patch.Operations[0].Path)
+}
+
+func TestSubscriptionKey(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.Options{}
+	o.ConfigOptions.Params = url.Values{"namespaces": []string{"bookinfo"}, "queryTime": []string{"1"}}
+	o.AccessibleNamespaces = graph.AccessibleNamespaces{
+		graph.GetClusterSensitiveKey("east", "bookinfo"): &graph.AccessibleNamespace{},
+	}
+	key := subscriptionKey(o, time.Minute)
+
+	// queryTime is ignored
+	o.ConfigOptions.Params = url.Values{"namespaces": []string{"bookinfo"}, "queryTime": []string{"2"}}
+	assert.Equal(key, subscriptionKey(o, time.Minute))
+
+	// interval and accessible namespaces are not
+	assert.NotEqual(key, subscriptionKey(o, time.Hour))
+	o.AccessibleNamespaces[graph.GetClusterSensitiveKey("east", "other")] = &graph.AccessibleNamespace{}
+	assert.NotEqual(key, subscriptionKey(o, time.Minute))
+}
+
+func TestGraphStreamerSharesSubscription(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var lock sync.Mutex
+	generated := 0
+	streamer := newGraphStreamer(func(ctx context.Context, business *business.Layer, o graph.Options) cytoscape.Config {
+		lock.Lock()
+		defer lock.Unlock()
+		generated++
+		if generated == 1 {
+			return streamTestConfig(o.TelemetryOptions.QueryTime, []string{"a"}, nil)
+		}
+		return streamTestConfig(o.TelemetryOptions.QueryTime, []string{"a", "b"}, map[string][2]string{"ab": {"a", "b"}})
+	})
+
+	o := graph.Options{}
+	o.ConfigOptions.Params = url.Values{"namespaces": []string{"bookinfo"}}
+
+	first := streamer.Subscribe(nil, o, 50*time.Millisecond)
+	event := <-first.Events
+	assert.Equal(GraphStreamEventGraph, event.Type)
+	assert.Len(event.Data.(cytoscape.Config).Elements.Nodes, 1)
+
+	// the late subscriber shares the subscription and immediately gets the latest full graph
+	second := streamer.Subscribe(nil, o, 50*time.Millisecond)
+	streamer.lock.Lock()
+	assert.Len(streamer.subscriptions, 1)
+	streamer.lock.Unlock()
+	event = <-second.Events
+	assert.Equal(GraphStreamEventGraph, event.Type)
+
+	// both get the patch
+	for _, subscriber := range []*GraphSubscriber{first, second} {
+		select {
+		case event = <-subscriber.Events:
+		case <-time.After(5 * time.Second):
+			require.Fail("timed out waiting for patch")
+		}
+		require.Equal(GraphStreamEventPatch, event.Type)
+		assert.Contains(event.Data.(GraphPatch).Operations, GraphPatchOperation{Op: GraphPatchAdd, Path: "/elements/edges/ab", Value: &cytoscape.EdgeData{ID: "ab", Source: "a", Target: "b"}})
+	}
+
+	first.Close()
+	first.Close()
+	_, open := <-first.Events
+	assert.False(open)
+	streamer.lock.Lock()
+	assert.Len(streamer.subscriptions, 1)
+	streamer.lock.Unlock()
+
+	second.Close()
+	streamer.lock.Lock()
+	assert.Empty(streamer.subscriptions)
+	streamer.lock.Unlock()
+}
+
+func TestGraphStreamerError(t *testing.T) {
+	assert := assert.New(t)
+
+	streamer := newGraphStreamer(func(ctx context.Context, business *business.Layer, o graph.Options) cytoscape.Config {
+		graph.Error("prometheus unavailable")
+		return cytoscape.Config{}
+	})
+
+	subscriber := streamer.Subscribe(nil, graph.Options{}, time.Minute)
+	defer subscriber.Close()
+
+	event := <-subscriber.Events
+	assert.Equal(GraphStreamEventError, event.Type)
+	assert.Equal("prometheus unavailable", event.Data)
+}
+
+func TestGraphStreamerRebindsToLiveSubscriber(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	firstLayer, secondLayer := &business.Layer{}, &business.Layer{}
+	used := make(chan *business.Layer, 100)
+	streamer := newGraphStreamer(func(ctx context.Context, business *business.Layer, o graph.Options) cytoscape.Config {
+		used <- business
+		return streamTestConfig(o.TelemetryOptions.QueryTime, []string{"a"}, nil)
+	})
+
+	o := graph.Options{}
+	o.ConfigOptions.Params = url.Values{"namespaces": []string{"bookinfo"}}
+
+	first := streamer.Subscribe(firstLayer, o, 10*time.Millisecond)
+	<-first.Events
+	require.Same(firstLayer, <-used)
+
+	second := streamer.Subscribe(secondLayer, o, 10*time.Millisecond)
+	defer second.Close()
+	go func() {
+		for range second.Events {
+		}
+	}()
+	first.Close()
+
+	// once the first subscriber is gone its layer is never used again
+	drain := time.After(50 * time.Millisecond)
+	for done := false; !done; {
+		select {
+		case <-used:
+		case <-drain:
+			done = true
+		}
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case layer := <-used:
+			assert.Same(secondLayer, layer)
+		case <-time.After(5 * time.Second):
+			require.Fail("timed out waiting for graph generation")
+		}
+	}
+}
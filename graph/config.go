@@ -8,3 +8,10 @@ type ConfigVendor interface {
 	// definitions for error handling. Refer to the Cytoscape implementation as an example.
 	NewConfig(trafficMap TrafficMap, o ConfigOptions) interface{}
 }
+
+// RawConfig can be returned by a ConfigVendor producing a non-JSON document. The Data is returned
+// to the client as-is, using the provided ContentType.
+type RawConfig struct {
+	ContentType string
+	Data        []byte
+}
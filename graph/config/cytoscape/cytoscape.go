@@ -19,6 +19,7 @@ import (
 	"crypto/md5"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/kiali/kiali/graph"
@@ -79,6 +80,48 @@ type VSInfo struct {
 	Hostnames []string `json:"hostnames,omitempty"`
 }
 
+// DiffInfo describes how a node or edge changed between the baseline and current graphs. It is set only for diff graphs.
+type DiffInfo struct {
+	Status            string   `json:"status"`                      // added | changed | removed | unchanged
+	Changed           []string `json:"changed,omitempty"`           // the changed attributes (e.g. rate, errorRate, responseTime, isMTLS)
+	BaselineRate      string   `json:"baselineRate,omitempty"`      // total rate for the baseline time window
+	RateDelta         string   `json:"rateDelta,omitempty"`         // current rate minus baseline rate
+	BaselineErrorRate string   `json:"baselineErrorRate,omitempty"` // error percentage for the baseline time window
+	ErrorRateDelta    string   `json:"errorRateDelta,omitempty"`    // current error percentage minus baseline error percentage
+	ResponseTimeDelta string   `json:"responseTimeDelta,omitempty"` // in millis
+	ThroughputDelta   string   `json:"throughputDelta,omitempty"`   // in bytes/sec
+}
+
+// AnomalyInfo compares request traffic to the request traffic at a historical baseline. It is set only when the
+// anomaly appender is requested. The baseline values, ratios and delta are set only when there was baseline traffic.
+type AnomalyInfo struct {
+	IsAnomaly            bool   `json:"isAnomaly"`                      // true if any comparison exceeds its threshold
+	BaselineErrorRate    string `json:"baselineErrorRate,omitempty"`    // error percentage at the baseline
+	BaselineRate         string `json:"baselineRate,omitempty"`         // request rate at the baseline
+	BaselineResponseTime string `json:"baselineResponseTime,omitempty"` // in millis, edges only
+	ErrorRateDelta       string `json:"errorRateDelta,omitempty"`       // current error percentage minus baseline error percentage
+	RateRatio            string `json:"rateRatio,omitempty"`            // current rate / baseline rate
+	ResponseTime         string `json:"responseTime,omitempty"`         // 95th percentile in millis, edges only
+	ResponseTimeRatio    string `json:"responseTimeRatio,omitempty"`    // current response time / baseline response time
+}
+
+// BurnRateInfo holds the error budget burn rates, keyed by window (e.g. "5m"). It is set only when the slo appender
+// is requested.
+type BurnRateInfo struct {
+	IsBurning bool              `json:"isBurning"` // true if both windows of any window pair exceed the pair's threshold
+	Rates     map[string]string `json:"rates"`     // a burn rate of 1 consumes the error budget exactly
+}
+
+// ResilienceInfo holds the Envoy upstream resilience stats for the destination service. It is set only when the
+// resilience appender is requested. Rates are per second.
+type ResilienceInfo struct {
+	DestinationRules []graph.ConfigReference `json:"destinationRules,omitempty"` // the DestinationRules configuring the destination service
+	EjectionsActive  string                  `json:"ejectionsActive,omitempty"`  // hosts currently ejected by outlier detection
+	PendingOverflow  string                  `json:"pendingOverflow,omitempty"`  // requests rejected by the circuit breaker
+	Retries          string                  `json:"retries,omitempty"`          // request retries
+	RetryOverflow    string                  `json:"retryOverflow,omitempty"`    // retries not attempted because the retry circuit breaker was open
+}
+
 // HealthConfig maps annotations information for health
 type HealthConfig map[string]string
 
@@ -96,8 +139,15 @@ type NodeData struct {
 	Version               string              `json:"version,omitempty"`
 	Service               string              `json:"service,omitempty"`               // requested service for NodeTypeService
 	Aggregate             string              `json:"aggregate,omitempty"`             // set like "<aggregate>=<aggregateVal>"
+	Anomaly               *AnomalyInfo        `json:"anomaly,omitempty"`               // set only when the anomaly appender is requested
+	BudgetRemaining       string              `json:"budgetRemaining,omitempty"`       // percentage, set only when the slo appender is requested
+	BurnRate              *BurnRateInfo       `json:"burnRate,omitempty"`              // set only when the slo appender is requested
+	Collapsed             []string            `json:"collapsed,omitempty"`             // IDs of the leaf nodes folded into a collapsed aggregate node
+	Community             string              `json:"community,omitempty"`             // set only when community layout hints are requested
 	DestServices          []graph.ServiceName `json:"destServices,omitempty"`          // requested services for [dest] node
+	Diff                  *DiffInfo           `json:"diff,omitempty"`                  // set only for diff graphs
 	Labels                map[string]string   `json:"labels,omitempty"`                // k8s labels associated with the node
+	Resilience            *ResilienceInfo     `json:"resilience,omitempty"`            // set only when the resilience appender is requested
 	Traffic               []ProtocolTraffic   `json:"traffic,omitempty"`               // traffic rates for all detected protocols
 	HealthData            interface{}         `json:"healthData"`                      // data to calculate health status from configurations
 	HealthDataApp         interface{}         `json:"-"`                               // for local use to generate appBox health
@@ -114,6 +164,7 @@ type NodeData struct {
 	IsAmbient             bool                `json:"isAmbient,omitempty"`             // true (captured by ambient) | false
 	IsBox                 string              `json:"isBox,omitempty"`                 // set for NodeTypeBox, current values: [ 'app', 'cluster', 'namespace' ]
 	IsDead                bool                `json:"isDead,omitempty"`                // true (has no pods) | false
+	IsFindHit             bool                `json:"isFindHit,omitempty"`             // true if matched by the requested find expression
 	IsGateway             *GWInfo             `json:"isGateway,omitempty"`             // Istio ingress/egress gateway information
 	IsIdle                bool                `json:"isIdle,omitempty"`                // true | false
 	IsInaccessible        bool                `json:"isInaccessible,omitempty"`        // true if the node exists in an inaccessible namespace
@@ -132,8 +183,14 @@ type EdgeData struct {
 	Target string `json:"target"` // child node ID
 
 	// App Fields (not required by Cytoscape)
+	Anomaly         *AnomalyInfo    `json:"anomaly,omitempty"`         // set only when the anomaly appender is requested
+	BudgetRemaining string          `json:"budgetRemaining,omitempty"` // percentage, set only when the slo appender is requested
+	BurnRate        *BurnRateInfo   `json:"burnRate,omitempty"`        // set only when the slo appender is requested
 	DestPrincipal   string          `json:"destPrincipal,omitempty"`   // principal used for the edge destination
+	Diff            *DiffInfo       `json:"diff,omitempty"`            // set only for diff graphs
+	IsFindHit       bool            `json:"isFindHit,omitempty"`       // true if matched by the requested find expression
 	IsMTLS          string          `json:"isMTLS,omitempty"`          // set to the percentage of traffic using a mutual TLS connection
+	Resilience      *ResilienceInfo `json:"resilience,omitempty"`      // set only when the resilience appender is requested
 	ResponseTime    string          `json:"responseTime,omitempty"`    // in millis
 	SourcePrincipal string          `json:"sourcePrincipal,omitempty"` // principal used for the edge source
 	Throughput      string          `json:"throughput,omitempty"`      // in bytes/sec (request or response, depends on client request)
@@ -154,10 +211,16 @@ type Elements struct {
 }
 
 type Config struct {
-	Timestamp int64    `json:"timestamp"`
-	Duration  int64    `json:"duration"`
-	GraphType string   `json:"graphType"`
-	Elements  Elements `json:"elements"`
+	Timestamp   int64        `json:"timestamp"`
+	Duration    int64        `json:"duration"`
+	GraphType   string       `json:"graphType"`
+	Elements    Elements     `json:"elements"`
+	LayoutHints *LayoutHints `json:"layoutHints,omitempty"` // set only when layout hints are requested
+}
+
+// NodeID returns the cytoscape node ID for the traffic map node ID
+func NodeID(id string) string {
+	return nodeHash(id)
 }
 
 func nodeHash(id string) string {
@@ -173,8 +236,30 @@ func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Confi
 	nodes := []*NodeWrapper{}
 	edges := []*EdgeWrapper{}
 
+	var layoutHints *LayoutHints
+	if o.LayoutHints.Collapse || o.LayoutHints.Communities {
+		layoutHints = &LayoutHints{}
+	}
+	if o.LayoutHints.Collapse {
+		trafficMap, layoutHints.CollapsedNodes = collapseLeaves(trafficMap, o.LayoutHints.CollapseThreshold)
+	}
+
 	buildConfig(trafficMap, &nodes, &edges, o)
 
+	// Assign communities before adding compound nodes, boxes are not part of a community
+	if o.LayoutHints.Communities {
+		membership, count, modularity := communities(trafficMap)
+		nodeCommunities := make(map[string]int, len(membership))
+		for id, c := range membership {
+			nodeCommunities[nodeHash(id)] = c
+		}
+		for _, nw := range nodes {
+			nw.Data.Community = strconv.Itoa(nodeCommunities[nw.Data.ID])
+		}
+		layoutHints.Communities = count
+		layoutHints.Modularity = fmt.Sprintf("%.3f", modularity)
+	}
+
 	// Add compound nodes as needed, inner boxes first
 	if strings.Contains(o.BoxBy, graph.BoxByApp) || o.GraphType == graph.GraphTypeApp || o.GraphType == graph.GraphTypeVersionedApp {
 		boxByApp(&nodes)
@@ -232,10 +317,11 @@ func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Confi
 
 	elements := Elements{nodes, edges}
 	result = Config{
-		Duration:  int64(o.Duration.Seconds()),
-		Timestamp: o.QueryTime,
-		GraphType: o.GraphType,
-		Elements:  elements,
+		Duration:    int64(o.Duration.Seconds()),
+		Timestamp:   o.QueryTime,
+		GraphType:   o.GraphType,
+		Elements:    elements,
+		LayoutHints: layoutHints,
 	}
 	return result
 }
@@ -289,6 +375,11 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			nd.IsIdle = val.(bool)
 		}
 
+		// node may match the find expression
+		if val, ok := n.Metadata[graph.IsFindHit]; ok {
+			nd.IsFindHit = val.(bool)
+		}
+
 		// node may be a root
 		if val, ok := n.Metadata[graph.IsRoot]; ok {
 			nd.IsRoot = val.(bool)
@@ -413,11 +504,39 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			}
 		}
 
+		// node may be part of a diff graph
+		if val, ok := n.Metadata[graph.Diff]; ok {
+			nd.Diff = toDiffInfo(val.(*graph.DiffInfo))
+		}
+
+		// node may have been compared to a baseline
+		if val, ok := n.Metadata[graph.Anomaly]; ok {
+			nd.Anomaly = toAnomalyInfo(val.(*graph.AnomalyInfo))
+		}
+
+		// node may have error budget burn rates
+		if val, ok := n.Metadata[graph.BurnRate]; ok {
+			nd.BurnRate = toBurnRateInfo(val.(*graph.BurnRateInfo))
+			nd.BudgetRemaining = fmt.Sprintf("%.1f", n.Metadata[graph.BudgetRemaining].(float64))
+		}
+
+		// node may have envoy resilience stats
+		if val, ok := n.Metadata[graph.Resilience]; ok {
+			nd.Resilience = toResilienceInfo(val.(*graph.ResilienceInfo))
+		}
+
 		// node may be an aggregate
 		if n.NodeType == graph.NodeTypeAggregate {
 			nd.Aggregate = fmt.Sprintf("%s=%s", n.Metadata[graph.Aggregate].(string), n.Metadata[graph.AggregateValue].(string))
 		}
 
+		// node may hold collapsed leaf nodes
+		if val, ok := n.Metadata[graph.Collapsed]; ok {
+			for _, id := range val.([]string) {
+				nd.Collapsed = append(nd.Collapsed, nodeHash(id))
+			}
+		}
+
 		nw := NodeWrapper{
 			Data: nd,
 		}
@@ -446,6 +565,22 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			if e.Metadata[graph.SourcePrincipal] != nil {
 				ed.SourcePrincipal = e.Metadata[graph.SourcePrincipal].(string)
 			}
+			if val, ok := e.Metadata[graph.IsFindHit]; ok {
+				ed.IsFindHit = val.(bool)
+			}
+			if val, ok := e.Metadata[graph.Diff]; ok {
+				ed.Diff = toDiffInfo(val.(*graph.DiffInfo))
+			}
+			if val, ok := e.Metadata[graph.Anomaly]; ok {
+				ed.Anomaly = toAnomalyInfo(val.(*graph.AnomalyInfo))
+			}
+			if val, ok := e.Metadata[graph.BurnRate]; ok {
+				ed.BurnRate = toBurnRateInfo(val.(*graph.BurnRateInfo))
+				ed.BudgetRemaining = fmt.Sprintf("%.1f", e.Metadata[graph.BudgetRemaining].(float64))
+			}
+			if val, ok := e.Metadata[graph.Resilience]; ok {
+				ed.Resilience = toResilienceInfo(val.(*graph.ResilienceInfo))
+			}
 			addEdgeTelemetry(e, &ed)
 
 			ew := EdgeWrapper{
@@ -564,6 +699,95 @@ func addEdgeTelemetry(e *graph.Edge, ed *EdgeData) {
 	}
 }
 
+func toDiffInfo(di *graph.DiffInfo) *DiffInfo {
+	result := &DiffInfo{
+		Status:         di.Status,
+		RateDelta:      deltaToString(2, di.RateDelta),
+		ErrorRateDelta: deltaToString(1, di.ErrorRateDelta),
+	}
+	for _, k := range di.Changed {
+		result.Changed = append(result.Changed, string(k))
+	}
+	if di.HasBaselineMetrics {
+		result.BaselineRate = rateToString(2, di.BaselineRate)
+		result.BaselineErrorRate = fmt.Sprintf("%.1f", di.BaselineErrorRate)
+	}
+	if di.ResponseTimeDelta != 0 {
+		result.ResponseTimeDelta = fmt.Sprintf("%.0f", di.ResponseTimeDelta)
+	}
+	if di.ThroughputDelta != 0 {
+		result.ThroughputDelta = fmt.Sprintf("%.0f", di.ThroughputDelta)
+	}
+	return result
+}
+
+func toAnomalyInfo(ai *graph.AnomalyInfo) *AnomalyInfo {
+	result := &AnomalyInfo{
+		IsAnomaly: ai.IsAnomaly,
+	}
+	if ai.ResponseTime > 0 {
+		result.ResponseTime = fmt.Sprintf("%.0f", ai.ResponseTime)
+	}
+	if !ai.HasBaseline {
+		return result
+	}
+	result.BaselineRate = rateToString(2, ai.BaselineRate)
+	result.BaselineErrorRate = fmt.Sprintf("%.1f", ai.BaselineErrorRate)
+	result.RateRatio = fmt.Sprintf("%.2f", ai.RateRatio)
+	if ai.ErrorRateDelta != 0 {
+		result.ErrorRateDelta = fmt.Sprintf("%.1f", ai.ErrorRateDelta)
+	}
+	if ai.BaselineResponseTime > 0 {
+		result.BaselineResponseTime = fmt.Sprintf("%.0f", ai.BaselineResponseTime)
+	}
+	if ai.ResponseTimeRatio > 0 {
+		result.ResponseTimeRatio = fmt.Sprintf("%.2f", ai.ResponseTimeRatio)
+	}
+	return result
+}
+
+func toBurnRateInfo(bi *graph.BurnRateInfo) *BurnRateInfo {
+	result := &BurnRateInfo{
+		IsBurning: bi.IsBurning,
+		Rates:     make(map[string]string, len(bi.Rates)),
+	}
+	for window, rate := range bi.Rates {
+		result.Rates[window] = fmt.Sprintf("%.2f", rate)
+	}
+	return result
+}
+
+func toResilienceInfo(ri *graph.ResilienceInfo) *ResilienceInfo {
+	result := &ResilienceInfo{
+		DestinationRules: ri.DestinationRules,
+	}
+	if ri.EjectionsActive > 0 {
+		result.EjectionsActive = fmt.Sprintf("%.0f", ri.EjectionsActive)
+	}
+	if ri.PendingOverflow > 0 {
+		result.PendingOverflow = rateToString(2, ri.PendingOverflow)
+	}
+	if ri.Retries > 0 {
+		result.Retries = rateToString(2, ri.Retries)
+	}
+	if ri.RetryOverflow > 0 {
+		result.RetryOverflow = rateToString(2, ri.RetryOverflow)
+	}
+	return result
+}
+
+// deltaToString is like rateToString but supports negative values, returning "" for no change
+func deltaToString(minPrecision int, delta float64) string {
+	switch {
+	case delta > 0:
+		return rateToString(minPrecision, delta)
+	case delta < 0:
+		return "-" + rateToString(minPrecision, -delta)
+	default:
+		return ""
+	}
+}
+
 func getRate(md graph.Metadata, k graph.MetadataKey) float64 {
 	if rate, ok := md[k]; ok {
 		return rate.(float64)
@@ -0,0 +1,354 @@
+package cytoscape
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// CollapsedAggregate is the aggregate of the synthetic nodes holding collapsed leaf nodes. The aggregate value is
+// the number of collapsed nodes.
+const CollapsedAggregate = "collapsed"
+
+// LayoutHints summarizes the layout hints computed server-side. It is set only when layout hints are requested.
+type LayoutHints struct {
+	CollapsedNodes int    `json:"collapsedNodes,omitempty"` // number of leaf nodes folded into collapsed aggregate nodes
+	Communities    int    `json:"communities,omitempty"`    // number of communities, see NodeData.Community
+	Modularity     string `json:"modularity,omitempty"`     // modularity of the communities, [-0.5..1], higher is better
+}
+
+// collapseLeaves returns a traffic map in which the low-traffic leaf nodes of a parent are folded into a synthetic
+// aggregate node. A leaf is a node without outbound edges and with a single inbound edge. It is low-traffic when the
+// edge carries less than threshold percent of the parent's outbound traffic (of the same unit). Leaves are folded per
+// parent, protocol, cluster and namespace, and only when there are at least two to fold. The given traffic map is
+// not modified. Also returns the number of collapsed nodes.
+func collapseLeaves(trafficMap graph.TrafficMap, threshold float64) (graph.TrafficMap, int) {
+	inbound := make(map[string][]*graph.Edge, len(trafficMap))
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			inbound[e.Dest.ID] = append(inbound[e.Dest.ID], e)
+		}
+	}
+
+	// group the low-traffic leaves, keyed by "parentID protocol cluster namespace"
+	groups := map[string][]*graph.Edge{}
+	for _, n := range trafficMap {
+		totals := map[string]float64{}
+		for _, e := range n.Edges {
+			rate, unit := graph.EdgeTotalRate(e)
+			totals[unit] += rate
+		}
+		for _, e := range n.Edges {
+			leaf := e.Dest
+			if len(leaf.Edges) > 0 || len(inbound[leaf.ID]) != 1 || leaf.NodeType == graph.NodeTypeAggregate {
+				continue
+			}
+			if _, isRoot := leaf.Metadata[graph.IsRoot]; isRoot {
+				continue
+			}
+			if rate, unit := graph.EdgeTotalRate(e); totals[unit] > 0 && rate*100.0/totals[unit] >= threshold {
+				continue
+			}
+			key := fmt.Sprintf("%s %v %s %s", n.ID, e.Metadata[graph.ProtocolKey], leaf.Cluster, leaf.Namespace)
+			groups[key] = append(groups[key], e)
+		}
+	}
+
+	collapsedNodes := 0
+	result := make(graph.TrafficMap, len(trafficMap))
+	for id, n := range trafficMap {
+		result[id] = n
+	}
+	for key, edges := range groups {
+		if len(edges) < 2 {
+			continue
+		}
+
+		parent := edges[0].Source
+		if copied := result[parent.ID]; copied == parent {
+			// copy the parent, so that the given traffic map is not modified
+			copied = &graph.Node{}
+			*copied = *parent
+			result[parent.ID] = copied
+		}
+		parent = result[parent.ID]
+
+		first := edges[0].Dest
+		aggregate := graph.NewAggregateNodeExplicit(fmt.Sprintf("%s_%s", CollapsedAggregate, key), first.Cluster, first.Namespace, CollapsedAggregate, strconv.Itoa(len(edges)), "", "")
+		aggregateEdge := graph.NewEdge(parent, &aggregate)
+		aggregateEdge.Metadata[graph.ProtocolKey] = edges[0].Metadata[graph.ProtocolKey]
+
+		folded := make(map[*graph.Edge]bool, len(edges))
+		collapsed := make([]string, 0, len(edges))
+		for _, e := range edges {
+			folded[e] = true
+			collapsed = append(collapsed, e.Dest.ID)
+			graph.AggregateNodeTraffic(e.Dest, &aggregate)
+			graph.AggregateEdgeTraffic(e, &aggregateEdge)
+			delete(result, e.Dest.ID)
+		}
+		sort.Strings(collapsed)
+		aggregate.Metadata[graph.Collapsed] = collapsed
+
+		parentEdges := make([]*graph.Edge, 0, len(parent.Edges)-len(edges)+1)
+		for _, e := range parent.Edges {
+			if !folded[e] {
+				parentEdges = append(parentEdges, e)
+			}
+		}
+		parent.Edges = append(parentEdges, &aggregateEdge)
+		result[aggregate.ID] = &aggregate
+		collapsedNodes += len(edges)
+	}
+
+	return result, collapsedNodes
+}
+
+// communities assigns the nodes of the traffic map to communities, using the Louvain method to maximize the modularity
+// of the undirected graph weighted by the edge rates. Returns the community of each node, keyed by node ID, numbered
+// from the largest community, along with the number of communities and the modularity.
+//
+// The weight of an edge is 1 plus its rate relative to the mean rate of the edges with the same unit, such that all
+// edges, including idle edges, connect their nodes, while rates of different units can be combined.
+func communities(trafficMap graph.TrafficMap) (map[string]int, int, float64) {
+	ids := make([]string, 0, len(trafficMap))
+	for id := range trafficMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	unitTotals := map[string]float64{}
+	unitCounts := map[string]int{}
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			rate, unit := graph.EdgeTotalRate(e)
+			unitTotals[unit] += rate
+			unitCounts[unit]++
+		}
+	}
+
+	g := newLouvainGraph(len(ids))
+	for _, id := range ids {
+		for _, e := range trafficMap[id].Edges {
+			dest, ok := index[e.Dest.ID]
+			if !ok {
+				continue
+			}
+			weight := 1.0
+			if rate, unit := graph.EdgeTotalRate(e); unitTotals[unit] > 0 {
+				weight += rate * float64(unitCounts[unit]) / unitTotals[unit]
+			}
+			g.addEdge(index[id], dest, weight)
+		}
+	}
+
+	membership := louvain(g)
+
+	// number the communities by size, largest first, ties broken by their first node ID
+	sizes := map[int]int{}
+	first := map[int]int{}
+	for i, c := range membership {
+		if _, ok := first[c]; !ok {
+			first[c] = i
+		}
+		sizes[c]++
+	}
+	ordered := make([]int, 0, len(sizes))
+	for c := range sizes {
+		ordered = append(ordered, c)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if sizes[ordered[i]] != sizes[ordered[j]] {
+			return sizes[ordered[i]] > sizes[ordered[j]]
+		}
+		return first[ordered[i]] < first[ordered[j]]
+	})
+	renumber := make(map[int]int, len(ordered))
+	for i, c := range ordered {
+		renumber[c] = i
+	}
+
+	result := make(map[string]int, len(ids))
+	for i, id := range ids {
+		result[id] = renumber[membership[i]]
+	}
+
+	return result, len(ordered), g.modularity(membership)
+}
+
+// louvainGraph is an undirected weighted graph. Self-loops hold the weight of the edges internal to an aggregated node.
+type louvainGraph struct {
+	adjacent []map[int]float64 // symmetric, excluding self-loops
+	loops    []float64
+	total    float64 // the sum of all edge weights
+}
+
+func newLouvainGraph(size int) *louvainGraph {
+	g := &louvainGraph{
+		adjacent: make([]map[int]float64, size),
+		loops:    make([]float64, size),
+	}
+	for i := range g.adjacent {
+		g.adjacent[i] = map[int]float64{}
+	}
+	return g
+}
+
+func (g *louvainGraph) addEdge(i, j int, weight float64) {
+	if i == j {
+		g.loops[i] += weight
+	} else {
+		g.adjacent[i][j] += weight
+		g.adjacent[j][i] += weight
+	}
+	g.total += weight
+}
+
+func (g *louvainGraph) degree(i int) float64 {
+	degree := 2 * g.loops[i]
+	for _, w := range g.adjacent[i] {
+		degree += w
+	}
+	return degree
+}
+
+// neighbors returns the sorted neighbors, for a deterministic result
+func (g *louvainGraph) neighbors(i int) []int {
+	neighbors := make([]int, 0, len(g.adjacent[i]))
+	for j := range g.adjacent[i] {
+		neighbors = append(neighbors, j)
+	}
+	sort.Ints(neighbors)
+	return neighbors
+}
+
+// modularity returns the modularity of the given community membership
+func (g *louvainGraph) modularity(membership []int) float64 {
+	if g.total == 0 {
+		return 0.0
+	}
+	internal := map[int]float64{}
+	totals := map[int]float64{}
+	for i := range g.adjacent {
+		c := membership[i]
+		internal[c] += g.loops[i]
+		totals[c] += g.degree(i)
+		for j, w := range g.adjacent[i] {
+			if j > i && membership[j] == c {
+				internal[c] += w
+			}
+		}
+	}
+	q := 0.0
+	for c, tot := range totals {
+		q += internal[c]/g.total - (tot/(2*g.total))*(tot/(2*g.total))
+	}
+	return q
+}
+
+// louvain returns the community of each node. Each pass moves nodes to the neighboring community with the best
+// modularity gain until no move improves it, and then aggregates the communities into the nodes of the next pass.
+func louvain(g *louvainGraph) []int {
+	membership := make([]int, len(g.adjacent))
+	for i := range membership {
+		membership[i] = i
+	}
+	if g.total == 0 {
+		return membership
+	}
+
+	for {
+		communities, moved := g.moveNodes()
+		if !moved {
+			return membership
+		}
+		for i, c := range membership {
+			membership[i] = communities[c]
+		}
+		g = g.aggregate(communities)
+	}
+}
+
+// moveNodes performs the local moving phase, returning the (renumbered) community of each node and whether any
+// node was moved
+func (g *louvainGraph) moveNodes() ([]int, bool) {
+	const epsilon = 1e-12
+	size := len(g.adjacent)
+	m2 := 2 * g.total
+
+	community := make([]int, size)
+	totals := make([]float64, size)
+	degrees := make([]float64, size)
+	for i := range community {
+		community[i] = i
+		degrees[i] = g.degree(i)
+		totals[i] = degrees[i]
+	}
+
+	moved := false
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < size; i++ {
+			current := community[i]
+			links := map[int]float64{}
+			for _, j := range g.neighbors(i) {
+				links[community[j]] += g.adjacent[i][j]
+			}
+
+			totals[current] -= degrees[i]
+			best := current
+			bestGain := links[current] - totals[current]*degrees[i]/m2
+			candidates := make([]int, 0, len(links))
+			for c := range links {
+				candidates = append(candidates, c)
+			}
+			sort.Ints(candidates)
+			for _, c := range candidates {
+				if gain := links[c] - totals[c]*degrees[i]/m2; gain > bestGain+epsilon {
+					best = c
+					bestGain = gain
+				}
+			}
+			totals[best] += degrees[i]
+
+			if best != current {
+				community[i] = best
+				improved = true
+				moved = true
+			}
+		}
+	}
+
+	renumber := map[int]int{}
+	for i, c := range community {
+		if _, ok := renumber[c]; !ok {
+			renumber[c] = len(renumber)
+		}
+		community[i] = renumber[c]
+	}
+	return community, moved
+}
+
+// aggregate returns the graph whose nodes are the communities
+func (g *louvainGraph) aggregate(community []int) *louvainGraph {
+	size := 0
+	for _, c := range community {
+		size = max(size, c+1)
+	}
+	result := newLouvainGraph(size)
+	for i := range g.adjacent {
+		result.addEdge(community[i], community[i], g.loops[i])
+		for j, w := range g.adjacent[i] {
+			if j > i {
+				result.addEdge(community[i], community[j], w)
+			}
+		}
+	}
+	return result
+}
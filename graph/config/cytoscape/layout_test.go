@@ -0,0 +1,130 @@
+package cytoscape
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func layoutTestNode(trafficMap graph.TrafficMap, namespace, workload string) *graph.Node {
+	n, _ := graph.NewNode("east", "", "", namespace, workload, workload, "v1", graph.GraphTypeWorkload)
+	trafficMap[n.ID] = n
+	return n
+}
+
+func layoutTestEdge(source, dest *graph.Node, rate float64) *graph.Edge {
+	e := source.AddEdge(dest)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", rate, "200", "-", "", source.Metadata, dest.Metadata, e.Metadata)
+	return e
+}
+
+func TestCollapseLeaves(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	trafficMap := graph.NewTrafficMap()
+	frontend := layoutTestNode(trafficMap, "shop", "frontend")
+	backend := layoutTestNode(trafficMap, "shop", "backend")
+	db := layoutTestNode(trafficMap, "shop", "db")
+	layoutTestEdge(frontend, backend, 1000.0)
+	layoutTestEdge(backend, db, 10.0)
+	for i := 0; i < 3; i++ {
+		layoutTestEdge(frontend, layoutTestNode(trafficMap, "shop", fmt.Sprintf("audit-%d", i)), 1.0)
+	}
+	// a low-traffic leaf alone in its namespace is not collapsed
+	layoutTestEdge(frontend, layoutTestNode(trafficMap, "other", "metrics"), 1.0)
+
+	collapsed, count := collapseLeaves(trafficMap, 1.0)
+	assert.Equal(3, count)
+	assert.Len(trafficMap, 7, "the given traffic map is not modified")
+	assert.Len(trafficMap[frontend.ID].Edges, 5)
+	require.Len(collapsed, 5)
+
+	var aggregate *graph.Node
+	for _, n := range collapsed {
+		if n.NodeType == graph.NodeTypeAggregate {
+			aggregate = n
+		}
+	}
+	require.NotNil(aggregate)
+	assert.Equal("shop", aggregate.Namespace)
+	assert.Equal(CollapsedAggregate, aggregate.Metadata[graph.Aggregate])
+	assert.Equal("3", aggregate.Metadata[graph.AggregateValue])
+	assert.Len(aggregate.Metadata[graph.Collapsed], 3)
+
+	// the backend is a parent of db, but db carries all of the backend's traffic
+	assert.Len(collapsed[backend.ID].Edges, 1)
+	require.Len(collapsed[frontend.ID].Edges, 3)
+	for _, e := range collapsed[frontend.ID].Edges {
+		if e.Dest.ID == aggregate.ID {
+			assert.Equal(3.0, e.Metadata["http"])
+		}
+	}
+
+	config := NewConfig(collapsed, graph.ConfigOptions{})
+	for _, n := range config.Elements.Nodes {
+		if n.Data.NodeType == graph.NodeTypeAggregate {
+			assert.Equal("collapsed=3", n.Data.Aggregate)
+			assert.Len(n.Data.Collapsed, 3)
+		}
+	}
+}
+
+func TestCommunities(t *testing.T) {
+	assert := assert.New(t)
+
+	// two triangles joined by a single low-traffic edge
+	trafficMap := graph.NewTrafficMap()
+	a := layoutTestNode(trafficMap, "left", "a")
+	b := layoutTestNode(trafficMap, "left", "b")
+	c := layoutTestNode(trafficMap, "left", "c")
+	x := layoutTestNode(trafficMap, "right", "x")
+	y := layoutTestNode(trafficMap, "right", "y")
+	z := layoutTestNode(trafficMap, "right", "z")
+	layoutTestEdge(a, b, 100.0)
+	layoutTestEdge(b, c, 100.0)
+	layoutTestEdge(c, a, 100.0)
+	layoutTestEdge(x, y, 100.0)
+	layoutTestEdge(y, z, 100.0)
+	layoutTestEdge(z, x, 100.0)
+	layoutTestEdge(c, x, 1.0)
+
+	membership, count, modularity := communities(trafficMap)
+	assert.Equal(2, count)
+	assert.Equal(membership[a.ID], membership[b.ID])
+	assert.Equal(membership[a.ID], membership[c.ID])
+	assert.Equal(membership[x.ID], membership[y.ID])
+	assert.Equal(membership[x.ID], membership[z.ID])
+	assert.NotEqual(membership[a.ID], membership[x.ID])
+	assert.Greater(modularity, 0.4)
+
+	config := NewConfig(trafficMap, graph.ConfigOptions{LayoutHints: graph.LayoutHintsOptions{Communities: true}})
+	assert.Equal(2, config.LayoutHints.Communities)
+	assert.Equal(0, config.LayoutHints.CollapsedNodes)
+	for _, n := range config.Elements.Nodes {
+		assert.NotEmpty(n.Data.Community)
+	}
+
+	// by default no hints are computed
+	config = NewConfig(trafficMap, graph.ConfigOptions{})
+	assert.Nil(config.LayoutHints)
+	for _, n := range config.Elements.Nodes {
+		assert.Empty(n.Data.Community)
+	}
+}
+
+func TestCommunitiesNoEdges(t *testing.T) {
+	trafficMap := graph.NewTrafficMap()
+	layoutTestNode(trafficMap, "shop", "a")
+	layoutTestNode(trafficMap, "shop", "b")
+
+	membership, count, modularity := communities(trafficMap)
+	assert.Equal(t, 2, count)
+	assert.Len(t, membership, 2)
+	assert.Equal(t, 0.0, modularity)
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// DOTContentType is the media type for Graphviz DOT documents
+const DOTContentType = "text/vnd.graphviz; charset=utf-8"
+
+// NewDOTConfig is required by the graph/ConfigVendor interface. Box nodes are rendered as Graphviz
+// cluster subgraphs.
+func NewDOTConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) graph.RawConfig {
+	doc := newDocument(trafficMap, o)
+
+	var sb strings.Builder
+	sb.WriteString("digraph kiali {\n")
+	fmt.Fprintf(&sb, "  graph [graphType=%s, duration=%d, timestamp=%d];\n", dotQuote(doc.config.GraphType), doc.config.Duration, doc.config.Timestamp)
+	doc.writeDOTNodes(&sb, "", 1)
+	for _, ew := range doc.config.Elements.Edges {
+		ed := ew.Data
+		attrs := append([]attribute{{Key: "label", Value: edgeLabel(ed)}}, edgeAttributes(ed)...)
+		if ed.Traffic.Protocol == graph.TCP.Name {
+			attrs = append(attrs, attribute{Key: "style", Value: "dashed"})
+		}
+		fmt.Fprintf(&sb, "  %s -> %s [%s];\n", dotQuote(ed.Source), dotQuote(ed.Target), dotAttributes(attrs))
+	}
+	sb.WriteString("}\n")
+
+	return graph.RawConfig{ContentType: DOTContentType, Data: []byte(sb.String())}
+}
+
+// writeDOTNodes recursively writes the children of the parent node, box nodes become subgraphs
+func (doc *document) writeDOTNodes(sb *strings.Builder, parent string, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, nd := range doc.children[parent] {
+		attrs := append([]attribute{{Key: "label", Value: nodeLabel(nd)}}, nodeAttributes(nd)...)
+		if nd.NodeType == graph.NodeTypeBox {
+			// graphviz only draws subgraphs having names starting with "cluster"
+			fmt.Fprintf(sb, "%ssubgraph %s {\n", indent, dotQuote("cluster_"+nd.ID))
+			for _, attr := range attrs {
+				fmt.Fprintf(sb, "%s  %s=%s;\n", indent, dotQuote(attr.Key), dotQuote(attr.Value))
+			}
+			doc.writeDOTNodes(sb, nd.ID, depth+1)
+			fmt.Fprintf(sb, "%s}\n", indent)
+			continue
+		}
+		fmt.Fprintf(sb, "%s%s [%s];\n", indent, dotQuote(nd.ID), dotAttributes(attrs))
+	}
+}
+
+func dotAttributes(attrs []attribute) string {
+	result := make([]string, len(attrs))
+	for i, attr := range attrs {
+		result[i] = fmt.Sprintf("%s=%s", dotQuote(attr.Key), dotQuote(attr.Value))
+	}
+	return strings.Join(result, ", ")
+}
+
+// dotQuote returns the value as a DOT quoted string
+func dotQuote(val string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(val) + `"`
+}
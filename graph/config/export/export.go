@@ -0,0 +1,230 @@
+// Package export provides conversion from our graph to document formats understood by
+// offline tools: Graphviz DOT, GraphML and Mermaid.
+//
+// Algorithm: Generate the Cytoscape configuration, which resolves boxing, traffic rates and
+//            node decorations, and then render its elements using the requested format.
+//            Box (compound) nodes are rendered as nested subgraphs, all other node and
+//            edge information is rendered as attributes.
+//
+// The package provides the DOT, GraphML and Mermaid implementations of graph/ConfigVendor.
+
+package export
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/models"
+)
+
+// attribute is a single key/value pair rendered for a node or edge
+type attribute struct {
+	Key   string
+	Value string
+}
+
+// document provides convenient access to the cytoscape elements for rendering
+type document struct {
+	config   cytoscape.Config
+	children map[string][]*cytoscape.NodeData // parent ID => child nodes, top-level nodes have parent ""
+}
+
+func newDocument(trafficMap graph.TrafficMap, o graph.ConfigOptions) *document {
+	config := cytoscape.NewConfig(trafficMap, o)
+
+	children := make(map[string][]*cytoscape.NodeData)
+	for _, nw := range config.Elements.Nodes {
+		children[nw.Data.Parent] = append(children[nw.Data.Parent], nw.Data)
+	}
+
+	return &document{config: config, children: children}
+}
+
+// nodeLabel returns a human readable name for the node
+func nodeLabel(nd *cytoscape.NodeData) string {
+	switch nd.NodeType {
+	case graph.NodeTypeAggregate:
+		return nd.Aggregate
+	case graph.NodeTypeApp:
+		if nd.Version != "" {
+			return fmt.Sprintf("%s %s", nd.App, nd.Version)
+		}
+		return nd.App
+	case graph.NodeTypeBox:
+		switch nd.IsBox {
+		case graph.BoxByApp:
+			return nd.App
+		case graph.BoxByNamespace:
+			return nd.Namespace
+		default:
+			return nd.Cluster
+		}
+	case graph.NodeTypeService:
+		return nd.Service
+	case graph.NodeTypeUnknown:
+		return graph.Unknown
+	default:
+		return nd.Workload
+	}
+}
+
+// edgeLabel returns a short description of the edge traffic
+func edgeLabel(ed *cytoscape.EdgeData) string {
+	protocol := ed.Traffic.Protocol
+	rate, ok := ed.Traffic.Rates[protocol]
+	if !ok {
+		return protocol
+	}
+	for _, p := range graph.Protocols {
+		if p.Name == protocol {
+			return fmt.Sprintf("%s %s%s", protocol, rate, p.UnitShort)
+		}
+	}
+	return fmt.Sprintf("%s %s", protocol, rate)
+}
+
+// nodeAttributes returns all of the relevant node information, in a predictable order
+func nodeAttributes(nd *cytoscape.NodeData) []attribute {
+	attrs := []attribute{{Key: "nodeType", Value: nd.NodeType}}
+	attrs = appendString(attrs, "cluster", nd.Cluster)
+	attrs = appendString(attrs, "namespace", nd.Namespace)
+	attrs = appendString(attrs, "workload", nd.Workload)
+	attrs = appendString(attrs, "app", nd.App)
+	attrs = appendString(attrs, "version", nd.Version)
+	attrs = appendString(attrs, "service", nd.Service)
+	attrs = appendString(attrs, "aggregate", nd.Aggregate)
+	attrs = appendString(attrs, "isBox", nd.IsBox)
+	attrs = appendBool(attrs, "hasCB", nd.HasCB)
+	attrs = appendBool(attrs, "hasVS", nd.HasVS != nil)
+	attrs = appendBool(attrs, "isAmbient", nd.IsAmbient)
+	attrs = appendBool(attrs, "isDead", nd.IsDead)
+	attrs = appendBool(attrs, "isGateway", nd.IsGateway != nil)
+	attrs = appendBool(attrs, "isIdle", nd.IsIdle)
+	attrs = appendBool(attrs, "isInaccessible", nd.IsInaccessible)
+	attrs = appendBool(attrs, "isOutOfMesh", nd.IsOutOfMesh)
+	attrs = appendBool(attrs, "isOutside", nd.IsOutside)
+	attrs = appendBool(attrs, "isRoot", nd.IsRoot)
+	attrs = appendBool(attrs, "isServiceEntry", nd.IsServiceEntry != nil)
+	attrs = appendBool(attrs, "isWaypoint", nd.IsWaypoint)
+	for _, pt := range nd.Traffic {
+		attrs = appendRates(attrs, pt.Rates)
+	}
+	attrs = append(attrs, healthAttributes(nd.HealthData)...)
+	if nd.Diff != nil {
+		attrs = append(attrs, attribute{Key: "diffStatus", Value: nd.Diff.Status})
+	}
+
+	return attrs
+}
+
+// edgeAttributes returns all of the relevant edge information, in a predictable order
+func edgeAttributes(ed *cytoscape.EdgeData) []attribute {
+	attrs := []attribute{}
+	attrs = appendString(attrs, "protocol", ed.Traffic.Protocol)
+	attrs = appendRates(attrs, ed.Traffic.Rates)
+	attrs = appendString(attrs, "responseTime", ed.ResponseTime)
+	attrs = appendString(attrs, "throughput", ed.Throughput)
+	attrs = appendString(attrs, "isMTLS", ed.IsMTLS)
+	attrs = appendString(attrs, "sourcePrincipal", ed.SourcePrincipal)
+	attrs = appendString(attrs, "destPrincipal", ed.DestPrincipal)
+	if ed.Diff != nil {
+		attrs = append(attrs, attribute{Key: "diffStatus", Value: ed.Diff.Status})
+	}
+
+	return attrs
+}
+
+// healthAttributes summarizes the health data attached to a node. Health status is calculated
+// client-side, so we provide the inputs: error percentages and workload replica counts.
+func healthAttributes(healthData interface{}) []attribute {
+	var requests *models.RequestHealth
+	var statuses []*models.WorkloadStatus
+
+	switch health := healthData.(type) {
+	case *models.AppHealth:
+		requests = &health.Requests
+		statuses = health.WorkloadStatuses
+	case *models.ServiceHealth:
+		requests = &health.Requests
+	case *models.WorkloadHealth:
+		requests = &health.Requests
+		if health.WorkloadStatus != nil {
+			statuses = []*models.WorkloadStatus{health.WorkloadStatus}
+		}
+	default:
+		return nil
+	}
+
+	attrs := []attribute{}
+	if errRate, ok := errorPercentage(requests.Inbound); ok {
+		attrs = append(attrs, attribute{Key: "healthInboundErrorRate", Value: errRate})
+	}
+	if errRate, ok := errorPercentage(requests.Outbound); ok {
+		attrs = append(attrs, attribute{Key: "healthOutboundErrorRate", Value: errRate})
+	}
+	if len(statuses) > 0 {
+		desired := int32(0)
+		available := int32(0)
+		for _, ws := range statuses {
+			desired += ws.DesiredReplicas
+			available += ws.AvailableReplicas
+		}
+		attrs = append(attrs, attribute{Key: "healthDesiredReplicas", Value: fmt.Sprintf("%d", desired)})
+		attrs = append(attrs, attribute{Key: "healthAvailableReplicas", Value: fmt.Sprintf("%d", available)})
+	}
+
+	return attrs
+}
+
+// errorPercentage returns the percentage of erroneous requests, false if there are no requests
+func errorPercentage(requests map[string]map[string]float64) (string, bool) {
+	total := 0.0
+	errs := 0.0
+	for protocol, codes := range requests {
+		for code, val := range codes {
+			total += val
+			switch {
+			case code == "-":
+				errs += val
+			case protocol == "grpc" && len(code) != 3:
+				if graph.IsGRPCErr(code) {
+					errs += val
+				}
+			case graph.IsHTTPErr(code):
+				errs += val
+			}
+		}
+	}
+	if total == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%.1f", errs/total*100.0), true
+}
+
+func appendString(attrs []attribute, key, val string) []attribute {
+	if val == "" {
+		return attrs
+	}
+	return append(attrs, attribute{Key: key, Value: val})
+}
+
+func appendBool(attrs []attribute, key string, val bool) []attribute {
+	if !val {
+		return attrs
+	}
+	return append(attrs, attribute{Key: key, Value: "true"})
+}
+
+func appendRates(attrs []attribute, rates map[string]string) []attribute {
+	keys := make([]string, 0, len(rates))
+	for k := range rates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, attribute{Key: k, Value: rates[k]})
+	}
+	return attrs
+}
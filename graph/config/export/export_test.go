@@ -0,0 +1,132 @@
+package export
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/models"
+)
+
+func testTrafficMap(t *testing.T) graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	productpage, err := graph.NewNode("east", "", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	require.NoError(t, err)
+	reviews, err := graph.NewNode("east", "", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	require.NoError(t, err)
+	reviewsV2, err := graph.NewNode("east", "", "", "bookinfo", "reviews-v2", "reviews", "v2", graph.GraphTypeVersionedApp)
+	require.NoError(t, err)
+	mysql, err := graph.NewNode("east", "", "", "db", "mysql-v1", "mysql", "v1", graph.GraphTypeVersionedApp)
+	require.NoError(t, err)
+	trafficMap[productpage.ID] = productpage
+	trafficMap[reviews.ID] = reviews
+	trafficMap[reviewsV2.ID] = reviewsV2
+	trafficMap[mysql.ID] = mysql
+
+	e := productpage.AddEdge(reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 8.0, "200", "-", "", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 2.0, "503", "UH", "", productpage.Metadata, reviews.Metadata, e.Metadata)
+	e.Metadata[graph.IsMTLS] = 100.0
+
+	e = productpage.AddEdge(reviewsV2)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 10.0, "200", "-", "", productpage.Metadata, reviewsV2.Metadata, e.Metadata)
+
+	e = reviews.AddEdge(mysql)
+	e.Metadata[graph.ProtocolKey] = "tcp"
+	graph.AddToMetadata("tcp", 150.0, "", "-", "", reviews.Metadata, mysql.Metadata, e.Metadata)
+
+	health := models.EmptyWorkloadHealth()
+	health.Requests.Inbound["http"] = map[string]float64{"200": 8.0, "503": 2.0}
+	health.WorkloadStatus = &models.WorkloadStatus{Name: "reviews-v1", DesiredReplicas: 2, AvailableReplicas: 1}
+	reviews.Metadata[graph.HealthData] = health
+
+	return trafficMap
+}
+
+func testConfigOptions(boxBy string) graph.ConfigOptions {
+	return graph.ConfigOptions{
+		BoxBy: boxBy,
+		CommonOptions: graph.CommonOptions{
+			Duration:  10 * time.Minute,
+			GraphType: graph.GraphTypeVersionedApp,
+			QueryTime: 1523364075,
+		},
+	}
+}
+
+func TestDOTConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	config := NewDOTConfig(testTrafficMap(t), testConfigOptions(graph.BoxByNamespace))
+	assert.Equal(DOTContentType, config.ContentType)
+
+	dot := string(config.Data)
+	assert.True(strings.HasPrefix(dot, "digraph kiali {\n"))
+	assert.True(strings.HasSuffix(dot, "}\n"))
+	// the bookinfo namespace box, and the reviews app box. Single member boxes are not generated.
+	assert.Equal(2, strings.Count(dot, "subgraph \"cluster_"))
+	assert.Contains(dot, `"isBox"="namespace";`)
+	assert.Contains(dot, `"label"="reviews v1", "nodeType"="app", "cluster"="east", "namespace"="bookinfo"`)
+	assert.Contains(dot, `"healthInboundErrorRate"="20.0", "healthDesiredReplicas"="2", "healthAvailableReplicas"="1"`)
+	assert.Contains(dot, `"label"="http 10.00rps", "protocol"="http", "http"="10.00", "http5xx"="2.00", "httpPercentErr"="20.0", "httpPercentReq"="50.0"`)
+	assert.Contains(dot, `"isMTLS"="100"`)
+	assert.Contains(dot, `"label"="tcp 150.00bps", "protocol"="tcp", "tcp"="150.00", "style"="dashed"`)
+}
+
+func TestGraphMLConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	config := NewGraphMLConfig(testTrafficMap(t), testConfigOptions(graph.BoxByCluster))
+	assert.Equal(GraphMLContentType, config.ContentType)
+
+	// must be well-formed xml
+	var parsed struct {
+		XMLName xml.Name
+		Keys    []struct {
+			ID  string `xml:"id,attr"`
+			For string `xml:"for,attr"`
+		} `xml:"key"`
+	}
+	require.NoError(t, xml.Unmarshal(config.Data, &parsed))
+	assert.Equal("graphml", parsed.XMLName.Local)
+	keys := map[string]string{}
+	for _, k := range parsed.Keys {
+		keys[k.ID] = k.For
+	}
+	assert.Equal("node", keys["node_healthInboundErrorRate"])
+	assert.Equal("node", keys["node_label"])
+	assert.Equal("edge", keys["edge_httpPercentErr"])
+	assert.Equal("edge", keys["edge_protocol"])
+
+	graphML := string(config.Data)
+	// single cluster is not boxed, but app boxes are nested graphs
+	assert.NotContains(graphML, `<data key="node_isBox">cluster</data>`)
+	assert.Equal(1, strings.Count(graphML, `<data key="node_isBox">app</data>`))
+	assert.Equal(2, strings.Count(graphML, `edgedefault="directed"`))
+	assert.Contains(graphML, `<data key="edge_label">http 10.00rps</data>`)
+}
+
+func TestMermaidConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	config := NewMermaidConfig(testTrafficMap(t), testConfigOptions(graph.BoxByNamespace))
+	assert.Equal(MermaidContentType, config.ContentType)
+
+	mermaid := string(config.Data)
+	assert.True(strings.HasPrefix(mermaid, "flowchart LR\n"))
+	assert.Equal(2, strings.Count(mermaid, "subgraph "))
+	assert.Equal(2, strings.Count(mermaid, "  end\n"))
+	assert.Contains(mermaid, `(["reviews v1"])`)
+	assert.Equal(1, strings.Count(mermaid, " hasErr\n"))
+	assert.Contains(mermaid, `-->|"http 10.00rps"|`)
+	assert.Contains(mermaid, `-.->|"tcp 150.00bps"|`)
+	assert.Contains(mermaid, "%% protocol=http http=10.00 http5xx=2.00 httpPercentErr=20.0 httpPercentReq=50.0 isMTLS=100\n")
+}
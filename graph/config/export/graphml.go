@@ -0,0 +1,98 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// GraphMLContentType is the media type for GraphML documents
+const GraphMLContentType = "application/graphml+xml; charset=utf-8"
+
+// NewGraphMLConfig is required by the graph/ConfigVendor interface. Box nodes are rendered as
+// nested graphs. Every attribute is declared as a string GraphML key.
+func NewGraphMLConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) graph.RawConfig {
+	doc := newDocument(trafficMap, o)
+
+	// GraphML requires the keys be declared up front
+	nodeKeys := map[string]bool{"label": true}
+	for _, nw := range doc.config.Elements.Nodes {
+		for _, attr := range nodeAttributes(nw.Data) {
+			nodeKeys[attr.Key] = true
+		}
+	}
+	edgeKeys := map[string]bool{"label": true}
+	for _, ew := range doc.config.Elements.Edges {
+		for _, attr := range edgeAttributes(ew.Data) {
+			edgeKeys[attr.Key] = true
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">` + "\n")
+	writeGraphMLKeys(&sb, "node", nodeKeys)
+	writeGraphMLKeys(&sb, "edge", edgeKeys)
+	fmt.Fprintf(&sb, "  <graph id=\"kiali\" edgedefault=\"directed\">\n")
+	fmt.Fprintf(&sb, "    <desc>graphType=%s duration=%d timestamp=%d</desc>\n", xmlEscape(doc.config.GraphType), doc.config.Duration, doc.config.Timestamp)
+	doc.writeGraphMLNodes(&sb, "", 2)
+	for _, ew := range doc.config.Elements.Edges {
+		ed := ew.Data
+		fmt.Fprintf(&sb, "    <edge id=\"%s\" source=\"%s\" target=\"%s\">\n", xmlEscape(ed.ID), xmlEscape(ed.Source), xmlEscape(ed.Target))
+		attrs := append([]attribute{{Key: "label", Value: edgeLabel(ed)}}, edgeAttributes(ed)...)
+		writeGraphMLData(&sb, "edge", attrs, "      ")
+		sb.WriteString("    </edge>\n")
+	}
+	sb.WriteString("  </graph>\n")
+	sb.WriteString("</graphml>\n")
+
+	return graph.RawConfig{ContentType: GraphMLContentType, Data: []byte(sb.String())}
+}
+
+// writeGraphMLNodes recursively writes the children of the parent node, box nodes contain nested graphs
+func (doc *document) writeGraphMLNodes(sb *strings.Builder, parent string, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, nd := range doc.children[parent] {
+		fmt.Fprintf(sb, "%s<node id=\"%s\">\n", indent, xmlEscape(nd.ID))
+		attrs := append([]attribute{{Key: "label", Value: nodeLabel(nd)}}, nodeAttributes(nd)...)
+		writeGraphMLData(sb, "node", attrs, indent+"  ")
+		if nd.NodeType == graph.NodeTypeBox {
+			fmt.Fprintf(sb, "%s  <graph id=\"%s:\" edgedefault=\"directed\">\n", indent, xmlEscape(nd.ID))
+			doc.writeGraphMLNodes(sb, nd.ID, depth+2)
+			fmt.Fprintf(sb, "%s  </graph>\n", indent)
+		}
+		fmt.Fprintf(sb, "%s</node>\n", indent)
+	}
+}
+
+func writeGraphMLKeys(sb *strings.Builder, elementType string, keys map[string]bool) {
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+	for _, k := range sortedKeys {
+		fmt.Fprintf(sb, "  <key id=\"%s\" for=\"%s\" attr.name=\"%s\" attr.type=\"string\"/>\n", graphMLKeyID(elementType, k), elementType, xmlEscape(k))
+	}
+}
+
+func writeGraphMLData(sb *strings.Builder, elementType string, attrs []attribute, indent string) {
+	for _, attr := range attrs {
+		fmt.Fprintf(sb, "%s<data key=\"%s\">%s</data>\n", indent, graphMLKeyID(elementType, attr.Key), xmlEscape(attr.Value))
+	}
+}
+
+// graphMLKeyID ensures key IDs are unique across node and edge keys of the same name
+func graphMLKeyID(elementType, key string) string {
+	return fmt.Sprintf("%s_%s", elementType, xmlEscape(key))
+}
+
+func xmlEscape(val string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(val))
+	return buf.String()
+}
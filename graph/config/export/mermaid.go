@@ -0,0 +1,103 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+// MermaidContentType is the media type for Mermaid documents
+const MermaidContentType = "text/vnd.mermaid; charset=utf-8"
+
+// NewMermaidConfig is required by the graph/ConfigVendor interface. Box nodes are rendered as
+// subgraphs. Mermaid does not support arbitrary attributes, so they are rendered as comments
+// preceding each node and edge. TCP edges are dotted, nodes with erroneous inbound requests
+// are assigned the "hasErr" class.
+func NewMermaidConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) graph.RawConfig {
+	doc := newDocument(trafficMap, o)
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	fmt.Fprintf(&sb, "  %%%% graphType=%s duration=%d timestamp=%d\n", doc.config.GraphType, doc.config.Duration, doc.config.Timestamp)
+	sb.WriteString("  classDef hasErr stroke:#c9190b,stroke-width:2px\n")
+	doc.writeMermaidNodes(&sb, "", 1)
+	for _, ew := range doc.config.Elements.Edges {
+		ed := ew.Data
+		writeMermaidComment(&sb, "  ", edgeAttributes(ed))
+		arrow := "-->"
+		if ed.Traffic.Protocol == graph.TCP.Name {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s|%s| %s\n", mermaidID(ed.Source), arrow, mermaidQuote(edgeLabel(ed)), mermaidID(ed.Target))
+	}
+
+	return graph.RawConfig{ContentType: MermaidContentType, Data: []byte(sb.String())}
+}
+
+// writeMermaidNodes recursively writes the children of the parent node, box nodes become subgraphs
+func (doc *document) writeMermaidNodes(sb *strings.Builder, parent string, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, nd := range doc.children[parent] {
+		attrs := nodeAttributes(nd)
+		writeMermaidComment(sb, indent, attrs)
+		if nd.NodeType == graph.NodeTypeBox {
+			fmt.Fprintf(sb, "%ssubgraph %s [%s]\n", indent, mermaidID(nd.ID), mermaidQuote(nodeLabel(nd)))
+			doc.writeMermaidNodes(sb, nd.ID, depth+1)
+			fmt.Fprintf(sb, "%send\n", indent)
+			continue
+		}
+		fmt.Fprintf(sb, "%s%s%s\n", indent, mermaidID(nd.ID), mermaidShape(nd))
+		if hasInboundErrors(attrs) {
+			fmt.Fprintf(sb, "%sclass %s hasErr\n", indent, mermaidID(nd.ID))
+		}
+	}
+}
+
+// mermaidShape returns the node text wrapped in a shape reflecting the node type
+func mermaidShape(nd *cytoscape.NodeData) string {
+	label := mermaidQuote(nodeLabel(nd))
+	switch nd.NodeType {
+	case graph.NodeTypeService:
+		return fmt.Sprintf("{{%s}}", label)
+	case graph.NodeTypeApp:
+		return fmt.Sprintf("([%s])", label)
+	case graph.NodeTypeAggregate:
+		return fmt.Sprintf("[/%s/]", label)
+	case graph.NodeTypeUnknown:
+		return fmt.Sprintf("((%s))", label)
+	default:
+		return fmt.Sprintf("[%s]", label)
+	}
+}
+
+func hasInboundErrors(attrs []attribute) bool {
+	for _, attr := range attrs {
+		if attr.Key == "healthInboundErrorRate" {
+			return attr.Value != "0.0"
+		}
+	}
+	return false
+}
+
+func writeMermaidComment(sb *strings.Builder, indent string, attrs []attribute) {
+	if len(attrs) == 0 {
+		return
+	}
+	pairs := make([]string, len(attrs))
+	for i, attr := range attrs {
+		pairs[i] = fmt.Sprintf("%s=%s", attr.Key, attr.Value)
+	}
+	fmt.Fprintf(sb, "%s%%%% %s\n", indent, strings.ReplaceAll(strings.Join(pairs, " "), "\n", " "))
+}
+
+// mermaidID ensures the ID is a valid mermaid identifier, the cytoscape IDs are hex hashes
+func mermaidID(id string) string {
+	return "n" + id
+}
+
+// mermaidQuote returns the text as a mermaid string, using entity codes for characters that would end the string
+func mermaidQuote(text string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(text) + `"`
+}
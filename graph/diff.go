@@ -0,0 +1,331 @@
+package graph
+
+// Diff.go supports comparing two TrafficMaps, typically generated for the same namespaces over two
+// different time windows.  The result is a union TrafficMap in which every node and edge is decorated
+// with DiffInfo metadata describing how it changed between the baseline and the current TrafficMap.
+
+import (
+	"math"
+	"reflect"
+)
+
+// The supported diff statuses
+const (
+	DiffAdded     string = "added"     // present only in the current TrafficMap
+	DiffChanged   string = "changed"   // present in both TrafficMaps, with some differing metadata
+	DiffRemoved   string = "removed"   // present only in the baseline TrafficMap
+	DiffUnchanged string = "unchanged" // present in both TrafficMaps, with equivalent metadata
+)
+
+// DiffInfo is stored as Diff metadata on each node and edge of a diff TrafficMap. For nodes the rates
+// reflect inbound request traffic, for edges the rates reflect the edge's protocol traffic.
+type DiffInfo struct {
+	Status             string        // one of the Diff<Status> constants
+	Changed            []MetadataKey // the compared metadata keys found to differ
+	BaselineRate       float64       // the total rate in the baseline TrafficMap
+	RateDelta          float64       // current total rate minus baseline total rate
+	BaselineErrorRate  float64       // the error percentage in the baseline TrafficMap
+	ErrorRateDelta     float64       // current error percentage minus baseline error percentage
+	ResponseTimeDelta  float64       // current response time minus baseline response time (edges only)
+	ThroughputDelta    float64       // current throughput minus baseline throughput (edges only)
+	HasBaselineMetrics bool          // false when the element is not present in the baseline
+}
+
+// diffNodeKeys are the non-traffic node metadata keys compared for changes. Traffic, and the health
+// data derived from traffic, is compared via rates and error rates, using the diff tolerance.
+var diffNodeKeys = []MetadataKey{
+	HasCB,
+	HasFaultInjection,
+	HasHealthConfig,
+	HasMirroring,
+	HasRequestRouting,
+	HasRequestTimeout,
+	HasTCPTrafficShifting,
+	HasTrafficShifting,
+	HasVS,
+	IsDead,
+	IsIdle,
+	IsOutOfMesh,
+}
+
+// diffEdgeKeys are the non-traffic edge metadata keys compared for changes.
+var diffEdgeKeys = []MetadataKey{
+	DestPrincipal,
+	SourcePrincipal,
+}
+
+// DiffTrafficMaps returns a new TrafficMap holding the union of the current and baseline TrafficMaps.
+// Nodes and edges present in the current TrafficMap keep their current metadata, those present only
+// in the baseline keep the baseline metadata.  Every node and edge is decorated with Diff metadata.
+// Numeric values are considered changed when their relative difference exceeds tolerance, a
+// percentage.  The provided TrafficMaps are not altered.
+func DiffTrafficMaps(current, baseline TrafficMap, tolerance float64) TrafficMap {
+	diffMap := NewTrafficMap()
+
+	// first add all nodes, without edges, so that edges can be re-pointed to the union nodes
+	for id, n := range current {
+		dn := copyNode(n)
+		if bn, ok := baseline[id]; ok {
+			dn.Metadata[Diff] = diffNode(n, bn, tolerance)
+		} else {
+			dn.Metadata[Diff] = diffNode(n, nil, tolerance)
+		}
+		diffMap[id] = dn
+	}
+	for id, bn := range baseline {
+		if _, ok := diffMap[id]; ok {
+			continue
+		}
+		dn := copyNode(bn)
+		dn.Metadata[Diff] = &DiffInfo{
+			Status:             DiffRemoved,
+			BaselineRate:       nodeRequestRate(bn),
+			RateDelta:          -nodeRequestRate(bn),
+			BaselineErrorRate:  nodeErrorRate(bn),
+			ErrorRateDelta:     -nodeErrorRate(bn),
+			HasBaselineMetrics: true,
+		}
+		diffMap[id] = dn
+	}
+
+	// then add the edges
+	for id, n := range current {
+		source := diffMap[id]
+		var baselineEdges []*Edge
+		if bn, ok := baseline[id]; ok {
+			baselineEdges = bn.Edges
+		}
+		for _, e := range n.Edges {
+			de := source.AddEdge(diffMap[e.Dest.ID])
+			de.Metadata = copyMetadata(e.Metadata)
+			if be := findEdge(baselineEdges, e.Dest.ID, e.Metadata[ProtocolKey]); be != nil {
+				de.Metadata[Diff] = diffEdge(e, be, tolerance)
+			} else {
+				de.Metadata[Diff] = diffEdge(e, nil, tolerance)
+			}
+		}
+	}
+	for id, bn := range baseline {
+		source := diffMap[id]
+		var currentEdges []*Edge
+		if n, ok := current[id]; ok {
+			currentEdges = n.Edges
+		}
+		for _, be := range bn.Edges {
+			if findEdge(currentEdges, be.Dest.ID, be.Metadata[ProtocolKey]) != nil {
+				continue
+			}
+			de := source.AddEdge(diffMap[be.Dest.ID])
+			de.Metadata = copyMetadata(be.Metadata)
+			rate, errRate := edgeRates(be)
+			de.Metadata[Diff] = &DiffInfo{
+				Status:             DiffRemoved,
+				BaselineRate:       rate,
+				RateDelta:          -rate,
+				BaselineErrorRate:  errRate,
+				ErrorRateDelta:     -errRate,
+				HasBaselineMetrics: true,
+			}
+		}
+	}
+
+	return diffMap
+}
+
+func diffNode(n, bn *Node, tolerance float64) *DiffInfo {
+	rate := nodeRequestRate(n)
+	errRate := nodeErrorRate(n)
+	if bn == nil {
+		return &DiffInfo{
+			Status:         DiffAdded,
+			RateDelta:      rate,
+			ErrorRateDelta: errRate,
+		}
+	}
+
+	baselineRate := nodeRequestRate(bn)
+	baselineErrRate := nodeErrorRate(bn)
+	di := &DiffInfo{
+		Status:             DiffUnchanged,
+		BaselineRate:       baselineRate,
+		RateDelta:          rate - baselineRate,
+		BaselineErrorRate:  baselineErrRate,
+		ErrorRateDelta:     errRate - baselineErrRate,
+		HasBaselineMetrics: true,
+	}
+	if isChanged(rate, baselineRate, tolerance) {
+		di.Changed = append(di.Changed, diffRate)
+	}
+	if isChanged(errRate, baselineErrRate, tolerance) {
+		di.Changed = append(di.Changed, diffErrorRate)
+	}
+	di.Changed = append(di.Changed, changedKeys(n.Metadata, bn.Metadata, diffNodeKeys)...)
+	if len(di.Changed) > 0 {
+		di.Status = DiffChanged
+	}
+
+	return di
+}
+
+func diffEdge(e, be *Edge, tolerance float64) *DiffInfo {
+	rate, errRate := edgeRates(e)
+	if be == nil {
+		return &DiffInfo{
+			Status:            DiffAdded,
+			RateDelta:         rate,
+			ErrorRateDelta:    errRate,
+			ResponseTimeDelta: getFloat(e.Metadata, ResponseTime),
+			ThroughputDelta:   getFloat(e.Metadata, Throughput),
+		}
+	}
+
+	baselineRate, baselineErrRate := edgeRates(be)
+	di := &DiffInfo{
+		Status:             DiffUnchanged,
+		BaselineRate:       baselineRate,
+		RateDelta:          rate - baselineRate,
+		BaselineErrorRate:  baselineErrRate,
+		ErrorRateDelta:     errRate - baselineErrRate,
+		ResponseTimeDelta:  getFloat(e.Metadata, ResponseTime) - getFloat(be.Metadata, ResponseTime),
+		ThroughputDelta:    getFloat(e.Metadata, Throughput) - getFloat(be.Metadata, Throughput),
+		HasBaselineMetrics: true,
+	}
+	if isChanged(rate, baselineRate, tolerance) {
+		di.Changed = append(di.Changed, diffRate)
+	}
+	if isChanged(errRate, baselineErrRate, tolerance) {
+		di.Changed = append(di.Changed, diffErrorRate)
+	}
+	if isChanged(getFloat(e.Metadata, IsMTLS), getFloat(be.Metadata, IsMTLS), tolerance) {
+		di.Changed = append(di.Changed, IsMTLS)
+	}
+	if isChanged(getFloat(e.Metadata, ResponseTime), getFloat(be.Metadata, ResponseTime), tolerance) {
+		di.Changed = append(di.Changed, ResponseTime)
+	}
+	if isChanged(getFloat(e.Metadata, Throughput), getFloat(be.Metadata, Throughput), tolerance) {
+		di.Changed = append(di.Changed, Throughput)
+	}
+	di.Changed = append(di.Changed, changedKeys(e.Metadata, be.Metadata, diffEdgeKeys)...)
+	if len(di.Changed) > 0 {
+		di.Status = DiffChanged
+	}
+
+	return di
+}
+
+// the pseudo-keys used to report traffic changes in DiffInfo.Changed
+const (
+	diffErrorRate MetadataKey = "errorRate"
+	diffRate      MetadataKey = "rate"
+)
+
+// isChanged returns true if the relative difference between the values exceeds tolerance (a percentage)
+func isChanged(val, baselineVal, tolerance float64) bool {
+	if val == baselineVal {
+		return false
+	}
+	largest := math.Max(math.Abs(val), math.Abs(baselineVal))
+	return math.Abs(val-baselineVal)/largest*100.0 > tolerance
+}
+
+func changedKeys(md, baselineMd Metadata, keys []MetadataKey) []MetadataKey {
+	var changed []MetadataKey
+	for _, k := range keys {
+		val, ok := md[k]
+		baselineVal, baselineOk := baselineMd[k]
+		if ok != baselineOk || !reflect.DeepEqual(val, baselineVal) {
+			changed = append(changed, k)
+		}
+	}
+	return changed
+}
+
+// edgeRates returns the total rate and the error percentage for the edge's protocol
+func edgeRates(e *Edge) (rate, errRate float64) {
+	errTotal := 0.0
+	for _, p := range Protocols {
+		if p.Name != e.Metadata[ProtocolKey] {
+			continue
+		}
+		for _, r := range p.EdgeRates {
+			switch {
+			case r.IsTotal:
+				rate = getFloat(e.Metadata, r.Name)
+			case r.IsErr:
+				errTotal += getFloat(e.Metadata, r.Name)
+			}
+		}
+	}
+	if rate > 0 {
+		errRate = errTotal / rate * 100.0
+	}
+	return rate, errRate
+}
+
+// nodeRequestRate returns the total inbound request rate, summed over the request protocols
+func nodeRequestRate(n *Node) float64 {
+	rate := 0.0
+	for _, p := range Protocols {
+		if p.Unit != requestsPerSecond {
+			continue
+		}
+		for _, r := range p.NodeRates {
+			if r.IsIn {
+				rate += getFloat(n.Metadata, r.Name)
+			}
+		}
+	}
+	return rate
+}
+
+// nodeErrorRate returns the inbound error percentage, over the request protocols
+func nodeErrorRate(n *Node) float64 {
+	rate := nodeRequestRate(n)
+	if rate == 0 {
+		return 0
+	}
+	errTotal := 0.0
+	for _, p := range Protocols {
+		if p.Unit != requestsPerSecond {
+			continue
+		}
+		for _, r := range p.NodeRates {
+			if r.IsErr {
+				errTotal += getFloat(n.Metadata, r.Name)
+			}
+		}
+	}
+	return errTotal / rate * 100.0
+}
+
+func findEdge(edges []*Edge, destID string, protocol interface{}) *Edge {
+	for _, e := range edges {
+		if e.Dest.ID == destID && e.Metadata[ProtocolKey] == protocol {
+			return e
+		}
+	}
+	return nil
+}
+
+func copyNode(n *Node) *Node {
+	dn := *n
+	dn.Edges = []*Edge{}
+	dn.Metadata = copyMetadata(n.Metadata)
+	return &dn
+}
+
+// copyMetadata performs a shallow copy, sufficient to decorate the copy without affecting the original
+func copyMetadata(md Metadata) Metadata {
+	mdCopy := NewMetadata()
+	for k, v := range md {
+		mdCopy[k] = v
+	}
+	return mdCopy
+}
+
+func getFloat(md Metadata, k MetadataKey) float64 {
+	if val, ok := md[k].(float64); ok {
+		return val
+	}
+	return 0.0
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDiffTestNode(t *testing.T, workload string) *Node {
+	n, err := NewNode("east", "", "", "bookinfo", workload, workload, "v1", GraphTypeWorkload)
+	require.NoError(t, err)
+	return n
+}
+
+func addDiffTestEdge(source, dest *Node, rate, errRate float64) *Edge {
+	e := source.AddEdge(dest)
+	e.Metadata[ProtocolKey] = "http"
+	AddToMetadata("http", rate-errRate, "200", "-", "", source.Metadata, dest.Metadata, e.Metadata)
+	AddToMetadata("http", errRate, "500", "-", "", source.Metadata, dest.Metadata, e.Metadata)
+	return e
+}
+
+func TestDiffTrafficMaps(t *testing.T) {
+	assert := assert.New(t)
+
+	// baseline: productpage -> reviews, productpage -> details
+	baseline := NewTrafficMap()
+	bProductpage := newDiffTestNode(t, "productpage")
+	bReviews := newDiffTestNode(t, "reviews")
+	bDetails := newDiffTestNode(t, "details")
+	baseline[bProductpage.ID] = bProductpage
+	baseline[bReviews.ID] = bReviews
+	baseline[bDetails.ID] = bDetails
+	addDiffTestEdge(bProductpage, bReviews, 10.0, 0.0)
+	addDiffTestEdge(bProductpage, bDetails, 5.0, 0.0)
+
+	// current: productpage -> reviews (with errors), productpage -> ratings
+	current := NewTrafficMap()
+	productpage := newDiffTestNode(t, "productpage")
+	reviews := newDiffTestNode(t, "reviews")
+	ratings := newDiffTestNode(t, "ratings")
+	current[productpage.ID] = productpage
+	current[reviews.ID] = reviews
+	current[ratings.ID] = ratings
+	addDiffTestEdge(productpage, reviews, 10.0, 2.0)
+	addDiffTestEdge(productpage, ratings, 4.0, 0.0)
+	productpage.Metadata[IsOutOfMesh] = true
+
+	diffMap := DiffTrafficMaps(current, baseline, 10.0)
+	assert.Equal(4, len(diffMap))
+
+	// inputs are not altered
+	assert.Equal(3, len(current))
+	assert.Equal(2, len(productpage.Edges))
+	_, found := productpage.Metadata[Diff]
+	assert.False(found)
+
+	// nodes
+	di := diffMap[productpage.ID].Metadata[Diff].(*DiffInfo)
+	assert.Equal(DiffChanged, di.Status)
+	assert.Equal([]MetadataKey{IsOutOfMesh}, di.Changed)
+
+	di = diffMap[reviews.ID].Metadata[Diff].(*DiffInfo)
+	assert.Equal(DiffChanged, di.Status)
+	assert.Equal([]MetadataKey{diffErrorRate}, di.Changed)
+	assert.Equal(20.0, di.ErrorRateDelta)
+	assert.Equal(0.0, di.RateDelta)
+
+	di = diffMap[ratings.ID].Metadata[Diff].(*DiffInfo)
+	assert.Equal(DiffAdded, di.Status)
+	assert.Equal(4.0, di.RateDelta)
+	assert.False(di.HasBaselineMetrics)
+
+	di = diffMap[bDetails.ID].Metadata[Diff].(*DiffInfo)
+	assert.Equal(DiffRemoved, di.Status)
+	assert.Equal(5.0, di.BaselineRate)
+	assert.Equal(-5.0, di.RateDelta)
+
+	// edges
+	edges := diffMap[productpage.ID].Edges
+	assert.Equal(3, len(edges))
+	for _, e := range edges {
+		assert.Same(diffMap[productpage.ID], e.Source)
+		assert.Same(diffMap[e.Dest.ID], e.Dest)
+
+		di := e.Metadata[Diff].(*DiffInfo)
+		switch e.Dest.ID {
+		case reviews.ID:
+			assert.Equal(DiffChanged, di.Status)
+			assert.Equal(20.0, di.ErrorRateDelta)
+			assert.Equal(10.0, di.BaselineRate)
+		case ratings.ID:
+			assert.Equal(DiffAdded, di.Status)
+			assert.Equal(4.0, di.RateDelta)
+		case bDetails.ID:
+			assert.Equal(DiffRemoved, di.Status)
+			assert.Equal(-5.0, di.RateDelta)
+		default:
+			assert.Fail("unexpected edge dest", e.Dest.ID)
+		}
+	}
+}
+
+func TestDiffTrafficMapsTolerance(t *testing.T) {
+	assert := assert.New(t)
+
+	baseline := NewTrafficMap()
+	bSource := newDiffTestNode(t, "productpage")
+	bDest := newDiffTestNode(t, "reviews")
+	baseline[bSource.ID] = bSource
+	baseline[bDest.ID] = bDest
+	addDiffTestEdge(bSource, bDest, 10.0, 0.0)
+
+	current := NewTrafficMap()
+	source := newDiffTestNode(t, "productpage")
+	dest := newDiffTestNode(t, "reviews")
+	current[source.ID] = source
+	current[dest.ID] = dest
+	addDiffTestEdge(source, dest, 10.5, 0.0)
+
+	// a 5% increase is within the tolerance
+	diffMap := DiffTrafficMaps(current, baseline, 10.0)
+	di := diffMap[source.ID].Edges[0].Metadata[Diff].(*DiffInfo)
+	assert.Equal(DiffUnchanged, di.Status)
+	assert.InDelta(0.5, di.RateDelta, 0.0001)
+
+	// but not when tolerating no change
+	diffMap = DiffTrafficMaps(current, baseline, 0.0)
+	di = diffMap[source.ID].Edges[0].Metadata[Diff].(*DiffInfo)
+	assert.Equal(DiffChanged, di.Status)
+	assert.Equal([]MetadataKey{diffRate}, di.Changed)
+}
@@ -0,0 +1,545 @@
+// Package find evaluates the graph find/hide expression language server-side. The language is the one
+// supported by the graph toolbar (see config.GraphFindOption): expressions like "app = reviews",
+// "%httperr > 5" or "!mtls", combined with AND and OR. AND binds tighter than OR, and the expressions
+// of an AND clause must all apply to nodes, or all apply to edges.
+package find
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// Expression is a parsed find/hide expression, a disjunction of conjunctive clauses
+type Expression struct {
+	clauses []clause
+}
+
+// clause is a conjunction of conditions, all applying to nodes or all applying to edges
+type clause struct {
+	edgeConditions []edgeCondition
+	nodeConditions []nodeCondition
+}
+
+type (
+	edgeCondition func(e *graph.Edge) bool
+	nodeCondition func(n *graph.Node) bool
+)
+
+// parsed is a single parsed expression, exactly one of the conditions is set
+type parsed struct {
+	edge edgeCondition
+	node nodeCondition
+}
+
+// operators in the order they must be detected, longer operators sharing characters with shorter ones first
+var operators = []string{"!=", "!*=", "!$=", "!^=", ">=", "<=", "*=", "$=", "^=", "=", ">", "<", "!"}
+
+var (
+	doubleSpaces = regexp.MustCompile(` +`)
+	// mnemonic qualifiers of unary operands (e.g. 'has cb' -> 'cb')
+	isHas    = regexp.MustCompile(`(?i) (is|has) `)
+	notIsHas = regexp.MustCompile(`(?i) !\s*(is|has) `)
+	// string operators
+	not           = regexp.MustCompile(`(?i) not `)
+	notContains   = regexp.MustCompile(`(?i) !\s*contains `)
+	notStartsWith = regexp.MustCompile(`(?i) !\s*startswith `)
+	notEndsWith   = regexp.MustCompile(`(?i) !\s*endswith `)
+	contains      = regexp.MustCompile(`(?i) contains `)
+	startsWith    = regexp.MustCompile(`(?i) startswith `)
+	endsWith      = regexp.MustCompile(`(?i) endswith `)
+	// conjunctions
+	and = regexp.MustCompile(`(?i) and `)
+	or  = regexp.MustCompile(`(?i) or `)
+)
+
+// Parse parses a find/hide expression. An empty expression returns a nil Expression, which matches nothing.
+func Parse(expression string) (*Expression, error) {
+	prepared := prepare(expression)
+	if prepared == "" {
+		return nil, nil
+	}
+
+	result := &Expression{}
+	for _, orClause := range strings.Split(prepared, " OR ") {
+		expressions := strings.Split(orClause, " AND ")
+		conjunctive := len(expressions) > 1
+		c := clause{}
+		for _, e := range expressions {
+			p, err := parseExpression(e, conjunctive)
+			if err != nil {
+				return nil, err
+			}
+			if (p.edge != nil && len(c.nodeConditions) > 0) || (p.node != nil && len(c.edgeConditions) > 0) {
+				return nil, fmt.Errorf("invalid expression, can not AND node and edge criteria")
+			}
+			if p.edge != nil {
+				c.edgeConditions = append(c.edgeConditions, p.edge)
+			} else {
+				c.nodeConditions = append(c.nodeConditions, p.node)
+			}
+		}
+		result.clauses = append(result.clauses, c)
+	}
+	return result, nil
+}
+
+// MatchNode returns true if the node satisfies the expression
+func (e *Expression) MatchNode(n *graph.Node) bool {
+	if e == nil {
+		return false
+	}
+	for _, c := range e.clauses {
+		if len(c.nodeConditions) == 0 {
+			continue
+		}
+		match := true
+		for _, condition := range c.nodeConditions {
+			if match = condition(n); !match {
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchEdge returns true if the edge satisfies the expression
+func (e *Expression) MatchEdge(edge *graph.Edge) bool {
+	if e == nil {
+		return false
+	}
+	for _, c := range e.clauses {
+		if len(c.edgeConditions) == 0 {
+			continue
+		}
+		match := true
+		for _, condition := range c.edgeConditions {
+			if match = condition(edge); !match {
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// prepare normalizes the mnemonic operators, and the conjunctions, of the expression
+func prepare(val string) string {
+	val = doubleSpaces.ReplaceAllString(val, " ")
+
+	val = " " + val
+	val = isHas.ReplaceAllString(val, " ")
+	val = notIsHas.ReplaceAllString(val, " ! ")
+
+	val = not.ReplaceAllString(val, " !")
+	val = notContains.ReplaceAllString(val, " !*= ")
+	val = notStartsWith.ReplaceAllString(val, " !^= ")
+	val = notEndsWith.ReplaceAllString(val, " !$= ")
+	val = contains.ReplaceAllString(val, " *= ")
+	val = startsWith.ReplaceAllString(val, " ^= ")
+	val = endsWith.ReplaceAllString(val, " $= ")
+
+	val = and.ReplaceAllString(val, " AND ")
+	val = or.ReplaceAllString(val, " OR ")
+
+	return strings.TrimSpace(val)
+}
+
+func parseExpression(expression string, conjunctive bool) (parsed, error) {
+	op := ""
+	for _, o := range operators {
+		if strings.Contains(expression, o) {
+			op = o
+			break
+		}
+	}
+
+	if op == "" {
+		if len(strings.Split(strings.TrimSpace(expression), " ")) > 1 {
+			return parsed{}, fmt.Errorf("no valid operator found in expression [%s]", expression)
+		}
+		return parseUnaryExpression(strings.TrimSpace(expression), false)
+	}
+
+	field, val, _ := strings.Cut(expression, op)
+	field = strings.TrimSpace(field)
+	val = strings.TrimSpace(val)
+
+	if op == "!" {
+		return parseUnaryExpression(val, true)
+	}
+
+	switch strings.ToLower(field) {
+	//
+	// nodes...
+	//
+	case "app":
+		return nodeString(op, val, func(n *graph.Node) string { return n.App })
+	case "cluster":
+		return nodeString(op, val, func(n *graph.Node) string { return n.Cluster })
+	case "grpcin":
+		return nodeNumeric(op, val, nodeRate("grpcIn"))
+	case "grpcout":
+		return nodeNumeric(op, val, nodeRate("grpcOut"))
+	case "httpin":
+		return nodeNumeric(op, val, nodeRate("httpIn"))
+	case "httpout":
+		return nodeNumeric(op, val, nodeRate("httpOut"))
+	case "name":
+		if conjunctive {
+			return parsed{}, fmt.Errorf("can not use 'AND' with 'name' operand")
+		}
+		names := []func(n *graph.Node) string{
+			func(n *graph.Node) string { return metadataString(n.Metadata, graph.AggregateValue) },
+			func(n *graph.Node) string { return n.App },
+			func(n *graph.Node) string { return n.Service },
+			func(n *graph.Node) string { return n.Workload },
+		}
+		compare, err := stringComparison(op)
+		if err != nil {
+			return parsed{}, err
+		}
+		// a negation must hold for every name, otherwise any name may match
+		isNegation := strings.HasPrefix(op, "!")
+		return parsed{node: func(n *graph.Node) bool {
+			for _, name := range names {
+				if compare(name(n), val) != isNegation {
+					return !isNegation
+				}
+			}
+			return isNegation
+		}}, nil
+	case "node":
+		nodeType := strings.ToLower(val)
+		switch nodeType {
+		case "op", "operation":
+			nodeType = graph.NodeTypeAggregate
+		case "svc":
+			nodeType = graph.NodeTypeService
+		case "wl":
+			nodeType = graph.NodeTypeWorkload
+		}
+		switch nodeType {
+		case graph.NodeTypeAggregate, graph.NodeTypeApp, graph.NodeTypeService, graph.NodeTypeWorkload, graph.NodeTypeUnknown:
+			return nodeString(op, nodeType, func(n *graph.Node) string { return n.NodeType })
+		default:
+			return parsed{}, fmt.Errorf("invalid node type [%s], expected app | operation | service | unknown | workload", nodeType)
+		}
+	case "ns", "namespace":
+		return nodeString(op, val, func(n *graph.Node) string { return n.Namespace })
+	case "op", "operation":
+		return nodeString(op, val, func(n *graph.Node) string { return metadataString(n.Metadata, graph.AggregateValue) })
+	case "rank":
+		return parsed{}, fmt.Errorf("operand [%s] is computed by the client and is not supported server-side", field)
+	case "svc", "service":
+		return nodeString(op, val, func(n *graph.Node) string { return n.Service })
+	case "tcpin":
+		return nodeNumeric(op, val, nodeRate("tcpIn"))
+	case "tcpout":
+		return nodeNumeric(op, val, nodeRate("tcpOut"))
+	case "version":
+		return nodeString(op, val, func(n *graph.Node) string { return n.Version })
+	case "wl", "workload":
+		return nodeString(op, val, func(n *graph.Node) string { return n.Workload })
+	//
+	// edges...
+	//
+	case "destprincipal":
+		return edgeString(op, val, func(e *graph.Edge) string { return metadataString(e.Metadata, graph.DestPrincipal) })
+	case "grpc":
+		return edgeNumeric(op, val, edgeRate("grpc"))
+	case "%grpcerror", "%grpcerr":
+		return edgeNumeric(op, val, edgePercentErr(graph.GRPC))
+	case "%grpctraffic":
+		return edgeNumeric(op, val, edgePercentReq(graph.GRPC))
+	case "http":
+		return edgeNumeric(op, val, edgeRate("http"))
+	case "%httperror", "%httperr":
+		return edgeNumeric(op, val, edgePercentErr(graph.HTTP))
+	case "%httptraffic":
+		return edgeNumeric(op, val, edgePercentReq(graph.HTTP))
+	case "protocol":
+		return edgeString(op, val, func(e *graph.Edge) string { return metadataString(e.Metadata, graph.ProtocolKey) })
+	case "rt", "responsetime":
+		return edgeNumeric(op, val, edgeRate(graph.ResponseTime))
+	case "sourceprincipal":
+		return edgeString(op, val, func(e *graph.Edge) string { return metadataString(e.Metadata, graph.SourcePrincipal) })
+	case "tcp":
+		return edgeNumeric(op, val, edgeRate("tcp"))
+	case "throughput":
+		return edgeNumeric(op, val, edgeRate(graph.Throughput))
+	default:
+		// special node operand
+		if label, ok := cutPrefixFold(field, "label:"); ok {
+			return nodeString(op, val, func(n *graph.Node) string {
+				labels, _ := n.Metadata[graph.Labels].(graph.LabelsMetadata)
+				return labels[label]
+			})
+		}
+		return parsed{}, fmt.Errorf("invalid operand [%s]", field)
+	}
+}
+
+// nodeFlags are the unary node operands, and the metadata they test
+var nodeFlags = map[string]graph.MetadataKey{
+	"cb":                 graph.HasCB,
+	"circuitbreaker":     graph.HasCB,
+	"dead":               graph.IsDead,
+	"fi":                 graph.HasFaultInjection,
+	"faultinjection":     graph.HasFaultInjection,
+	"inaccessible":       graph.IsInaccessible,
+	"idle":               graph.IsIdle,
+	"mirroring":          graph.HasMirroring,
+	"outside":            graph.IsOutside,
+	"outsider":           graph.IsOutside,
+	"rr":                 graph.HasRequestRouting,
+	"requestrouting":     graph.HasRequestRouting,
+	"rto":                graph.HasRequestTimeout,
+	"requesttimeout":     graph.HasRequestTimeout,
+	"se":                 graph.IsServiceEntry,
+	"serviceentry":       graph.IsServiceEntry,
+	"om":                 graph.IsOutOfMesh,
+	"outofmesh":          graph.IsOutOfMesh,
+	"tcpts":              graph.HasTCPTrafficShifting,
+	"tcptrafficshifting": graph.HasTCPTrafficShifting,
+	"ts":                 graph.HasTrafficShifting,
+	"trafficshifting":    graph.HasTrafficShifting,
+	"trafficsource":      graph.IsRoot,
+	"root":               graph.IsRoot,
+	"vs":                 graph.HasVS,
+	"virtualservice":     graph.HasVS,
+	"we":                 graph.HasWorkloadEntry,
+	"workloadentry":      graph.HasWorkloadEntry,
+}
+
+func parseUnaryExpression(field string, isNegation bool) (parsed, error) {
+	lowerField := strings.ToLower(field)
+	if key, ok := nodeFlags[lowerField]; ok {
+		return parsed{node: func(n *graph.Node) bool { return isSet(n.Metadata[key]) != isNegation }}, nil
+	}
+
+	switch lowerField {
+	case "sc", "sidecar":
+		// the inverse of outofmesh
+		return parsed{node: func(n *graph.Node) bool { return isSet(n.Metadata[graph.IsOutOfMesh]) == isNegation }}, nil
+	case "healthy":
+		return parsed{}, fmt.Errorf("operand [%s] is computed by the client and is not supported server-side", field)
+	case "mtls":
+		return parsed{edge: func(e *graph.Edge) bool {
+			mtls, _ := e.Metadata[graph.IsMTLS].(float64)
+			return (mtls > 0) != isNegation
+		}}, nil
+	case "traffic":
+		return parsed{edge: func(e *graph.Edge) bool { return hasTraffic(e) != isNegation }}, nil
+	}
+
+	// special node operand
+	if label, ok := cutPrefixFold(field, "label:"); ok {
+		return parsed{node: func(n *graph.Node) bool {
+			labels, _ := n.Metadata[graph.Labels].(graph.LabelsMetadata)
+			_, hasLabel := labels[label]
+			return hasLabel != isNegation
+		}}, nil
+	}
+
+	return parsed{}, fmt.Errorf("invalid node or edge operand [%s]", field)
+}
+
+func nodeString(op, val string, get func(n *graph.Node) string) (parsed, error) {
+	compare, err := stringComparison(op)
+	if err != nil {
+		return parsed{}, err
+	}
+	return parsed{node: func(n *graph.Node) bool { return compare(get(n), val) }}, nil
+}
+
+func edgeString(op, val string, get func(e *graph.Edge) string) (parsed, error) {
+	compare, err := stringComparison(op)
+	if err != nil {
+		return parsed{}, err
+	}
+	return parsed{edge: func(e *graph.Edge) bool { return compare(get(e), val) }}, nil
+}
+
+func nodeNumeric(op, val string, get func(n *graph.Node) float64) (parsed, error) {
+	compare, err := numericComparison(op, val)
+	if err != nil {
+		return parsed{}, err
+	}
+	return parsed{node: func(n *graph.Node) bool { return compare(get(n)) }}, nil
+}
+
+func edgeNumeric(op, val string, get func(e *graph.Edge) float64) (parsed, error) {
+	compare, err := numericComparison(op, val)
+	if err != nil {
+		return parsed{}, err
+	}
+	return parsed{edge: func(e *graph.Edge) bool { return compare(get(e)) }}, nil
+}
+
+// stringComparison returns the comparison for the operator, comparisons are case sensitive
+func stringComparison(op string) (func(actual, val string) bool, error) {
+	switch op {
+	case "=":
+		return func(actual, val string) bool { return actual == val }, nil
+	case "!=":
+		return func(actual, val string) bool { return actual != val }, nil
+	case "*=":
+		return strings.Contains, nil
+	case "!*=":
+		return func(actual, val string) bool { return !strings.Contains(actual, val) }, nil
+	case "^=":
+		return strings.HasPrefix, nil
+	case "!^=":
+		return func(actual, val string) bool { return !strings.HasPrefix(actual, val) }, nil
+	case "$=":
+		return strings.HasSuffix, nil
+	case "!$=":
+		return func(actual, val string) bool { return !strings.HasSuffix(actual, val) }, nil
+	default:
+		return nil, fmt.Errorf("invalid operator [%s] for string condition", op)
+	}
+}
+
+// numericComparison returns the comparison of a value to val. An equality test with a non-numeric
+// val tests whether the value is unset (=) or set (!=), missing values are 0.
+func numericComparison(op, val string) (func(actual float64) bool, error) {
+	num, err := strconv.ParseFloat(val, 64)
+	isNumeric := err == nil
+	switch op {
+	case ">", "<", ">=", "<=":
+		if !isNumeric {
+			return nil, fmt.Errorf("invalid value [%s], expected a numeric value (use '.' for decimals)", val)
+		}
+	case "=":
+		if !isNumeric {
+			return func(actual float64) bool { return actual == 0 }, nil
+		}
+	case "!=":
+		if !isNumeric {
+			return func(actual float64) bool { return actual != 0 }, nil
+		}
+	default:
+		return nil, fmt.Errorf("invalid operator [%s] for numeric condition", op)
+	}
+
+	switch op {
+	case ">":
+		return func(actual float64) bool { return actual > num }, nil
+	case "<":
+		return func(actual float64) bool { return actual < num }, nil
+	case ">=":
+		return func(actual float64) bool { return actual >= num }, nil
+	case "<=":
+		return func(actual float64) bool { return actual <= num }, nil
+	case "=":
+		return func(actual float64) bool { return actual == num }, nil
+	default:
+		return func(actual float64) bool { return actual != num }, nil
+	}
+}
+
+func nodeRate(key graph.MetadataKey) func(n *graph.Node) float64 {
+	return func(n *graph.Node) float64 {
+		val, _ := n.Metadata[key].(float64)
+		return val
+	}
+}
+
+func edgeRate(key graph.MetadataKey) func(e *graph.Edge) float64 {
+	return func(e *graph.Edge) float64 {
+		val, _ := e.Metadata[key].(float64)
+		return val
+	}
+}
+
+// edgePercentErr returns the error percentage of the edge's protocol traffic, as reported by the graph
+func edgePercentErr(p graph.Protocol) func(e *graph.Edge) float64 {
+	return func(e *graph.Edge) float64 {
+		total, errs := 0.0, 0.0
+		for _, r := range p.EdgeRates {
+			val, _ := e.Metadata[r.Name].(float64)
+			switch {
+			case r.IsTotal:
+				total = val
+			case r.IsErr:
+				errs += val
+			}
+		}
+		if total == 0 {
+			return 0
+		}
+		return errs / total * 100
+	}
+}
+
+// edgePercentReq returns the edge's percentage of the source node's outbound protocol traffic, as reported by the graph
+func edgePercentReq(p graph.Protocol) func(e *graph.Edge) float64 {
+	return func(e *graph.Edge) float64 {
+		total, out := 0.0, 0.0
+		for _, r := range p.EdgeRates {
+			if r.IsTotal {
+				total, _ = e.Metadata[r.Name].(float64)
+			}
+		}
+		for _, r := range p.NodeRates {
+			if r.IsOut {
+				out, _ = e.Source.Metadata[r.Name].(float64)
+			}
+		}
+		if total == 0 || out == 0 {
+			return 0
+		}
+		return total / out * 100
+	}
+}
+
+func hasTraffic(e *graph.Edge) bool {
+	for _, p := range graph.Protocols {
+		for _, r := range p.EdgeRates {
+			if val, _ := e.Metadata[r.Name].(float64); r.IsTotal && val > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func metadataString(md graph.Metadata, key graph.MetadataKey) string {
+	val, _ := md[key].(string)
+	return val
+}
+
+// isSet returns true for metadata the graph reports as set: true booleans, non-zero numbers, non-empty strings
+// and any other non-nil value
+func isSet(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case *graph.SEInfo:
+		return v != nil
+	default:
+		return true
+	}
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return "", false
+	}
+	return s[len(prefix):], true
+}
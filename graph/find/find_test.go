@@ -0,0 +1,211 @@
+package find
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// productpage -> reviews-v1 (http, 10% errors, mtls), productpage -> details-v1 (http), reviews-v1 -> ratings-v1 (http)
+// and reviews-v1 -> mysql-v1 (tcp). ratings has a circuit breaker, idle-v1 is an idle node.
+func findTestTrafficMap(t *testing.T) graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+	newNode := func(app string) *graph.Node {
+		n, err := graph.NewNode("east", "bookinfo", app, "bookinfo", app+"-v1", app, "v1", graph.GraphTypeVersionedApp)
+		require.NoError(t, err)
+		n.Metadata[graph.Labels] = graph.LabelsMetadata{"app": app}
+		trafficMap[n.ID] = n
+		return n
+	}
+	addEdge := func(source, dest *graph.Node, protocol string, rate float64, code string) *graph.Edge {
+		e := source.AddEdge(dest)
+		e.Metadata[graph.ProtocolKey] = protocol
+		graph.AddToMetadata(protocol, rate, code, "-", "", source.Metadata, dest.Metadata, e.Metadata)
+		return e
+	}
+
+	productpage := newNode("productpage")
+	productpage.Metadata[graph.IsRoot] = true
+	reviews := newNode("reviews")
+	details := newNode("details")
+	ratings := newNode("ratings")
+	ratings.Metadata[graph.HasCB] = true
+	mysql := newNode("mysql")
+	idle := newNode("idle")
+	idle.Metadata[graph.IsIdle] = true
+
+	e := addEdge(productpage, reviews, "http", 9.0, "200")
+	graph.AddToMetadata("http", 1.0, "503", "-", "", productpage.Metadata, reviews.Metadata, e.Metadata)
+	e.Metadata[graph.IsMTLS] = 100.0
+	e.Metadata[graph.ResponseTime] = 250.0
+	addEdge(productpage, details, "http", 10.0, "200")
+	addEdge(reviews, ratings, "http", 5.0, "200")
+	addEdge(reviews, mysql, "tcp", 1000.0, "")
+
+	return trafficMap
+}
+
+func findNode(trafficMap graph.TrafficMap, app string) *graph.Node {
+	for _, n := range trafficMap {
+		if n.App == app {
+			return n
+		}
+	}
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, valid := range []string{
+		"app = reviews",
+		"app contains view OR workload endswith -v1",
+		"name != reviews",
+		"node = wl",
+		"httpin > 1.5 AND httpout = 0",
+		"has cb",
+		"is not root",
+		"!mtls",
+		"label:app",
+		"label:app = reviews",
+		"%httperr >= 5 and protocol = http",
+		"rt > 100 or traffic",
+	} {
+		expression, err := Parse(valid)
+		assert.NoError(err, valid)
+		assert.NotNil(expression, valid)
+	}
+
+	expression, err := Parse("  ")
+	assert.NoError(err)
+	assert.Nil(expression)
+	assert.False(expression.MatchNode(&graph.Node{}))
+
+	for _, invalid := range []string{
+		"app reviews",
+		"foo = bar",
+		"app = reviews AND http > 5",
+		"name = reviews AND app = reviews",
+		"node = pod",
+		"httpin > lots",
+		"app > 5",
+		"http *= 5",
+		"unknownflag",
+		"healthy",
+		"rank > 5",
+	} {
+		_, err := Parse(invalid)
+		assert.Error(err, invalid)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+	trafficMap := findTestTrafficMap(t)
+	productpage := findNode(trafficMap, "productpage")
+	reviews := findNode(trafficMap, "reviews")
+	ratings := findNode(trafficMap, "ratings")
+
+	matchNode := func(expression string, n *graph.Node) bool {
+		e, err := Parse(expression)
+		assert.NoError(err, expression)
+		return e.MatchNode(n)
+	}
+	assert.True(matchNode("app = reviews", reviews))
+	assert.True(matchNode("APP startswith rev", reviews))
+	assert.False(matchNode("app not contains view", reviews))
+	assert.True(matchNode("name = reviews-v1", reviews))
+	assert.False(matchNode("name != reviews-v1", reviews))
+	assert.True(matchNode("name != details", reviews))
+	assert.True(matchNode("node = app", reviews))
+	assert.True(matchNode("httpin = 10 AND httpout = 5", reviews))
+	assert.True(matchNode("tcpout > 999", reviews))
+	assert.True(matchNode("httpin = x", productpage), "non-numeric equality tests for unset")
+	assert.True(matchNode("has cb", ratings))
+	assert.False(matchNode("cb", reviews))
+	assert.True(matchNode("is not cb", reviews))
+	assert.True(matchNode("trafficsource", productpage))
+	assert.True(matchNode("sidecar", reviews))
+	assert.True(matchNode("label:app = ratings", ratings))
+	assert.False(matchNode("!label:app", ratings))
+	assert.True(matchNode("app = details OR app = ratings", ratings))
+	assert.False(matchNode("http > 0", reviews), "edge expressions do not match nodes")
+
+	var reviewsEdge, mysqlEdge *graph.Edge
+	for _, e := range productpage.Edges {
+		if e.Dest == reviews {
+			reviewsEdge = e
+		}
+	}
+	for _, e := range reviews.Edges {
+		if e.Metadata[graph.ProtocolKey] == "tcp" {
+			mysqlEdge = e
+		}
+	}
+	require.NotNil(t, reviewsEdge)
+	require.NotNil(t, mysqlEdge)
+
+	matchEdge := func(expression string, e *graph.Edge) bool {
+		ex, err := Parse(expression)
+		assert.NoError(err, expression)
+		return ex.MatchEdge(e)
+	}
+	assert.True(matchEdge("%httperr = 10", reviewsEdge))
+	assert.True(matchEdge("%httptraffic = 50", reviewsEdge))
+	assert.True(matchEdge("mtls AND rt > 200", reviewsEdge))
+	assert.False(matchEdge("!mtls", reviewsEdge))
+	assert.True(matchEdge("protocol = tcp AND tcp >= 1000", mysqlEdge))
+	assert.True(matchEdge("traffic", mysqlEdge))
+	assert.False(matchEdge("app = reviews", reviewsEdge), "node expressions do not match edges")
+}
+
+func TestHide(t *testing.T) {
+	assert := assert.New(t)
+	trafficMap := findTestTrafficMap(t)
+
+	// hiding reviews removes its edges, ratings and mysql are left without edges and are removed too
+	expression, err := Parse("app = reviews")
+	require.NoError(t, err)
+	Hide(trafficMap, expression)
+
+	assert.Len(trafficMap, 3)
+	assert.NotNil(findNode(trafficMap, "idle"), "idle nodes are kept")
+	productpage := findNode(trafficMap, "productpage")
+	require.NotNil(t, productpage)
+	require.Len(t, productpage.Edges, 1)
+	assert.Equal("details", productpage.Edges[0].Dest.App)
+
+	// hiding edges removes the nodes only connected by hidden edges
+	trafficMap = findTestTrafficMap(t)
+	expression, err = Parse("protocol = tcp")
+	require.NoError(t, err)
+	Hide(trafficMap, expression)
+
+	assert.Len(trafficMap, 5)
+	assert.Nil(findNode(trafficMap, "mysql"))
+	assert.Len(findNode(trafficMap, "reviews").Edges, 1)
+
+	// nil expressions hide nothing
+	trafficMap = findTestTrafficMap(t)
+	Hide(trafficMap, nil)
+	assert.Len(trafficMap, 6)
+}
+
+func TestFind(t *testing.T) {
+	assert := assert.New(t)
+	trafficMap := findTestTrafficMap(t)
+
+	expression, err := Parse("app = ratings OR %httperr > 5")
+	require.NoError(t, err)
+	Find(trafficMap, expression)
+
+	for _, n := range trafficMap {
+		assert.Equal(n.App == "ratings", n.Metadata[graph.IsFindHit] == true, n.App)
+		for _, e := range n.Edges {
+			assert.Equal(n.App == "productpage" && e.Dest.App == "reviews", e.Metadata[graph.IsFindHit] == true)
+		}
+	}
+}
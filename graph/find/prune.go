@@ -0,0 +1,66 @@
+package find
+
+import (
+	"github.com/kiali/kiali/graph"
+)
+
+// Hide removes the nodes and edges matching the expression from the TrafficMap, like the graph toolbar's
+// hide. The edges of removed nodes are also removed, as are the nodes left without any edge not matching the
+// expression. Idle nodes are only removed when matched, showing them is an explicit option.
+func Hide(trafficMap graph.TrafficMap, expression *Expression) {
+	if expression == nil {
+		return
+	}
+
+	hidden := map[string]bool{}
+	hasVisibleEdge := map[string]bool{}
+	for id, n := range trafficMap {
+		if expression.MatchNode(n) {
+			hidden[id] = true
+		}
+	}
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			if !hidden[e.Source.ID] && !hidden[e.Dest.ID] && !expression.MatchEdge(e) {
+				hasVisibleEdge[e.Source.ID] = true
+				hasVisibleEdge[e.Dest.ID] = true
+			}
+		}
+	}
+	for id, n := range trafficMap {
+		if isIdle, _ := n.Metadata[graph.IsIdle].(bool); !isIdle && !hasVisibleEdge[id] {
+			hidden[id] = true
+		}
+	}
+
+	for id := range hidden {
+		delete(trafficMap, id)
+	}
+	for _, n := range trafficMap {
+		edges := make([]*graph.Edge, 0, len(n.Edges))
+		for _, e := range n.Edges {
+			if !hidden[e.Dest.ID] && !expression.MatchEdge(e) {
+				edges = append(edges, e)
+			}
+		}
+		n.Edges = edges
+	}
+}
+
+// Find marks the nodes and edges matching the expression with graph.IsFindHit metadata
+func Find(trafficMap graph.TrafficMap, expression *Expression) {
+	if expression == nil {
+		return
+	}
+
+	for _, n := range trafficMap {
+		if expression.MatchNode(n) {
+			n.Metadata[graph.IsFindHit] = true
+		}
+		for _, e := range n.Edges {
+			if expression.MatchEdge(e) {
+				e.Metadata[graph.IsFindHit] = true
+			}
+		}
+	}
+}
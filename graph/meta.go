@@ -15,8 +15,13 @@ func NewMetadata() Metadata {
 const (
 	Aggregate             MetadataKey = "aggregate" // the prom attribute used for aggregation
 	AggregateValue        MetadataKey = "aggregateValue"
+	Anomaly               MetadataKey = "anomaly"         // *AnomalyInfo, set only when the anomaly appender is requested
+	BudgetRemaining       MetadataKey = "budgetRemaining" // float64 percentage, set only when the slo appender is requested
+	BurnRate              MetadataKey = "burnRate"        // *BurnRateInfo, set only when the slo appender is requested
+	Collapsed             MetadataKey = "collapsed"       // []string, IDs of the leaf nodes folded into a collapsed aggregate node
 	DestPrincipal         MetadataKey = "destPrincipal"
 	DestServices          MetadataKey = "destServices"
+	Diff                  MetadataKey = "diff" // *DiffInfo, set only for diff graphs
 	HealthData            MetadataKey = "healthData"
 	HealthDataApp         MetadataKey = "healthDataApp" // for storing app health on versioned app nodes
 	HasCB                 MetadataKey = "hasCB"
@@ -33,6 +38,7 @@ const (
 	IsDead                MetadataKey = "isDead"
 	IsEgressCluster       MetadataKey = "isEgressCluster"  // PassthroughCluster or BlackHoleCluster
 	IsEgressGateway       MetadataKey = "isEgressGateway"  // Identifies a node that is an Istio egress gateway
+	IsFindHit             MetadataKey = "isFindHit"        // set only when a find expression is requested
 	IsGatewayAPI          MetadataKey = "isGatewayAPI"     // Identifies a node that is a Gateway API gateway (ingress)
 	IsIngressGateway      MetadataKey = "isIngressGateway" // Identifies a node that is an Istio ingress gateway
 	IsIdle                MetadataKey = "isIdle"
@@ -47,6 +53,7 @@ const (
 	IsWaypoint            MetadataKey = "isWaypoint"
 	Labels                MetadataKey = "labels"
 	ProtocolKey           MetadataKey = "protocol"
+	Resilience            MetadataKey = "resilience" // *ResilienceInfo, set only when the resilience appender is requested
 	ResponseTime          MetadataKey = "responseTime"
 	SourcePrincipal       MetadataKey = "sourcePrincipal"
 	Throughput            MetadataKey = "throughput"
@@ -66,6 +73,43 @@ func (dsm DestServicesMetadata) Add(key string, service ServiceName) DestService
 	return dsm
 }
 
+// AnomalyInfo compares a node's inbound, or an edge's, request traffic to the request traffic at a historical
+// baseline. The ratios and delta are set only when there is baseline traffic.
+type AnomalyInfo struct {
+	BaselineErrorRate    float64 // error percentage at the baseline
+	BaselineRate         float64 // request rate at the baseline
+	BaselineResponseTime float64 // in millis, edges only
+	ErrorRateDelta       float64 // current error percentage minus baseline error percentage
+	HasBaseline          bool    // true if there was request traffic at the baseline
+	IsAnomaly            bool    // true if any of the comparisons exceeds its threshold
+	RateRatio            float64 // current rate / baseline rate
+	ResponseTime         float64 // in millis, edges only. Unlike ResponseTime metadata this is always the 95th percentile
+	ResponseTimeRatio    float64 // current response time / baseline response time
+}
+
+// BurnRateInfo holds the error budget burn rates of an edge's, or service node's, request traffic. The error budget is
+// the failure threshold of the HealthConfig tolerances matching the traffic, a burn rate of 1 consumes it exactly.
+type BurnRateInfo struct {
+	IsBurning bool               // true if, for any window pair, both windows burn faster than the pair's threshold
+	Rates     map[string]float64 // burn rate per window, keyed by the window duration (e.g. "5m")
+}
+
+// ResilienceInfo holds the Envoy upstream resilience stats (retries, circuit breaking and outlier detection) reported
+// by the source proxies for an edge's, or service node's, destination service. Rates are per second.
+type ResilienceInfo struct {
+	DestinationRules []ConfigReference // the DestinationRules configuring the destination service, if any
+	EjectionsActive  float64           // max number of destination hosts currently ejected by outlier detection
+	PendingOverflow  float64           // requests rejected because the connection pool's pending queue was full
+	Retries          float64           // request retries
+	RetryOverflow    float64           // retries not attempted because the retry circuit breaker was open
+}
+
+// ConfigReference identifies an Istio config object
+type ConfigReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
 type GatewaysMetadata map[string][]string
 type LabelsMetadata map[string]string
 type VirtualServicesMetadata map[string][]string
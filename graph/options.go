@@ -22,7 +22,10 @@ import (
 // The supported vendors
 const (
 	VendorCytoscape        string = "cytoscape"
+	VendorDOT              string = "dot"
+	VendorGraphML          string = "graphml"
 	VendorIstio            string = "istio"
+	VendorMermaid          string = "mermaid"
 	defaultConfigVendor    string = VendorCytoscape
 	defaultTelemetryVendor string = VendorIstio
 )
@@ -47,6 +50,13 @@ const (
 	defaultRateTcp            string = RateSent
 )
 
+// The supported layout hints
+const (
+	LayoutHintCollapse       string  = "collapse"    // fold low-traffic leaf nodes into synthetic aggregate nodes
+	LayoutHintCommunities    string  = "communities" // group nodes into communities using their edge rates
+	defaultCollapseThreshold float64 = 1.0           // percent
+)
+
 const (
 	graphKindNamespace string = "namespace"
 	graphKindNode      string = "node"
@@ -74,10 +84,30 @@ type CommonOptions struct {
 
 // ConfigOptions are those supplied to Config Vendors
 type ConfigOptions struct {
-	BoxBy string
+	BoxBy       string
+	Find        string // find expression, matching nodes and edges are marked
+	Hide        string // hide expression, matching nodes and edges are removed
+	LayoutHints LayoutHintsOptions
 	CommonOptions
 }
 
+// LayoutHintsOptions request layout hints computed server-side, by default none are computed
+type LayoutHintsOptions struct {
+	Collapse          bool    // fold low-traffic leaf nodes into synthetic aggregate nodes
+	CollapseThreshold float64 // percent of the parent's outbound traffic under which a leaf is considered low-traffic
+	Communities       bool    // assign each node to a community
+}
+
+// defaultDiffTolerance is the percent difference tolerated before a diff value is considered changed
+const defaultDiffTolerance float64 = 10.0
+
+// DiffOptions are those that apply only to diff graphs, they define the baseline time window
+type DiffOptions struct {
+	BaselineDuration  time.Duration
+	BaselineQueryTime int64   // unix time in seconds
+	Tolerance         float64 // percent difference tolerated before a value is considered changed
+}
+
 type RequestedAppenders struct {
 	All           bool
 	AppenderNames []string
@@ -121,8 +151,10 @@ type TelemetryOptions struct {
 // Options comprises all available options
 type Options struct {
 	ConfigVendor    string
+	Snapshot        string // the ID of a stored graph snapshot to serve, instead of generating the graph from telemetry
 	TelemetryVendor string
 	ConfigOptions
+	DiffOptions
 	TelemetryOptions
 }
 
@@ -144,19 +176,27 @@ func NewOptions(r *net_http.Request, namespacesService *business.NamespaceServic
 	var injectServiceNodes bool
 	var queryTime int64
 	appenders := RequestedAppenders{All: true}
+	baselineDurationString := params.Get("baselineDuration")
+	baselineQueryTimeString := params.Get("baselineQueryTime")
 	boxBy := params.Get("boxBy")
+	collapseThresholdString := params.Get("collapseThreshold")
 	// @TODO requires refactoring to use clusterNameFromQuery
 	cluster := params.Get("clusterName")
 	configVendor := params.Get("configVendor")
+	diffToleranceString := params.Get("diffTolerance")
 	durationString := params.Get("duration")
+	find := strings.TrimSpace(params.Get("find"))
 	graphType := params.Get("graphType")
+	hide := strings.TrimSpace(params.Get("hide"))
 	includeIdleEdgesString := params.Get("includeIdleEdges")
 	injectServiceNodesString := params.Get("injectServiceNodes")
+	layoutHintsString := params.Get("layoutHints")
 	namespaces := params.Get("namespaces") // csl of namespaces
 	queryTimeString := params.Get("queryTime")
 	rateGrpc := params.Get("rateGrpc")
 	rateHttp := params.Get("rateHttp")
 	rateTcp := params.Get("rateTcp")
+	snapshot := strings.TrimSpace(params.Get("snapshot"))
 	telemetryVendor := params.Get("telemetryVendor")
 
 	if _, ok := params["appenders"]; ok {
@@ -171,7 +211,7 @@ func NewOptions(r *net_http.Request, namespacesService *business.NamespaceServic
 	}
 	if configVendor == "" {
 		configVendor = defaultConfigVendor
-	} else if configVendor != VendorCytoscape {
+	} else if configVendor != VendorCytoscape && configVendor != VendorDOT && configVendor != VendorGraphML && configVendor != VendorMermaid {
 		BadRequest(fmt.Sprintf("Invalid configVendor [%s]", configVendor))
 	}
 	if durationString == "" {
@@ -213,6 +253,26 @@ func NewOptions(r *net_http.Request, namespacesService *business.NamespaceServic
 			}
 		}
 	}
+	layoutHints := LayoutHintsOptions{CollapseThreshold: defaultCollapseThreshold}
+	if layoutHintsString != "" {
+		for _, hint := range strings.Split(layoutHintsString, ",") {
+			switch strings.TrimSpace(hint) {
+			case LayoutHintCollapse:
+				layoutHints.Collapse = true
+			case LayoutHintCommunities:
+				layoutHints.Communities = true
+			default:
+				BadRequest(fmt.Sprintf("Invalid layoutHints [%s]", layoutHintsString))
+			}
+		}
+	}
+	if collapseThresholdString != "" {
+		var collapseThresholdErr error
+		layoutHints.CollapseThreshold, collapseThresholdErr = strconv.ParseFloat(collapseThresholdString, 64)
+		if collapseThresholdErr != nil || layoutHints.CollapseThreshold < 0 || layoutHints.CollapseThreshold > 100 {
+			BadRequest(fmt.Sprintf("Invalid collapseThreshold [%s]", collapseThresholdString))
+		}
+	}
 	if includeIdleEdgesString == "" {
 		includeIdleEdges = defaultIncludeIdleEdges
 	} else {
@@ -240,6 +300,34 @@ func NewOptions(r *net_http.Request, namespacesService *business.NamespaceServic
 			BadRequest(fmt.Sprintf("Invalid queryTime [%s]", queryTimeString))
 		}
 	}
+
+	// diff options default to the time window immediately preceding the requested time window
+	baselineDuration := duration
+	if baselineDurationString != "" {
+		var baselineDurationErr error
+		baselineDuration, baselineDurationErr = model.ParseDuration(baselineDurationString)
+		if baselineDurationErr != nil {
+			BadRequest(fmt.Sprintf("Invalid baselineDuration [%s]", baselineDurationString))
+		}
+	}
+	var baselineQueryTime int64
+	if baselineQueryTimeString == "" {
+		baselineQueryTime = queryTime - int64(time.Duration(duration).Seconds())
+	} else {
+		var baselineQueryTimeErr error
+		baselineQueryTime, baselineQueryTimeErr = strconv.ParseInt(baselineQueryTimeString, 10, 64)
+		if baselineQueryTimeErr != nil {
+			BadRequest(fmt.Sprintf("Invalid baselineQueryTime [%s]", baselineQueryTimeString))
+		}
+	}
+	diffTolerance := defaultDiffTolerance
+	if diffToleranceString != "" {
+		var diffToleranceErr error
+		diffTolerance, diffToleranceErr = strconv.ParseFloat(diffToleranceString, 64)
+		if diffToleranceErr != nil || diffTolerance < 0 {
+			BadRequest(fmt.Sprintf("Invalid diffTolerance [%s]", diffToleranceString))
+		}
+	}
 	if telemetryVendor == "" {
 		telemetryVendor = defaultTelemetryVendor
 	} else if telemetryVendor != VendorIstio {
@@ -248,7 +336,7 @@ func NewOptions(r *net_http.Request, namespacesService *business.NamespaceServic
 
 	// Process namespaces options:
 	namespaceMap := NewNamespaceInfoMap()
-	accessibleNamespaces := getAccessibleNamespaces(r.Context(), namespacesService)
+	accessibleNamespaces := GetAccessibleNamespaces(r.Context(), namespacesService)
 
 	// If path variable is set then it is the only relevant namespace (it's a node graph)
 	// Else if namespaces query param is set it specifies the relevant namespaces
@@ -257,12 +345,16 @@ func NewOptions(r *net_http.Request, namespacesService *business.NamespaceServic
 		namespaces = namespace
 	}
 
-	if namespaces == "" {
+	// a snapshot provides its own namespaces
+	if namespaces == "" && snapshot == "" {
 		BadRequest("At least one namespace must be specified via the namespaces query parameter.")
 	}
 
 	for _, namespaceName := range strings.Split(namespaces, ",") {
 		namespaceName = strings.TrimSpace(namespaceName)
+		if namespaceName == "" && snapshot != "" {
+			continue
+		}
 		var earliestCreationTimestamp *time.Time
 		for _, an := range accessibleNamespaces {
 			if namespaceName == an.Name {
@@ -340,9 +432,13 @@ func NewOptions(r *net_http.Request, namespacesService *business.NamespaceServic
 
 	options := Options{
 		ConfigVendor:    configVendor,
+		Snapshot:        snapshot,
 		TelemetryVendor: telemetryVendor,
 		ConfigOptions: ConfigOptions{
-			BoxBy: boxBy,
+			BoxBy:       boxBy,
+			Find:        find,
+			Hide:        hide,
+			LayoutHints: layoutHints,
 			CommonOptions: CommonOptions{
 				Duration:  time.Duration(duration),
 				GraphType: graphType,
@@ -350,6 +446,11 @@ func NewOptions(r *net_http.Request, namespacesService *business.NamespaceServic
 				QueryTime: queryTime,
 			},
 		},
+		DiffOptions: DiffOptions{
+			BaselineDuration:  time.Duration(baselineDuration),
+			BaselineQueryTime: baselineQueryTime,
+			Tolerance:         diffTolerance,
+		},
 		TelemetryOptions: TelemetryOptions{
 			AccessibleNamespaces: accessibleNamespaces,
 			Appenders:            appenders,
@@ -379,6 +480,30 @@ func NewOptions(r *net_http.Request, namespacesService *business.NamespaceServic
 	return options
 }
 
+// BaselineOptions returns a copy of the options, altered to generate the baseline graph of a diff
+// request. The query time and duration are replaced by those of the DiffOptions.
+func (o Options) BaselineOptions() Options {
+	baseline := o
+	baseline.ConfigOptions.Duration = o.BaselineDuration
+	baseline.ConfigOptions.QueryTime = o.BaselineQueryTime
+	baseline.TelemetryOptions.Duration = o.BaselineDuration
+	baseline.TelemetryOptions.QueryTime = o.BaselineQueryTime
+
+	baseline.Namespaces = NewNamespaceInfoMap()
+	for name, ns := range o.Namespaces {
+		var earliestCreationTimestamp time.Time
+		for _, an := range o.AccessibleNamespaces {
+			if name == an.Name && (earliestCreationTimestamp.IsZero() || earliestCreationTimestamp.After(an.CreationTimestamp)) {
+				earliestCreationTimestamp = an.CreationTimestamp
+			}
+		}
+		ns.Duration = getSafeNamespaceDuration(name, earliestCreationTimestamp, o.BaselineDuration, o.BaselineQueryTime)
+		baseline.Namespaces[name] = ns
+	}
+
+	return baseline
+}
+
 // GetGraphKind will return the kind of graph represented by the options.
 func (o *TelemetryOptions) GetGraphKind() string {
 	if o.NodeOptions.App != "" ||
@@ -390,11 +515,11 @@ func (o *TelemetryOptions) GetGraphKind() string {
 	return graphKindNamespace
 }
 
-// getAccessibleNamespaces returns a Set of all namespaces accessible to the user.
+// GetAccessibleNamespaces returns a Set of all namespaces accessible to the user.
 // The Set is implemented using the map convention. Each map entry is set to the
 // creation timestamp of the namespace, to be used to ensure valid time ranges for
 // queries against the namespace.
-func getAccessibleNamespaces(ctx context.Context, namespacesService *business.NamespaceService) AccessibleNamespaces {
+func GetAccessibleNamespaces(ctx context.Context, namespacesService *business.NamespaceService) AccessibleNamespaces {
 	namespaces, err := namespacesService.GetNamespaces(ctx)
 	CheckError(err)
 
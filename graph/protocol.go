@@ -246,6 +246,23 @@ func ResetOutgoingMetadata(sourceMetadata Metadata) {
 	delete(sourceMetadata, tcpOut)
 }
 
+// EdgeTotalRate returns the total rate of the edge for its protocol, and the unit of the rate. It is 0 and an empty
+// unit when the edge has no known protocol.
+func EdgeTotalRate(e *Edge) (float64, string) {
+	for _, p := range Protocols {
+		if p.Name != e.Metadata[ProtocolKey] {
+			continue
+		}
+		for _, r := range p.EdgeRates {
+			if r.IsTotal {
+				rate, _ := e.Metadata[r.Name].(float64)
+				return rate, p.Unit
+			}
+		}
+	}
+	return 0.0, ""
+}
+
 // AggregateNodeTraffic adds all <nodeMetadata> values (for all protocols) into aggregateNodeMetadata.
 func AggregateNodeTraffic(node, aggregateNode *Node) {
 	for _, protocol := range Protocols {
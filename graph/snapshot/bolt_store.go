@@ -0,0 +1,127 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltDataBucket = []byte("data")
+	boltInfoBucket = []byte("info")
+)
+
+// BoltStore stores the snapshots in a single BoltDB file. The Info of each snapshot is kept in its own
+// bucket, as JSON, so that listing the snapshots does not require decoding them.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore returns a BoltStore using the provided database file, which is created if needed. The
+// file is locked for the lifetime of the store.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(filepath.Clean(path), 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open graph snapshot database [%s]: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(boltDataBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(boltInfoBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close implements Store
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Delete implements Store
+func (s *BoltStore) Delete(id string) error {
+	if err := ValidateID(id); err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltInfoBucket).Get([]byte(id)) == nil {
+			return ErrNotFound
+		}
+		if err := tx.Bucket(boltDataBucket).Delete([]byte(id)); err != nil {
+			return err
+		}
+		return tx.Bucket(boltInfoBucket).Delete([]byte(id))
+	})
+}
+
+// Get implements Store
+func (s *BoltStore) Get(id string) (*Snapshot, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		// the value is only valid for the life of the transaction
+		if v := tx.Bucket(boltDataBucket).Get([]byte(id)); v != nil {
+			data = append([]byte{}, v...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrNotFound
+	}
+	return decode(bytes.NewReader(data))
+}
+
+// List implements Store
+func (s *BoltStore) List() ([]Info, error) {
+	infos := []Info{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltInfoBucket).ForEach(func(k, v []byte) error {
+			info := Info{}
+			if err := json.Unmarshal(v, &info); err != nil {
+				return fmt.Errorf("unable to decode graph snapshot info [%s]: %w", k, err)
+			}
+			infos = append(infos, info)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortInfos(infos)
+	return infos, nil
+}
+
+// Save implements Store
+func (s *BoltStore) Save(snapshot *Snapshot) error {
+	if err := ValidateID(snapshot.ID); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := encode(&buf, snapshot); err != nil {
+		return fmt.Errorf("unable to encode graph snapshot [%s]: %w", snapshot.ID, err)
+	}
+	info, err := json.Marshal(snapshot.Info)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(boltDataBucket).Put([]byte(snapshot.ID), buf.Bytes()); err != nil {
+			return err
+		}
+		return tx.Bucket(boltInfoBucket).Put([]byte(snapshot.ID), info)
+	})
+}
@@ -0,0 +1,121 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util"
+)
+
+const fileStoreSuffix = ".snapshot"
+
+// FileStore stores each snapshot in its own file, in a single directory
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore using the provided directory, which is created if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create graph snapshot directory [%s]: %w", dir, err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(id string) (string, error) {
+	if err := ValidateID(id); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, id+fileStoreSuffix), nil
+}
+
+// Close implements Store
+func (s *FileStore) Close() error {
+	return nil
+}
+
+// Delete implements Store
+func (s *FileStore) Delete(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Get implements Store
+func (s *FileStore) Get(id string) (*Snapshot, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	return decode(f)
+}
+
+// List implements Store. Unreadable files are skipped.
+func (s *FileStore) List() ([]Info, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []Info{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), fileStoreSuffix)
+		if !ok || entry.IsDir() || ValidateID(id) != nil {
+			continue
+		}
+		info, err := s.readInfo(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			log.Debugf("Skipping unreadable graph snapshot [%s]: %v", entry.Name(), err)
+			continue
+		}
+		infos = append(infos, *info)
+	}
+	sortInfos(infos)
+	return infos, nil
+}
+
+func (s *FileStore) readInfo(path string) (*Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return decodeInfo(f)
+}
+
+// Save implements Store. The file is written atomically, readers never see a partial snapshot.
+func (s *FileStore) Save(snapshot *Snapshot) error {
+	path, err := s.path(snapshot.ID)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, snapshot); err != nil {
+		return fmt.Errorf("unable to encode graph snapshot [%s]: %w", snapshot.ID, err)
+	}
+	return util.WriteFileAtomic(path, buf.Bytes())
+}
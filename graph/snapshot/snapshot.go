@@ -0,0 +1,320 @@
+// Package snapshot supports persisting generated graph.TrafficMaps, such that a graph can be served after
+// the telemetry used to generate it is no longer available (e.g. after Prometheus retention has rolled
+// off the raw series).
+//
+// A snapshot is stored as gzipped gob: the snapshot Info followed by a flattened TrafficMap, in which
+// edges reference their nodes by ID. Only metadata values of the types registered below are stored,
+// other values are server-side only and are dropped.
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// ErrNotFound is returned by a Store when the requested snapshot does not exist
+var ErrNotFound = errors.New("graph snapshot not found")
+
+// Namespace is a namespace of a snapshot, in a cluster where it was accessible when the snapshot was taken
+type Namespace struct {
+	Cluster string `json:"cluster"`
+	Name    string `json:"name"`
+}
+
+// Info describes a stored snapshot, without its TrafficMap
+type Info struct {
+	Created            time.Time   `json:"created"`
+	Duration           int64       `json:"duration"` // the query range of the graph, in seconds
+	EdgeCount          int         `json:"edgeCount"`
+	GraphType          string      `json:"graphType"`
+	ID                 string      `json:"id"`
+	InjectServiceNodes bool        `json:"injectServiceNodes"`
+	Namespaces         []Namespace `json:"namespaces"`
+	NodeCount          int         `json:"nodeCount"`
+	QueryTime          int64       `json:"queryTime"` // unix time in seconds
+}
+
+// Snapshot is a stored TrafficMap
+type Snapshot struct {
+	Info
+	TrafficMap graph.TrafficMap
+}
+
+// Store persists snapshots. Implementations must be safe for concurrent use.
+type Store interface {
+	// Close releases the resources held by the store
+	Close() error
+	// Delete removes a snapshot, returns ErrNotFound if it does not exist
+	Delete(id string) error
+	// Get returns a snapshot, or ErrNotFound
+	Get(id string) (*Snapshot, error)
+	// List returns the Info of all stored snapshots, most recent first
+	List() ([]Info, error)
+	// Save stores a snapshot, replacing any snapshot with the same ID
+	Save(snapshot *Snapshot) error
+}
+
+// NewSnapshot returns a snapshot of the provided TrafficMap, with a new ID. The counts are set from the TrafficMap.
+func NewSnapshot(trafficMap graph.TrafficMap, graphType string, injectServiceNodes bool, namespaces []Namespace, queryTime int64, duration time.Duration) *Snapshot {
+	created := time.Now().UTC()
+	sortedNamespaces := append([]Namespace{}, namespaces...)
+	sort.Slice(sortedNamespaces, func(i, j int) bool {
+		if sortedNamespaces[i].Name != sortedNamespaces[j].Name {
+			return sortedNamespaces[i].Name < sortedNamespaces[j].Name
+		}
+		return sortedNamespaces[i].Cluster < sortedNamespaces[j].Cluster
+	})
+
+	edgeCount := 0
+	for _, n := range trafficMap {
+		edgeCount += len(n.Edges)
+	}
+
+	return &Snapshot{
+		Info: Info{
+			Created:            created,
+			Duration:           int64(duration.Seconds()),
+			EdgeCount:          edgeCount,
+			GraphType:          graphType,
+			ID:                 util.NewTimeSortableID(created),
+			InjectServiceNodes: injectServiceNodes,
+			Namespaces:         sortedNamespaces,
+			NodeCount:          len(trafficMap),
+			QueryTime:          queryTime,
+		},
+		TrafficMap: trafficMap,
+	}
+}
+
+// ValidateID returns an error if the ID is not a snapshot ID. Stores must reject other IDs, they may be used in paths.
+func ValidateID(id string) error {
+	if !util.IsTimeSortableID(id) {
+		return fmt.Errorf("invalid graph snapshot id [%s]", id)
+	}
+	return nil
+}
+
+// metadataTypes are the types of the metadata values that are stored
+var metadataTypes = map[reflect.Type]bool{}
+
+func registerMetadataTypes(values ...interface{}) {
+	for _, v := range values {
+		gob.Register(v)
+		metadataTypes[reflect.TypeOf(v)] = true
+	}
+}
+
+func init() {
+	registerMetadataTypes(
+		true,
+		0.0,
+		"",
+		[]string{},
+		map[string]string{},
+		[]graph.WEInfo{},
+		&graph.AnomalyInfo{},
+		&graph.BurnRateInfo{},
+		&graph.DiffInfo{},
+		&graph.ResilienceInfo{},
+		&graph.SEInfo{},
+		graph.DestServicesMetadata{},
+		graph.GatewaysMetadata{},
+		graph.LabelsMetadata{},
+		graph.Responses{},
+		graph.VirtualServicesMetadata{},
+		&models.AppHealth{},
+		&models.ServiceHealth{},
+		&models.WorkloadHealth{},
+	)
+}
+
+type storedNode struct {
+	App       string
+	Cluster   string
+	Detached  bool // true if the node is only an edge destination, not a TrafficMap entry
+	ID        string
+	Metadata  map[graph.MetadataKey]interface{}
+	Namespace string
+	NodeType  string
+	Service   string
+	Version   string
+	Workload  string
+}
+
+type storedEdge struct {
+	Dest     string
+	Metadata map[graph.MetadataKey]interface{}
+	Source   string
+}
+
+type storedTrafficMap struct {
+	Edges []storedEdge
+	Nodes []storedNode
+}
+
+func storableMetadata(metadata graph.Metadata) map[graph.MetadataKey]interface{} {
+	result := make(map[graph.MetadataKey]interface{}, len(metadata))
+	for k, v := range metadata {
+		if v == nil || !metadataTypes[reflect.TypeOf(v)] {
+			continue
+		}
+		// gob can not encode nil pointers
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+func newNode(n *graph.Node, detached bool) storedNode {
+	return storedNode{
+		App:       n.App,
+		Cluster:   n.Cluster,
+		Detached:  detached,
+		ID:        n.ID,
+		Metadata:  storableMetadata(n.Metadata),
+		Namespace: n.Namespace,
+		NodeType:  n.NodeType,
+		Service:   n.Service,
+		Version:   n.Version,
+		Workload:  n.Workload,
+	}
+}
+
+func flatten(trafficMap graph.TrafficMap) storedTrafficMap {
+	ids := make([]string, 0, len(trafficMap))
+	for id := range trafficMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := storedTrafficMap{Nodes: make([]storedNode, 0, len(ids))}
+	detached := map[string]bool{}
+	for _, id := range ids {
+		n := trafficMap[id]
+		result.Nodes = append(result.Nodes, newNode(n, false))
+		for _, e := range n.Edges {
+			if _, ok := trafficMap[e.Dest.ID]; !ok && !detached[e.Dest.ID] {
+				detached[e.Dest.ID] = true
+				result.Nodes = append(result.Nodes, newNode(e.Dest, true))
+			}
+			result.Edges = append(result.Edges, storedEdge{
+				Dest:     e.Dest.ID,
+				Metadata: storableMetadata(e.Metadata),
+				Source:   n.ID,
+			})
+		}
+	}
+	return result
+}
+
+func (s storedTrafficMap) trafficMap() (graph.TrafficMap, error) {
+	nodes := make(map[string]*graph.Node, len(s.Nodes))
+	trafficMap := graph.NewTrafficMap()
+	for _, sn := range s.Nodes {
+		n := &graph.Node{
+			App:       sn.App,
+			Cluster:   sn.Cluster,
+			Edges:     []*graph.Edge{},
+			ID:        sn.ID,
+			Metadata:  graph.Metadata(sn.Metadata),
+			Namespace: sn.Namespace,
+			NodeType:  sn.NodeType,
+			Service:   sn.Service,
+			Version:   sn.Version,
+			Workload:  sn.Workload,
+		}
+		if n.Metadata == nil {
+			n.Metadata = graph.NewMetadata()
+		}
+		nodes[n.ID] = n
+		if !sn.Detached {
+			trafficMap[n.ID] = n
+		}
+	}
+	for _, se := range s.Edges {
+		source, sourceOK := nodes[se.Source]
+		dest, destOK := nodes[se.Dest]
+		if !sourceOK || !destOK {
+			return nil, fmt.Errorf("graph snapshot edge [%s -> %s] references an unknown node", se.Source, se.Dest)
+		}
+		e := &graph.Edge{Source: source, Dest: dest, Metadata: graph.Metadata(se.Metadata)}
+		if e.Metadata == nil {
+			e.Metadata = graph.NewMetadata()
+		}
+		source.Edges = append(source.Edges, e)
+	}
+	return trafficMap, nil
+}
+
+// encode writes the gzipped Info followed by the flattened TrafficMap
+func encode(w io.Writer, snapshot *Snapshot) error {
+	zw := gzip.NewWriter(w)
+	encoder := gob.NewEncoder(zw)
+	if err := encoder.Encode(snapshot.Info); err != nil {
+		return err
+	}
+	if err := encoder.Encode(flatten(snapshot.TrafficMap)); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// decodeInfo reads only the Info written by encode
+func decodeInfo(r io.Reader) (*Info, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	info := &Info{}
+	if err := gob.NewDecoder(zr).Decode(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// decode reads a snapshot written by encode
+func decode(r io.Reader) (*Snapshot, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	decoder := gob.NewDecoder(zr)
+	snapshot := &Snapshot{}
+	if err := decoder.Decode(&snapshot.Info); err != nil {
+		return nil, err
+	}
+	stored := storedTrafficMap{}
+	if err := decoder.Decode(&stored); err != nil {
+		return nil, err
+	}
+	if snapshot.TrafficMap, err = stored.trafficMap(); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// sortInfos sorts the most recent first
+func sortInfos(infos []Info) {
+	sort.Slice(infos, func(i, j int) bool {
+		if !infos[i].Created.Equal(infos[j].Created) {
+			return infos[i].Created.After(infos[j].Created)
+		}
+		return infos[i].ID > infos[j].ID
+	})
+}
@@ -0,0 +1,177 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func snapshotTestTrafficMap() graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+	productpage, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviews, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	// a destination that is not a TrafficMap entry
+	external, _ := graph.NewNode(config.DefaultClusterID, "unknown", "www.google.com", "unknown", "", "", "", graph.GraphTypeVersionedApp)
+	trafficMap[productpage.ID] = productpage
+	trafficMap[reviews.ID] = reviews
+
+	productpage.Metadata[graph.IsRoot] = true
+	productpage.Metadata[graph.HealthData] = &models.AppHealth{
+		WorkloadStatuses: []*models.WorkloadStatus{{Name: "productpage-v1", DesiredReplicas: 1, AvailableReplicas: 1}},
+		Requests:         models.NewEmptyRequestHealth(),
+	}
+	productpage.Metadata["serverSideOnly"] = make(chan bool) // not storable, dropped
+	reviews.Metadata[graph.DestServices] = graph.NewDestServicesMetadata().Add("reviews", graph.ServiceName{Cluster: config.DefaultClusterID, Namespace: "bookinfo", Name: "reviews"})
+	reviews.Metadata[graph.Labels] = graph.LabelsMetadata{"app": "reviews"}
+	reviews.Metadata[graph.IsServiceEntry] = (*graph.SEInfo)(nil) // nil pointers are dropped
+
+	e := productpage.AddEdge(reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 10.0, "200", "-", "", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 1.0, "500", "-", "", productpage.Metadata, reviews.Metadata, e.Metadata)
+	e.Metadata[graph.ResponseTime] = 25.0
+	e.Metadata[graph.Resilience] = &graph.ResilienceInfo{Retries: 0.5}
+
+	e = productpage.AddEdge(external)
+	e.Metadata[graph.ProtocolKey] = "tcp"
+
+	return trafficMap
+}
+
+func testStore(t *testing.T, store Store) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	queryTime := time.Now().Unix()
+	first := NewSnapshot(snapshotTestTrafficMap(), graph.GraphTypeVersionedApp, true, []Namespace{{Cluster: "east", Name: "travels"}, {Cluster: "west", Name: "bookinfo"}, {Cluster: "east", Name: "bookinfo"}}, queryTime, 10*time.Minute)
+	assert.Equal(2, first.NodeCount)
+	assert.Equal(2, first.EdgeCount)
+	assert.Equal([]Namespace{{Cluster: "east", Name: "bookinfo"}, {Cluster: "west", Name: "bookinfo"}, {Cluster: "east", Name: "travels"}}, first.Namespaces)
+	require.NoError(store.Save(first))
+
+	second := NewSnapshot(graph.NewTrafficMap(), graph.GraphTypeWorkload, false, []Namespace{{Cluster: "east", Name: "bookinfo"}}, queryTime, time.Hour)
+	second.Created = first.Created.Add(time.Second)
+	require.NoError(store.Save(second))
+
+	infos, err := store.List()
+	require.NoError(err)
+	require.Len(infos, 2)
+	assert.Equal(second.ID, infos[0].ID, "most recent first")
+	assert.Equal(first.Info.ID, infos[1].ID)
+	assert.Equal(int64(600), infos[1].Duration)
+	assert.True(infos[1].InjectServiceNodes)
+
+	s, err := store.Get(first.ID)
+	require.NoError(err)
+	assert.Equal(queryTime, s.QueryTime)
+	assert.Equal(graph.GraphTypeVersionedApp, s.GraphType)
+	require.Len(s.TrafficMap, 2)
+
+	productpageID, _, _ := graph.Id(config.DefaultClusterID, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	productpage := s.TrafficMap[productpageID]
+	require.NotNil(productpage)
+	assert.Equal("productpage-v1", productpage.Workload)
+	assert.Equal(true, productpage.Metadata[graph.IsRoot])
+	assert.Equal(11.0, productpage.Metadata["httpOut"])
+	health := productpage.Metadata[graph.HealthData].(*models.AppHealth)
+	assert.Equal("productpage-v1", health.WorkloadStatuses[0].Name)
+	assert.NotContains(productpage.Metadata, graph.MetadataKey("serverSideOnly"))
+
+	require.Len(productpage.Edges, 2)
+	for _, e := range productpage.Edges {
+		assert.Same(productpage, e.Source)
+		switch e.Metadata[graph.ProtocolKey] {
+		case "http":
+			assert.Same(s.TrafficMap[e.Dest.ID], e.Dest, "edges reference the TrafficMap nodes")
+			assert.Equal(11.0, e.Metadata["http"])
+			assert.Equal(25.0, e.Metadata[graph.ResponseTime])
+			assert.Equal(0.5, e.Metadata[graph.Resilience].(*graph.ResilienceInfo).Retries)
+			responses := e.Metadata["httpResponses"].(graph.Responses)
+			assert.Equal(10.0, responses["200"].Flags["-"])
+
+			reviews := e.Dest
+			assert.Equal("reviews", reviews.Metadata[graph.DestServices].(graph.DestServicesMetadata)["reviews"].Name)
+			assert.Equal("reviews", reviews.Metadata[graph.Labels].(graph.LabelsMetadata)["app"])
+			assert.NotContains(reviews.Metadata, graph.IsServiceEntry)
+		case "tcp":
+			assert.Equal("www.google.com", e.Dest.Service)
+			assert.NotContains(s.TrafficMap, e.Dest.ID, "detached nodes remain detached")
+			assert.NotNil(e.Dest.Metadata)
+		default:
+			assert.Failf("unexpected edge", "%v", e.Metadata[graph.ProtocolKey])
+		}
+	}
+
+	require.NoError(store.Delete(first.ID))
+	_, err = store.Get(first.ID)
+	assert.ErrorIs(err, ErrNotFound)
+	assert.ErrorIs(store.Delete(first.ID), ErrNotFound)
+	infos, err = store.List()
+	require.NoError(err)
+	assert.Len(infos, 1)
+
+	// invalid IDs are rejected, they may be used in paths
+	_, err = store.Get("../" + second.ID)
+	assert.Error(err)
+	assert.NotErrorIs(err, ErrNotFound)
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(filepath.Join(dir, "snapshots"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	testStore(t, store)
+
+	// leftovers are ignored when listing
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "snapshots", "notes.txt"), []byte("ignored"), 0o600))
+	infos, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, infos, 1)
+}
+
+func TestBoltStore(t *testing.T) {
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "snapshots.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	testStore(t, store)
+}
+
+func TestNewStore(t *testing.T) {
+	conf := config.NewConfig().GraphSnapshots
+	conf.Path = t.TempDir()
+	store, err := NewStore(conf)
+	require.NoError(t, err)
+	assert.IsType(t, &FileStore{}, store)
+
+	conf.Store = config.GraphSnapshotStoreBolt
+	conf.Path = filepath.Join(conf.Path, "snapshots.db")
+	store, err = NewStore(conf)
+	require.NoError(t, err)
+	assert.IsType(t, &BoltStore{}, store)
+	store.Close()
+
+	conf.Store = "s3"
+	_, err = NewStore(conf)
+	assert.Error(t, err)
+}
+
+func TestValidateID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(ValidateID(util.NewTimeSortableID(time.Now())))
+	assert.Error(ValidateID(""))
+	assert.Error(ValidateID("20240101T000000Z-0000000g"))
+	assert.Error(ValidateID("../20240101T000000Z-00000000"))
+}
@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kiali/kiali/config"
+)
+
+var (
+	defaultStore      Store
+	defaultStoreMutex sync.Mutex
+)
+
+// NewStore returns the Store configured by the provided settings
+func NewStore(conf config.GraphSnapshots) (Store, error) {
+	switch conf.Store {
+	case config.GraphSnapshotStoreBolt:
+		return NewBoltStore(conf.Path)
+	case config.GraphSnapshotStoreFilesystem:
+		return NewFileStore(conf.Path)
+	default:
+		return nil, fmt.Errorf("unsupported graph snapshot store [%s]", conf.Store)
+	}
+}
+
+// DefaultStore returns the configured Store shared by the server, creating it on first use. Stores may
+// lock their files, so a single instance must be used.
+func DefaultStore(conf *config.Config) (Store, error) {
+	defaultStoreMutex.Lock()
+	defer defaultStoreMutex.Unlock()
+
+	if !conf.GraphSnapshots.Enabled {
+		return nil, fmt.Errorf("graph snapshots are disabled")
+	}
+	if defaultStore == nil {
+		store, err := NewStore(conf.GraphSnapshots)
+		if err != nil {
+			return nil, err
+		}
+		defaultStore = store
+	}
+	return defaultStore, nil
+}
+
+// CloseDefaultStore closes the shared Store, if it was created
+func CloseDefaultStore() {
+	defaultStoreMutex.Lock()
+	defer defaultStoreMutex.Unlock()
+
+	if defaultStore != nil {
+		defaultStore.Close()
+		defaultStore = nil
+	}
+}
@@ -0,0 +1,323 @@
+package appender
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry/istio/util"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// AnomalyAppenderName uniquely identifies the appender: anomaly
+	AnomalyAppenderName = "anomaly"
+
+	// anomalyErrorRateDelta is the increase, in percentage points, of the error rate considered anomalous
+	anomalyErrorRateDelta = 5.0
+	// anomalyQuantile is the response time percentile compared to the baseline
+	anomalyQuantile = 0.95
+)
+
+// AnomalyAppender is responsible for comparing the current request traffic to the request traffic at a
+// baseline offset (by default, the same time one week earlier). The request rate, error rate and 95th
+// percentile response time are compared, and the result is added to request edges, and to nodes with
+// inbound request traffic, as graph.Anomaly metadata. A rate or response time is considered anomalous
+// when its ratio to the baseline exceeds the threshold (or, for rates, falls below 1/threshold). Because
+// it repeats the telemetry queries for the baseline it is only run when explicitly requested.
+// Name: anomaly
+type AnomalyAppender struct {
+	GraphType          string
+	InjectServiceNodes bool
+	Namespaces         graph.NamespaceInfoMap
+	Offset             time.Duration // how far back the baseline is
+	QueryTime          int64         // unix time in seconds
+	Rates              graph.RequestedRates
+	Threshold          float64 // the ratio to the baseline considered anomalous, must be > 1
+}
+
+// anomalyBaseline holds the baseline request traffic. It is populated using graph.AddToMetadata, like the
+// traffic map, so the baseline rates can be read in the same way as the current rates.
+type anomalyBaseline struct {
+	edges map[string]graph.Metadata // key: "sourceID destID protocol"
+	nodes map[string]graph.Metadata // key: nodeID
+	seen  map[model.Fingerprint]bool
+}
+
+// Name implements Appender
+func (a AnomalyAppender) Name() string {
+	return AnomalyAppenderName
+}
+
+// IsFinalizer implements Appender
+func (a AnomalyAppender) IsFinalizer() bool {
+	return false
+}
+
+// AppendGraph implements Appender
+func (a AnomalyAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	// Anomalies only apply to request traffic (not TCP or gRPC-message traffic)
+	if a.Rates.Grpc != graph.RateRequests && a.Rates.Http != graph.RateRequests {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, globalInfo.PromClient)
+}
+
+func (a AnomalyAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
+	baselineTime := a.QueryTime - int64(a.Offset.Seconds())
+	log.Tracef("Generating anomalies using baseline [%s]; namespace = %v", time.Unix(baselineTime, 0).Format(graph.TF), namespace)
+
+	baseline := a.baselineTraffic(namespace, baselineTime, client)
+
+	rt := ResponseTimeAppender{
+		GraphType:          a.GraphType,
+		InjectServiceNodes: a.InjectServiceNodes,
+		Namespaces:         a.Namespaces,
+		Quantile:           anomalyQuantile,
+		QueryTime:          a.QueryTime,
+		Rates:              a.Rates,
+	}
+	responseTimes := rt.responseTimes(namespace, client)
+	rt.QueryTime = baselineTime
+	baselineResponseTimes := rt.responseTimes(namespace, client)
+
+	for _, n := range trafficMap {
+		if rate, errRate := a.requestRates(n.Metadata, false); rate > 0 {
+			baselineRate, baselineErrRate := a.requestRates(baseline.nodes[n.ID], false)
+			n.Metadata[graph.Anomaly] = a.anomalyInfo(rate, errRate, baselineRate, baselineErrRate, 0.0, 0.0)
+		}
+		for _, e := range n.Edges {
+			protocol, _ := e.Metadata[graph.ProtocolKey].(string)
+			if !a.isRequestProtocol(protocol) {
+				continue
+			}
+			key := fmt.Sprintf("%s %s %s", e.Source.ID, e.Dest.ID, protocol)
+			rate, errRate := a.requestRates(e.Metadata, true)
+			baselineRate, baselineErrRate := a.requestRates(baseline.edges[key], true)
+			e.Metadata[graph.Anomaly] = a.anomalyInfo(rate, errRate, baselineRate, baselineErrRate, responseTimes[key], baselineResponseTimes[key])
+		}
+	}
+}
+
+func (a AnomalyAppender) anomalyInfo(rate, errRate, baselineRate, baselineErrRate, responseTime, baselineResponseTime float64) *graph.AnomalyInfo {
+	info := &graph.AnomalyInfo{
+		BaselineErrorRate:    baselineErrRate,
+		BaselineRate:         baselineRate,
+		BaselineResponseTime: baselineResponseTime,
+		HasBaseline:          baselineRate > 0,
+		ResponseTime:         responseTime,
+	}
+	if !info.HasBaseline {
+		return info
+	}
+
+	info.RateRatio = rate / baselineRate
+	info.ErrorRateDelta = errRate - baselineErrRate
+	if responseTime > 0 && baselineResponseTime > 0 {
+		info.ResponseTimeRatio = responseTime / baselineResponseTime
+	}
+	info.IsAnomaly = info.RateRatio >= a.Threshold ||
+		info.RateRatio <= 1/a.Threshold ||
+		info.ErrorRateDelta >= anomalyErrorRateDelta ||
+		info.ResponseTimeRatio >= a.Threshold
+
+	return info
+}
+
+// requestRates returns the total request rate and error percentage, for edge metadata, or for node
+// metadata the inbound request rate and error percentage.
+func (a AnomalyAppender) requestRates(md graph.Metadata, isEdge bool) (rate, errRate float64) {
+	errTotal := 0.0
+	for _, p := range graph.Protocols {
+		if !a.isRequestProtocol(p.Name) {
+			continue
+		}
+		rates := p.NodeRates
+		if isEdge {
+			rates = p.EdgeRates
+		}
+		for _, r := range rates {
+			val, _ := md[r.Name].(float64)
+			switch {
+			case r.IsTotal, r.IsIn:
+				rate += val
+			case r.IsErr:
+				errTotal += val
+			}
+		}
+	}
+	if rate > 0 {
+		errRate = errTotal / rate * 100.0
+	}
+	return rate, errRate
+}
+
+func (a AnomalyAppender) isRequestProtocol(protocol string) bool {
+	return (protocol == graph.HTTP.Name && a.Rates.Http == graph.RateRequests) || (protocol == graph.GRPC.Name && a.Rates.Grpc == graph.RateRequests)
+}
+
+// baselineTraffic queries the request traffic for the namespace at the baseline time
+func (a AnomalyAppender) baselineTraffic(namespace string, baselineTime int64, client *prometheus.Client) *anomalyBaseline {
+	baseline := &anomalyBaseline{
+		edges: make(map[string]graph.Metadata),
+		nodes: make(map[string]graph.Metadata),
+		seen:  make(map[model.Fingerprint]bool),
+	}
+	duration := a.Namespaces[namespace].Duration
+
+	// query prometheus for the baseline request traffic in two queries:
+	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags"
+
+	// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic
+	query := fmt.Sprintf(`sum(rate(%s{reporter=~"destination|waypoint",destination_service_namespace="%s"}[%vs])) by (%s) > 0`,
+		"istio_requests_total",
+		namespace,
+		int(duration.Seconds()), // range duration for the query
+		groupBy)
+	incomingVector := promQuery(query, time.Unix(baselineTime, 0), client.GetContext(), client.API(), a)
+	a.populateBaseline(baseline, &incomingVector)
+
+	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
+	query = fmt.Sprintf(`sum(rate(%s{reporter=~"source|waypoint",source_workload_namespace="%s"}[%vs])) by (%s) > 0`,
+		"istio_requests_total",
+		namespace,
+		int(duration.Seconds()), // range duration for the query
+		groupBy)
+	outgoingVector := promQuery(query, time.Unix(baselineTime, 0), client.GetContext(), client.API(), a)
+	a.populateBaseline(baseline, &outgoingVector)
+
+	return baseline
+}
+
+func (a AnomalyAppender) populateBaseline(baseline *anomalyBaseline, vector *model.Vector) {
+	for _, s := range *vector {
+		m := s.Metric
+
+		// Edges within the namespace are reported by both queries, the reporter is not a grouping
+		// label so the duplicate time series has the same labels, apply it only once.
+		if baseline.seen[m.Fingerprint()] {
+			continue
+		}
+		baseline.seen[m.Fingerprint()] = true
+
+		lSourceCluster, sourceClusterOk := m["source_cluster"]
+		lSourceWlNs, sourceWlNsOk := m["source_workload_namespace"]
+		lSourceWl, sourceWlOk := m["source_workload"]
+		lSourceApp, sourceAppOk := m["source_canonical_service"]
+		lSourceVer, sourceVerOk := m["source_canonical_revision"]
+		lDestCluster, destClusterOk := m["destination_cluster"]
+		lDestSvcNs, destSvcNsOk := m["destination_service_namespace"]
+		lDestSvc, destSvcOk := m["destination_service"]
+		lDestSvcName, destSvcNameOk := m["destination_service_name"]
+		lDestWlNs, destWlNsOk := m["destination_workload_namespace"]
+		lDestWl, destWlOk := m["destination_workload"]
+		lDestApp, destAppOk := m["destination_canonical_service"]
+		lDestVer, destVerOk := m["destination_canonical_revision"]
+		lProtocol, protocolOk := m["request_protocol"]
+		lCode, codeOk := m["response_code"]
+		lGrpc, grpcOk := m["grpc_response_status"]
+		lFlags, flagsOk := m["response_flags"]
+
+		if !sourceWlNsOk || !sourceWlOk || !sourceAppOk || !sourceVerOk || !destSvcNsOk || !destSvcNameOk || !destSvcOk || !destWlNsOk || !destWlOk || !destAppOk || !destVerOk || !protocolOk || !codeOk || !flagsOk {
+			log.Warningf("populateBaseline: Skipping %s, missing expected labels", m.String())
+			continue
+		}
+
+		sourceWlNs := string(lSourceWlNs)
+		sourceWl := string(lSourceWl)
+		sourceApp := string(lSourceApp)
+		sourceVer := string(lSourceVer)
+		destSvc := string(lDestSvc)
+		protocol := string(lProtocol)
+
+		if !a.isRequestProtocol(protocol) {
+			continue
+		}
+
+		// handle clusters
+		sourceCluster, destCluster := util.HandleClusters(lSourceCluster, sourceClusterOk, lDestCluster, destClusterOk)
+
+		if util.IsBadSourceTelemetry(sourceCluster, sourceClusterOk, sourceWlNs, sourceWl, sourceApp) {
+			continue
+		}
+
+		val := float64(s.Value)
+
+		// handle unusual destinations
+		destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, _ := util.HandleDestination(sourceCluster, sourceWlNs, sourceWl, destCluster, string(lDestSvcNs), string(lDestSvc), string(lDestSvcName), string(lDestWlNs), string(lDestWl), string(lDestApp), string(lDestVer))
+
+		if util.IsBadDestTelemetry(destCluster, destClusterOk, destSvcNs, destSvc, destSvcName, destWl) {
+			continue
+		}
+
+		// Should not happen but if NaN for any reason, Just skip it
+		if math.IsNaN(val) {
+			continue
+		}
+
+		code := util.HandleResponseCode(protocol, string(lCode), grpcOk, string(lGrpc))
+		flags := string(lFlags)
+
+		// don't inject a service node if any of:
+		// - destSvcName is not set
+		// - destSvcName is PassthroughCluster (see https://github.com/kiali/kiali/issues/4488)
+		// - dest node is already a service node
+		inject := false
+		if a.InjectServiceNodes && graph.IsOK(destSvcName) && destSvcName != graph.PassthroughCluster {
+			_, destNodeType, err := graph.Id(destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, a.GraphType)
+			if err != nil {
+				log.Warningf("Skipping (a) %s, %s", m.String(), err)
+				continue
+			}
+			inject = (graph.NodeTypeService != destNodeType)
+		}
+
+		if inject {
+			a.addBaseline(baseline, val, protocol, code, flags, destSvc, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, "", "", "", "")
+			a.addBaseline(baseline, val, protocol, code, flags, destSvc, destCluster, destSvcNs, destSvcName, "", "", "", destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+		} else {
+			a.addBaseline(baseline, val, protocol, code, flags, destSvc, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+		}
+	}
+}
+
+func (a AnomalyAppender) addBaseline(baseline *anomalyBaseline, val float64, protocol, code, flags, host, sourceCluster, sourceNs, sourceSvc, sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer string) {
+	sourceID, _, err := graph.Id(sourceCluster, sourceNs, sourceSvc, sourceNs, sourceWl, sourceApp, sourceVer, a.GraphType)
+	if err != nil {
+		log.Warningf("Skipping addBaseline (source), %s", err)
+		return
+	}
+	destID, _, err := graph.Id(destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer, a.GraphType)
+	if err != nil {
+		log.Warningf("Skipping addBaseline (dest), %s", err)
+		return
+	}
+
+	key := fmt.Sprintf("%s %s %s", sourceID, destID, protocol)
+	graph.AddToMetadata(protocol, val, code, flags, host, baseline.metadata(baseline.nodes, sourceID), baseline.metadata(baseline.nodes, destID), baseline.metadata(baseline.edges, key))
+}
+
+// metadata returns the metadata for the key, adding it if necessary
+func (b *anomalyBaseline) metadata(m map[string]graph.Metadata, key string) graph.Metadata {
+	md, ok := m[key]
+	if !ok {
+		md = graph.NewMetadata()
+		m[key] = md
+	}
+	return md
+}
@@ -0,0 +1,225 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func mockQueryAt(api *prometheustest.PromAPIMock, query string, queryTime int64, ret *model.Vector) {
+	api.On(
+		"Query",
+		mock.Anything,
+		query,
+		time.Unix(queryTime, 0),
+	).Return(*ret, nil)
+}
+
+func anomalyTestMetric(destWorkload, destVersion, code string) model.Metric {
+	return model.Metric{
+		"source_cluster":                 config.DefaultClusterID,
+		"source_workload_namespace":      "bookinfo",
+		"source_workload":                "productpage-v1",
+		"source_canonical_service":       "productpage",
+		"source_canonical_revision":      "v1",
+		"destination_cluster":            config.DefaultClusterID,
+		"destination_service_namespace":  "bookinfo",
+		"destination_service":            "reviews.bookinfo.svc.cluster.local",
+		"destination_service_name":       "reviews",
+		"destination_workload_namespace": "bookinfo",
+		"destination_workload":           model.LabelValue(destWorkload),
+		"destination_canonical_service":  "reviews",
+		"destination_canonical_revision": model.LabelValue(destVersion),
+		"request_protocol":               "http",
+		"response_code":                  model.LabelValue(code),
+		"grpc_response_status":           "",
+		"response_flags":                 "-",
+	}
+}
+
+func anomalyTestTraffic() graph.TrafficMap {
+	productpage, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviewsV1, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	reviewsV2, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "reviews", "bookinfo", "reviews-v2", "reviews", "v2", graph.GraphTypeVersionedApp)
+	details, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "details", "bookinfo", "details-v1", "details", "v1", graph.GraphTypeVersionedApp)
+	trafficMap := graph.NewTrafficMap()
+
+	trafficMap[productpage.ID] = productpage
+	trafficMap[reviewsV1.ID] = reviewsV1
+	trafficMap[reviewsV2.ID] = reviewsV2
+	trafficMap[details.ID] = details
+
+	e := productpage.AddEdge(reviewsV1)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 9.0, "200", "-", "", productpage.Metadata, reviewsV1.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 1.0, "500", "-", "", productpage.Metadata, reviewsV1.Metadata, e.Metadata)
+
+	e = productpage.AddEdge(reviewsV2)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 5.0, "200", "-", "", productpage.Metadata, reviewsV2.Metadata, e.Metadata)
+
+	e = productpage.AddEdge(details)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 5.0, "200", "-", "", productpage.Metadata, details.Metadata, e.Metadata)
+
+	return trafficMap
+}
+
+func TestAnomaly(t *testing.T) {
+	assert := assert.New(t)
+
+	queryTime := time.Now().Unix()
+	baselineTime := queryTime - int64((7 * 24 * time.Hour).Seconds())
+
+	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags"
+	q0 := `round(sum(rate(istio_requests_total{reporter=~"destination|waypoint",destination_service_namespace="bookinfo"}[60s])) by (` + groupBy + `) > 0,0.001)`
+	v0 := model.Vector{
+		&model.Sample{
+			Metric: anomalyTestMetric("reviews-v1", "v1", "200"),
+			Value:  4.0},
+		&model.Sample{
+			Metric: anomalyTestMetric("reviews-v2", "v2", "200"),
+			Value:  5.0},
+	}
+	q1 := `round(sum(rate(istio_requests_total{reporter=~"source|waypoint",source_workload_namespace="bookinfo"}[60s])) by (` + groupBy + `) > 0,0.001)`
+	v1 := model.Vector{
+		&model.Sample{
+			Metric: anomalyTestMetric("reviews-v2", "v2", "200"),
+			Value:  5.0}, // same time series reported by incoming (q0), must be ignored
+	}
+
+	rtGroupBy := "le,source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol"
+	q2 := `round(histogram_quantile(0.95, sum(rate(istio_request_duration_milliseconds_bucket{reporter=~"destination|waypoint",destination_service_namespace="bookinfo"}[60s])) by (` + rtGroupBy + `)) > 0,0.001)`
+	q3 := `round(histogram_quantile(0.95, sum(rate(istio_request_duration_milliseconds_bucket{reporter=~"source|waypoint",source_workload_namespace="bookinfo"}[60s])) by (` + rtGroupBy + `)) > 0,0.001)`
+	rtMetric := func(destWorkload, destVersion string) model.Metric {
+		m := anomalyTestMetric(destWorkload, destVersion, "")
+		delete(m, "response_code")
+		delete(m, "grpc_response_status")
+		delete(m, "response_flags")
+		return m
+	}
+	v2 := model.Vector{
+		&model.Sample{
+			Metric: rtMetric("reviews-v1", "v1"),
+			Value:  100.0},
+		&model.Sample{
+			Metric: rtMetric("reviews-v2", "v2"),
+			Value:  300.0},
+	}
+	v2Baseline := model.Vector{
+		&model.Sample{
+			Metric: rtMetric("reviews-v1", "v1"),
+			Value:  90.0},
+		&model.Sample{
+			Metric: rtMetric("reviews-v2", "v2"),
+			Value:  100.0},
+	}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mockQueryAt(api, q0, baselineTime, &v0)
+	mockQueryAt(api, q1, baselineTime, &v1)
+	mockQueryAt(api, q2, queryTime, &v2)
+	mockQueryAt(api, q2, baselineTime, &v2Baseline)
+	mockQueryAt(api, q3, queryTime, &model.Vector{})
+	mockQueryAt(api, q3, baselineTime, &model.Vector{})
+
+	trafficMap := anomalyTestTraffic()
+
+	duration, _ := time.ParseDuration("60s")
+	appender := AnomalyAppender{
+		GraphType:          graph.GraphTypeVersionedApp,
+		InjectServiceNodes: false,
+		Namespaces: map[string]graph.NamespaceInfo{
+			"bookinfo": {
+				Name:     "bookinfo",
+				Duration: duration,
+			},
+		},
+		Offset:    7 * 24 * time.Hour,
+		QueryTime: queryTime,
+		Rates: graph.RequestedRates{
+			Grpc: graph.RateRequests,
+			Http: graph.RateRequests,
+			Tcp:  graph.RateTotal,
+		},
+		Threshold: 2.0,
+	}
+
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	productpageID, _, _ := graph.Id(config.DefaultClusterID, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	productpage := trafficMap[productpageID]
+	assert.Equal(3, len(productpage.Edges))
+	// productpage has no inbound traffic
+	_, ok := productpage.Metadata[graph.Anomaly]
+	assert.False(ok)
+
+	for _, e := range productpage.Edges {
+		anomaly := e.Metadata[graph.Anomaly].(*graph.AnomalyInfo)
+		switch e.Dest.Workload {
+		case "reviews-v1":
+			// rate went from 4 to 10, and errors from 0% to 10%
+			assert.True(anomaly.HasBaseline)
+			assert.True(anomaly.IsAnomaly)
+			assert.Equal(4.0, anomaly.BaselineRate)
+			assert.Equal(2.5, anomaly.RateRatio)
+			assert.Equal(0.0, anomaly.BaselineErrorRate)
+			assert.Equal(10.0, anomaly.ErrorRateDelta)
+			assert.Equal(100.0, anomaly.ResponseTime)
+			assert.Equal(90.0, anomaly.BaselineResponseTime)
+
+			nodeAnomaly := e.Dest.Metadata[graph.Anomaly].(*graph.AnomalyInfo)
+			assert.True(nodeAnomaly.IsAnomaly)
+			assert.Equal(4.0, nodeAnomaly.BaselineRate)
+			assert.Equal(0.0, nodeAnomaly.BaselineResponseTime)
+		case "reviews-v2":
+			// rate unchanged (the duplicate time series is not counted twice), but response time tripled
+			assert.True(anomaly.HasBaseline)
+			assert.True(anomaly.IsAnomaly)
+			assert.Equal(1.0, anomaly.RateRatio)
+			assert.Equal(0.0, anomaly.ErrorRateDelta)
+			assert.Equal(3.0, anomaly.ResponseTimeRatio)
+
+			nodeAnomaly := e.Dest.Metadata[graph.Anomaly].(*graph.AnomalyInfo)
+			assert.False(nodeAnomaly.IsAnomaly)
+			assert.Equal(1.0, nodeAnomaly.RateRatio)
+		case "details-v1":
+			// new traffic
+			assert.False(anomaly.HasBaseline)
+			assert.False(anomaly.IsAnomaly)
+			assert.Equal(0.0, anomaly.RateRatio)
+		default:
+			assert.Failf("unexpected edge", "%s", e.Dest.Workload)
+		}
+	}
+}
+
+func TestAnomalyNotDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.TelemetryOptions{}
+	o.Appenders.All = true
+	appenders, _ := ParseAppenders(o)
+	for _, a := range appenders {
+		assert.NotEqual(AnomalyAppenderName, a.Name())
+	}
+
+	o.Appenders.All = false
+	o.Appenders.AppenderNames = []string{AnomalyAppenderName}
+	appenders, _ = ParseAppenders(o)
+	assert.Equal(1, len(appenders))
+	anomaly := appenders[0].(AnomalyAppender)
+	assert.Equal(7*24*time.Hour, anomaly.Offset)
+	assert.Equal(2.0, anomaly.Threshold)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
@@ -12,10 +15,12 @@ import (
 )
 
 const (
-	defaultAggregate      = "request_operation"
-	defaultQuantile       = 0.95
-	defaultThroughputType = "response"
-	defaultWaypoints      = true
+	defaultAggregate        = "request_operation"
+	defaultAnomalyOffset    = "1w"
+	defaultAnomalyThreshold = 2.0
+	defaultQuantile         = 0.95
+	defaultThroughputType   = "response"
+	defaultWaypoints        = true
 )
 
 // ParseAppenders determines which appenders should run for this graphing request
@@ -30,6 +35,8 @@ func ParseAppenders(o graph.TelemetryOptions) (appenders []graph.Appender, final
 			// namespace appenders
 			case AggregateNodeAppenderName:
 				requestedAppenders[AggregateNodeAppenderName] = true
+			case AnomalyAppenderName:
+				requestedAppenders[AnomalyAppenderName] = true
 			case DeadNodeAppenderName:
 				requestedAppenders[DeadNodeAppenderName] = true
 			case IdleNodeAppenderName:
@@ -38,12 +45,16 @@ func ParseAppenders(o graph.TelemetryOptions) (appenders []graph.Appender, final
 				requestedAppenders[IstioAppenderName] = true
 			case MeshCheckAppenderName, SidecarsCheckAppenderName:
 				requestedAppenders[MeshCheckAppenderName] = true
+			case ResilienceAppenderName:
+				requestedAppenders[ResilienceAppenderName] = true
 			case ResponseTimeAppenderName:
 				requestedAppenders[ResponseTimeAppenderName] = true
 			case SecurityPolicyAppenderName:
 				requestedAppenders[SecurityPolicyAppenderName] = true
 			case ServiceEntryAppenderName:
 				requestedAppenders[ServiceEntryAppenderName] = true
+			case SLOAppenderName:
+				requestedAppenders[SLOAppenderName] = true
 			case ThroughputAppenderName:
 				requestedAppenders[ThroughputAppenderName] = true
 			case WorkloadEntryAppenderName:
@@ -150,6 +161,59 @@ func ParseAppenders(o graph.TelemetryOptions) (appenders []graph.Appender, final
 		}
 		appenders = append(appenders, a)
 	}
+	// the anomaly appender repeats the telemetry queries for the baseline, so unlike the other appenders
+	// it is not run by default, it must be explicitly requested
+	if _, ok := requestedAppenders[AnomalyAppenderName]; ok {
+		offsetString := o.Params.Get("anomalyOffset")
+		if offsetString == "" {
+			offsetString = defaultAnomalyOffset
+		}
+		offset, err := model.ParseDuration(offsetString)
+		if err != nil || offset <= 0 {
+			graph.BadRequest(fmt.Sprintf("Invalid anomalyOffset, expecting a positive duration. [%s]", offsetString))
+		}
+		threshold := defaultAnomalyThreshold
+		if thresholdString := o.Params.Get("anomalyThreshold"); thresholdString != "" {
+			var thresholdErr error
+			threshold, thresholdErr = strconv.ParseFloat(thresholdString, 64)
+			if thresholdErr != nil || threshold <= 1.0 {
+				graph.BadRequest(fmt.Sprintf("Invalid anomalyThreshold, expecting a number > 1. [%s]", thresholdString))
+			}
+		}
+		a := AnomalyAppender{
+			GraphType:          o.GraphType,
+			InjectServiceNodes: o.InjectServiceNodes,
+			Namespaces:         o.Namespaces,
+			Offset:             time.Duration(offset),
+			QueryTime:          o.QueryTime,
+			Rates:              o.Rates,
+			Threshold:          threshold,
+		}
+		appenders = append(appenders, a)
+	}
+	// the resilience appender requires Envoy stats that are not exposed by default, so like the anomaly appender
+	// it must be explicitly requested
+	if _, ok := requestedAppenders[ResilienceAppenderName]; ok {
+		a := ResilienceAppender{
+			AccessibleNamespaces: o.AccessibleNamespaces,
+			Namespaces:           o.Namespaces,
+			QueryTime:            o.QueryTime,
+		}
+		appenders = append(appenders, a)
+	}
+	// the slo appender queries the request traffic for each burn rate window, so like the anomaly appender it
+	// must be explicitly requested
+	if _, ok := requestedAppenders[SLOAppenderName]; ok {
+		a := SLOAppender{
+			GraphType:          o.GraphType,
+			HealthConfig:       config.Get().HealthConfig,
+			InjectServiceNodes: o.InjectServiceNodes,
+			Namespaces:         o.Namespaces,
+			QueryTime:          o.QueryTime,
+			Rates:              o.Rates,
+		}
+		appenders = append(appenders, a)
+	}
 	if _, ok := requestedAppenders[AggregateNodeAppenderName]; ok || o.Appenders.All {
 		aggregate := o.NodeOptions.Aggregate
 		if aggregate == "" {
@@ -0,0 +1,310 @@
+package appender
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/business/references"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// ResilienceAppenderName uniquely identifies the appender: resilience
+	ResilienceAppenderName = "resilience"
+)
+
+// ResilienceAppender is responsible for adding the Envoy upstream resilience stats to request edges and to
+// service nodes, as graph.Resilience metadata:
+// - retries:          envoy_cluster_upstream_rq_retry
+// - retry overflow:   envoy_cluster_upstream_rq_retry_overflow
+// - pending overflow: envoy_cluster_upstream_rq_pending_overflow (i.e. the circuit breaker tripped)
+// - ejections:        envoy_cluster_outlier_detection_ejections_active
+// The stats are reported by the source proxies per destination service (Envoy cluster), so an edge gets the stats
+// of its source for the destination service(s) it reaches, and a service node gets the stats of all its sources.
+// The DestinationRules configuring the destination service are added to the metadata as well.
+// Note that Istio does not expose these stats by default, they must be enabled using the proxyStatsMatcher
+// (e.g. inclusionPrefixes: ["cluster.outbound"]). Because it requires that configuration, and runs its own
+// queries, it is only run when explicitly requested.
+// Name: resilience
+type ResilienceAppender struct {
+	AccessibleNamespaces graph.AccessibleNamespaces
+	Namespaces           graph.NamespaceInfoMap
+	QueryTime            int64 // unix time in seconds
+}
+
+// resilienceKey identifies the stats reported by the proxies of a source app (version), for a destination host
+type resilienceKey struct {
+	app       string
+	host      string
+	namespace string
+	version   string
+}
+
+// resilienceStats maps resilienceKey to the reported stats
+type resilienceStats map[resilienceKey]*graph.ResilienceInfo
+
+// destinationRuleMap maps a service, keyed by "cluster namespace name", to the DestinationRules configuring it
+type destinationRuleMap map[string][]graph.ConfigReference
+
+// Name implements Appender
+func (a ResilienceAppender) Name() string {
+	return ResilienceAppenderName
+}
+
+// IsFinalizer implements Appender
+func (a ResilienceAppender) IsFinalizer() bool {
+	return false
+}
+
+// AppendGraph implements Appender
+func (a ResilienceAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	stats := a.resilienceStats(namespaceInfo.Namespace, globalInfo.PromClient)
+	if len(stats) == 0 {
+		return
+	}
+
+	a.applyResilience(trafficMap, stats, a.destinationRules(trafficMap, globalInfo, namespaceInfo))
+}
+
+// resilienceStats queries the stats reported by proxies in the namespace (outgoing) and the stats reported for
+// destination hosts in the namespace (incoming). A series matching both is only returned once by the "or".
+func (a ResilienceAppender) resilienceStats(namespace string, client *prometheus.Client) resilienceStats {
+	log.Tracef("Generating resilience stats; namespace = %v", namespace)
+
+	appLabel, versionLabel := resilienceLabelNames()
+	groupBy := []string{"namespace", "cluster_name"}
+	for _, l := range []string{appLabel, versionLabel} {
+		if l != "" {
+			groupBy = append(groupBy, l)
+		}
+	}
+	outgoing := fmt.Sprintf(`namespace="%s",cluster_name=~"outbound[|].+"`, namespace)
+	incoming := fmt.Sprintf(`cluster_name=~"outbound[|][^|]*[|][^|]*[|][^.|]+[.]%s[.].+"`, namespace)
+	duration := int(a.Namespaces[namespace].Duration.Seconds())
+
+	stats := resilienceStats{}
+	queries := []struct {
+		query string
+		add   func(ri *graph.ResilienceInfo, val float64)
+	}{
+		{
+			query: fmt.Sprintf(`sum(rate(envoy_cluster_upstream_rq_retry{%s}[%vs]) or rate(envoy_cluster_upstream_rq_retry{%s}[%vs])) by (%s) > 0`,
+				outgoing, duration, incoming, duration, strings.Join(groupBy, ",")),
+			add: func(ri *graph.ResilienceInfo, val float64) { ri.Retries += val },
+		},
+		{
+			query: fmt.Sprintf(`sum(rate(envoy_cluster_upstream_rq_retry_overflow{%s}[%vs]) or rate(envoy_cluster_upstream_rq_retry_overflow{%s}[%vs])) by (%s) > 0`,
+				outgoing, duration, incoming, duration, strings.Join(groupBy, ",")),
+			add: func(ri *graph.ResilienceInfo, val float64) { ri.RetryOverflow += val },
+		},
+		{
+			query: fmt.Sprintf(`sum(rate(envoy_cluster_upstream_rq_pending_overflow{%s}[%vs]) or rate(envoy_cluster_upstream_rq_pending_overflow{%s}[%vs])) by (%s) > 0`,
+				outgoing, duration, incoming, duration, strings.Join(groupBy, ",")),
+			add: func(ri *graph.ResilienceInfo, val float64) { ri.PendingOverflow += val },
+		},
+		{
+			query: fmt.Sprintf(`max(envoy_cluster_outlier_detection_ejections_active{%s} or envoy_cluster_outlier_detection_ejections_active{%s}) by (%s) > 0`,
+				outgoing, incoming, strings.Join(groupBy, ",")),
+			add: func(ri *graph.ResilienceInfo, val float64) { ri.EjectionsActive = max(ri.EjectionsActive, val) },
+		},
+	}
+
+	for _, q := range queries {
+		vector := promQuery(q.query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+		for _, s := range vector {
+			m := s.Metric
+			host, ok := parseOutboundClusterHost(string(m["cluster_name"]))
+			if !ok {
+				log.Tracef("Skipping %s, unexpected cluster name", m.String())
+				continue
+			}
+			key := resilienceKey{
+				host:      host,
+				namespace: string(m["namespace"]),
+			}
+			if appLabel != "" {
+				key.app = string(m[model.LabelName(appLabel)])
+			}
+			if versionLabel != "" {
+				key.version = string(m[model.LabelName(versionLabel)])
+			}
+			ri, ok := stats[key]
+			if !ok {
+				ri = &graph.ResilienceInfo{}
+				stats[key] = ri
+			}
+			q.add(ri, float64(s.Value))
+		}
+	}
+
+	return stats
+}
+
+// destinationRules returns the DestinationRules configuring the services of the clusters with traffic, using the
+// DestinationRule references
+func (a ResilienceAppender) destinationRules(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) destinationRuleMap {
+	result := destinationRuleMap{}
+
+	for _, cluster := range getTrafficClusters(trafficMap, namespaceInfo.Namespace, globalInfo) {
+		destinationRuleList, err := globalInfo.Business.IstioConfig.GetIstioConfigList(context.TODO(), cluster, business.IstioConfigCriteria{
+			IncludeDestinationRules: true,
+		})
+		graph.CheckError(err)
+		if len(destinationRuleList.DestinationRules) == 0 {
+			continue
+		}
+
+		namespaces := models.Namespaces{}
+		for _, ns := range a.AccessibleNamespaces {
+			if ns.Cluster == cluster {
+				namespaces = append(namespaces, models.Namespace{Cluster: ns.Cluster, Name: ns.Name})
+			}
+		}
+
+		drReferences := references.DestinationRuleReferences{
+			Namespace:        namespaceInfo.Namespace,
+			Namespaces:       namespaces,
+			DestinationRules: destinationRuleList.DestinationRules,
+			RegistryServices: globalInfo.Business.RegistryStatus.GetRegistryServices(business.RegistryCriteria{AllNamespaces: true, Cluster: cluster}),
+		}
+		result.addReferences(cluster, drReferences.References())
+	}
+
+	return result
+}
+
+// addReferences adds the service references of the DestinationRules
+func (drm destinationRuleMap) addReferences(cluster string, referencesMap models.IstioReferencesMap) {
+	for key, refs := range referencesMap {
+		for _, svc := range refs.ServiceReferences {
+			svcKey := fmt.Sprintf("%s %s %s", cluster, svc.Namespace, svc.Name)
+			drm[svcKey] = append(drm[svcKey], graph.ConfigReference{Name: key.Name, Namespace: key.Namespace})
+		}
+	}
+	for _, drs := range drm {
+		sort.Slice(drs, func(i, j int) bool {
+			if drs[i].Namespace != drs[j].Namespace {
+				return drs[i].Namespace < drs[j].Namespace
+			}
+			return drs[i].Name < drs[j].Name
+		})
+	}
+}
+
+func (a ResilienceAppender) applyResilience(trafficMap graph.TrafficMap, stats resilienceStats, destinationRules destinationRuleMap) {
+	for _, n := range trafficMap {
+		if n.NodeType == graph.NodeTypeService {
+			svc := graph.ServiceName{Cluster: n.Cluster, Namespace: n.Namespace, Name: n.Service}
+			if ri := stats.collect(nil, []graph.ServiceName{svc}, destinationRules); ri != nil {
+				n.Metadata[graph.Resilience] = ri
+			}
+		}
+
+		for _, e := range n.Edges {
+			if ri := stats.collect(n, destServices(e.Dest), destinationRules); ri != nil {
+				e.Metadata[graph.Resilience] = ri
+			}
+		}
+	}
+}
+
+// collect returns the stats reported by the source proxies, or by all proxies if source is nil, for the services.
+// It returns nil if no stats are reported.
+func (rs resilienceStats) collect(source *graph.Node, services []graph.ServiceName, destinationRules destinationRuleMap) *graph.ResilienceInfo {
+	var result *graph.ResilienceInfo
+
+	for _, svc := range services {
+		for key, ri := range rs {
+			if (source != nil && !key.isSource(source)) || !kubernetes.FilterByHost(key.host, svc.Namespace, svc.Name, svc.Namespace) {
+				continue
+			}
+			if result == nil {
+				result = &graph.ResilienceInfo{}
+			}
+			result.EjectionsActive = max(result.EjectionsActive, ri.EjectionsActive)
+			result.PendingOverflow += ri.PendingOverflow
+			result.Retries += ri.Retries
+			result.RetryOverflow += ri.RetryOverflow
+		}
+	}
+
+	if result != nil {
+		for _, svc := range services {
+			result.DestinationRules = append(result.DestinationRules, destinationRules[fmt.Sprintf("%s %s %s", svc.Cluster, svc.Namespace, svc.Name)]...)
+		}
+	}
+
+	return result
+}
+
+// isSource returns true if the stats are reported by the node's proxies. The proxies are identified by the app and
+// version labels, when the labels are reported and set on the node.
+func (key resilienceKey) isSource(n *graph.Node) bool {
+	if key.namespace != n.Namespace || !graph.IsOK(n.App) {
+		return false
+	}
+	if key.app != "" && key.app != n.App {
+		return false
+	}
+	return key.version == "" || !graph.IsOK(n.Version) || key.version == n.Version
+}
+
+// destServices returns the services reached by traffic to the node
+func destServices(n *graph.Node) []graph.ServiceName {
+	if n.NodeType == graph.NodeTypeService {
+		return []graph.ServiceName{{Cluster: n.Cluster, Namespace: n.Namespace, Name: n.Service}}
+	}
+
+	services := []graph.ServiceName{}
+	if destSvcs, ok := n.Metadata[graph.DestServices].(graph.DestServicesMetadata); ok {
+		for _, svc := range destSvcs {
+			services = append(services, svc)
+		}
+	}
+	return services
+}
+
+// parseOutboundClusterHost returns the host of an Envoy outbound cluster name: outbound|<port>|<subset>|<host>
+func parseOutboundClusterHost(clusterName string) (string, bool) {
+	parts := strings.Split(clusterName, "|")
+	if len(parts) != 4 || parts[0] != "outbound" || parts[3] == "" {
+		return "", false
+	}
+	return parts[3], true
+}
+
+// resilienceLabelNames returns the Prometheus label names for the configured app and version pod labels. Pod labels
+// are typically added to the Envoy stats using a labelmap, which replaces invalid characters with underscores.
+func resilienceLabelNames() (appLabel, versionLabel string) {
+	sanitize := func(name string) string {
+		return strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+				return r
+			}
+			return '_'
+		}, name)
+	}
+	labels := config.Get().IstioLabels
+	return sanitize(labels.AppLabelName), sanitize(labels.VersionLabelName)
+}
@@ -0,0 +1,166 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/models"
+)
+
+func resilienceTestMetric(namespace, app, version, clusterName string) model.Metric {
+	return model.Metric{
+		"namespace":    model.LabelValue(namespace),
+		"app":          model.LabelValue(app),
+		"version":      model.LabelValue(version),
+		"cluster_name": model.LabelValue(clusterName),
+	}
+}
+
+func TestResilience(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	outgoing := `namespace="bookinfo",cluster_name=~"outbound[|].+"`
+	incoming := `cluster_name=~"outbound[|][^|]*[|][^|]*[|][^.|]+[.]bookinfo[.].+"`
+	groupBy := "namespace,cluster_name,app,version"
+	rateQuery := func(metric string) string {
+		return `round(sum(rate(` + metric + `{` + outgoing + `}[60s]) or rate(` + metric + `{` + incoming + `}[60s])) by (` + groupBy + `) > 0,0.001)`
+	}
+
+	v0 := model.Vector{
+		&model.Sample{
+			Metric: resilienceTestMetric("bookinfo", "productpage", "v1", "outbound|9080|v1|reviews.bookinfo.svc.cluster.local"),
+			Value:  1.5},
+		&model.Sample{
+			Metric: resilienceTestMetric("bookinfo", "productpage", "v1", "outbound|9080|v2|reviews.bookinfo.svc.cluster.local"),
+			Value:  0.5},
+		&model.Sample{
+			Metric: resilienceTestMetric("other", "client", "v1", "outbound|9080||reviews.bookinfo.svc.cluster.local"),
+			Value:  3.0},
+		&model.Sample{
+			Metric: resilienceTestMetric("bookinfo", "productpage", "v1", "inbound|9080||"),
+			Value:  10.0}, // not an outbound cluster, must be ignored
+	}
+	v1 := model.Vector{
+		&model.Sample{
+			Metric: resilienceTestMetric("bookinfo", "productpage", "v1", "outbound|9080||details.bookinfo.svc.cluster.local"),
+			Value:  0.2},
+	}
+	q3 := `round(max(envoy_cluster_outlier_detection_ejections_active{` + outgoing + `} or envoy_cluster_outlier_detection_ejections_active{` + incoming + `}) by (` + groupBy + `) > 0,0.001)`
+	v3 := model.Vector{
+		&model.Sample{
+			Metric: resilienceTestMetric("bookinfo", "productpage", "v1", "outbound|9080|v1|reviews.bookinfo.svc.cluster.local"),
+			Value:  1.0},
+		&model.Sample{
+			Metric: resilienceTestMetric("other", "client", "v1", "outbound|9080||reviews.bookinfo.svc.cluster.local"),
+			Value:  2.0},
+	}
+
+	client, api, err := setupMocked()
+	require.NoError(err)
+	mockQuery(api, rateQuery("envoy_cluster_upstream_rq_retry"), &v0)
+	mockQuery(api, rateQuery("envoy_cluster_upstream_rq_retry_overflow"), &model.Vector{})
+	mockQuery(api, rateQuery("envoy_cluster_upstream_rq_pending_overflow"), &v1)
+	mockQuery(api, q3, &v3)
+
+	productpage, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviewsService, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "reviews", "bookinfo", "", "", "", graph.GraphTypeVersionedApp)
+	reviewsV1, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	reviewsV1.Metadata[graph.DestServices] = graph.NewDestServicesMetadata().Add("reviews", graph.ServiceName{Cluster: config.DefaultClusterID, Namespace: "bookinfo", Name: "reviews"})
+	details, _ := graph.NewNode(config.DefaultClusterID, "bookinfo", "details", "bookinfo", "details-v1", "details", "v1", graph.GraphTypeVersionedApp)
+	details.Metadata[graph.DestServices] = graph.NewDestServicesMetadata().Add("details", graph.ServiceName{Cluster: config.DefaultClusterID, Namespace: "bookinfo", Name: "details"})
+	trafficMap := graph.NewTrafficMap()
+	trafficMap[productpage.ID] = productpage
+	trafficMap[reviewsService.ID] = reviewsService
+	trafficMap[reviewsV1.ID] = reviewsV1
+	trafficMap[details.ID] = details
+	toReviews := productpage.AddEdge(reviewsService)
+	toReviewsV1 := reviewsService.AddEdge(reviewsV1)
+	toDetails := productpage.AddEdge(details)
+
+	duration, _ := time.ParseDuration("60s")
+	appender := ResilienceAppender{
+		Namespaces: graph.NamespaceInfoMap{
+			"bookinfo": {
+				Name:     "bookinfo",
+				Duration: duration,
+			},
+		},
+		QueryTime: time.Now().Unix(),
+	}
+
+	stats := appender.resilienceStats("bookinfo", client)
+	assert.Len(stats, 3)
+
+	destinationRules := destinationRuleMap{}
+	destinationRules.addReferences(config.DefaultClusterID, models.IstioReferencesMap{
+		models.IstioReferenceKey{Namespace: "bookinfo", Name: "reviews", ObjectType: "destinationrule"}: &models.IstioReferences{
+			ServiceReferences: []models.ServiceReference{{Name: "reviews", Namespace: "bookinfo"}},
+		},
+	})
+	appender.applyResilience(trafficMap, stats, destinationRules)
+
+	// productpage's own stats, summed over the subsets
+	ri := toReviews.Metadata[graph.Resilience].(*graph.ResilienceInfo)
+	assert.Equal(2.0, ri.Retries)
+	assert.Equal(1.0, ri.EjectionsActive)
+	assert.Equal(0.0, ri.PendingOverflow)
+	assert.Equal([]graph.ConfigReference{{Name: "reviews", Namespace: "bookinfo"}}, ri.DestinationRules)
+
+	ri = toDetails.Metadata[graph.Resilience].(*graph.ResilienceInfo)
+	assert.Equal(0.2, ri.PendingOverflow)
+	assert.Equal(0.0, ri.Retries)
+	assert.Empty(ri.DestinationRules)
+
+	// the service node gets the stats of all its sources
+	ri = reviewsService.Metadata[graph.Resilience].(*graph.ResilienceInfo)
+	assert.Equal(5.0, ri.Retries)
+	assert.Equal(2.0, ri.EjectionsActive)
+	assert.Len(ri.DestinationRules, 1)
+
+	// there are no proxy stats for service node sources
+	_, ok := toReviewsV1.Metadata[graph.Resilience]
+	assert.False(ok)
+	_, ok = details.Metadata[graph.Resilience]
+	assert.False(ok)
+}
+
+func TestParseOutboundClusterHost(t *testing.T) {
+	assert := assert.New(t)
+
+	host, ok := parseOutboundClusterHost("outbound|9080|v1|reviews.bookinfo.svc.cluster.local")
+	assert.True(ok)
+	assert.Equal("reviews.bookinfo.svc.cluster.local", host)
+
+	host, ok = parseOutboundClusterHost("outbound|443||www.google.com")
+	assert.True(ok)
+	assert.Equal("www.google.com", host)
+
+	for _, name := range []string{"inbound|9080||", "PassthroughCluster", "outbound|9080|v1|"} {
+		_, ok = parseOutboundClusterHost(name)
+		assert.False(ok, name)
+	}
+}
+
+func TestResilienceNotDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.TelemetryOptions{}
+	o.Appenders.All = true
+	appenders, _ := ParseAppenders(o)
+	for _, a := range appenders {
+		assert.NotEqual(ResilienceAppenderName, a.Name())
+	}
+
+	o.Appenders.All = false
+	o.Appenders.AppenderNames = []string{ResilienceAppenderName}
+	appenders, _ = ParseAppenders(o)
+	assert.Equal(1, len(appenders))
+	assert.Equal(ResilienceAppenderName, appenders[0].Name())
+}
@@ -66,7 +66,11 @@ func (a ResponseTimeAppender) AppendGraph(trafficMap graph.TrafficMap, globalInf
 }
 
 func (a ResponseTimeAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
-	// create map to quickly look up responseTime
+	applyResponseTime(trafficMap, a.responseTimes(namespace, client))
+}
+
+// responseTimes returns a map to quickly look up responseTime, keyed by "sourceID destID protocol"
+func (a ResponseTimeAppender) responseTimes(namespace string, client *prometheus.Client) map[string]float64 {
 	responseTimeMap := make(map[string]float64)
 	duration := a.Namespaces[namespace].Duration
 
@@ -134,7 +138,7 @@ func (a ResponseTimeAppender) appendGraph(trafficMap graph.TrafficMap, namespace
 		a.populateResponseTimeMap(responseTimeMap, &outgoingVector)
 	}
 
-	applyResponseTime(trafficMap, responseTimeMap)
+	return responseTimeMap
 }
 
 func applyResponseTime(trafficMap graph.TrafficMap, responseTimeMap map[string]float64) {
@@ -0,0 +1,371 @@
+package appender
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// SLOAppenderName uniquely identifies the appender: slo
+const SLOAppenderName = "slo"
+
+// SLOWindow is a multi-window burn rate condition. The error budget is burning when the burn rate exceeds the
+// threshold over both the long and the short window, the short window lets the condition reset soon after the
+// errors stop.
+type SLOWindow struct {
+	Long      time.Duration
+	Short     time.Duration
+	Threshold float64
+}
+
+// SLOWindows are the usual fast burn (2% of a 30 day budget in 1h) and slow burn (5% in 6h) conditions
+var SLOWindows = []SLOWindow{
+	{Long: time.Hour, Short: 5 * time.Minute, Threshold: 14.4},
+	{Long: 6 * time.Hour, Short: 30 * time.Minute, Threshold: 6.0},
+}
+
+// SLOWindowKeys returns the keys of graph.BurnRateInfo.Rates (e.g. "5m"), shortest window first
+func SLOWindowKeys() []string {
+	keys := []string{}
+	for _, w := range sloWindowDurations() {
+		keys = append(keys, sloWindowKey(w))
+	}
+	return keys
+}
+
+// SLOAppender is responsible for evaluating the error budget burn rates of request edges, and of service nodes'
+// inbound request traffic, against the HealthConfig tolerances. The failure threshold of a tolerance is the error
+// budget, as a percentage of the requests, for the matching response codes. The burn rate over a window is the
+// error percentage divided by the budget, so a burn rate of 1 consumes the budget exactly. When several tolerances
+// match, the highest burn rate applies. The burn rates are added as graph.BurnRate metadata, and the budget
+// remaining over the longest window as graph.BudgetRemaining metadata. Because it queries the request traffic
+// for each window it is only run when explicitly requested.
+// Name: slo
+type SLOAppender struct {
+	GraphType          string
+	HealthConfig       config.HealthConfig
+	InjectServiceNodes bool
+	Namespaces         graph.NamespaceInfoMap
+	QueryTime          int64 // unix time in seconds
+	Rates              graph.RequestedRates
+}
+
+// sloRequests holds request rates keyed by protocol and then by response code
+type sloRequests map[string]map[string]float64
+
+// sloTraffic holds the request traffic over one window
+type sloTraffic struct {
+	edges map[string]sloRequests // key: "sourceID destID protocol"
+	nodes map[string]sloRequests // key: nodeID, the node's inbound requests
+}
+
+// sloTolerance is a config.Tolerance with compiled expressions. As with health, the expressions are unanchored
+// and an empty expression matches everything.
+type sloTolerance struct {
+	code      *regexp.Regexp
+	direction *regexp.Regexp
+	failure   float64
+	protocol  *regexp.Regexp
+}
+
+// sloRate is a config.Rate with compiled expressions
+type sloRate struct {
+	kind       *regexp.Regexp
+	name       *regexp.Regexp
+	namespace  *regexp.Regexp
+	tolerances []sloTolerance
+}
+
+// Name implements Appender
+func (a SLOAppender) Name() string {
+	return SLOAppenderName
+}
+
+// IsFinalizer implements Appender
+func (a SLOAppender) IsFinalizer() bool {
+	return false
+}
+
+// AppendGraph implements Appender
+func (a SLOAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	// Error budgets only apply to request traffic (not TCP or gRPC-message traffic)
+	if a.Rates.Grpc != graph.RateRequests && a.Rates.Http != graph.RateRequests {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, globalInfo.PromClient)
+}
+
+func (a SLOAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
+	rates := newSLORates(a.HealthConfig)
+	if len(rates) == 0 {
+		log.Debugf("SLO appender: no health config rates, skipping namespace [%s]", namespace)
+		return
+	}
+
+	windows := sloWindowDurations()
+	traffic := make(map[time.Duration]*sloTraffic, len(windows))
+	for _, w := range windows {
+		traffic[w] = a.windowTraffic(namespace, w, client)
+	}
+
+	for _, n := range trafficMap {
+		// the inbound traffic of a service node is only complete when processing its namespace
+		if n.NodeType == graph.NodeTypeService && n.Namespace == namespace {
+			requests := make(map[time.Duration]sloRequests, len(windows))
+			for w, t := range traffic {
+				requests[w] = t.nodes[n.ID]
+			}
+			setBurnRate(n.Metadata, requests, findSLORate(rates, n).matching("inbound"))
+		}
+		for _, e := range n.Edges {
+			protocol, _ := e.Metadata[graph.ProtocolKey].(string)
+			if !a.isRequestProtocol(protocol) || (e.Source.Namespace != namespace && e.Dest.Namespace != namespace) {
+				continue
+			}
+			key := fmt.Sprintf("%s %s %s", e.Source.ID, e.Dest.ID, protocol)
+			requests := make(map[time.Duration]sloRequests, len(windows))
+			for w, t := range traffic {
+				requests[w] = t.edges[key]
+			}
+			// an edge is evaluated against both the destination's inbound and the source's outbound tolerances
+			tolerances := append(findSLORate(rates, e.Dest).matching("inbound"), findSLORate(rates, e.Source).matching("outbound")...)
+			setBurnRate(e.Metadata, requests, tolerances)
+		}
+	}
+}
+
+func (a SLOAppender) isRequestProtocol(protocol string) bool {
+	return (protocol == graph.HTTP.Name && a.Rates.Http == graph.RateRequests) || (protocol == graph.GRPC.Name && a.Rates.Grpc == graph.RateRequests)
+}
+
+// windowTraffic queries the request traffic for the namespace over the window, ending at the query time
+func (a SLOAppender) windowTraffic(namespace string, window time.Duration, client *prometheus.Client) *sloTraffic {
+	namespaceInfo := a.Namespaces[namespace]
+	namespaceInfo.Duration = window
+
+	// the anomaly appender's baseline provides the request traffic, by response code, for any time and duration
+	anomaly := AnomalyAppender{
+		GraphType:          a.GraphType,
+		InjectServiceNodes: a.InjectServiceNodes,
+		Namespaces:         graph.NamespaceInfoMap{namespace: namespaceInfo},
+		Rates:              a.Rates,
+	}
+	baseline := anomaly.baselineTraffic(namespace, a.QueryTime, client)
+
+	traffic := &sloTraffic{
+		edges: make(map[string]sloRequests, len(baseline.edges)),
+		nodes: make(map[string]sloRequests),
+	}
+	for key, md := range baseline.edges {
+		// node IDs do not contain spaces
+		fields := strings.Split(key, " ")
+		destID, protocol := fields[1], fields[2]
+		codes := responseRates(md, protocol)
+		traffic.edges[key] = sloRequests{protocol: codes}
+
+		inbound, ok := traffic.nodes[destID]
+		if !ok {
+			inbound = sloRequests{}
+			traffic.nodes[destID] = inbound
+		}
+		if _, ok := inbound[protocol]; !ok {
+			inbound[protocol] = make(map[string]float64, len(codes))
+		}
+		for code, rate := range codes {
+			inbound[protocol][code] += rate
+		}
+	}
+	return traffic
+}
+
+// responseRates returns the edge request rates keyed by response code
+func responseRates(md graph.Metadata, protocol string) map[string]float64 {
+	codes := map[string]float64{}
+	for _, p := range graph.Protocols {
+		if p.Name != protocol {
+			continue
+		}
+		responses, _ := md[p.EdgeResponses].(graph.Responses)
+		for code, detail := range responses {
+			// every request has response flags, "-" if none
+			for _, rate := range detail.Flags {
+				codes[code] += rate
+			}
+		}
+	}
+	return codes
+}
+
+// setBurnRate adds the burn rates, and budget remaining, for the per-window requests. Nothing is added if there
+// are no matching tolerances or no requests.
+func setBurnRate(md graph.Metadata, requests map[time.Duration]sloRequests, tolerances []sloTolerance) {
+	if len(tolerances) == 0 {
+		return
+	}
+
+	windows := sloWindowDurations()
+	info := &graph.BurnRateInfo{Rates: make(map[string]float64, len(windows))}
+	hasRequests := false
+	for _, w := range windows {
+		rate, ok := burnRate(requests[w], tolerances)
+		hasRequests = hasRequests || ok
+		info.Rates[sloWindowKey(w)] = rate
+	}
+	if !hasRequests {
+		return
+	}
+
+	for _, w := range SLOWindows {
+		if info.Rates[sloWindowKey(w.Long)] > w.Threshold && info.Rates[sloWindowKey(w.Short)] > w.Threshold {
+			info.IsBurning = true
+		}
+	}
+	md[graph.BurnRate] = info
+	md[graph.BudgetRemaining] = math.Max(0.0, 100.0*(1.0-info.Rates[sloWindowKey(windows[len(windows)-1])]))
+}
+
+// burnRate returns the highest burn rate of the tolerances, and false if there are no requests
+func burnRate(requests sloRequests, tolerances []sloTolerance) (float64, bool) {
+	burn := 0.0
+	hasRequests := false
+	for protocol, codes := range requests {
+		total := 0.0
+		for _, rate := range codes {
+			total += rate
+		}
+		if total == 0 {
+			continue
+		}
+		hasRequests = true
+
+		for _, t := range tolerances {
+			if !t.protocol.MatchString(protocol) {
+				continue
+			}
+			errors := 0.0
+			for code, rate := range codes {
+				if t.code.MatchString(code) {
+					errors += rate
+				}
+			}
+			burn = math.Max(burn, errors/total*100.0/t.failure)
+		}
+	}
+	return burn, hasRequests
+}
+
+func sloWindowKey(window time.Duration) string {
+	return model.Duration(window).String()
+}
+
+// sloWindowDurations returns the distinct window durations, shortest first
+func sloWindowDurations() []time.Duration {
+	seen := map[time.Duration]bool{}
+	windows := []time.Duration{}
+	for _, w := range SLOWindows {
+		for _, d := range []time.Duration{w.Short, w.Long} {
+			if !seen[d] {
+				seen[d] = true
+				windows = append(windows, d)
+			}
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	return windows
+}
+
+// newSLORates compiles the health config rates. Invalid expressions are logged and the rate, or tolerance,
+// is ignored. Tolerances without a failure threshold define no error budget and are also ignored.
+func newSLORates(conf config.HealthConfig) []sloRate {
+	rates := []sloRate{}
+	for _, r := range conf.Rate {
+		rate := sloRate{}
+		var err error
+		if rate.namespace, err = regexp.Compile(r.Namespace); err != nil {
+			log.Warningf("SLO appender: ignoring health config rate, invalid namespace expression [%s]: %v", r.Namespace, err)
+			continue
+		}
+		if rate.kind, err = regexp.Compile(r.Kind); err != nil {
+			log.Warningf("SLO appender: ignoring health config rate, invalid kind expression [%s]: %v", r.Kind, err)
+			continue
+		}
+		if rate.name, err = regexp.Compile(r.Name); err != nil {
+			log.Warningf("SLO appender: ignoring health config rate, invalid name expression [%s]: %v", r.Name, err)
+			continue
+		}
+		for _, t := range r.Tolerance {
+			if t.Failure <= 0 {
+				continue
+			}
+			tolerance := sloTolerance{failure: float64(t.Failure)}
+			// as with health, an X in the code matches any digit (e.g. 5XX)
+			code := strings.NewReplacer("X", `\d`, "x", `\d`).Replace(t.Code)
+			if tolerance.code, err = regexp.Compile(code); err != nil {
+				log.Warningf("SLO appender: ignoring health config tolerance, invalid code expression [%s]: %v", t.Code, err)
+				continue
+			}
+			if tolerance.protocol, err = regexp.Compile(t.Protocol); err != nil {
+				log.Warningf("SLO appender: ignoring health config tolerance, invalid protocol expression [%s]: %v", t.Protocol, err)
+				continue
+			}
+			if tolerance.direction, err = regexp.Compile(t.Direction); err != nil {
+				log.Warningf("SLO appender: ignoring health config tolerance, invalid direction expression [%s]: %v", t.Direction, err)
+				continue
+			}
+			rate.tolerances = append(rate.tolerances, tolerance)
+		}
+		rates = append(rates, rate)
+	}
+	return rates
+}
+
+// findSLORate returns the first rate matching the node, like health, or the last rate if none match
+func findSLORate(rates []sloRate, n *graph.Node) sloRate {
+	var kind, name string
+	switch n.NodeType {
+	case graph.NodeTypeApp:
+		kind, name = "app", n.App
+	case graph.NodeTypeService:
+		kind, name = "service", n.Service
+	case graph.NodeTypeWorkload:
+		kind, name = "workload", n.Workload
+	}
+	for _, r := range rates {
+		if r.namespace.MatchString(n.Namespace) && r.kind.MatchString(kind) && r.name.MatchString(name) {
+			return r
+		}
+	}
+	return rates[len(rates)-1]
+}
+
+// matching returns the rate's tolerances that apply to the traffic direction
+func (r sloRate) matching(direction string) []sloTolerance {
+	tolerances := []sloTolerance{}
+	for _, t := range r.tolerances {
+		if t.direction.MatchString(direction) {
+			tolerances = append(tolerances, t)
+		}
+	}
+	return tolerances
+}
@@ -0,0 +1,189 @@
+package appender
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+)
+
+func sloTestMetric(app, code string) model.Metric {
+	return model.Metric{
+		"source_cluster":                 config.DefaultClusterID,
+		"source_workload_namespace":      "bookinfo",
+		"source_workload":                "productpage-v1",
+		"source_canonical_service":       "productpage",
+		"source_canonical_revision":      "v1",
+		"destination_cluster":            config.DefaultClusterID,
+		"destination_service_namespace":  "bookinfo",
+		"destination_service":            model.LabelValue(app + ".bookinfo.svc.cluster.local"),
+		"destination_service_name":       model.LabelValue(app),
+		"destination_workload_namespace": "bookinfo",
+		"destination_workload":           model.LabelValue(app + "-v1"),
+		"destination_canonical_service":  model.LabelValue(app),
+		"destination_canonical_revision": "v1",
+		"request_protocol":               "http",
+		"response_code":                  model.LabelValue(code),
+		"grpc_response_status":           "",
+		"response_flags":                 "-",
+	}
+}
+
+// productpage -> reviews (service) -> reviews-v1, and productpage -> details (service) -> details-v1
+func sloTestTraffic(t *testing.T) graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+	newNode := func(service, workload, app, version string) *graph.Node {
+		n, err := graph.NewNode(config.DefaultClusterID, "bookinfo", service, "bookinfo", workload, app, version, graph.GraphTypeVersionedApp)
+		if workload == "" {
+			n, err = graph.NewNode(config.DefaultClusterID, "bookinfo", service, "", "", "", "", graph.GraphTypeVersionedApp)
+		}
+		require.NoError(t, err)
+		trafficMap[n.ID] = n
+		return n
+	}
+	addEdge := func(source, dest *graph.Node, protocol string) {
+		e := source.AddEdge(dest)
+		e.Metadata[graph.ProtocolKey] = protocol
+	}
+
+	productpage := newNode("", "productpage-v1", "productpage", "v1")
+	reviews := newNode("reviews", "", "", "")
+	reviewsV1 := newNode("reviews", "reviews-v1", "reviews", "v1")
+	details := newNode("details", "", "", "")
+	detailsV1 := newNode("details", "details-v1", "details", "v1")
+	mysql := newNode("mysql", "", "", "")
+
+	addEdge(productpage, reviews, "http")
+	addEdge(reviews, reviewsV1, "http")
+	addEdge(productpage, details, "http")
+	addEdge(details, detailsV1, "http")
+	addEdge(productpage, mysql, "tcp")
+
+	return trafficMap
+}
+
+func TestSLO(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	client, api, err := setupMocked()
+	require.NoError(err)
+
+	queryTime := time.Now().Unix()
+	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags"
+
+	// reviews errors burst in the last hour: 20% errors over 5m and 1h, 5% over 30m, 0.5% over 6h.
+	// details has a steady 10% errors.
+	reviews := map[time.Duration][2]float64{
+		5 * time.Minute:  {8.0, 2.0},
+		30 * time.Minute: {19.0, 1.0},
+		time.Hour:        {4.0, 1.0},
+		6 * time.Hour:    {199.0, 1.0},
+	}
+	for window, rates := range reviews {
+		q0 := fmt.Sprintf(`round(sum(rate(istio_requests_total{reporter=~"destination|waypoint",destination_service_namespace="bookinfo"}[%ds])) by (%s) > 0,0.001)`, int(window.Seconds()), groupBy)
+		v0 := model.Vector{
+			&model.Sample{Metric: sloTestMetric("reviews", "200"), Value: model.SampleValue(rates[0])},
+			&model.Sample{Metric: sloTestMetric("reviews", "500"), Value: model.SampleValue(rates[1])},
+			&model.Sample{Metric: sloTestMetric("details", "200"), Value: 9.0},
+			&model.Sample{Metric: sloTestMetric("details", "503"), Value: 1.0},
+		}
+		q1 := fmt.Sprintf(`round(sum(rate(istio_requests_total{reporter=~"source|waypoint",source_workload_namespace="bookinfo"}[%ds])) by (%s) > 0,0.001)`, int(window.Seconds()), groupBy)
+		mockQueryAt(api, q0, queryTime, &v0)
+		mockQueryAt(api, q1, queryTime, &model.Vector{})
+	}
+
+	appender := SLOAppender{
+		GraphType: graph.GraphTypeVersionedApp,
+		HealthConfig: config.HealthConfig{
+			Rate: []config.Rate{
+				{
+					Namespace: "bookinfo",
+					Kind:      "service",
+					Name:      "details",
+					Tolerance: []config.Tolerance{{Code: "5XX", Failure: 20}},
+				},
+				{
+					Tolerance: []config.Tolerance{
+						{Code: "5XX", Protocol: "http", Direction: "inbound", Failure: 1},
+						{Code: "4XX", Protocol: "http", Degraded: 10}, // no failure threshold, no budget
+					},
+				},
+			},
+		},
+		InjectServiceNodes: true,
+		Namespaces: graph.NamespaceInfoMap{
+			"bookinfo": {Name: "bookinfo", Duration: 10 * time.Minute},
+		},
+		QueryTime: queryTime,
+		Rates: graph.RequestedRates{
+			Grpc: graph.RateRequests,
+			Http: graph.RateRequests,
+			Tcp:  graph.RateTotal,
+		},
+	}
+
+	trafficMap := sloTestTraffic(t)
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	burnRate := func(md graph.Metadata) *graph.BurnRateInfo {
+		info, ok := md[graph.BurnRate].(*graph.BurnRateInfo)
+		require.True(ok)
+		return info
+	}
+
+	edges := 0
+	for _, n := range trafficMap {
+		switch {
+		case n.NodeType != graph.NodeTypeService:
+			assert.NotContains(n.Metadata, graph.BurnRate)
+		case n.Service == "reviews":
+			info := burnRate(n.Metadata)
+			assert.Equal(map[string]float64{"5m": 20.0, "30m": 5.0, "1h": 20.0, "6h": 0.5}, info.Rates)
+			assert.True(info.IsBurning, "fast burn")
+			assert.InDelta(50.0, n.Metadata[graph.BudgetRemaining], 0.0001)
+		case n.Service == "details":
+			// the details service tolerance applies
+			info := burnRate(n.Metadata)
+			assert.Equal(0.5, info.Rates["6h"])
+			assert.False(info.IsBurning)
+		case n.Service == "mysql":
+			assert.NotContains(n.Metadata, graph.BurnRate)
+		}
+
+		for _, e := range n.Edges {
+			edges++
+			switch {
+			case e.Metadata[graph.ProtocolKey] == "tcp":
+				assert.NotContains(e.Metadata, graph.BurnRate)
+			case e.Dest.Workload == "reviews-v1", e.Dest.Service == "reviews":
+				info := burnRate(e.Metadata)
+				assert.Equal(20.0, info.Rates["1h"])
+				assert.True(info.IsBurning)
+			case e.Dest.Workload == "details-v1":
+				// the workload's inbound tolerance (1%) burns faster than the service's outbound tolerance (20%)
+				info := burnRate(e.Metadata)
+				assert.Equal(map[string]float64{"5m": 10.0, "30m": 10.0, "1h": 10.0, "6h": 10.0}, info.Rates)
+				assert.True(info.IsBurning, "slow burn")
+				assert.Equal(0.0, e.Metadata[graph.BudgetRemaining])
+			case e.Dest.Service == "details":
+				info := burnRate(e.Metadata)
+				assert.Equal(0.5, info.Rates["5m"])
+				assert.InDelta(50.0, e.Metadata[graph.BudgetRemaining], 0.0001)
+			default:
+				assert.Failf("unexpected edge", "%s -> %s", e.Source.ID, e.Dest.ID)
+			}
+		}
+	}
+	assert.Equal(5, edges)
+}
+
+func TestSLOWindowKeys(t *testing.T) {
+	assert.Equal(t, []string{"5m", "30m", "1h", "6h"}, SLOWindowKeys())
+}
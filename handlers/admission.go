@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	admission_v1 "k8s.io/api/admission/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// AdmissionReview is the handler of the validating admission webhook. It validates the Istio config of the cluster
+// with the reviewed object created, updated or deleted, and denies or warns about the checks the change would add,
+// according to the configured action of each severity. Kinds that Kiali doesn't validate are always allowed.
+func AdmissionReview(conf *config.Config, layerFactory func() *business.Layer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review := admission_v1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Unable to decode the admission review: "+err.Error())
+			return
+		}
+		if review.Request == nil {
+			RespondWithError(w, http.StatusBadRequest, "The admission review has no request")
+			return
+		}
+
+		response := reviewAdmissionRequest(r, conf, layerFactory, review.Request)
+		response.UID = review.Request.UID
+		RespondWithJSON(w, http.StatusOK, admission_v1.AdmissionReview{
+			TypeMeta: review.TypeMeta,
+			Response: response,
+		})
+	}
+}
+
+func reviewAdmissionRequest(r *http.Request, conf *config.Config, layerFactory func() *business.Layer, request *admission_v1.AdmissionRequest) *admission_v1.AdmissionResponse {
+	response := &admission_v1.AdmissionResponse{Allowed: true}
+
+	object, remove := request.Object.Raw, false
+	switch request.Operation {
+	case admission_v1.Create, admission_v1.Update:
+	case admission_v1.Delete:
+		object, remove = request.OldObject.Raw, true
+	default:
+		return response
+	}
+
+	// Gateway API kinds share the plural names of the Istio ones
+	resourceType := request.Resource.Resource
+	if request.Resource.Group == kubernetes.K8sNetworkingGroupVersionV1.Group {
+		resourceType = "k8s" + resourceType
+	}
+	if _, ok := kubernetes.ResourceTypesToAPI[resourceType]; !ok {
+		return response
+	}
+
+	cluster := clusterNameFromQuery(r.URL.Query())
+	current, proposed, err := layerFactory().Validations.GetProposedValidations(r.Context(), cluster, request.Namespace, resourceType, object, remove)
+	if err != nil {
+		// the webhook fails open, the change is validated again once applied
+		log.Errorf("Admission webhook unable to validate %s %s/%s: %s", resourceType, request.Namespace, request.Name, err)
+		response.Warnings = []string{fmt.Sprintf("Kiali was unable to validate the change: %s", err)}
+		return response
+	}
+
+	newChecks := proposed.NewChecks(current)
+	newChecks.StripIgnoredChecks()
+
+	webhook := conf.KialiFeatureFlags.Validations.AdmissionWebhook
+	denied := []string{}
+	for _, key := range sortedValidationKeys(newChecks) {
+		for _, check := range newChecks[key].Checks {
+			action := config.AdmissionActionIgnore
+			switch check.Severity {
+			case models.ErrorSeverity:
+				action = webhook.ErrorAction
+			case models.WarningSeverity:
+				action = webhook.WarningAction
+			}
+			message := admissionCheckMessage(key, check)
+			switch action {
+			case config.AdmissionActionDeny:
+				denied = append(denied, message)
+			case config.AdmissionActionWarn:
+				response.Warnings = append(response.Warnings, message)
+			}
+		}
+	}
+
+	if len(denied) > 0 {
+		response.Allowed = false
+		response.Result = &meta_v1.Status{
+			Status:  meta_v1.StatusFailure,
+			Code:    http.StatusForbidden,
+			Reason:  meta_v1.StatusReasonForbidden,
+			Message: "Kiali validation failed: " + strings.Join(denied, "; "),
+		}
+	}
+	return response
+}
+
+func admissionCheckMessage(key models.IstioValidationKey, check *models.IstioCheck) string {
+	message := fmt.Sprintf("%s %s/%s: %s %s", key.ObjectType, key.Namespace, key.Name, check.Severity, check.GetFullMessage())
+	if check.Path != "" {
+		message = fmt.Sprintf("%s [%s]", message, check.Path)
+	}
+	return message
+}
+
+func sortedValidationKeys(validations models.IstioValidations) []models.IstioValidationKey {
+	keys := make([]models.IstioValidationKey, 0, len(validations))
+	for key := range validations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ObjectType != keys[j].ObjectType {
+			return keys[i].ObjectType < keys[j].ObjectType
+		}
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	return keys
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admission_v1 "k8s.io/api/admission/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func setupAdmissionReview(t *testing.T, conf *config.Config) *httptest.Server {
+	t.Helper()
+	config.Set(conf)
+
+	k8s := kubetest.NewFakeK8sClient(
+		&core_v1.ConfigMap{ObjectMeta: meta_v1.ObjectMeta{Name: "istio", Namespace: "istio-system"}},
+		&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-system"}},
+		data.AddHttpRoutesToVirtualService(data.CreateHttpRouteDestination("reviews", "v1", -1),
+			data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})),
+		data.AddSubsetToDestinationRule(data.CreateSubset("v1", "v1"), data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews")),
+	)
+	cache := business.SetupBusinessLayer(t, k8s, *conf)
+	cache.SetRegistryStatus(map[string]*kubernetes.RegistryStatus{
+		conf.KubernetesConfig.ClusterName: {
+			Services: data.CreateFakeRegistryServices("reviews.bookinfo.svc.cluster.local", "bookinfo", "*"),
+		},
+	})
+
+	clients := map[string]kubernetes.ClientInterface{conf.KubernetesConfig.ClusterName: k8s}
+	ts := httptest.NewServer(AdmissionReview(conf, func() *business.Layer {
+		return business.NewWithBackends(clients, clients, nil, nil)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func postAdmissionReview(t *testing.T, ts *httptest.Server, request *admission_v1.AdmissionRequest) *admission_v1.AdmissionResponse {
+	t.Helper()
+	review := admission_v1.AdmissionReview{
+		TypeMeta: meta_v1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  request,
+	}
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reviewed := admission_v1.AdmissionReview{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reviewed))
+	require.NotNil(t, reviewed.Response)
+	assert.Equal(t, "AdmissionReview", reviewed.Kind)
+	assert.Equal(t, request.UID, reviewed.Response.UID)
+	return reviewed.Response
+}
+
+func destinationRuleRequest(t *testing.T, operation admission_v1.Operation, subsets bool) *admission_v1.AdmissionRequest {
+	dr := data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews")
+	if subsets {
+		dr = data.AddSubsetToDestinationRule(data.CreateSubset("v1", "v1"), dr)
+	}
+	raw, err := json.Marshal(dr)
+	require.NoError(t, err)
+
+	request := &admission_v1.AdmissionRequest{
+		UID:       types.UID("1234"),
+		Resource:  meta_v1.GroupVersionResource{Group: "networking.istio.io", Version: "v1", Resource: kubernetes.DestinationRules},
+		Name:      "reviews",
+		Namespace: "bookinfo",
+		Operation: operation,
+	}
+	if operation == admission_v1.Delete {
+		request.OldObject = runtime.RawExtension{Raw: raw}
+	} else {
+		request.Object = runtime.RawExtension{Raw: raw}
+	}
+	return request
+}
+
+func TestAdmissionReviewWarns(t *testing.T) {
+	ts := setupAdmissionReview(t, config.NewConfig())
+
+	response := postAdmissionReview(t, ts, destinationRuleRequest(t, admission_v1.Update, false))
+	assert.True(t, response.Allowed)
+	require.Len(t, response.Warnings, 1)
+	assert.Contains(t, response.Warnings[0], "virtualservice bookinfo/reviews: warning KIA1107")
+
+	response = postAdmissionReview(t, ts, destinationRuleRequest(t, admission_v1.Update, true))
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Warnings)
+}
+
+func TestAdmissionReviewDenies(t *testing.T) {
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.Validations.AdmissionWebhook.WarningAction = config.AdmissionActionDeny
+	ts := setupAdmissionReview(t, conf)
+
+	response := postAdmissionReview(t, ts, destinationRuleRequest(t, admission_v1.Delete, true))
+	assert.False(t, response.Allowed)
+	require.NotNil(t, response.Result)
+	assert.Equal(t, int32(http.StatusForbidden), response.Result.Code)
+	assert.Contains(t, response.Result.Message, "KIA1107")
+
+	// kinds not validated by Kiali are always allowed
+	response = postAdmissionReview(t, ts, &admission_v1.AdmissionRequest{
+		UID:       types.UID("5678"),
+		Resource:  meta_v1.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		Operation: admission_v1.Create,
+		Object:    runtime.RawExtension{Raw: []byte(`{"metadata":{"name":"foo"}}`)},
+	})
+	assert.True(t, response.Allowed)
+}
+
+func TestAdmissionReviewBadRequest(t *testing.T) {
+	ts := setupAdmissionReview(t, config.NewConfig())
+
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewReader([]byte(`{"kind":"AdmissionReview"}`)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
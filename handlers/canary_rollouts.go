@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/models"
+)
+
+// CanaryRolloutStart starts the canary rollout of the service given in the path, as defined by the body
+func CanaryRolloutStart(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+	cluster := clusterNameFromQuery(r.URL.Query())
+
+	spec := models.CanaryRolloutSpec{}
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Canary rollout request with bad spec: "+err.Error())
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	rollout, err := business.CanaryRollout.StartCanaryRollout(userContext(r), cluster, namespace, service, spec)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	audit(r, "CANARY ROLLOUT on Namespace: "+namespace+" Service: "+service+" Stable: "+spec.StableVersion+" Canary: "+spec.CanaryVersion+" Steps: "+fmt.Sprint(rollout.Spec.Steps))
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// CanaryRollout returns the state of the last canary rollout of the service given in the path
+func CanaryRollout(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	cluster := clusterNameFromQuery(r.URL.Query())
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	rollout, err := business.CanaryRollout.GetCanaryRollout(r.Context(), cluster, params["namespace"], params["service"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// CanaryRolloutRollback aborts the progressing canary rollout of the service given in the path
+func CanaryRolloutRollback(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+	cluster := clusterNameFromQuery(r.URL.Query())
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	rollout, err := business.CanaryRollout.RollbackCanaryRollout(userContext(r), cluster, namespace, service)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	audit(r, "CANARY ROLLBACK on Namespace: "+namespace+" Service: "+service)
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
@@ -14,31 +14,48 @@ package handlers
 //
 // The current Handlers:
 //   GraphNamespaces: Generate a graph for one or more requested namespaces.
+//   GraphNamespacesBlastRadius: Analyze the upstream and downstream nodes affected by a degradation of a namespaces graph node.
+//   GraphNamespacesDiff: Generate a namespaces graph comparing the requested time window to a baseline time window.
+//   GraphNamespacesStream: Stream a namespaces graph, sending the full graph followed by periodic patches (SSE).
+//   GraphNamespaceSLO: Summarize the error budget burn rates of a namespace's services and request edges.
 //   GraphNode:       Generate a graph for a specific node, detailing the immediate incoming and outgoing traffic.
+//   GraphSnapshotCreate: Generate a namespaces graph and store it as a snapshot.
+//   GraphSnapshots:  List the stored graph snapshots.
 //
 // The handlers accept the following query parameters (see notes below)
 //   appenders:       Comma-separated list of TelemetryVendor-specific appenders to run. (default: all)
-//   configVendor:    default: cytoscape
+//   baselineDuration:  Diff only, time.Duration of the baseline query range (default: duration)
+//   baselineQueryTime: Diff only, Unix time (seconds) for the baseline query (default: queryTime-duration)
+//   configVendor:    cytoscape | dot | graphml | mermaid (default: cytoscape)
+//   diffTolerance:   Diff only, percent difference tolerated before a value is considered changed (default: 10)
 //   duration:        time.Duration indicating desired query range duration, (default: 10m)
+//   find:            Find expression (graph toolbar syntax), matching nodes and edges are flagged (default: none)
 //   graphType:       Determines how to present the telemetry data. app | service | versionedApp | workload (default: workload)
 //   boxBy:           If supported by vendor, visually box by a specified node attribute (default: none)
+//   hide:            Hide expression (graph toolbar syntax), matching nodes and edges are removed (default: none)
+//   node:            BlastRadius only, the ID of the node to analyze. Required.
 //   namespaces:      Comma-separated list of namespace names to use in the graph. Will override namespace path param
 //   queryTime:       Unix time (seconds) for query such that range is queryTime-duration..queryTime (default now)
+//   refreshInterval: Stream only, time.Duration between graph regenerations (default: 15s, minimum: 5s)
+//   snapshot:        Namespaces and BlastRadius only, the ID of a stored graph snapshot to serve instead of querying telemetry
 //   TelemetryVendor: default: istio
 //
 //  Note: some handlers may ignore some query parameters.
 //  Note: vendors may support additional, vendor-specific query parameters.
 //
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"time"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/grafana"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/api"
+	"github.com/kiali/kiali/graph/snapshot"
 	"github.com/kiali/kiali/istio"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/cache"
@@ -72,6 +89,226 @@ func GraphNamespaces(
 	}
 }
 
+// GraphNamespacesDiff is a REST http.HandlerFunc handling diff graph generation for 1 or more namespaces
+func GraphNamespacesDiff(
+	conf *config.Config,
+	kialiCache cache.KialiCache,
+	clientFactory kubernetes.ClientFactory,
+	prom prometheus.ClientInterface,
+	cpm business.ControlPlaneMonitor,
+	traceClientLoader func() tracing.ClientInterface,
+	grafana *grafana.Service,
+	discovery *istio.Discovery,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer handlePanic(w)
+
+		business, err := getLayer(r, conf, kialiCache, clientFactory, cpm, prom, traceClientLoader, grafana, discovery)
+		graph.CheckError(err)
+
+		o := graph.NewOptions(r, &business.Namespace)
+
+		code, payload := api.GraphNamespacesDiff(r.Context(), business, o)
+		respond(w, code, payload)
+	}
+}
+
+// GraphNamespacesBlastRadius is a REST http.HandlerFunc returning the upstream callers and downstream
+// dependencies of a node in the namespaces graph, and the percentage of ingress traffic traversing the node.
+func GraphNamespacesBlastRadius(
+	conf *config.Config,
+	kialiCache cache.KialiCache,
+	clientFactory kubernetes.ClientFactory,
+	prom prometheus.ClientInterface,
+	cpm business.ControlPlaneMonitor,
+	traceClientLoader func() tracing.ClientInterface,
+	grafana *grafana.Service,
+	discovery *istio.Discovery,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer handlePanic(w)
+
+		nodeID := r.URL.Query().Get("node")
+		if nodeID == "" {
+			graph.BadRequest("The 'node' query parameter is required")
+		}
+
+		business, err := getLayer(r, conf, kialiCache, clientFactory, cpm, prom, traceClientLoader, grafana, discovery)
+		graph.CheckError(err)
+
+		o := graph.NewOptions(r, &business.Namespace)
+
+		code, payload := api.GraphNamespacesBlastRadius(r.Context(), business, o, nodeID)
+		respond(w, code, payload)
+	}
+}
+
+// GraphNamespaceSLO is a REST http.HandlerFunc returning the error budget burn rates of the namespace's services
+// and request edges, evaluated against the HealthConfig tolerances.
+func GraphNamespaceSLO(
+	conf *config.Config,
+	kialiCache cache.KialiCache,
+	clientFactory kubernetes.ClientFactory,
+	prom prometheus.ClientInterface,
+	cpm business.ControlPlaneMonitor,
+	traceClientLoader func() tracing.ClientInterface,
+	grafana *grafana.Service,
+	discovery *istio.Discovery,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer handlePanic(w)
+
+		business, err := getLayer(r, conf, kialiCache, clientFactory, cpm, prom, traceClientLoader, grafana, discovery)
+		graph.CheckError(err)
+
+		o := graph.NewOptions(r, &business.Namespace)
+
+		code, payload := api.GraphNamespaceSLO(r.Context(), business, o)
+		respond(w, code, payload)
+	}
+}
+
+// GraphSnapshots is a REST http.HandlerFunc listing the stored graph snapshots. Only the snapshots for which
+// all namespaces are accessible are returned.
+func GraphSnapshots(
+	conf *config.Config,
+	kialiCache cache.KialiCache,
+	clientFactory kubernetes.ClientFactory,
+	prom prometheus.ClientInterface,
+	cpm business.ControlPlaneMonitor,
+	traceClientLoader func() tracing.ClientInterface,
+	grafana *grafana.Service,
+	discovery *istio.Discovery,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer handlePanic(w)
+
+		store, err := snapshot.DefaultStore(conf)
+		if err != nil {
+			graph.BadRequest(err.Error())
+		}
+
+		business, err := getLayer(r, conf, kialiCache, clientFactory, cpm, prom, traceClientLoader, grafana, discovery)
+		graph.CheckError(err)
+
+		accessibleNamespaces := graph.GetAccessibleNamespaces(r.Context(), &business.Namespace)
+
+		code, payload := api.ListGraphSnapshots(store, accessibleNamespaces)
+		respond(w, code, payload)
+	}
+}
+
+// GraphSnapshotCreate is a REST http.HandlerFunc generating a namespaces graph and storing it as a snapshot
+func GraphSnapshotCreate(
+	conf *config.Config,
+	kialiCache cache.KialiCache,
+	clientFactory kubernetes.ClientFactory,
+	prom prometheus.ClientInterface,
+	cpm business.ControlPlaneMonitor,
+	traceClientLoader func() tracing.ClientInterface,
+	grafana *grafana.Service,
+	discovery *istio.Discovery,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer handlePanic(w)
+
+		store, err := snapshot.DefaultStore(conf)
+		if err != nil {
+			graph.BadRequest(err.Error())
+		}
+
+		business, err := getLayer(r, conf, kialiCache, clientFactory, cpm, prom, traceClientLoader, grafana, discovery)
+		graph.CheckError(err)
+
+		o := graph.NewOptions(r, &business.Namespace)
+
+		code, payload := api.CreateGraphSnapshot(r.Context(), business, store, o)
+		respond(w, code, payload)
+	}
+}
+
+const (
+	defaultGraphStreamRefreshInterval = 15 * time.Second
+	minGraphStreamRefreshInterval     = 5 * time.Second
+)
+
+// GraphNamespacesStream is a REST http.HandlerFunc streaming a graph for 1 or more namespaces using
+// server-sent events. Identical graph requests share the server-side graph generation.
+func GraphNamespacesStream(
+	conf *config.Config,
+	kialiCache cache.KialiCache,
+	clientFactory kubernetes.ClientFactory,
+	prom prometheus.ClientInterface,
+	cpm business.ControlPlaneMonitor,
+	traceClientLoader func() tracing.ClientInterface,
+	grafana *grafana.Service,
+	discovery *istio.Discovery,
+) http.HandlerFunc {
+	streamer := api.NewGraphStreamer()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer handlePanic(w)
+
+		business, err := getLayer(r, conf, kialiCache, clientFactory, cpm, prom, traceClientLoader, grafana, discovery)
+		graph.CheckError(err)
+
+		o := graph.NewOptions(r, &business.Namespace)
+		if o.Snapshot != "" {
+			graph.BadRequest("Graph streaming does not support the 'snapshot' query parameter")
+		}
+		if o.ConfigVendor != graph.VendorCytoscape {
+			graph.BadRequest(fmt.Sprintf("Invalid configVendor [%s], graph streaming supports only [%s]", o.ConfigVendor, graph.VendorCytoscape))
+		}
+
+		interval := defaultGraphStreamRefreshInterval
+		if intervalString := r.URL.Query().Get("refreshInterval"); intervalString != "" {
+			interval, err = time.ParseDuration(intervalString)
+			if err != nil || interval < minGraphStreamRefreshInterval {
+				graph.BadRequest(fmt.Sprintf("Invalid refreshInterval [%s], must be a duration of at least %v", intervalString, minGraphStreamRefreshInterval))
+			}
+		}
+
+		subscriber := streamer.Subscribe(business, o, interval)
+		defer subscriber.Close()
+
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		_ = rc.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event, ok := <-subscriber.Events:
+				if !ok {
+					// the subscriber was dropped, the client is expected to reconnect
+					return
+				}
+				// extend the server's write timeout, if supported, to cover the next event
+				if err := rc.SetWriteDeadline(time.Now().Add(2 * interval)); err != nil {
+					log.Tracef("Unable to extend write deadline for graph stream: %v", err)
+				}
+				data, err := json.Marshal(event.Data)
+				if err != nil {
+					log.Errorf("Unable to marshal graph stream event: %v", err)
+					return
+				}
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+					log.Debugf("Graph stream closed: %v", err)
+					return
+				}
+				if err := rc.Flush(); err != nil {
+					log.Errorf("Unable to flush graph stream: %v", err)
+					return
+				}
+			}
+		}
+	}
+}
+
 // GraphNode is a REST http.HandlerFunc handling node-detail graph config generation.
 func GraphNode(
 	conf *config.Config,
@@ -125,6 +362,13 @@ func handlePanic(w http.ResponseWriter) {
 
 func respond(w http.ResponseWriter, code int, payload interface{}) {
 	if code == http.StatusOK {
+		// non-JSON config vendors provide the document as-is
+		if raw, ok := payload.(graph.RawConfig); ok {
+			w.Header().Set("Content-Type", raw.ContentType)
+			w.WriteHeader(code)
+			_, _ = w.Write(raw.Data)
+			return
+		}
 		RespondWithJSONIndent(w, code, payload)
 		return
 	}
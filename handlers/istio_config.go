@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
 
 	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/business/confighistory"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
@@ -188,7 +192,7 @@ func IstioConfigDelete(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
 		return
 	}
-	err = business.IstioConfig.DeleteIstioConfigDetail(r.Context(), cluster, namespace, objectType, object)
+	err = business.IstioConfig.DeleteIstioConfigDetail(userContext(r), cluster, namespace, objectType, object)
 	if err != nil {
 		handleErrorResponse(w, err)
 		return
@@ -206,6 +210,11 @@ func IstioConfigUpdate(w http.ResponseWriter, r *http.Request) {
 
 	query := r.URL.Query()
 	cluster := clusterNameFromQuery(query)
+	dryRun, err := dryRunFromQuery(query)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid dryRun param: "+err.Error())
+		return
+	}
 
 	if !business.GetIstioAPI(objectType) {
 		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
@@ -224,7 +233,16 @@ func IstioConfigUpdate(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Update request with bad update patch: "+err.Error())
 	}
 	jsonPatch := string(body)
-	updatedConfigDetails, err := business.IstioConfig.UpdateIstioConfigDetail(r.Context(), cluster, namespace, objectType, object, jsonPatch)
+	if dryRun {
+		result, err := business.IstioConfig.DryRunUpdateIstioConfigDetail(r.Context(), cluster, namespace, objectType, object, jsonPatch)
+		if err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		RespondWithJSON(w, http.StatusOK, result)
+		return
+	}
+	updatedConfigDetails, err := business.IstioConfig.UpdateIstioConfigDetail(userContext(r), cluster, namespace, objectType, object, jsonPatch)
 	if err != nil {
 		handleErrorResponse(w, err)
 		return
@@ -234,6 +252,77 @@ func IstioConfigUpdate(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, updatedConfigDetails)
 }
 
+// IstioCheckFix previews the fix of a validation check given in the body, or applies it with the user's token
+// when the preview query param is false
+func IstioCheckFix(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cluster := clusterNameFromQuery(query)
+	preview := true
+	if previewParam := query.Get("preview"); previewParam != "" {
+		var err error
+		if preview, err = strconv.ParseBool(previewParam); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid preview param: "+err.Error())
+			return
+		}
+	}
+
+	fix := models.IstioCheckFix{}
+	if err := json.NewDecoder(r.Body).Decode(&fix); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Fix request with bad fix: "+err.Error())
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	var result models.IstioCheckFixResult
+	if preview {
+		result, err = business.IstioConfig.PreviewIstioCheckFix(r.Context(), cluster, fix)
+	} else {
+		result, err = business.IstioConfig.ApplyIstioCheckFix(userContext(r), cluster, fix)
+	}
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	if result.Applied {
+		audit(r, "FIX on Namespace: "+fix.Namespace+" Type: "+fix.ObjectType+" Name: "+fix.Name+" Patch: "+fix.Patch)
+	}
+	RespondWithJSON(w, http.StatusOK, result)
+}
+
+// IstioRouteSimulate returns where the VirtualServices route the HTTP request given in the body, sent from the
+// namespace of the path
+func IstioRouteSimulate(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+	cluster := clusterNameFromQuery(query)
+
+	request := models.RouteSimulationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Simulate request with bad request: "+err.Error())
+		return
+	}
+	request.SourceNamespace = params["namespace"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	simulation, err := business.IstioConfig.SimulateRoute(r.Context(), cluster, request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, simulation)
+}
+
 func IstioConfigCreate(w http.ResponseWriter, r *http.Request) {
 	// Feels kinda replicated for multiple functions..
 	params := mux.Vars(r)
@@ -242,6 +331,11 @@ func IstioConfigCreate(w http.ResponseWriter, r *http.Request) {
 
 	query := r.URL.Query()
 	cluster := clusterNameFromQuery(query)
+	dryRun, err := dryRunFromQuery(query)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid dryRun param: "+err.Error())
+		return
+	}
 
 	if !business.GetIstioAPI(objectType) {
 		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
@@ -260,7 +354,17 @@ func IstioConfigCreate(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Create request could not be read: "+err.Error())
 	}
 
-	createdConfigDetails, err := business.IstioConfig.CreateIstioConfigDetail(r.Context(), cluster, namespace, objectType, body)
+	if dryRun {
+		result, err := business.IstioConfig.DryRunCreateIstioConfigDetail(r.Context(), cluster, namespace, objectType, body)
+		if err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		RespondWithJSON(w, http.StatusOK, result)
+		return
+	}
+
+	createdConfigDetails, err := business.IstioConfig.CreateIstioConfigDetail(userContext(r), cluster, namespace, objectType, body)
 	if err != nil {
 		handleErrorResponse(w, err)
 		return
@@ -270,6 +374,182 @@ func IstioConfigCreate(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, createdConfigDetails)
 }
 
+// IstioConfigBulkApply creates the Istio objects of a multi-document YAML in one or more clusters. Nothing is
+// created when an object is rejected, the objects already created are deleted when one of them fails.
+func IstioConfigBulkApply(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	namespace := query.Get("namespace")
+	dryRun, err := dryRunFromQuery(query)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid dryRun param: "+err.Error())
+		return
+	}
+	clusters := []string{}
+	if clustersParam := query.Get("clusters"); clustersParam != "" {
+		clusters = strings.Split(clustersParam, ",")
+	} else if clusterName := query.Get("clusterName"); clusterName != "" {
+		clusters = append(clusters, clusterName)
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Bulk apply request could not be read: "+err.Error())
+		return
+	}
+
+	result, err := business.IstioConfig.ApplyIstioConfigBulk(userContext(r), clusters, namespace, body, dryRun)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	if !dryRun {
+		audit(r, "BULK APPLY on Clusters: "+strings.Join(result.Clusters, ",")+" Applied: "+strconv.FormatBool(result.Applied)+" Objects: "+string(body))
+	}
+	code := http.StatusOK
+	for _, object := range result.Objects {
+		if object.Status == models.BulkObjectFailed {
+			code = http.StatusUnprocessableEntity
+			break
+		}
+	}
+	RespondWithJSON(w, code, result)
+}
+
+// IstioConfigExport returns an archive with the Istio config of a namespace and a Kustomization file
+func IstioConfigExport(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+
+	query := r.URL.Query()
+	cluster := clusterNameFromQuery(query)
+	format := query.Get("format")
+	if format == "" {
+		format = business.IstioConfigExportTar
+	}
+	fileName, contentType := namespace+"-istio-config.tar.gz", "application/gzip"
+	if format == business.IstioConfigExportZip {
+		fileName, contentType = namespace+"-istio-config.zip", "application/zip"
+	}
+	includeServices := false
+	if param := query.Get("includeServices"); param != "" {
+		var err error
+		if includeServices, err = strconv.ParseBool(param); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid includeServices param: "+err.Error())
+			return
+		}
+	}
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	archive, err := business.IstioConfig.ExportIstioConfig(r.Context(), cluster, namespace, format, includeServices)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(archive); err != nil {
+		log.Errorf("Error writing the Istio config export of namespace [%s]: %v", namespace, err)
+	}
+}
+
+// dryRunFromQuery returns whether the change is only submitted in dry-run mode. Default is false.
+func dryRunFromQuery(query url.Values) (bool, error) {
+	dryRun := query.Get("dryRun")
+	if dryRun == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(dryRun)
+}
+
+// IstioConfigHistory returns the revisions of an Istio object recorded by Kiali, most recent first
+func IstioConfigHistory(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+
+	query := r.URL.Query()
+	cluster := clusterNameFromQuery(query)
+
+	if !config.Get().IstioConfigHistory.Enabled {
+		RespondWithError(w, http.StatusBadRequest, "Istio config history is disabled")
+		return
+	}
+	if !business.GetIstioAPI(objectType) {
+		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	revisions, err := business.IstioConfig.GetIstioConfigHistory(r.Context(), cluster, namespace, objectType, object)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, revisions)
+}
+
+// IstioConfigRollback restores an Istio object to its state after a recorded revision, with the user's token
+func IstioConfigRollback(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+	revision := params["revision"]
+
+	query := r.URL.Query()
+	cluster := clusterNameFromQuery(query)
+
+	if !config.Get().IstioConfigHistory.Enabled {
+		RespondWithError(w, http.StatusBadRequest, "Istio config history is disabled")
+		return
+	}
+	if !business.GetIstioAPI(objectType) {
+		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	rollback, err := business.IstioConfig.RollbackIstioConfigDetail(userContext(r), cluster, namespace, objectType, object, revision)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	audit(r, "ROLLBACK on Namespace: "+namespace+" Type: "+objectType+" Name: "+object+" Revision: "+revision)
+	RespondWithJSON(w, http.StatusOK, rollback)
+}
+
+// userContext returns the context of the request carrying the user, recorded in the Istio config history
+func userContext(r *http.Request) context.Context {
+	return confighistory.WithUser(r.Context(), r.Header.Get("Kiali-User"))
+}
+
 func checkObjectType(objectType string) bool {
 	return business.GetIstioAPI(objectType)
 }
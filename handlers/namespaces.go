@@ -3,10 +3,14 @@ package handlers
 import (
 	"io"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/istio"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
@@ -139,6 +143,72 @@ func ConfigValidationSummary(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, validationSummaries)
 }
 
+// ValidationsTrend is the API returning the validation counts of the background validations over time, for the
+// given namespaces or all the accessible namespaces of the cluster. The duration is in seconds, all the kept counts
+// are returned when not set.
+func ValidationsTrend(w http.ResponseWriter, r *http.Request) {
+	if !config.Get().KialiFeatureFlags.Validations.Background.Enabled {
+		RespondWithError(w, http.StatusServiceUnavailable, "Background validations are disabled in config")
+		return
+	}
+
+	params := r.URL.Query()
+	cluster := clusterNameFromQuery(params)
+	since := time.Time{}
+	if dur := params.Get("duration"); dur != "" {
+		num, err := strconv.ParseInt(dur, 10, 64)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "bad request, cannot parse query parameter 'duration'")
+			return
+		}
+		since = time.Now().Add(-time.Duration(num) * time.Second)
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// only the namespaces accessible to the user are included
+	loadedNamespaces, err := business.Namespace.GetClusterNamespaces(r.Context(), cluster)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	requested := []string{}
+	if namespaces := params.Get("namespaces"); namespaces != "" {
+		requested = strings.Split(namespaces, ",")
+	}
+	nss := []string{}
+	for _, ns := range loadedNamespaces {
+		if len(requested) == 0 || slices.Contains(requested, ns.Name) {
+			nss = append(nss, ns.Name)
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, business.Validations.GetValidationsTrend(cluster, nss, since))
+}
+
+// MeshValidations is the API validating the config of all the accessible clusters of the mesh against each other
+func MeshValidations(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	validations, err := business.Validations.GetMeshValidations(r.Context())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, validations)
+}
+
 // NamespaceUpdate is the API to perform a patch on a Namespace configuration
 func NamespaceUpdate(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
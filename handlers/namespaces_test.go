@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -22,6 +23,7 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/prometheustest"
 )
@@ -159,6 +161,48 @@ func TestNamespaceInfo(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode, string(actual))
 }
 
+func TestValidationsTrend(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	k8s := kubetest.NewFakeK8sClient(
+		&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-system"}},
+	)
+	business.SetupBusinessLayer(t, k8s, *conf)
+
+	authInfo := map[string]*api.AuthInfo{conf.KubernetesConfig.ClusterName: {Token: "test"}}
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/istio/validations/trend", WithAuthInfo(authInfo, ValidationsTrend))
+	ts := httptest.NewServer(mr)
+	t.Cleanup(ts.Close)
+
+	resp, err := http.Get(ts.URL + "/api/istio/validations/trend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "the background validations are disabled")
+
+	conf.KialiFeatureFlags.Validations.Background.Enabled = true
+	config.Set(conf)
+
+	resp, err = http.Get(ts.URL + "/api/istio/validations/trend?namespaces=bookinfo,unknown&duration=3600")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, string(actual))
+	trend := models.ValidationsTrend{}
+	assert.NoError(t, json.Unmarshal(actual, &trend))
+	assert.Equal(t, []string{"bookinfo"}, trend.Namespaces, "only the known namespaces are included")
+	assert.Empty(t, trend.Samples)
+
+	resp, err = http.Get(ts.URL + "/api/istio/validations/trend?duration=1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
 type notRemote struct{}
 
 func (n *notRemote) IsRemoteCluster(context.Context, string) bool { return false }
@@ -237,6 +237,46 @@ func WorkloadUpdate(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, workloadDetails)
 }
 
+// WorkloadAuthorization simulates a request to the workload, described by the query params, and returns the verdict
+// of its AuthorizationPolicies
+func WorkloadAuthorization(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+	request := models.AuthorizationSimulationRequest{
+		SourceNamespace:      query.Get("sourceNamespace"),
+		SourceServiceAccount: query.Get("sourceServiceAccount"),
+		SourcePrincipal:      query.Get("sourcePrincipal"),
+		SourceIP:             query.Get("sourceIP"),
+		RequestPrincipal:     query.Get("requestPrincipal"),
+		Namespace:            params["namespace"],
+		Workload:             params["workload"],
+		Host:                 query.Get("host"),
+		Method:               query.Get("method"),
+		Path:                 query.Get("path"),
+	}
+	if port := query.Get("port"); port != "" {
+		var err error
+		if request.Port, err = strconv.Atoi(port); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid port: "+err.Error())
+			return
+		}
+	}
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	simulation, err := business.Authorization.SimulateAuthorization(r.Context(), clusterNameFromQuery(query), request)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, simulation)
+}
+
 // PodDetails is the API handler to fetch all details to be displayed, related to a single pod
 func PodDetails(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -92,6 +92,9 @@ const (
 	K8sActualTLSRouteType = "TLSRoute"
 	K8sActualTLSRoutes    = "tlsroutes"
 
+	// K8s Core
+	Services = "services"
+
 	// Authorization PeerAuthentications
 	AuthorizationPolicies     = "authorizationpolicies"
 	AuthorizationPoliciesType = "AuthorizationPolicy"
@@ -0,0 +1,113 @@
+package models
+
+// AuthorizationVerdict is the outcome of the AuthorizationPolicies for a request
+type AuthorizationVerdict string
+
+const (
+	// AuthorizationAllow means the request is allowed
+	AuthorizationAllow AuthorizationVerdict = "ALLOW"
+	// AuthorizationDeny means the request is denied
+	AuthorizationDeny AuthorizationVerdict = "DENY"
+	// AuthorizationCustom means the request is allowed by the policies only if the external authorizer allows it
+	AuthorizationCustom AuthorizationVerdict = "CUSTOM"
+)
+
+// AuthorizationSimulationRequest is a request to a workload, evaluated against the AuthorizationPolicies
+type AuthorizationSimulationRequest struct {
+	// Namespace of the source workload
+	// example: bookinfo
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// Service account of the source workload
+	// example: bookinfo-productpage
+	SourceServiceAccount string `json:"sourceServiceAccount"`
+
+	// Principal of the source. It is <trust domain>/ns/<namespace>/sa/<service account> when not set.
+	// example: cluster.local/ns/bookinfo/sa/bookinfo-productpage
+	SourcePrincipal string `json:"sourcePrincipal"`
+
+	// IP of the source
+	// example: 10.0.0.1
+	SourceIP string `json:"sourceIP"`
+
+	// Principal of the request, from its JWT
+	// example: issuer.example.com/subject
+	RequestPrincipal string `json:"requestPrincipal"`
+
+	// Namespace of the destination workload
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// Name of the destination workload
+	// required: true
+	// example: reviews-v1
+	Workload string `json:"workload"`
+
+	// Host of the request
+	// example: reviews.bookinfo.svc.cluster.local
+	Host string `json:"host"`
+
+	// Destination port
+	// example: 9080
+	Port int `json:"port"`
+
+	// HTTP method of the request, empty for TCP traffic
+	// example: GET
+	Method string `json:"method"`
+
+	// HTTP path of the request, empty for TCP traffic
+	// example: /reviews/1
+	Path string `json:"path"`
+}
+
+// AuthorizationPolicyEvaluation is the evaluation of an AuthorizationPolicy applying to the destination workload
+type AuthorizationPolicyEvaluation struct {
+	// Name of the AuthorizationPolicy
+	// required: true
+	Name string `json:"name"`
+
+	// Namespace of the AuthorizationPolicy
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// Action of the AuthorizationPolicy: ALLOW, DENY, AUDIT or CUSTOM
+	// required: true
+	Action string `json:"action"`
+
+	// Indexes of the rules matching the request
+	// required: true
+	MatchedRules []int `json:"matchedRules"`
+
+	// Whether the policy determined the verdict
+	// required: true
+	Determining bool `json:"determining"`
+}
+
+// AuthorizationSimulation is the verdict of the AuthorizationPolicies for a request and the policies that
+// determined it
+type AuthorizationSimulation struct {
+	// The simulated request
+	// required: true
+	Request AuthorizationSimulationRequest `json:"request"`
+
+	// The verdict of the policies
+	// required: true
+	// example: DENY
+	Verdict AuthorizationVerdict `json:"verdict"`
+
+	// Explanation of the verdict
+	// required: true
+	Reason string `json:"reason"`
+
+	// Whether an AUDIT policy matches the request
+	// required: true
+	Audited bool `json:"audited"`
+
+	// The policies applying to the destination workload
+	// required: true
+	Policies []AuthorizationPolicyEvaluation `json:"policies"`
+
+	// Parts of the policies that can't be evaluated from the request, assumed not matching
+	Notes []string `json:"notes"`
+}
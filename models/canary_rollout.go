@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// Phases of a CanaryRollout
+const (
+	CanaryRolloutProgressing = "progressing"
+	CanaryRolloutRolledBack  = "rolledback"
+	CanaryRolloutSucceeded   = "succeeded"
+)
+
+// CanaryRolloutSpec defines how the traffic of a service is shifted from its stable version to a canary version
+type CanaryRolloutSpec struct {
+	// The version receiving the traffic not sent to the canary
+	// required: true
+	// example: v1
+	StableVersion string `json:"stableVersion"`
+
+	// The version rolled out
+	// required: true
+	// example: v2
+	CanaryVersion string `json:"canaryVersion"`
+
+	// The successive weights of the canary version, in percent, ending at 100. Defaults to 10, 25, 50, 100.
+	// example: [10, 25, 50, 100]
+	Steps []int32 `json:"steps,omitempty"`
+
+	// How long each weight is kept before moving to the next step, as a Go duration. Defaults to 5m.
+	// example: 5m
+	StepInterval string `json:"stepInterval,omitempty"`
+
+	// The maximum ratio (0 to 1) of the requests in error to the canary version, 0 allows no error. Defaults to 0.05
+	// when not set.
+	// example: 0.05
+	MaxErrorRatio *float64 `json:"maxErrorRatio,omitempty"`
+
+	// The maximum 95th percentile latency of the canary version, in milliseconds. Not checked when 0.
+	// example: 500
+	MaxLatency float64 `json:"maxLatency,omitempty"`
+}
+
+// CanaryRollout is the state of the rollout of a canary version of a service. The traffic is shifted step by step
+// to the canary version while its health is within the thresholds, it is sent back to the stable version otherwise.
+type CanaryRollout struct {
+	// required: true
+	Cluster string `json:"cluster"`
+
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Service string `json:"service"`
+
+	// required: true
+	Spec CanaryRolloutSpec `json:"spec"`
+
+	// The phase: progressing, succeeded or rolledback
+	// required: true
+	// example: progressing
+	Phase string `json:"phase"`
+
+	// The index of the current step
+	// required: true
+	Step int `json:"step"`
+
+	// The current weight of the canary version, in percent
+	// required: true
+	CanaryWeight int32 `json:"canaryWeight"`
+
+	// required: true
+	StartTime time.Time `json:"startTime"`
+
+	// When the weight or the phase last changed
+	// required: true
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+
+	// What happened last, i.e. why the rollout was rolled back
+	Message string `json:"message,omitempty"`
+
+	// The health of the canary version at the last check
+	CanaryHealth *ServiceVersionHealth `json:"canaryHealth,omitempty"`
+}
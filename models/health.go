@@ -1,6 +1,8 @@
 package models
 
 import (
+	"strings"
+
 	"github.com/prometheus/common/model"
 
 	"github.com/kiali/kiali/log"
@@ -27,6 +29,17 @@ type ServiceHealth struct {
 	Requests RequestHealth `json:"requests"`
 }
 
+// ServiceVersionHealth contains the health of the requests to a version of a service
+type ServiceVersionHealth struct {
+	Version string `json:"version"`
+	// ErrorRatio is the ratio of the requests in error: HTTP 5xx, no response, or gRPC error status
+	ErrorRatio float64 `json:"errorRatio"`
+	// LatencyP95 is the 95th percentile of the request duration in milliseconds, 0 when there is no request
+	LatencyP95  float64       `json:"latencyP95"`
+	RequestRate float64       `json:"requestRate"`
+	Requests    RequestHealth `json:"requests"`
+}
+
 // AppHealth contains aggregated health from various sources, for a given app
 type AppHealth struct {
 	WorkloadStatuses []*WorkloadStatus `json:"workloadStatuses"`
@@ -188,6 +201,23 @@ func aggregate(sample *model.Sample, requests map[string]map[string]float64) {
 	requests[protocol][code] += float64(sample.Value)
 }
 
+// InboundErrorRatio returns the request rate and the ratio of the requests in error of the inbound requests
+func (in *RequestHealth) InboundErrorRatio() (rate float64, errorRatio float64) {
+	errors := 0.0
+	for protocol, codes := range in.Inbound {
+		for code, value := range codes {
+			rate += value
+			if code == "-" || (protocol == "grpc" && code != "0") || (protocol != "grpc" && strings.HasPrefix(code, "5")) {
+				errors += value
+			}
+		}
+	}
+	if rate == 0 {
+		return 0, 0
+	}
+	return rate, errors / rate
+}
+
 // CastWorkloadStatus returns a WorkloadStatus out of a given Workload
 func (w Workload) CastWorkloadStatus() *WorkloadStatus {
 	syncedProxies := int32(-1)
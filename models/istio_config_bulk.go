@@ -0,0 +1,51 @@
+package models
+
+// Statuses of an IstioConfigBulkObject
+const (
+	BulkObjectCreated    = "created"
+	BulkObjectFailed     = "failed"
+	BulkObjectRolledBack = "rolledback"
+	BulkObjectSkipped    = "skipped"
+	BulkObjectValid      = "valid"
+)
+
+// IstioConfigBulkApply is the result of applying a set of Istio objects to one or more clusters. The objects are all
+// validated before any of them is created, when one fails to be created the objects already created are deleted.
+type IstioConfigBulkApply struct {
+	// Whether all the objects were created. It is false when the apply was only validated (dry-run).
+	// required: true
+	Applied bool `json:"applied"`
+
+	// The clusters targeted
+	// required: true
+	Clusters []string `json:"clusters"`
+
+	// The objects per cluster, in the order they are applied
+	// required: true
+	Objects []IstioConfigBulkObject `json:"objects"`
+}
+
+// IstioConfigBulkObject is the outcome of one object of a bulk apply in one cluster
+type IstioConfigBulkObject struct {
+	// required: true
+	Cluster string `json:"cluster"`
+
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The Istio object type
+	// required: true
+	// example: virtualservices
+	ObjectType string `json:"objectType"`
+
+	// required: true
+	Name string `json:"name"`
+
+	// The status: valid, created, failed, rolledback or skipped
+	// required: true
+	// example: created
+	Status string `json:"status"`
+
+	// Why the object failed, or failed to be rolled back
+	Error string `json:"error,omitempty"`
+}
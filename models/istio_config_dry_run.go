@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+)
+
+// Operations of an IstioConfigChange
+const (
+	ChangeAdd     = "add"
+	ChangeRemove  = "remove"
+	ChangeReplace = "replace"
+)
+
+// IstioConfigDryRun is the result of a create or update of an Istio object admitted but not persisted by the API server
+type IstioConfigDryRun struct {
+	// The object as it is now, null when the object is created
+	Current json.RawMessage `json:"current"`
+
+	// The object as it would be after the change, as returned by the API server
+	// required: true
+	Result json.RawMessage `json:"result"`
+
+	// The changes between the current object and the resulting object. Fields maintained by the API server are left out.
+	// required: true
+	Diff []IstioConfigChange `json:"diff"`
+
+	// Validation of the resulting object
+	Validation *IstioValidation `json:"validation"`
+
+	// Checks of the Istio config, including other objects, that the change would add
+	// required: true
+	NewChecks IstioValidations `json:"newChecks"`
+}
+
+// IstioConfigChange is a change of a field of an Istio object
+type IstioConfigChange struct {
+	// JSON pointer of the field
+	// required: true
+	// example: /spec/http/0/route/0/weight
+	Path string `json:"path"`
+
+	// The operation: add, remove or replace
+	// required: true
+	// example: replace
+	Op string `json:"op"`
+
+	// Value of the field before the change
+	// example: 80
+	From interface{} `json:"from,omitempty"`
+
+	// Value of the field after the change
+	// example: 50
+	To interface{} `json:"to,omitempty"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Operations of an IstioConfigRevision
+const (
+	RevisionCreate   = "create"
+	RevisionDelete   = "delete"
+	RevisionRollback = "rollback"
+	RevisionUpdate   = "update"
+)
+
+// IstioConfigRevision is a change of an Istio object done through Kiali
+type IstioConfigRevision struct {
+	// Id of the revision
+	// required: true
+	// example: 20240101T120000Z-1a2b3c4d
+	ID string `json:"id"`
+
+	// Cluster of the object
+	Cluster string `json:"cluster"`
+
+	// Namespace of the object
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// Type of the object
+	// required: true
+	// example: virtualservices
+	ObjectType string `json:"objectType"`
+
+	// Name of the object
+	// required: true
+	// example: reviews
+	Name string `json:"name"`
+
+	// The operation: create, update, delete or rollback
+	// required: true
+	// example: update
+	Operation string `json:"operation"`
+
+	// Id of the revision restored by a rollback
+	RolledBackTo string `json:"rolledBackTo,omitempty"`
+
+	// User doing the change, when known
+	// example: admin
+	User string `json:"user"`
+
+	// Time of the change
+	// required: true
+	Timestamp time.Time `json:"timestamp"`
+
+	// The object before the change, null when it is created
+	Before json.RawMessage `json:"before"`
+
+	// The object after the change, null when it is deleted
+	After json.RawMessage `json:"after"`
+}
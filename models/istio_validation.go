@@ -46,6 +46,46 @@ type IstioValidationSummary struct {
 // ValidationSummaries holds a map of IstioValidationSummary per cluster and namespace
 type ValidationSummaries map[string]map[string]*IstioValidationSummary
 
+// ValidationsTrend is the history of the validation counts found by the background validations
+// swagger:model
+type ValidationsTrend struct {
+	// Cluster of the counts
+	// required: true
+	// example: east
+	Cluster string `json:"cluster"`
+
+	// Namespaces included in the counts
+	// required: true
+	Namespaces []string `json:"namespaces"`
+
+	// Counts of each background validation, oldest first
+	// required: true
+	Samples []ValidationsTrendSample `json:"samples"`
+}
+
+// ValidationsTrendSample are the counts of a single background validation
+type ValidationsTrendSample struct {
+	// Time of the validation, in unix seconds
+	// required: true
+	Timestamp int64 `json:"timestamp"`
+
+	// Number of error checks
+	// required: true
+	Errors int `json:"errors"`
+
+	// Number of warning checks
+	// required: true
+	Warnings int `json:"warnings"`
+
+	// Number of objects that are not valid
+	// required: true
+	InvalidObjects int `json:"invalidObjects"`
+
+	// Number of checks per code
+	// required: true
+	Codes map[string]int `json:"codes"`
+}
+
 // IstioValidations represents a set of IstioValidation grouped by IstioValidationKey.
 type IstioValidations map[IstioValidationKey]*IstioValidation
 
@@ -105,6 +145,53 @@ type IstioCheck struct {
 	// String that describes where in the yaml file is the check located
 	// example: spec/http[0]/route
 	Path string `json:"path"`
+
+	// Machine-applicable fixes of the check, if any
+	Fixes []IstioCheckFix `json:"fixes,omitempty"`
+}
+
+// IstioCheckFix represents a fix of a check: a JSON merge patch of an object in the cluster of the validation.
+// swagger:model
+type IstioCheckFix struct {
+	// Description of the change
+	// required: true
+	// example: Add subset v2 to DestinationRule reviews
+	Description string `json:"description"`
+
+	// Resource type of the patched object, as used by the Istio config API
+	// required: true
+	// example: destinationrules
+	ObjectType string `json:"objectType"`
+
+	// Namespace of the patched object
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// Name of the patched object
+	// required: true
+	// example: reviews
+	Name string `json:"name"`
+
+	// JSON merge patch of the object
+	// required: true
+	// example: {"spec":{"trafficPolicy":{"tls":null}}}
+	Patch string `json:"patch"`
+}
+
+// IstioCheckFixResult is the object patched by a fix, previewed or applied
+type IstioCheckFixResult struct {
+	// The fix
+	Fix IstioCheckFix `json:"fix"`
+
+	// Whether the patch was applied or only previewed
+	Applied bool `json:"applied"`
+
+	// The object before the patch
+	Object json.RawMessage `json:"object"`
+
+	// The object after the patch
+	Patched json.RawMessage `json:"patched"`
 }
 
 type SeverityLevel string
@@ -119,6 +206,7 @@ var ObjectTypeSingular = map[string]string{
 	"gateways":               "gateway",
 	"virtualservices":        "virtualservice",
 	"destinationrules":       "destinationrule",
+	"envoyfilters":           "envoyfilter",
 	"serviceentries":         "serviceentry",
 	"rules":                  "rule",
 	"quotaspecs":             "quotaspec",
@@ -132,7 +220,9 @@ var ObjectTypeSingular = map[string]string{
 	"peerauthentications":    "peerauthentication",
 	"requestauthentications": "requestauthentication",
 	"workloads":              "workload",
-	"wasmplugins":            "wasmpluin",
+	"workloadentries":        "workloadentry",
+	"workloadgroups":         "workloadgroup",
+	"wasmplugins":            "wasmplugin",
 	"telemetries":            "telemetry",
 	"k8sgateways":            "k8sgateway",
 	"k8sgrpcroutes":          "k8sgrpcroute",
@@ -173,6 +263,26 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "This field requires mTLS to be enabled",
 		Severity: ErrorSeverity,
 	},
+	"crosscluster.destinationrule.trafficpolicy": {
+		Code:     "KIA2001",
+		Message:  "Traffic policy for this host differs from the DestinationRules of other clusters",
+		Severity: WarningSeverity,
+	},
+	"crosscluster.peerauthentication.mode": {
+		Code:     "KIA2002",
+		Message:  "mTLS mode differs from the PeerAuthentications of other clusters for the same workloads",
+		Severity: WarningSeverity,
+	},
+	"crosscluster.service.missing": {
+		Code:     "KIA2003",
+		Message:  "This service only exists in other clusters of the mesh, it must exist in every cluster routing to it",
+		Severity: WarningSeverity,
+	},
+	"crosscluster.trustdomain.mismatch": {
+		Code:     "KIA2004",
+		Message:  "Trust domain differs from other clusters of the mesh and is not one of their trust domain aliases",
+		Severity: ErrorSeverity,
+	},
 	"destinationrules.multimatch": {
 		Code:     "KIA0201",
 		Message:  "More than one DestinationRules for the same host subset combination",
@@ -218,6 +328,26 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "This subset has not labels",
 		Severity: WarningSeverity,
 	},
+	"envoyfilter.proxyversion.invalid": {
+		Code:     "KIA1701",
+		Message:  "Proxy version is not a valid regular expression",
+		Severity: ErrorSeverity,
+	},
+	"envoyfilter.proxyversion.nomatch": {
+		Code:     "KIA1702",
+		Message:  "Proxy version doesn't match the version of any control plane, the patch is not applied",
+		Severity: WarningSeverity,
+	},
+	"envoyfilter.context.nogateway": {
+		Code:     "KIA1703",
+		Message:  "This patch applies to gateways but the selected workloads are not gateways",
+		Severity: WarningSeverity,
+	},
+	"envoyfilter.context.nosidecar": {
+		Code:     "KIA1704",
+		Message:  "This patch applies to sidecars but the selected workloads are gateways",
+		Severity: WarningSeverity,
+	},
 	"gateways.multimatch": {
 		Code:     "KIA0301",
 		Message:  "More than one Gateway for the same host port combination",
@@ -283,6 +413,46 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "Route is pointing to a non-existent K8s gateway",
 		Severity: ErrorSeverity,
 	},
+	"k8sroutes.parentref.sectionnotfound": {
+		Code:     "KIA1403",
+		Message:  "No listener with this section name found in the K8s gateway",
+		Severity: ErrorSeverity,
+	},
+	"k8sroutes.parentref.portnotfound": {
+		Code:     "KIA1404",
+		Message:  "No listener with this port found in the K8s gateway",
+		Severity: ErrorSeverity,
+	},
+	"k8sroutes.parentref.kindnotallowed": {
+		Code:     "KIA1405",
+		Message:  "No listener of the K8s gateway accepts routes of this kind",
+		Severity: ErrorSeverity,
+	},
+	"k8sroutes.parentref.namespacenotallowed": {
+		Code:     "KIA1406",
+		Message:  "No listener of the K8s gateway allows routes from this namespace",
+		Severity: ErrorSeverity,
+	},
+	"k8sroutes.hostname.nointersection": {
+		Code:     "KIA1407",
+		Message:  "No hostname intersects with the hostnames of the K8s gateway listeners",
+		Severity: ErrorSeverity,
+	},
+	"k8sroutes.backendref.kindnotsupported": {
+		Code:     "KIA1408",
+		Message:  "Backend kind is not supported",
+		Severity: ErrorSeverity,
+	},
+	"k8sroutes.backendref.portnotfound": {
+		Code:     "KIA1409",
+		Message:  "Port not found in the backend Service",
+		Severity: ErrorSeverity,
+	},
+	"k8sroutes.rule.conflict": {
+		Code:     "KIA1410",
+		Message:  "Rule matches the same requests as an older route attached to the same listener, it is ignored for them",
+		Severity: WarningSeverity,
+	},
 	"peerauthentication.mtls.destinationrulemissing": {
 		Code:     "KIA0401",
 		Message:  "Mesh-wide Destination Rule enabling mTLS is missing",
@@ -313,6 +483,21 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "Port name must follow <protocol>[-suffix] form",
 		Severity: ErrorSeverity,
 	},
+	"requestauthentication.jwksuri.invalid": {
+		Code:     "KIA1901",
+		Message:  "jwksUri is not a valid HTTP or HTTPS URL",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentication.issuer.invalid": {
+		Code:     "KIA1902",
+		Message:  "Issuer is empty or contains whitespaces",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentication.issuer.duplicate": {
+		Code:     "KIA1903",
+		Message:  "Issuer is already defined for the same workloads",
+		Severity: WarningSeverity,
+	},
 	"service.deployment.port.mismatch": {
 		Code:     "KIA0701",
 		Message:  "Deployment exposing same port as Service not found",
@@ -378,6 +563,11 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "This workload is not covered by any authorization policy",
 		Severity: WarningSeverity,
 	},
+	"workloadgroup.template.noserviceentry": {
+		Code:     "KIA1801",
+		Message:  "No ServiceEntry workloadSelector matches the labels of this template",
+		Severity: WarningSeverity,
+	},
 }
 
 func Build(checkId string, path string) IstioCheck {
@@ -402,6 +592,22 @@ func (ic IstioCheck) GetFullMessage() string {
 	return ic.Code + " " + ic.Message
 }
 
+// AddFix adds a fix patching the given object with the JSON merge patch of the given value
+func (ic *IstioCheck) AddFix(description, objectType, namespace, name string, patch interface{}) {
+	bytePatch, err := json.Marshal(patch)
+	if err != nil {
+		log.Errorf("Unable to marshal the fix [%s] of check %s: %s", description, ic.Code, err)
+		return
+	}
+	ic.Fixes = append(ic.Fixes, IstioCheckFix{
+		Description: description,
+		ObjectType:  objectType,
+		Namespace:   namespace,
+		Name:        name,
+		Patch:       string(bytePatch),
+	})
+}
+
 func (iv IstioValidations) FilterBySingleType(objectType, name string) IstioValidations {
 	fiv := IstioValidations{}
 	for k, v := range iv {
@@ -478,6 +684,40 @@ func (iv IstioValidations) MergeValidations(validations IstioValidations) IstioV
 	return iv
 }
 
+// NewChecks returns the validations with only the checks not found in the previous validations for the same object.
+// A check is the same when it has the same code, path and severity. Objects without new checks are left out.
+func (iv IstioValidations) NewChecks(previous IstioValidations) IstioValidations {
+	niv := IstioValidations{}
+	for key, validation := range iv {
+		var checks []*IstioCheck
+	NextCheck:
+		for _, check := range validation.Checks {
+			if prev, ok := previous[key]; ok {
+				for _, existing := range prev.Checks {
+					if check.Code == existing.Code &&
+						check.Path == existing.Path &&
+						check.Severity == existing.Severity {
+						continue NextCheck
+					}
+				}
+			}
+			checks = append(checks, check)
+		}
+		if len(checks) > 0 {
+			niv[key] = &IstioValidation{
+				Name:       validation.Name,
+				Namespace:  validation.Namespace,
+				Cluster:    validation.Cluster,
+				ObjectType: validation.ObjectType,
+				Valid:      validation.Valid,
+				Checks:     checks,
+				References: validation.References,
+			}
+		}
+	}
+	return niv
+}
+
 func (iv IstioValidations) MergeReferences(validations IstioValidations) IstioValidations {
 	for _, currentValidations := range iv {
 		if currentValidations.References == nil {
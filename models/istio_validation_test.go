@@ -84,3 +84,49 @@ func TestSummarizeValidations(t *testing.T) {
 	assert.Equal(1, summary.Warnings)
 	assert.Equal(1, summary.Errors)
 }
+
+func TestNewChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	key1 := IstioValidationKey{ObjectType: "virtualservice", Name: "foo", Namespace: "bookinfo", Cluster: "east"}
+	key2 := IstioValidationKey{ObjectType: "destinationrule", Name: "bar", Namespace: "bookinfo", Cluster: "east"}
+
+	previous := IstioValidations{
+		key1: &IstioValidation{
+			Name:       "foo",
+			ObjectType: "virtualservice",
+			Valid:      false,
+			Checks: []*IstioCheck{
+				{Code: "FOO1", Severity: ErrorSeverity, Path: "spec/http[0]"},
+			},
+		},
+	}
+	current := IstioValidations{
+		key1: &IstioValidation{
+			Name:       "foo",
+			ObjectType: "virtualservice",
+			Valid:      false,
+			Checks: []*IstioCheck{
+				{Code: "FOO1", Severity: ErrorSeverity, Path: "spec/http[0]"},
+				{Code: "FOO1", Severity: ErrorSeverity, Path: "spec/http[1]"},
+			},
+		},
+		key2: &IstioValidation{
+			Name:       "bar",
+			ObjectType: "destinationrule",
+			Valid:      true,
+			Checks: []*IstioCheck{
+				{Code: "BAR1", Severity: WarningSeverity},
+			},
+		},
+	}
+
+	newChecks := current.NewChecks(previous)
+	assert.Len(newChecks, 2)
+	assert.Len(newChecks[key1].Checks, 1)
+	assert.Equal("spec/http[1]", newChecks[key1].Checks[0].Path)
+	assert.Len(newChecks[key2].Checks, 1)
+	assert.Len(current[key1].Checks, 2, "the validations are not modified")
+
+	assert.Empty(current.NewChecks(current))
+}
@@ -97,6 +97,7 @@ type IstioMeshConfig struct {
 	} `yaml:"defaultConfig" json:"defaultConfig"`
 	OutboundTrafficPolicy OutboundPolicy `yaml:"outboundTrafficPolicy,omitempty"`
 	TrustDomain           string         `yaml:"trustDomain,omitempty"`
+	TrustDomainAliases    []string       `yaml:"trustDomainAliases,omitempty" json:"trustDomainAliases,omitempty"`
 }
 
 func (imc IstioMeshConfig) GetEnableAutoMtls() bool {
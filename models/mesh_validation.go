@@ -0,0 +1,29 @@
+package models
+
+// MeshValidations are the validations of the config of the clusters of the mesh against each other
+type MeshValidations struct {
+	// Clusters of the mesh with the validations of their mesh settings
+	// required: true
+	Clusters []ClusterValidation `json:"clusters"`
+
+	// Validations of the Istio objects conflicting with the objects of other clusters
+	// required: true
+	Validations []*IstioValidation `json:"validations"`
+}
+
+// ClusterValidation is the validation of the mesh settings of a cluster
+type ClusterValidation struct {
+	// Name of the cluster
+	// required: true
+	// example: east
+	Cluster string `json:"cluster"`
+
+	// Trust domain of the control plane managing the cluster
+	// required: true
+	// example: cluster.local
+	TrustDomain string `json:"trustDomain"`
+
+	// Checks of the mesh settings of the cluster
+	// required: true
+	Checks []*IstioCheck `json:"checks"`
+}
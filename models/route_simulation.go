@@ -0,0 +1,130 @@
+package models
+
+import (
+	api_networking_v1 "istio.io/api/networking/v1"
+)
+
+// MeshGateway is the gateway name of the sidecars in the VirtualService gateways
+const MeshGateway = "mesh"
+
+// RouteSimulationRequest is an HTTP request routed by the VirtualServices
+type RouteSimulationRequest struct {
+	// Host of the request
+	// required: true
+	// example: reviews
+	Host string `json:"host"`
+
+	// Gateway receiving the request as <namespace>/<name>, or mesh for the sidecars. Default is mesh.
+	// example: istio-system/bookinfo-gateway
+	Gateway string `json:"gateway"`
+
+	// Namespace of the source workload. It is the namespace of the API path.
+	// example: bookinfo
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// Labels of the source workload
+	SourceLabels map[string]string `json:"sourceLabels"`
+
+	// Port of the request
+	// example: 9080
+	Port int `json:"port"`
+
+	// Scheme of the request
+	// example: http
+	Scheme string `json:"scheme"`
+
+	// Method of the request
+	// example: GET
+	Method string `json:"method"`
+
+	// Authority of the request. Default is the host.
+	// example: reviews:9080
+	Authority string `json:"authority"`
+
+	// Path of the request, it can include a query string
+	// example: /api/v2?user=jason
+	Path string `json:"path"`
+
+	// Headers of the request, names are case insensitive
+	Headers map[string]string `json:"headers"`
+
+	// Query params of the request, added to the ones of the path
+	QueryParams map[string]string `json:"queryParams"`
+}
+
+// RouteSimulationDestination is a destination of the chosen route
+type RouteSimulationDestination struct {
+	// Host of the destination
+	// required: true
+	Host string `json:"host"`
+
+	// Subset of the destination
+	Subset string `json:"subset"`
+
+	// Labels of the subset, from its DestinationRule
+	SubsetLabels map[string]string `json:"subsetLabels"`
+
+	// DestinationRule defining the subset
+	DestinationRule *IstioReference `json:"destinationRule"`
+
+	// Port of the destination
+	Port uint32 `json:"port"`
+
+	// Percentage of the traffic sent to the destination
+	// required: true
+	Weight int32 `json:"weight"`
+}
+
+// RouteSimulation is where the VirtualServices send a request
+type RouteSimulation struct {
+	// The simulated request
+	// required: true
+	Request RouteSimulationRequest `json:"request"`
+
+	// VirtualService routing the request, if any
+	VirtualService *IstioReference `json:"virtualService"`
+
+	// VirtualService the route is delegated to, if any
+	Delegate *IstioReference `json:"delegate"`
+
+	// Index of the chosen http route, -1 when no route matches
+	// required: true
+	RouteIndex int `json:"routeIndex"`
+
+	// Name of the chosen http route
+	RouteName string `json:"routeName"`
+
+	// Index of the matching condition of the chosen route, -1 when the route has no conditions
+	// required: true
+	MatchIndex int `json:"matchIndex"`
+
+	// Destinations of the request with their weights
+	// required: true
+	Destinations []RouteSimulationDestination `json:"destinations"`
+
+	// Redirect of the chosen route
+	Redirect *api_networking_v1.HTTPRedirect `json:"redirect"`
+
+	// Direct response of the chosen route
+	DirectResponse *api_networking_v1.HTTPDirectResponse `json:"directResponse"`
+
+	// Rewrite of the chosen route
+	Rewrite *api_networking_v1.HTTPRewrite `json:"rewrite"`
+
+	// Timeout of the chosen route
+	// example: 10s
+	Timeout string `json:"timeout"`
+
+	// Retries of the chosen route
+	Retries *api_networking_v1.HTTPRetry `json:"retries"`
+
+	// Fault injection of the chosen route
+	Fault *api_networking_v1.HTTPFaultInjection `json:"fault"`
+
+	// Explanation of the routing
+	// required: true
+	Reason string `json:"reason"`
+
+	// Parts of the routing that can't be simulated
+	Notes []string `json:"notes"`
+}
@@ -466,7 +466,7 @@ func (workload *Workload) HasIstioSidecar() bool {
 }
 
 // IsGateway return true if the workload is Ingress or Egress Gateway
-func (workload *Workload) IsGateway() bool {
+func (workload *WorkloadListItem) IsGateway() bool {
 	conf := config.Get()
 	if workload.Type == "Deployment" {
 		if labelValue, ok := workload.Labels["operator.istio.io/component"]; ok && (labelValue == "IngressGateways" || labelValue == "EgressGateways") {
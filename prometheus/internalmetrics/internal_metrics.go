@@ -19,6 +19,10 @@ const (
 	labelRoute            = "route"
 	labelQueryGroup       = "query_group"
 	labelCheckerName      = "checker"
+	labelCluster          = "cluster"
+	labelCode             = "code"
+	labelKind             = "kind"
+	labelSeverity         = "severity"
 	labelNamespace        = "namespace"
 	labelService          = "service"
 	labelType             = "type"
@@ -40,6 +44,8 @@ type MetricsType struct {
 	MeshGraphMarshalTime           *prometheus.HistogramVec
 	PrometheusProcessingTime       *prometheus.HistogramVec
 	SingleValidationProcessingTime *prometheus.HistogramVec
+	ValidationChecks               *prometheus.GaugeVec
+	ValidationInvalidObjects       *prometheus.GaugeVec
 	ValidationProcessingTime       *prometheus.HistogramVec
 }
 
@@ -124,6 +130,20 @@ var Metrics = MetricsType{
 		},
 		[]string{labelNamespace, labelType, labelName},
 	),
+	ValidationChecks: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_validation_checks",
+			Help: "The number of validation checks found by the last background validation of the Istio config.",
+		},
+		[]string{labelCluster, labelNamespace, labelKind, labelSeverity, labelCode},
+	),
+	ValidationInvalidObjects: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_validation_invalid_objects",
+			Help: "The number of invalid objects found by the last background validation of the Istio config.",
+		},
+		[]string{labelCluster, labelNamespace, labelKind},
+	),
 }
 
 // SuccessOrFailureMetricType let's you capture metrics for both successes and failures,
@@ -185,6 +205,8 @@ func RegisterInternalMetrics() {
 		Metrics.CheckerProcessingTime,
 		Metrics.ValidationProcessingTime,
 		Metrics.SingleValidationProcessingTime,
+		Metrics.ValidationChecks,
+		Metrics.ValidationInvalidObjects,
 	)
 }
 
@@ -364,6 +386,45 @@ func GetValidationProcessingTimePrometheusTimer(namespace string, service string
 	return timer
 }
 
+// ValidationCheckLabels are the labels of the validation checks count
+type ValidationCheckLabels struct {
+	Cluster   string
+	Namespace string
+	Kind      string
+	Severity  string
+	Code      string
+}
+
+// ValidationObjectLabels are the labels of the invalid objects count
+type ValidationObjectLabels struct {
+	Cluster   string
+	Namespace string
+	Kind      string
+}
+
+// SetValidationCounts replaces the validation checks and invalid objects counts, the series not in the given
+// counts are removed, so the metrics always reflect the last validation.
+func SetValidationCounts(checks map[ValidationCheckLabels]int, invalidObjects map[ValidationObjectLabels]int) {
+	Metrics.ValidationChecks.Reset()
+	for l, count := range checks {
+		Metrics.ValidationChecks.With(prometheus.Labels{
+			labelCluster:   l.Cluster,
+			labelNamespace: l.Namespace,
+			labelKind:      l.Kind,
+			labelSeverity:  l.Severity,
+			labelCode:      l.Code,
+		}).Set(float64(count))
+	}
+	Metrics.ValidationInvalidObjects.Reset()
+	for l, count := range invalidObjects {
+		Metrics.ValidationInvalidObjects.With(prometheus.Labels{
+			labelCluster:   l.Cluster,
+			labelNamespace: l.Namespace,
+			labelKind:      l.Kind,
+		}).Set(float64(count))
+	}
+}
+
 // GetSingleValidationProcessingTimePrometheusTimer returns a timer that can be used to store
 // a value for the single validation processing time metric (time to validate a specific
 // Istio object in a specific namespace. The timer is ticking immediately when this function returns.
@@ -324,6 +324,8 @@ func NewRoutes(
 		// swagger:route PATCH /namespaces/{namespace}/istio/{object_type}/{object} config istioConfigUpdate
 		// ---
 		// Endpoint to update the Istio Config of an Istio object used for templates and adapters using Json Merge Patch strategy.
+		// When the dryRun param is true the patch is only admitted by the API server, the response is an istioConfigDryRunResponse
+		// with the diff of the object and its validations.
 		//
 		//     Consumes:
 		//	   - application/json
@@ -346,9 +348,163 @@ func NewRoutes(
 			handlers.IstioConfigUpdate,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/istio/{object_type}/{object}/history config istioConfigHistory
+		// ---
+		// Endpoint to get the revisions of an Istio object recorded by Kiali, most recent first.
+		// The changes done through Kiali are only recorded when the Istio config history is enabled.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: istioConfigHistoryResponse
+		//
+		{
+			"IstioConfigHistory",
+			"GET",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/history",
+			handlers.IstioConfigHistory,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/istio/{object_type}/{object}/history/{revision}/rollback config istioConfigRollback
+		// ---
+		// Endpoint to restore an Istio object to its state after a recorded revision. The object is patched, created again
+		// or deleted as needed. The rollback is recorded as a new revision.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigRevisionResponse
+		//
+		{
+			"IstioConfigRollback",
+			"POST",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/history/{revision}/rollback",
+			handlers.IstioConfigRollback,
+			true,
+		},
+		// swagger:route POST /istio/bulk config istioConfigBulkApply
+		// ---
+		// Endpoint to create the Istio objects of a multi-document YAML in one or more clusters.
+		// All the objects are created in dry-run mode first, nothing is created when one of them is rejected. They are then
+		// created in dependency order, gateways first, and the objects already created are deleted when one of them fails.
+		// When the dryRun param is true the objects are only validated.
+		//
+		//     Consumes:
+		//     - application/yaml
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      422: istioConfigBulkApplyResponse
+		//      500: internalError
+		//      200: istioConfigBulkApplyResponse
+		//
+		{
+			"IstioConfigBulkApply",
+			"POST",
+			"/api/istio/bulk",
+			handlers.IstioConfigBulkApply,
+			true,
+		},
+		// swagger:route POST /istio/fixes config istioCheckFix
+		// ---
+		// Endpoint to preview or apply a fix of a validation check using Json Merge Patch strategy.
+		// The fix is only previewed unless the preview param is false.
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioCheckFixResponse
+		//
+		{
+			"IstioCheckFix",
+			"POST",
+			"/api/istio/fixes",
+			handlers.IstioCheckFix,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/istio/simulate config istioRouteSimulate
+		// ---
+		// Endpoint to simulate the routing of an HTTP request sent from the namespace by the VirtualServices.
+		// It returns the chosen http route and its destinations with their DestinationRule subsets.
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: routeSimulationResponse
+		//
+		{
+			"IstioRouteSimulate",
+			"POST",
+			"/api/namespaces/{namespace}/istio/simulate",
+			handlers.IstioRouteSimulate,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/istio/export config istioConfigExport
+		// ---
+		// Endpoint to export the Istio config of a namespace for a GitOps repository.
+		// It returns an archive, a gzip compressed tar or a zip, with one YAML file per object and a Kustomization file
+		// listing them. The fields maintained by the API server are left out.
+		//
+		//     Produces:
+		//     - application/gzip
+		//     - application/zip
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      500: internalError
+		//      200: istioConfigExportResponse
+		//
+		{
+			"IstioConfigExport",
+			"GET",
+			"/api/namespaces/{namespace}/istio/export",
+			handlers.IstioConfigExport,
+			true,
+		},
 		// swagger:route POST /namespaces/{namespace}/istio/{object_type} config istioConfigCreate
 		// ---
 		// Endpoint to create an Istio object by using an Istio Config item
+		// When the dryRun param is true the object is only admitted by the API server, the response is an istioConfigDryRunResponse
+		// with the object and its validations.
 		//
 		//     Produces:
 		//     - application/json
@@ -435,6 +591,77 @@ func NewRoutes(
 			handlers.ServiceUpdate,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/canary services canaryRolloutStart
+		// ---
+		// Endpoint to start the canary rollout of a service. The traffic is shifted step by step to the canary version
+		// while its error ratio and latency are within the thresholds, and sent back to the stable version otherwise.
+		// Rollouts can only be started when they are enabled in the Kiali configuration.
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: canaryRolloutResponse
+		//
+		{
+			"CanaryRolloutStart",
+			"POST",
+			"/api/namespaces/{namespace}/services/{service}/canary",
+			handlers.CanaryRolloutStart,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/canary services canaryRollout
+		// ---
+		// Endpoint to get the progress of the last canary rollout of a service
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: canaryRolloutResponse
+		//
+		{
+			"CanaryRollout",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/canary",
+			handlers.CanaryRollout,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/canary/rollback services canaryRolloutRollback
+		// ---
+		// Endpoint to abort the progressing canary rollout of a service, sending all the traffic back to the stable version
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: canaryRolloutResponse
+		//
+		{
+			"CanaryRolloutRollback",
+			"POST",
+			"/api/namespaces/{namespace}/services/{service}/canary/rollback",
+			handlers.CanaryRolloutRollback,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/apps/{app}/spans traces appSpans
 		// ---
 		// Endpoint to get Tracing spans for a given app
@@ -663,6 +890,28 @@ func NewRoutes(
 			handlers.WorkloadUpdate,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/authorization workloads workloadAuthorization
+		// ---
+		// Endpoint to simulate a request to the workload and get the verdict of its AuthorizationPolicies
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: authorizationSimulationResponse
+		//
+		{
+			"WorkloadAuthorization",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/authorization",
+			handlers.WorkloadAuthorization,
+			true,
+		},
 		// swagger:route GET /clusters/apps apps appList
 		// ---
 		// Endpoint to get the list of apps for a cluster
@@ -1021,6 +1270,49 @@ func NewRoutes(
 			handlers.ConfigValidationSummary,
 			true,
 		},
+		// swagger:route GET /istio/validations/trend namespaces validationsTrend
+		// ---
+		// Get the validation counts of the background validations over time for the given namespaces.
+		// The counts are kept in memory by the Kiali pod serving the request, since it started.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: validationsTrendResponse
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//
+		{
+			"ValidationsTrend",
+			"GET",
+			"/api/istio/validations/trend",
+			handlers.ValidationsTrend,
+			true,
+		},
+		// swagger:route GET /istio/validations/mesh namespaces meshValidations
+		// ---
+		// Get the validations of the config of all the clusters of the mesh against each other
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: meshValidationsResponse
+		//      500: internalError
+		//
+		{
+			"MeshValidations",
+			"GET",
+			"/api/istio/validations/mesh",
+			handlers.MeshValidations,
+			true,
+		},
 		// swagger:route GET /mesh/tls tls meshTls
 		// ---
 		// Get TLS status for the whole mesh
@@ -1146,6 +1438,137 @@ func NewRoutes(
 			handlers.GraphNamespaces(conf, kialiCache, clientFactory, prom, cpm, traceClientLoader, grafana, discovery),
 			true,
 		},
+		// swagger:route GET /namespaces/graph/diff graphs graphNamespacesDiff
+		// ---
+		// The backing JSON for a namespaces graph comparing the requested time window to a baseline time window.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: graphResponse
+		//
+		{
+			"GraphNamespacesDiff",
+			"GET",
+			"/api/namespaces/graph/diff",
+			handlers.GraphNamespacesDiff(conf, kialiCache, clientFactory, prom, cpm, traceClientLoader, grafana, discovery),
+			true,
+		},
+		// swagger:route GET /namespaces/graph/blast_radius graphs graphNamespacesBlastRadius
+		// ---
+		// The upstream callers and downstream dependencies of a node in the namespaces graph, weighted by traffic, and
+		// the percentage of ingress traffic traversing the node.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: blastRadiusResponse
+		//
+		{
+			"GraphNamespacesBlastRadius",
+			"GET",
+			"/api/namespaces/graph/blast_radius",
+			handlers.GraphNamespacesBlastRadius(conf, kialiCache, clientFactory, prom, cpm, traceClientLoader, grafana, discovery),
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/slo graphs namespaceSLO
+		// ---
+		// The error budget burn rates of the namespace's services and request edges, evaluated against the health
+		// config tolerances over multiple windows. Burning services and edges are listed first.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: sloResponse
+		//
+		{
+			"GraphNamespaceSLO",
+			"GET",
+			"/api/namespaces/{namespace}/slo",
+			handlers.GraphNamespaceSLO(conf, kialiCache, clientFactory, prom, cpm, traceClientLoader, grafana, discovery),
+			true,
+		},
+		// swagger:route GET /namespaces/graph/snapshots graphs graphSnapshots
+		// ---
+		// The stored graph snapshots, most recent first. Only the snapshots whose namespaces are all accessible are listed.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: graphSnapshotsResponse
+		//
+		{
+			"GraphSnapshots",
+			"GET",
+			"/api/namespaces/graph/snapshots",
+			handlers.GraphSnapshots(conf, kialiCache, clientFactory, prom, cpm, traceClientLoader, grafana, discovery),
+			true,
+		},
+		// swagger:route POST /namespaces/graph/snapshots graphs graphSnapshotCreate
+		// ---
+		// Generate a namespaces graph and store it as a snapshot. The snapshot can be served by the namespaces graph
+		// using the 'snapshot' query parameter.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      201: graphSnapshotResponse
+		//
+		{
+			"GraphSnapshotCreate",
+			"POST",
+			"/api/namespaces/graph/snapshots",
+			handlers.GraphSnapshotCreate(conf, kialiCache, clientFactory, prom, cpm, traceClientLoader, grafana, discovery),
+			true,
+		},
+		// swagger:route GET /namespaces/graph/stream graphs graphNamespacesStream
+		// ---
+		// A server-sent event stream for a namespaces graph. The first 'graph' event holds the full graph, subsequent
+		// 'patch' events hold the node and edge changes.
+		//
+		//     Produces:
+		//     - text/event-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200
+		//
+		{
+			"GraphNamespacesStream",
+			"GET",
+			"/api/namespaces/graph/stream",
+			handlers.GraphNamespacesStream(conf, kialiCache, clientFactory, prom, cpm, traceClientLoader, grafana, discovery),
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/aggregates/{aggregate}/{aggregateValue}/graph graphs graphAggregate
 		// ---
 		// The backing JSON for an aggregate node detail graph. (supported graphTypes: app | versionedApp | workload)
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/handlers"
+	"github.com/kiali/kiali/log"
+)
+
+var admissionServer *http.Server
+
+// StartAdmissionServer starts a new HTTPS server for the validating admission webhook of the Istio config
+func StartAdmissionServer(conf *config.Config, layerFactory func() *business.Layer) {
+	webhook := conf.KialiFeatureFlags.Validations.AdmissionWebhook
+	certFile, keyFile := webhook.CertFile, webhook.PrivateKeyFile
+	if certFile == "" || keyFile == "" {
+		certFile, keyFile = conf.Identity.CertFile, conf.Identity.PrivateKeyFile
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate", handlers.AdmissionReview(conf, layerFactory))
+
+	log.Infof("Starting Admission Webhook Server on [%v:%v]", conf.Server.Address, webhook.Port)
+	admissionServer = &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", conf.Server.Address, webhook.Port),
+		Handler:      mux,
+		TLSConfig:    &tls.Config{MinVersion: tls.VersionTLS12},
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+	go func() {
+		log.Warning(admissionServer.ListenAndServeTLS(certFile, keyFile))
+	}()
+}
+
+// StopAdmissionServer stops the admission webhook server
+func StopAdmissionServer() {
+	if admissionServer != nil {
+		log.Info("Stopping Admission Webhook Server")
+		admissionServer.Close()
+		admissionServer = nil
+	}
+}
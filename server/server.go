@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/http"
@@ -14,6 +15,8 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/grafana"
+	"github.com/kiali/kiali/graph/api"
+	"github.com/kiali/kiali/graph/snapshot"
 	"github.com/kiali/kiali/istio"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/cache"
@@ -34,6 +37,9 @@ type Server struct {
 	kialiCache          cache.KialiCache
 	prom                prometheus.ClientInterface
 	router              *mux.Router
+	stopBackgroundVals  context.CancelFunc
+	stopCanaryRollouts  context.CancelFunc
+	stopGraphSnapshots  context.CancelFunc
 	tracer              *sdktrace.TracerProvider
 	traceClientLoader   func() tracing.ClientInterface
 }
@@ -149,11 +155,54 @@ func (s *Server) Start() {
 	if s.conf.Server.Observability.Metrics.Enabled {
 		StartMetricsServer()
 	}
+
+	// Start the periodic graph snapshots, using the Kiali service account
+	if s.conf.GraphSnapshots.Enabled {
+		var ctx context.Context
+		ctx, s.stopGraphSnapshots = context.WithCancel(context.Background())
+		go api.RunGraphSnapshots(ctx, s.conf, s.saLayer)
+	}
+
+	// Start the background validations, using the Kiali service account
+	if s.conf.KialiFeatureFlags.Validations.Background.Enabled {
+		var ctx context.Context
+		ctx, s.stopBackgroundVals = context.WithCancel(context.Background())
+		go business.RunBackgroundValidations(ctx, s.conf, s.saLayer)
+	}
+
+	// Start the progression of the canary rollouts, using the Kiali service account
+	if s.conf.CanaryRollouts.Enabled {
+		var ctx context.Context
+		ctx, s.stopCanaryRollouts = context.WithCancel(context.Background())
+		go business.RunCanaryRollouts(ctx, s.conf, s.saLayer)
+	}
+
+	// Start the admission webhook, the API server calls it so it also uses the Kiali service account
+	if s.conf.KialiFeatureFlags.Validations.AdmissionWebhook.Enabled {
+		StartAdmissionServer(s.conf, s.saLayer)
+	}
+}
+
+// saLayer returns a business layer using the Kiali service account for the background and webhook requests
+func (s *Server) saLayer() *business.Layer {
+	saClients := s.clientFactory.GetSAClients()
+	return business.NewWithBackends(saClients, saClients, s.prom, nil)
 }
 
 // Stop the HTTP server
 func (s *Server) Stop() {
 	StopMetricsServer()
+	StopAdmissionServer()
+	if s.stopBackgroundVals != nil {
+		s.stopBackgroundVals()
+	}
+	if s.stopCanaryRollouts != nil {
+		s.stopCanaryRollouts()
+	}
+	if s.stopGraphSnapshots != nil {
+		s.stopGraphSnapshots()
+	}
+	snapshot.CloseDefaultStore()
 	log.Infof("Server endpoint will stop at [%v]", s.httpServer.Addr)
 	s.httpServer.Close()
 	observability.StopTracer(s.tracer)
@@ -3,6 +3,7 @@ package data
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	k8s_networking_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	k8s_networking_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/kiali/kiali/kubernetes"
@@ -126,6 +127,64 @@ func AddBackendRefToGRPCRoute(name, namespace string, rt *k8s_networking_v1.GRPC
 	return rt
 }
 
+func CreateTCPRoute(name string, namespace string, gateway string) *k8s_networking_v1alpha2.TCPRoute {
+	rt := k8s_networking_v1alpha2.TCPRoute{}
+	rt.Name = name
+	rt.Namespace = namespace
+	rt.Spec.ParentRefs = append(rt.Spec.ParentRefs, createGatewayParentRef(gateway, namespace))
+	return &rt
+}
+
+func AddBackendRefToTCPRoute(name, namespace string, port int, rt *k8s_networking_v1alpha2.TCPRoute) *k8s_networking_v1alpha2.TCPRoute {
+	rt.Spec.Rules = append(rt.Spec.Rules, k8s_networking_v1alpha2.TCPRouteRule{
+		BackendRefs: []k8s_networking_v1.BackendRef{createServiceBackendRef(name, namespace, port)},
+	})
+	return rt
+}
+
+func CreateTLSRoute(name string, namespace string, gateway string, hosts []string) *k8s_networking_v1alpha2.TLSRoute {
+	rt := k8s_networking_v1alpha2.TLSRoute{}
+	rt.Name = name
+	rt.Namespace = namespace
+	for _, host := range hosts {
+		rt.Spec.Hostnames = append(rt.Spec.Hostnames, k8s_networking_v1.Hostname(host))
+	}
+	rt.Spec.ParentRefs = append(rt.Spec.ParentRefs, createGatewayParentRef(gateway, namespace))
+	return &rt
+}
+
+func AddBackendRefToTLSRoute(name, namespace string, port int, rt *k8s_networking_v1alpha2.TLSRoute) *k8s_networking_v1alpha2.TLSRoute {
+	rt.Spec.Rules = append(rt.Spec.Rules, k8s_networking_v1alpha2.TLSRouteRule{
+		BackendRefs: []k8s_networking_v1.BackendRef{createServiceBackendRef(name, namespace, port)},
+	})
+	return rt
+}
+
+func createGatewayParentRef(name, namespace string) k8s_networking_v1.ParentReference {
+	ns := k8s_networking_v1.Namespace(namespace)
+	group := k8s_networking_v1.Group(kubernetes.K8sNetworkingGroupVersionV1.Group)
+	kind := k8s_networking_v1.Kind(kubernetes.K8sActualGatewayType)
+	return k8s_networking_v1.ParentReference{
+		Name:      k8s_networking_v1.ObjectName(name),
+		Namespace: &ns,
+		Group:     &group,
+		Kind:      &kind}
+}
+
+func createServiceBackendRef(name, namespace string, port int) k8s_networking_v1.BackendRef {
+	kind := k8s_networking_v1.Kind(kubernetes.ServiceType)
+	ns := k8s_networking_v1.Namespace(namespace)
+	portNumber := k8s_networking_v1.PortNumber(port)
+	return k8s_networking_v1.BackendRef{
+		BackendObjectReference: k8s_networking_v1.BackendObjectReference{
+			Kind:      &kind,
+			Name:      k8s_networking_v1.ObjectName(name),
+			Namespace: &ns,
+			Port:      &portNumber,
+		},
+	}
+}
+
 func CreateEmptyK8sGateway(name, namespace string) *k8s_networking_v1.Gateway {
 	gw := k8s_networking_v1.Gateway{}
 	gw.Name = name
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/tools/cmd"
+	"github.com/kiali/kiali/tools/validator"
+)
+
+const (
+	exitValidationErrors = 1
+	exitFailure          = 2
+)
+
+var (
+	ambientFlag            bool
+	autoMtlsFlag           bool
+	clusterFlag            string
+	failOnWarningFlag      bool
+	gatewayToNamespaceFlag bool
+	namespaceFlag          string
+	outputFlag             string
+	policyAllowAnyFlag     bool
+	proxyVersionFlag       string
+)
+
+func init() {
+	flag.BoolVar(&ambientFlag, "ambient", false, "the mesh has Ambient enabled, ambient gateway classes are accepted")
+	flag.BoolVar(&autoMtlsFlag, "auto-mtls", true, "the mesh config enableAutoMtls value")
+	flag.StringVar(&clusterFlag, "cluster", config.DefaultClusterID, "cluster name of the validated objects")
+	flag.BoolVar(&failOnWarningFlag, "fail-on-warning", false, "exit with a non-zero code on warnings too")
+	flag.BoolVar(&gatewayToNamespaceFlag, "gateway-to-namespace", false, "the PILOT_SCOPE_GATEWAY_TO_NAMESPACE control plane setting")
+	flag.StringVar(&namespaceFlag, "namespace", "default", "namespace of the objects that don't set one")
+	flag.StringVar(&outputFlag, "output", validator.FormatText, fmt.Sprintf("output format, one of %v", validator.Formats))
+	flag.BoolVar(&policyAllowAnyFlag, "policy-allow-any", true, "the mesh outbound traffic policy mode is ALLOW_ANY")
+	flag.StringVar(&proxyVersionFlag, "proxy-version", "", "the Istio version of the proxies, EnvoyFilter proxy versions are checked against it when set")
+}
+
+func main() {
+	flag.Usage = cmd.Usage("validate", "<file-or-dir>...")
+	flag.Parse()
+	cmd.ConfigureKialiLogger()
+	// the validations read Istio defaults, like the identity domain, from the config
+	config.Set(config.NewConfig())
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(exitFailure)
+	}
+	if !slices.Contains(validator.Formats, outputFlag) {
+		log.Errorf("Unsupported output format [%s], expected one of %v", outputFlag, validator.Formats)
+		os.Exit(exitFailure)
+	}
+
+	manifests := validator.NewManifests(namespaceFlag)
+	if err := manifests.LoadPaths(flag.Args()); err != nil {
+		log.Errorf("Unable to load manifests: %s", err)
+		os.Exit(exitFailure)
+	}
+	for _, skipped := range manifests.Skipped {
+		log.Debugf("Skipping unsupported kind %s", skipped)
+	}
+
+	var proxyVersions []string
+	if proxyVersionFlag != "" {
+		proxyVersions = []string{proxyVersionFlag}
+	}
+
+	validations := validator.Validate(manifests, validator.Options{
+		AmbientEnabled:     ambientFlag,
+		Cluster:            clusterFlag,
+		EnabledAutoMtls:    autoMtlsFlag,
+		GatewayToNamespace: gatewayToNamespaceFlag,
+		PolicyAllowAny:     policyAllowAnyFlag,
+		ProxyVersions:      proxyVersions,
+	})
+
+	if err := validator.Write(os.Stdout, outputFlag, validations, manifests); err != nil {
+		log.Errorf("Unable to write validations: %s", err)
+		os.Exit(exitFailure)
+	}
+
+	if validator.HasErrors(validations) || (failOnWarningFlag && validator.HasWarnings(validations)) {
+		os.Exit(exitValidationErrors)
+	}
+}
@@ -0,0 +1,401 @@
+// Package validator runs the Kiali Istio config validations against manifests read from files, without a cluster.
+package validator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	extentions_v1alpha1 "istio.io/client-go/pkg/apis/extensions/v1alpha1"
+	networking_v1 "istio.io/client-go/pkg/apis/networking/v1"
+	networking_v1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	security_v1 "istio.io/client-go/pkg/apis/security/v1"
+	telemetry_v1 "istio.io/client-go/pkg/apis/telemetry/v1"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	k8s_networking_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	k8s_networking_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	k8s_networking_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kiali/kiali/models"
+)
+
+// Source is where a manifest was read from
+type Source struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// ObjectKey identifies a manifest the way validations identify objects, by checker type, namespace and name
+type ObjectKey struct {
+	ObjectType string
+	Namespace  string
+	Name       string
+}
+
+// Manifests holds the objects read from files. Objects without a namespace are placed in DefaultNamespace.
+type Manifests struct {
+	DefaultNamespace string
+
+	IstioConfigList models.IstioConfigList
+	Namespaces      []core_v1.Namespace
+	Services        []core_v1.Service
+	Workloads       []*models.Workload
+
+	// Skipped lists the documents of a kind not used by the validations
+	Skipped []string
+	// Sources maps each loaded object to where it was read from
+	Sources map[ObjectKey]Source
+}
+
+// NewManifests returns empty Manifests, objects without a namespace go to the given namespace
+func NewManifests(defaultNamespace string) *Manifests {
+	return &Manifests{
+		DefaultNamespace: defaultNamespace,
+		Sources:          map[ObjectKey]Source{},
+	}
+}
+
+// LoadPaths loads every file of the given paths, directories are walked for .yaml, .yml and .json files.
+func (m *Manifests) LoadPaths(paths []string) error {
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			// explicitly requested files are always loaded, whatever their extension
+			if path != p {
+				switch strings.ToLower(filepath.Ext(path)) {
+				case ".yaml", ".yml", ".json":
+				default:
+					return nil
+				}
+			}
+			return m.LoadFile(path)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFile loads all the documents of a YAML or JSON file
+func (m *Manifests) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return m.Load(path, f)
+}
+
+// Load loads all the documents read from r. The file name is only used to report where objects come from.
+func (m *Manifests) Load(file string, r io.Reader) error {
+	docs, err := splitDocuments(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	for _, doc := range docs {
+		if err := m.loadDocument(Source{File: file, Line: doc.line}, doc.content); err != nil {
+			return fmt.Errorf("%s:%d: %w", file, doc.line, err)
+		}
+	}
+	return nil
+}
+
+type document struct {
+	line    int
+	content []byte
+}
+
+// splitDocuments splits a multi-document YAML stream, keeping the line where each document starts
+func splitDocuments(r io.Reader) ([]document, error) {
+	docs := []document{}
+	current := document{line: 1}
+	lineNumber := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if line == "---" || strings.HasPrefix(line, "--- ") {
+			docs = append(docs, current)
+			current = document{line: lineNumber + 1}
+			continue
+		}
+		current.content = append(current.content, line...)
+		current.content = append(current.content, '\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return append(docs, current), nil
+}
+
+func (m *Manifests) loadDocument(source Source, content []byte) error {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil
+	}
+	bValue, err := yaml.ToJSON(content)
+	if err != nil {
+		return err
+	}
+	if string(bValue) == "null" {
+		// only comments
+		return nil
+	}
+
+	typeMeta := meta_v1.TypeMeta{}
+	if err := json.Unmarshal(bValue, &typeMeta); err != nil {
+		return err
+	}
+	if typeMeta.Kind == "" {
+		return fmt.Errorf("missing kind")
+	}
+	gv, err := schema.ParseGroupVersion(typeMeta.APIVersion)
+	if err != nil {
+		return err
+	}
+
+	var object meta_v1.Object
+	objectType := strings.ToLower(typeMeta.Kind)
+	switch gv.Group + "/" + typeMeta.Kind {
+	case "networking.istio.io/DestinationRule":
+		dr := &networking_v1.DestinationRule{}
+		err = json.Unmarshal(bValue, dr)
+		m.IstioConfigList.DestinationRules = append(m.IstioConfigList.DestinationRules, dr)
+		object = dr
+	case "networking.istio.io/EnvoyFilter":
+		ef := &networking_v1alpha3.EnvoyFilter{}
+		err = json.Unmarshal(bValue, ef)
+		m.IstioConfigList.EnvoyFilters = append(m.IstioConfigList.EnvoyFilters, ef)
+		object = ef
+	case "networking.istio.io/Gateway":
+		gw := &networking_v1.Gateway{}
+		err = json.Unmarshal(bValue, gw)
+		m.IstioConfigList.Gateways = append(m.IstioConfigList.Gateways, gw)
+		object = gw
+	case "networking.istio.io/ServiceEntry":
+		se := &networking_v1.ServiceEntry{}
+		err = json.Unmarshal(bValue, se)
+		m.IstioConfigList.ServiceEntries = append(m.IstioConfigList.ServiceEntries, se)
+		object = se
+	case "networking.istio.io/Sidecar":
+		sc := &networking_v1.Sidecar{}
+		err = json.Unmarshal(bValue, sc)
+		m.IstioConfigList.Sidecars = append(m.IstioConfigList.Sidecars, sc)
+		object = sc
+	case "networking.istio.io/VirtualService":
+		vs := &networking_v1.VirtualService{}
+		err = json.Unmarshal(bValue, vs)
+		m.IstioConfigList.VirtualServices = append(m.IstioConfigList.VirtualServices, vs)
+		object = vs
+	case "networking.istio.io/WorkloadEntry":
+		we := &networking_v1.WorkloadEntry{}
+		err = json.Unmarshal(bValue, we)
+		m.IstioConfigList.WorkloadEntries = append(m.IstioConfigList.WorkloadEntries, we)
+		object = we
+	case "networking.istio.io/WorkloadGroup":
+		wg := &networking_v1.WorkloadGroup{}
+		err = json.Unmarshal(bValue, wg)
+		m.IstioConfigList.WorkloadGroups = append(m.IstioConfigList.WorkloadGroups, wg)
+		object = wg
+	case "security.istio.io/AuthorizationPolicy":
+		ap := &security_v1.AuthorizationPolicy{}
+		err = json.Unmarshal(bValue, ap)
+		m.IstioConfigList.AuthorizationPolicies = append(m.IstioConfigList.AuthorizationPolicies, ap)
+		object = ap
+	case "security.istio.io/PeerAuthentication":
+		pa := &security_v1.PeerAuthentication{}
+		err = json.Unmarshal(bValue, pa)
+		m.IstioConfigList.PeerAuthentications = append(m.IstioConfigList.PeerAuthentications, pa)
+		object = pa
+	case "security.istio.io/RequestAuthentication":
+		ra := &security_v1.RequestAuthentication{}
+		err = json.Unmarshal(bValue, ra)
+		m.IstioConfigList.RequestAuthentications = append(m.IstioConfigList.RequestAuthentications, ra)
+		object = ra
+	case "telemetry.istio.io/Telemetry":
+		tm := &telemetry_v1.Telemetry{}
+		err = json.Unmarshal(bValue, tm)
+		m.IstioConfigList.Telemetries = append(m.IstioConfigList.Telemetries, tm)
+		object = tm
+	case "extensions.istio.io/WasmPlugin":
+		wp := &extentions_v1alpha1.WasmPlugin{}
+		err = json.Unmarshal(bValue, wp)
+		m.IstioConfigList.WasmPlugins = append(m.IstioConfigList.WasmPlugins, wp)
+		object = wp
+	case "gateway.networking.k8s.io/Gateway":
+		gw := &k8s_networking_v1.Gateway{}
+		err = json.Unmarshal(bValue, gw)
+		m.IstioConfigList.K8sGateways = append(m.IstioConfigList.K8sGateways, gw)
+		object, objectType = gw, "k8sgateway"
+	case "gateway.networking.k8s.io/GRPCRoute":
+		route := &k8s_networking_v1.GRPCRoute{}
+		err = json.Unmarshal(bValue, route)
+		defaultParentRefs(route.Spec.ParentRefs)
+		for _, rule := range route.Spec.Rules {
+			for i := range rule.BackendRefs {
+				defaultBackendRef(&rule.BackendRefs[i].BackendRef)
+			}
+		}
+		m.IstioConfigList.K8sGRPCRoutes = append(m.IstioConfigList.K8sGRPCRoutes, route)
+		object, objectType = route, "k8sgrpcroute"
+	case "gateway.networking.k8s.io/HTTPRoute":
+		route := &k8s_networking_v1.HTTPRoute{}
+		err = json.Unmarshal(bValue, route)
+		defaultParentRefs(route.Spec.ParentRefs)
+		for _, rule := range route.Spec.Rules {
+			for i := range rule.BackendRefs {
+				defaultBackendRef(&rule.BackendRefs[i].BackendRef)
+			}
+		}
+		m.IstioConfigList.K8sHTTPRoutes = append(m.IstioConfigList.K8sHTTPRoutes, route)
+		object, objectType = route, "k8shttproute"
+	case "gateway.networking.k8s.io/ReferenceGrant":
+		rg := &k8s_networking_v1beta1.ReferenceGrant{}
+		err = json.Unmarshal(bValue, rg)
+		m.IstioConfigList.K8sReferenceGrants = append(m.IstioConfigList.K8sReferenceGrants, rg)
+		object, objectType = rg, "k8sreferencegrant"
+	case "gateway.networking.k8s.io/TCPRoute":
+		route := &k8s_networking_v1alpha2.TCPRoute{}
+		err = json.Unmarshal(bValue, route)
+		m.IstioConfigList.K8sTCPRoutes = append(m.IstioConfigList.K8sTCPRoutes, route)
+		object, objectType = route, "k8stcproute"
+	case "gateway.networking.k8s.io/TLSRoute":
+		route := &k8s_networking_v1alpha2.TLSRoute{}
+		err = json.Unmarshal(bValue, route)
+		m.IstioConfigList.K8sTLSRoutes = append(m.IstioConfigList.K8sTLSRoutes, route)
+		object, objectType = route, "k8stlsroute"
+	case "/Namespace":
+		ns := core_v1.Namespace{}
+		if err := json.Unmarshal(bValue, &ns); err != nil {
+			return err
+		}
+		m.Namespaces = append(m.Namespaces, ns)
+		m.Sources[ObjectKey{ObjectType: "namespace", Name: ns.Name}] = source
+		return nil
+	case "/Service":
+		svc := core_v1.Service{}
+		if err := json.Unmarshal(bValue, &svc); err != nil {
+			return err
+		}
+		m.setNamespace(&svc)
+		m.Services = append(m.Services, svc)
+		m.Sources[ObjectKey{ObjectType: "service", Namespace: svc.Namespace, Name: svc.Name}] = source
+		return nil
+	case "apps/Deployment", "apps/StatefulSet", "apps/DaemonSet", "apps/ReplicaSet":
+		workload, err := parseWorkload(typeMeta.Kind, bValue)
+		if err != nil {
+			return err
+		}
+		if workload.Namespace == "" {
+			workload.Namespace = m.DefaultNamespace
+		}
+		m.Workloads = append(m.Workloads, workload)
+		m.Sources[ObjectKey{ObjectType: "workload", Namespace: workload.Namespace, Name: workload.Name}] = source
+		return nil
+	default:
+		m.Skipped = append(m.Skipped, fmt.Sprintf("%s:%d: %s", source.File, source.Line, typeMeta.GroupVersionKind()))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	m.setNamespace(object)
+	m.Sources[ObjectKey{ObjectType: objectType, Namespace: object.GetNamespace(), Name: object.GetName()}] = source
+	return nil
+}
+
+func (m *Manifests) setNamespace(object meta_v1.Object) {
+	if object.GetNamespace() == "" {
+		object.SetNamespace(m.DefaultNamespace)
+	}
+}
+
+// defaultParentRefs sets the defaults the API server sets from the Gateway API CRD schemas
+func defaultParentRefs(refs []k8s_networking_v1.ParentReference) {
+	for i := range refs {
+		if refs[i].Group == nil {
+			group := k8s_networking_v1.Group(k8s_networking_v1.GroupName)
+			refs[i].Group = &group
+		}
+		if refs[i].Kind == nil {
+			kind := k8s_networking_v1.Kind("Gateway")
+			refs[i].Kind = &kind
+		}
+	}
+}
+
+// defaultBackendRef sets the defaults the API server sets from the Gateway API CRD schemas
+func defaultBackendRef(ref *k8s_networking_v1.BackendRef) {
+	if ref.Group == nil {
+		group := k8s_networking_v1.Group("")
+		ref.Group = &group
+	}
+	if ref.Kind == nil {
+		kind := k8s_networking_v1.Kind("Service")
+		ref.Kind = &kind
+	}
+}
+
+// parseWorkload parses a workload controller, there are no pods so the service accounts come from the pod template
+func parseWorkload(kind string, bValue []byte) (*models.Workload, error) {
+	var meta meta_v1.ObjectMeta
+	var podSpec core_v1.PodSpec
+	workload := &models.Workload{}
+
+	switch kind {
+	case "Deployment":
+		d := apps_v1.Deployment{}
+		if err := json.Unmarshal(bValue, &d); err != nil {
+			return nil, err
+		}
+		workload.ParseDeployment(&d)
+		meta, podSpec = d.ObjectMeta, d.Spec.Template.Spec
+	case "StatefulSet":
+		s := apps_v1.StatefulSet{}
+		if err := json.Unmarshal(bValue, &s); err != nil {
+			return nil, err
+		}
+		workload.ParseStatefulSet(&s)
+		meta, podSpec = s.ObjectMeta, s.Spec.Template.Spec
+	case "DaemonSet":
+		ds := apps_v1.DaemonSet{}
+		if err := json.Unmarshal(bValue, &ds); err != nil {
+			return nil, err
+		}
+		workload.ParseDaemonSet(&ds)
+		meta, podSpec = ds.ObjectMeta, ds.Spec.Template.Spec
+	case "ReplicaSet":
+		rs := apps_v1.ReplicaSet{}
+		if err := json.Unmarshal(bValue, &rs); err != nil {
+			return nil, err
+		}
+		workload.ParseReplicaSet(&rs)
+		meta, podSpec = rs.ObjectMeta, rs.Spec.Template.Spec
+	}
+
+	workload.Namespace = meta.Namespace
+	serviceAccount := podSpec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	workload.ServiceAccountNames = []string{serviceAccount}
+	return workload, nil
+}
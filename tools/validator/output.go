@@ -0,0 +1,256 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+	FormatText  = "text"
+)
+
+// Formats are the supported output formats
+var Formats = []string{FormatText, FormatJSON, FormatSARIF}
+
+// Write writes the validations in the given format
+func Write(w io.Writer, format string, validations models.IstioValidations, m *Manifests) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, validations)
+	case FormatSARIF:
+		return WriteSARIF(w, validations, m)
+	case FormatText:
+		return WriteText(w, validations, m)
+	default:
+		return fmt.Errorf("unsupported output format [%s], expected one of %v", format, Formats)
+	}
+}
+
+// HasErrors returns true when any check has error severity
+func HasErrors(validations models.IstioValidations) bool {
+	return hasSeverity(validations, models.ErrorSeverity)
+}
+
+// HasWarnings returns true when any check has warning severity
+func HasWarnings(validations models.IstioValidations) bool {
+	return hasSeverity(validations, models.WarningSeverity)
+}
+
+func hasSeverity(validations models.IstioValidations, severity models.SeverityLevel) bool {
+	for _, v := range validations {
+		for _, c := range v.Checks {
+			if c.Severity == severity {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the validation keys of objects with checks, ordered by type, namespace and name
+func sortedKeys(validations models.IstioValidations) []models.IstioValidationKey {
+	keys := make([]models.IstioValidationKey, 0, len(validations))
+	for k, v := range validations {
+		if len(v.Checks) > 0 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ObjectType != keys[j].ObjectType {
+			return keys[i].ObjectType < keys[j].ObjectType
+		}
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	return keys
+}
+
+func (m *Manifests) source(key models.IstioValidationKey) (Source, bool) {
+	if m == nil {
+		return Source{}, false
+	}
+	source, ok := m.Sources[ObjectKey{ObjectType: key.ObjectType, Namespace: key.Namespace, Name: key.Name}]
+	return source, ok
+}
+
+// WriteText writes one line per check, prefixed with the file and line of the object when known, and a summary
+func WriteText(w io.Writer, validations models.IstioValidations, m *Manifests) error {
+	errors, warnings := 0, 0
+	for _, key := range sortedKeys(validations) {
+		prefix := ""
+		if source, ok := m.source(key); ok {
+			prefix = fmt.Sprintf("%s:%d: ", source.File, source.Line)
+		}
+		for _, c := range validations[key].Checks {
+			switch c.Severity {
+			case models.ErrorSeverity:
+				errors++
+			case models.WarningSeverity:
+				warnings++
+			}
+			path := ""
+			if c.Path != "" {
+				path = fmt.Sprintf(" [%s]", c.Path)
+			}
+			if _, err := fmt.Fprintf(w, "%s%s %s/%s: %s %s%s\n", prefix, key.ObjectType, key.Namespace, key.Name, c.Severity, c.GetFullMessage(), path); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d error(s), %d warning(s) in %d object(s)\n", errors, warnings, len(validations))
+	return err
+}
+
+// WriteJSON writes the validations as returned by the Kiali API
+func WriteJSON(w io.Writer, validations models.IstioValidations) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(validations)
+}
+
+// SARIF 2.1.0, only the properties used to report the checks
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func sarifLevel(severity models.SeverityLevel) string {
+	switch severity {
+	case models.ErrorSeverity:
+		return "error"
+	case models.WarningSeverity:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// WriteSARIF writes the validations as a SARIF log, one result per check with one rule per check code
+func WriteSARIF(w io.Writer, validations models.IstioValidations, m *Manifests) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "kiali",
+				InformationURI: "https://kiali.io",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	rules := map[string]bool{}
+	for _, key := range sortedKeys(validations) {
+		location := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{
+				FullyQualifiedName: fmt.Sprintf("%s/%s/%s", key.ObjectType, key.Namespace, key.Name),
+				Kind:               "object",
+			}},
+		}
+		if source, ok := m.source(key); ok {
+			location.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: source.File},
+				Region:           sarifRegion{StartLine: source.Line},
+			}
+		}
+
+		for _, c := range validations[key].Checks {
+			if !rules[c.Code] {
+				rules[c.Code] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+					ID:               c.Code,
+					ShortDescription: sarifMessage{Text: c.Message},
+					HelpURI:          "https://kiali.io/docs/features/validations/#" + strings.ToLower(c.Code),
+				})
+			}
+			message := c.Message
+			if c.Path != "" {
+				message = fmt.Sprintf("%s (%s)", c.Message, c.Path)
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    c.Code,
+				Level:     sarifLevel(c.Severity),
+				Message:   sarifMessage{Text: message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
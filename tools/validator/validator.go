@@ -0,0 +1,119 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// Options are the mesh settings that Kiali otherwise reads from the control plane
+type Options struct {
+	// AmbientEnabled adds the ambient Gateway API classes to the known classes
+	AmbientEnabled bool
+	// Cluster is the cluster name set on the validations
+	Cluster string
+	// EnabledAutoMtls is the mesh config enableAutoMtls
+	EnabledAutoMtls bool
+	// GatewayToNamespace is the PILOT_SCOPE_GATEWAY_TO_NAMESPACE setting
+	GatewayToNamespace bool
+	// PolicyAllowAny is true when the outbound traffic policy mode is ALLOW_ANY
+	PolicyAllowAny bool
+	// ProxyVersions are the Istio versions of the proxies, the EnvoyFilter proxy versions are not checked when empty
+	ProxyVersions []string
+}
+
+// DefaultOptions returns the options matching a default Istio installation
+func DefaultOptions() Options {
+	return Options{
+		Cluster:         config.DefaultClusterID,
+		EnabledAutoMtls: true,
+		PolicyAllowAny:  true,
+	}
+}
+
+// Validate runs the same checkers used to validate the config of a cluster against the manifests
+func Validate(m *Manifests, opts Options) models.IstioValidations {
+	conf := config.Get()
+
+	namespaces := m.namespaces(opts.Cluster)
+	workloadsPerNamespace := map[string]models.WorkloadList{}
+	for _, ns := range namespaces {
+		workloadsPerNamespace[ns.Name] = models.WorkloadList{Namespace: ns.Name, Workloads: []models.WorkloadListItem{}}
+	}
+	serviceAccounts := map[string][]string{opts.Cluster: {}}
+	istioDomain := conf.ExternalServices.Istio.IstioIdentityDomain
+	saDomain := strings.Replace(istioDomain, "svc.", "", 1)
+	for _, w := range m.Workloads {
+		item := models.WorkloadListItem{}
+		item.ParseWorkload(w)
+		item.Cluster = opts.Cluster
+		item.ServiceAccountNames = w.ServiceAccountNames
+		workloadList := workloadsPerNamespace[w.Namespace]
+		workloadList.Workloads = append(workloadList.Workloads, item)
+		workloadsPerNamespace[w.Namespace] = workloadList
+
+		for _, sa := range w.ServiceAccountNames {
+			serviceAccounts[opts.Cluster] = append(serviceAccounts[opts.Cluster], fmt.Sprintf("%s/ns/%s/sa/%s", saDomain, w.Namespace, sa))
+		}
+	}
+
+	registryServices := make([]*kubernetes.RegistryService, 0, len(m.Services))
+	for _, svc := range m.Services {
+		rSvc := &kubernetes.RegistryService{}
+		rSvc.Hostname = fmt.Sprintf("%s.%s.%s", svc.Name, svc.Namespace, istioDomain)
+		rSvc.Attributes.ServiceRegistry = "Kubernetes"
+		rSvc.Attributes.Name = svc.Name
+		rSvc.Attributes.Namespace = svc.Namespace
+		rSvc.Attributes.Labels = svc.Labels
+		rSvc.Attributes.LabelSelectors = svc.Spec.Selector
+		registryServices = append(registryServices, rSvc)
+	}
+
+	istioConfigList := m.IstioConfigList
+	mtlsDetails := kubernetes.MTLSDetails{
+		DestinationRules:    istioConfigList.DestinationRules,
+		PeerAuthentications: istioConfigList.PeerAuthentications,
+		EnabledAutoMtls:     opts.EnabledAutoMtls,
+	}
+	for _, pa := range istioConfigList.PeerAuthentications {
+		if pa.Namespace == conf.ExternalServices.Istio.RootNamespace {
+			mtlsDetails.MeshPeerAuthentications = append(mtlsDetails.MeshPeerAuthentications, pa)
+		}
+	}
+	rbacDetails := kubernetes.RBACDetails{AuthorizationPolicies: istioConfigList.AuthorizationPolicies}
+
+	objectCheckers := business.AllObjectCheckers(istioConfigList, workloadsPerNamespace, mtlsDetails, rbacDetails, namespaces, registryServices, opts.Cluster, serviceAccounts, opts.PolicyAllowAny, opts.GatewayToNamespace, kubernetes.GatewayAPIClasses(opts.AmbientEnabled), opts.ProxyVersions)
+	return business.RunObjectCheckers(objectCheckers)
+}
+
+// namespaces returns the Namespace manifests plus the namespaces of all the other manifests, sorted by name
+func (m *Manifests) namespaces(cluster string) models.Namespaces {
+	byName := map[string]models.Namespace{}
+	add := func(name string) {
+		if _, ok := byName[name]; !ok && name != "" {
+			byName[name] = models.Namespace{Name: name, Cluster: cluster, Labels: map[string]string{}, Annotations: map[string]string{}}
+		}
+	}
+	for _, ns := range m.Namespaces {
+		byName[ns.Name] = models.Namespace{Name: ns.Name, Cluster: cluster, Labels: ns.Labels, Annotations: ns.Annotations}
+	}
+	// the root namespace always exists
+	add(config.Get().ExternalServices.Istio.RootNamespace)
+	for key := range m.Sources {
+		add(key.Namespace)
+	}
+
+	namespaces := make(models.Namespaces, 0, len(byName))
+	for _, ns := range byName {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces
+}
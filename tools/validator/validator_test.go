@@ -0,0 +1,190 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+const bookinfoManifests = `apiVersion: v1
+kind: Service
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  selector:
+    app: reviews
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: reviews-v1
+  namespace: bookinfo
+spec:
+  template:
+    metadata:
+      labels:
+        app: reviews
+        version: v1
+    spec:
+      serviceAccountName: bookinfo-reviews
+---
+# only a comment
+---
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: reviews
+spec:
+  hosts:
+  - reviews
+  http:
+  - route:
+    - destination:
+        host: reviews
+        subset: v1
+    - destination:
+        host: ratings
+---
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  parentRefs:
+  - name: gateway
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: gateway
+  namespace: bookinfo
+spec:
+  gatewayClassName: istio
+  listeners:
+  - name: http
+    port: 80
+    protocol: HTTP
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`
+
+func loadBookinfo(t *testing.T) *Manifests {
+	m := NewManifests("bookinfo")
+	require.NoError(t, m.Load("bookinfo.yaml", strings.NewReader(bookinfoManifests)))
+	return m
+}
+
+func TestLoad(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	m := loadBookinfo(t)
+	require.Len(t, m.IstioConfigList.VirtualServices, 1)
+	assert.Equal("bookinfo", m.IstioConfigList.VirtualServices[0].Namespace, "default namespace")
+	assert.Len(m.IstioConfigList.DestinationRules, 1)
+	assert.Len(m.IstioConfigList.K8sHTTPRoutes, 1)
+	assert.Len(m.IstioConfigList.K8sGateways, 1)
+	assert.Len(m.Services, 1)
+	require.Len(t, m.Workloads, 1)
+	assert.Equal("bookinfo", m.Workloads[0].Namespace)
+	assert.Equal([]string{"bookinfo-reviews"}, m.Workloads[0].ServiceAccountNames)
+	assert.Equal("v1", m.Workloads[0].Labels["version"])
+	assert.Equal([]string{"bookinfo.yaml:74: /v1, Kind=ConfigMap"}, m.Skipped)
+
+	assert.Equal(Source{File: "bookinfo.yaml", Line: 1}, m.Sources[ObjectKey{ObjectType: "service", Namespace: "bookinfo", Name: "reviews"}])
+	assert.Equal(Source{File: "bookinfo.yaml", Line: 10}, m.Sources[ObjectKey{ObjectType: "workload", Namespace: "bookinfo", Name: "reviews-v1"}])
+	assert.Equal(Source{File: "bookinfo.yaml", Line: 26}, m.Sources[ObjectKey{ObjectType: "virtualservice", Namespace: "bookinfo", Name: "reviews"}])
+	assert.Equal(Source{File: "bookinfo.yaml", Line: 53}, m.Sources[ObjectKey{ObjectType: "k8shttproute", Namespace: "bookinfo", Name: "reviews"}])
+
+	err := NewManifests("default").Load("bad.yaml", strings.NewReader("metadata:\n  name: foo\n"))
+	assert.ErrorContains(err, "bad.yaml:1: missing kind")
+}
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	m := loadBookinfo(t)
+	opts := DefaultOptions()
+	validations := Validate(m, opts)
+
+	vs := validations[models.BuildKey("virtualservice", "reviews", "bookinfo", opts.Cluster)]
+	require.NotNil(t, vs)
+	require.Len(t, vs.Checks, 1, "the reviews service and subset are found")
+	assert.Equal("KIA1101", vs.Checks[0].Code)
+	assert.Equal("spec/http[0]/route[1]/destination/host", vs.Checks[0].Path)
+	assert.Equal(models.WarningSeverity, vs.Checks[0].Severity)
+	assert.False(HasErrors(validations))
+	assert.True(HasWarnings(validations))
+
+	// without ALLOW_ANY an unknown host is an error
+	opts.PolicyAllowAny = false
+	validations = Validate(m, opts)
+	assert.True(HasErrors(validations))
+}
+
+func TestWrite(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	m := loadBookinfo(t)
+	opts := DefaultOptions()
+	opts.PolicyAllowAny = false
+	validations := Validate(m, opts)
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, FormatText, validations, m))
+	assert.Contains(out.String(), "bookinfo.yaml:26: virtualservice bookinfo/reviews: error KIA1101 DestinationWeight on route doesn't have a valid service (host not found) [spec/http[0]/route[1]/destination/host]\n")
+	assert.Contains(out.String(), "1 error(s)")
+
+	out.Reset()
+	require.NoError(t, Write(&out, FormatJSON, validations, m))
+	parsed := map[string]map[string]*models.IstioValidation{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &parsed))
+	assert.Equal("KIA1101", parsed["virtualservice"]["reviews.bookinfo"].Checks[0].Code)
+
+	out.Reset()
+	require.NoError(t, Write(&out, FormatSARIF, validations, m))
+	sarif := sarifLog{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &sarif))
+	assert.Equal("2.1.0", sarif.Version)
+	require.Len(t, sarif.Runs, 1)
+	var result *sarifResult
+	for i, r := range sarif.Runs[0].Results {
+		if r.RuleID == "KIA1101" {
+			result = &sarif.Runs[0].Results[i]
+		}
+	}
+	require.NotNil(t, result)
+	assert.Equal("error", result.Level)
+	require.NotNil(t, result.Locations[0].PhysicalLocation)
+	assert.Equal("bookinfo.yaml", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(26, result.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal("virtualservice/bookinfo/reviews", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	assert.Error(Write(&out, "xml", validations, m))
+}
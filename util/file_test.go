@@ -0,0 +1,29 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "content.json")
+
+	require.NoError(t, WriteFileAtomic(path, []byte("first")))
+	require.NoError(t, WriteFileAtomic(path, []byte("second")))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal("second", string(content))
+
+	// the temporary files are renamed or removed
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(entries, 1)
+
+	assert.Error(WriteFileAtomic(filepath.Join(dir, "missing", "content.json"), []byte("third")))
+}
@@ -2,9 +2,16 @@ package util
 
 import (
 	cryptoRand "crypto/rand"
+	"encoding/hex"
+	"fmt"
 	mathRand "math/rand"
+	"regexp"
+	"time"
 )
 
+// timeSortableID matches the IDs generated by NewTimeSortableID
+var timeSortableID = regexp.MustCompile(`^[0-9]{8}T[0-9]{6}Z-[0-9a-f]{8}$`)
+
 // RandomString generates a random string of length n. Before calling this function, you should call
 // rand.Seed() to initialize the default source.
 //
@@ -54,3 +61,20 @@ func CryptoRandomString(n int) (string, error) {
 	}
 	return string(bytes), nil
 }
+
+// NewTimeSortableID returns a unique ID made of the UTC creation time, to the second, and a random suffix, such that
+// the IDs sort by creation time. The IDs only contain digits, letters and dashes, they can be used in paths.
+func NewTimeSortableID(created time.Time) string {
+	suffix, err := CryptoRandomBytes(4)
+	if err != nil {
+		// not expected, fall back to the nanoseconds, the IDs must just be unique
+		nanos := created.Nanosecond()
+		suffix = []byte{byte(nanos >> 24), byte(nanos >> 16), byte(nanos >> 8), byte(nanos)}
+	}
+	return fmt.Sprintf("%s-%s", created.UTC().Format("20060102T150405Z"), hex.EncodeToString(suffix))
+}
+
+// IsTimeSortableID returns true if the ID has the form of the IDs generated by NewTimeSortableID
+func IsTimeSortableID(id string) bool {
+	return timeSortableID.MatchString(id)
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTimeSortableID(t *testing.T) {
+	assert := assert.New(t)
+
+	earlier := NewTimeSortableID(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := NewTimeSortableID(time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC))
+	assert.Regexp(`^20240101T000000Z-[0-9a-f]{8}$`, earlier)
+	assert.Less(earlier, later)
+	assert.NotEqual(earlier, NewTimeSortableID(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.True(IsTimeSortableID(earlier))
+	assert.False(IsTimeSortableID(""))
+	assert.False(IsTimeSortableID("20240101T000000Z-0000000g"))
+	assert.False(IsTimeSortableID("../20240101T000000Z-00000000"))
+}